@@ -7,59 +7,262 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/nikallow/bookstores-api/internal/adapters/postgres"
+	"github.com/nikallow/bookstores-api/internal/apikeys"
+	"github.com/nikallow/bookstores-api/internal/audit"
+	"github.com/nikallow/bookstores-api/internal/auth"
+	"github.com/nikallow/bookstores-api/internal/authors"
 	"github.com/nikallow/bookstores-api/internal/books"
+	"github.com/nikallow/bookstores-api/internal/exports"
+	"github.com/nikallow/bookstores-api/internal/genres"
+	"github.com/nikallow/bookstores-api/internal/idempotency"
+	"github.com/nikallow/bookstores-api/internal/imports"
 	"github.com/nikallow/bookstores-api/internal/inventory"
 	appMiddleware "github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/orders"
+	"github.com/nikallow/bookstores-api/internal/promotions"
+	"github.com/nikallow/bookstores-api/internal/publishers"
+	"github.com/nikallow/bookstores-api/internal/ratelimit"
+	"github.com/nikallow/bookstores-api/internal/rates"
+	"github.com/nikallow/bookstores-api/internal/reservations"
+	"github.com/nikallow/bookstores-api/internal/response"
+	"github.com/nikallow/bookstores-api/internal/series"
 	"github.com/nikallow/bookstores-api/internal/stores"
+	"github.com/nikallow/bookstores-api/internal/tags"
+	"github.com/nikallow/bookstores-api/internal/transfers"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 )
 
+const (
+	requestTimeout = 60 * time.Second
+	exportTimeout  = 30 * time.Minute
+)
+
 type APIDependencies struct {
-	Logger           *slog.Logger
-	StoreHandler     *stores.Handler
-	BooksHandler     *books.Handler
-	InventoryHandler *inventory.Handler
+	Logger              *slog.Logger
+	DB                  *postgres.DB
+	RealIP              *appMiddleware.RealIP
+	Auth                *auth.Middleware
+	Idempotency         *idempotency.Middleware
+	RateLimit           *ratelimit.Limiter
+	StoreHandler        *stores.Handler
+	BooksHandler        *books.Handler
+	AuthorsHandler      *authors.Handler
+	PublishersHandler   *publishers.Handler
+	GenresHandler       *genres.Handler
+	TagsHandler         *tags.Handler
+	SeriesHandler       *series.Handler
+	InventoryHandler    *inventory.Handler
+	OrdersHandler       *orders.Handler
+	ReservationsHandler *reservations.Handler
+	TransfersHandler    *transfers.Handler
+	ImportsHandler      *imports.Handler
+	ExportsHandler      *exports.Handler
+	PromotionsHandler   *promotions.Handler
+	RatesHandler        *rates.Handler
+	APIKeysHandler      *apikeys.Handler
+	AuditHandler        *audit.Handler
 }
 
 func MountAPI(deps *APIDependencies) http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(deps.RealIP.Handler)
 	r.Use(appMiddleware.NewSlogLogger(deps.Logger))
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
 
-	r.Get("/swagger/*", httpSwagger.Handler(
-		httpSwagger.URL("/swagger/doc.json"),
-	))
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(requestTimeout))
 
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/plain")
-		w.Write([]byte("OK"))
-	})
+		r.With(deps.RateLimit.Limit(ratelimit.GroupIP)).Get("/swagger/*", httpSwagger.Handler(
+			httpSwagger.URL("/swagger/doc.json"),
+		))
 
-	r.Route("/stores", func(r chi.Router) {
-		r.Post("/", deps.StoreHandler.CreateStore)
-		r.Get("/", deps.StoreHandler.ListStores)
-		r.Get("/{storeUUID}", deps.StoreHandler.GetStore)
-		r.Put("/{storeUUID}", deps.StoreHandler.UpdateStore)
-		r.Delete("/{storeUUID}", deps.StoreHandler.DeleteStore)
+		r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("OK"))
+		})
+
+		// Only up or down here: the pool stats are for admins, see /health/db/stats.
+		r.Get("/health/db", func(w http.ResponseWriter, r *http.Request) {
+			if err := deps.DB.Ping(r.Context()); err != nil {
+				response.WriteError(w, r, http.StatusServiceUnavailable, "database is unavailable")
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("OK"))
+		})
 	})
 
-	r.Route("/books", func(r chi.Router) {
-		r.Post("/", deps.BooksHandler.CreateBook)
-		r.Get("/", deps.BooksHandler.ListBooks)
-		r.Get("/{bookID}", deps.BooksHandler.GetBook)
-		r.Get("/search", deps.BooksHandler.SearchBooks)
-		r.Get("/{bookID}/availability", deps.BooksHandler.GetBookAvailability)
+	// Everything else needs a bearer token or an API key. Reads are open to every role, writes are guarded
+	// per route: catalog and settings are for admins, store operations for the staff of the store.
+	// API keys are allowed what their scopes cover instead. Every client is rate limited, by its address
+	// before authentication and by its credentials after it, search and exports more strictly.
+	catalog := auth.Require(auth.ScopeCatalogWrite, auth.RoleAdmin)
+	pricing := auth.Require(auth.ScopePricingWrite, auth.RoleAdmin)
+	staff := []auth.Role{auth.RoleStoreManager, auth.RoleClerk}
+	skuStore := auth.BodyStore(func(req inventory.CreateSKURequest) uuid.UUID { return req.StoreUUID })
+	transferSource := auth.BodyStore(func(req transfers.CreateTransferRequest) uuid.UUID { return req.SourceStoreUUID })
+	orderStores := auth.BodySKUStores(deps.Auth, func(req orders.CreateOrderRequest) []uuid.UUID {
+		skus := make([]uuid.UUID, len(req.Items))
+		for i, item := range req.Items {
+			skus[i] = item.SKUUUID
+		}
+		return skus
 	})
 
-	r.Route("/skus", func(r chi.Router) {
-		r.Post("/", deps.InventoryHandler.CreateSKU)
-		r.Get("/{skuUUID}", deps.InventoryHandler.GetSKU)
-		r.Put("/{skuUUID}/price", deps.InventoryHandler.UpdateSKUPrice)
-		r.Post("/{skuUUID}/stock-adjustments", deps.InventoryHandler.AdjustSKUStock)
+	r.Group(func(r chi.Router) {
+		r.Use(deps.RateLimit.Limit(ratelimit.GroupIP))
+		r.Use(deps.Auth.Authenticate)
+		r.Use(deps.RateLimit.Limit(ratelimit.GroupDefault))
+		r.Use(deps.Idempotency.Handler)
+
+		// Exports page through whole tables while streaming them, far longer than other requests take.
+		r.Route("/exports", func(r chi.Router) {
+			r.Use(middleware.Timeout(exportTimeout))
+			r.Use(deps.RateLimit.Limit(ratelimit.GroupExports))
+			r.With(auth.RequireStore(auth.QueryStore("store_uuid"), auth.ScopeExportsRead, auth.RoleStoreManager)).Get("/inventory", deps.ExportsHandler.ExportInventory)
+			r.Get("/books", deps.ExportsHandler.ExportBooks)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Timeout(requestTimeout))
+
+			r.Route("/stores", func(r chi.Router) {
+				r.With(auth.RequireStore(auth.EveryStore, auth.ScopeStoresWrite)).Post("/", deps.StoreHandler.CreateStore)
+				r.Get("/", deps.StoreHandler.ListStores)
+				r.Get("/{storeUUID}", deps.StoreHandler.GetStore)
+				r.With(auth.RequireStore(auth.PathStore("storeUUID"), auth.ScopeStoresWrite, auth.RoleStoreManager)).Put("/{storeUUID}", deps.StoreHandler.UpdateStore)
+				r.With(auth.RequireStore(auth.PathStore("storeUUID"), auth.ScopeStoresWrite)).Delete("/{storeUUID}", deps.StoreHandler.DeleteStore)
+				r.Get("/{storeUUID}/skus", deps.InventoryHandler.ListStoreSKUs)
+			})
+
+			r.Route("/books", func(r chi.Router) {
+				r.With(catalog).Post("/", deps.BooksHandler.CreateBook)
+				r.Get("/", deps.BooksHandler.ListBooks)
+				r.Get("/{bookID}", deps.BooksHandler.GetBook)
+				r.With(catalog).Put("/{bookID}", deps.BooksHandler.UpdateBook)
+				r.With(catalog).Patch("/{bookID}", deps.BooksHandler.PatchBook)
+				r.With(catalog).Delete("/{bookID}", deps.BooksHandler.DeleteBook)
+				r.With(catalog).Post("/{bookID}/restore", deps.BooksHandler.RestoreBook)
+				r.With(deps.RateLimit.Limit(ratelimit.GroupSearch)).Get("/search", deps.BooksHandler.SearchBooks)
+				r.Get("/isbn/{isbn}", deps.BooksHandler.GetBookByISBN)
+				r.With(catalog).Put("/isbn/{isbn}", deps.BooksHandler.UpsertBookByISBN)
+				r.Get("/{bookID}/availability", deps.BooksHandler.GetBookAvailability)
+			})
+
+			r.Route("/authors", func(r chi.Router) {
+				r.With(catalog).Post("/", deps.AuthorsHandler.CreateAuthor)
+				r.Get("/", deps.AuthorsHandler.ListAuthors)
+				r.Get("/{authorID}", deps.AuthorsHandler.GetAuthor)
+				r.With(catalog).Put("/{authorID}", deps.AuthorsHandler.UpdateAuthor)
+				r.With(catalog).Delete("/{authorID}", deps.AuthorsHandler.DeleteAuthor)
+				r.Get("/{authorID}/books", deps.BooksHandler.ListAuthorBooks)
+			})
+
+			r.Route("/publishers", func(r chi.Router) {
+				r.With(catalog).Post("/", deps.PublishersHandler.CreatePublisher)
+				r.Get("/", deps.PublishersHandler.ListPublishers)
+				r.Get("/{publisherID}", deps.PublishersHandler.GetPublisher)
+				r.With(catalog).Put("/{publisherID}", deps.PublishersHandler.UpdatePublisher)
+				r.With(catalog).Delete("/{publisherID}", deps.PublishersHandler.DeletePublisher)
+			})
+
+			r.Route("/genres", func(r chi.Router) {
+				r.With(catalog).Post("/", deps.GenresHandler.CreateGenre)
+				r.Get("/", deps.GenresHandler.ListGenres)
+				r.Get("/{genreID}", deps.GenresHandler.GetGenre)
+				r.With(catalog).Put("/{genreID}", deps.GenresHandler.UpdateGenre)
+				r.With(catalog).Delete("/{genreID}", deps.GenresHandler.DeleteGenre)
+			})
+
+			r.Route("/tags", func(r chi.Router) {
+				r.With(catalog).Post("/", deps.TagsHandler.CreateTag)
+				r.Get("/", deps.TagsHandler.ListTags)
+				r.Get("/{tagID}", deps.TagsHandler.GetTag)
+				r.With(catalog).Put("/{tagID}", deps.TagsHandler.UpdateTag)
+				r.With(catalog).Delete("/{tagID}", deps.TagsHandler.DeleteTag)
+			})
+
+			r.Route("/series", func(r chi.Router) {
+				r.With(catalog).Post("/", deps.SeriesHandler.CreateSeries)
+				r.Get("/", deps.SeriesHandler.ListSeries)
+				r.Get("/{seriesID}", deps.SeriesHandler.GetSeries)
+				r.With(catalog).Put("/{seriesID}", deps.SeriesHandler.UpdateSeries)
+				r.With(catalog).Delete("/{seriesID}", deps.SeriesHandler.DeleteSeries)
+			})
+
+			r.Route("/skus", func(r chi.Router) {
+				r.With(auth.RequireStore(skuStore, auth.ScopeInventoryWrite, auth.RoleStoreManager)).Post("/", deps.InventoryHandler.CreateSKU)
+				r.Get("/{skuUUID}", deps.InventoryHandler.GetSKU)
+				r.With(auth.RequireStore(deps.Auth.SKUStore, auth.ScopeInventoryWrite, auth.RoleStoreManager)).Put("/{skuUUID}/price", deps.InventoryHandler.UpdateSKUPrice)
+				r.Get("/{skuUUID}/price-history", deps.InventoryHandler.ListSKUPriceHistory)
+				r.Get("/{skuUUID}/effective-price", deps.PromotionsHandler.GetEffectivePrice)
+				r.With(auth.RequireStore(deps.Auth.SKUStore, auth.ScopeInventoryWrite, auth.RoleStoreManager)).Post("/{skuUUID}/price-history/{priceUUID}/cancel", deps.InventoryHandler.CancelSKUPrice)
+				r.With(auth.RequireStore(deps.Auth.SKUStore, auth.ScopeInventoryWrite, staff...)).Post("/{skuUUID}/stock-adjustments", deps.InventoryHandler.AdjustSKUStock)
+				r.Get("/{skuUUID}/movements", deps.InventoryHandler.ListStockMovements)
+				r.With(auth.RequireStore(deps.Auth.SKUStore, auth.ScopeInventoryWrite, staff...)).Post("/{skuUUID}/reservations", deps.ReservationsHandler.CreateReservation)
+			})
+
+			r.Route("/reservations", func(r chi.Router) {
+				r.Get("/{reservationUUID}", deps.ReservationsHandler.GetReservation)
+				r.With(auth.RequireStore(deps.Auth.ReservationStore, auth.ScopeInventoryWrite, staff...)).Post("/{reservationUUID}/confirm", deps.ReservationsHandler.ConfirmReservation)
+				r.With(auth.RequireStore(deps.Auth.ReservationStore, auth.ScopeInventoryWrite, staff...)).Post("/{reservationUUID}/release", deps.ReservationsHandler.ReleaseReservation)
+			})
+
+			r.Route("/transfers", func(r chi.Router) {
+				r.With(auth.RequireStore(transferSource, auth.ScopeInventoryWrite, auth.RoleStoreManager)).Post("/", deps.TransfersHandler.CreateTransfer)
+				r.Get("/", deps.TransfersHandler.ListTransfers)
+				r.Get("/{transferUUID}", deps.TransfersHandler.GetTransfer)
+				r.With(auth.RequireStore(deps.Auth.TransferDestination, auth.ScopeInventoryWrite, staff...)).Post("/{transferUUID}/receive", deps.TransfersHandler.ReceiveTransfer)
+				r.With(auth.RequireStore(deps.Auth.TransferSource, auth.ScopeInventoryWrite, auth.RoleStoreManager)).Post("/{transferUUID}/cancel", deps.TransfersHandler.CancelTransfer)
+			})
+
+			r.Route("/orders", func(r chi.Router) {
+				r.With(auth.RequireStore(orderStores, auth.ScopeOrdersWrite, staff...)).Post("/", deps.OrdersHandler.CreateOrder)
+				r.Get("/", deps.OrdersHandler.ListOrders)
+				r.Get("/{orderUUID}", deps.OrdersHandler.GetOrder)
+				r.With(auth.RequireStore(deps.Auth.OrderStores, auth.ScopeOrdersWrite, staff...)).Post("/{orderUUID}/pay", deps.OrdersHandler.PayOrder)
+				r.With(auth.RequireStore(deps.Auth.OrderStores, auth.ScopeOrdersWrite, staff...)).Post("/{orderUUID}/fulfill", deps.OrdersHandler.FulfillOrder)
+				r.With(auth.RequireStore(deps.Auth.OrderStores, auth.ScopeOrdersWrite, staff...)).Post("/{orderUUID}/cancel", deps.OrdersHandler.CancelOrder)
+			})
+
+			r.Route("/imports", func(r chi.Router) {
+				r.With(catalog).Post("/", deps.ImportsHandler.CreateImport)
+				r.With(catalog).Get("/{importUUID}", deps.ImportsHandler.GetImport)
+				r.With(catalog).Get("/{importUUID}/errors", deps.ImportsHandler.GetImportErrors)
+			})
+
+			r.Route("/promotions", func(r chi.Router) {
+				r.With(pricing).Post("/", deps.PromotionsHandler.CreatePromotion)
+				r.Get("/", deps.PromotionsHandler.ListPromotions)
+				r.Get("/{promotionID}", deps.PromotionsHandler.GetPromotion)
+				r.With(pricing).Put("/{promotionID}", deps.PromotionsHandler.UpdatePromotion)
+				r.With(pricing).Delete("/{promotionID}", deps.PromotionsHandler.DeletePromotion)
+			})
+
+			r.Route("/exchange-rates", func(r chi.Router) {
+				r.Get("/", deps.RatesHandler.ListExchangeRates)
+				r.With(pricing).Put("/{base}/{quote}", deps.RatesHandler.SetExchangeRate)
+				r.With(pricing).Delete("/{base}/{quote}", deps.RatesHandler.DeleteExchangeRate)
+			})
+
+			r.Route("/api-keys", func(r chi.Router) {
+				r.Use(auth.RequireAdmin)
+				r.Post("/", deps.APIKeysHandler.CreateAPIKey)
+				r.Get("/", deps.APIKeysHandler.ListAPIKeys)
+				r.Get("/{keyUUID}", deps.APIKeysHandler.GetAPIKey)
+				r.Post("/{keyUUID}/revoke", deps.APIKeysHandler.RevokeAPIKey)
+				r.Post("/{keyUUID}/rotate", deps.APIKeysHandler.RotateAPIKey)
+			})
+
+			r.With(auth.RequireAdmin).Get("/audit", deps.AuditHandler.ListEvents)
+			r.With(auth.RequireAdmin).Get("/health/db/stats", func(w http.ResponseWriter, r *http.Request) {
+				response.WriteJSON(w, r, http.StatusOK, deps.DB.Stats())
+			})
+		})
 	})
 
 	return r
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/google/uuid"
+	"github.com/nikallow/bookstores-api/internal/adapters/postgres"
+	"github.com/nikallow/bookstores-api/internal/config"
+	"github.com/nikallow/bookstores-api/internal/exports"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+)
+
+const exportUsage = "usage: export [-format csv|ndjson|xlsx] [-store <uuid>] [-o <file>] inventory|books"
+
+// runExport handles `export inventory|books`: the export is written to a file, or to
+// stdout without -o.
+func runExport(cfg *config.Config, l *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	formatName := fs.String("format", "", "file format, csv, ndjson or xlsx (default: from the -o extension, else csv)")
+	store := fs.String("store", "", "export the inventory of this store only")
+	output := fs.String("o", "", "output file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New(exportUsage)
+	}
+	what := fs.Arg(0)
+	if what != "inventory" && what != "books" {
+		return errors.New(exportUsage)
+	}
+
+	name := *formatName
+	if name == "" {
+		name = filepath.Ext(*output)
+	}
+	format := exports.FormatCSV
+	if name != "" {
+		var ok bool
+		if format, ok = exports.ParseFormat(name); !ok {
+			return fmt.Errorf("unknown export format %q", name)
+		}
+	}
+
+	var storeUUID *uuid.UUID
+	if *store != "" {
+		if what != "inventory" {
+			return errors.New("-store applies to the inventory export only")
+		}
+		id, err := uuid.Parse(*store)
+		if err != nil {
+			return fmt.Errorf("invalid store uuid: %w", err)
+		}
+		storeUUID = &id
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	ctx = middleware.WithLogger(ctx, l.With("component", "export"))
+
+	db, err := postgres.NewPool(ctx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	service := exports.NewService(db, 1)
+	if what == "inventory" {
+		err = service.Inventory(ctx, storeUUID, format, out)
+	} else {
+		err = service.Books(ctx, format, out)
+	}
+	if err != nil {
+		if *output != "" {
+			os.Remove(*output)
+		}
+		return err
+	}
+	if f, ok := out.(*os.File); ok && f != os.Stdout {
+		return f.Close()
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/nikallow/bookstores-api/internal/adapters/postgres"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/books"
+	"github.com/nikallow/bookstores-api/internal/config"
+	"github.com/nikallow/bookstores-api/internal/imports"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+)
+
+// runImport handles `import [-dry-run] [-upsert] [-format csv|ndjson] <file>`: the rows of a
+// local file are imported right away, without an import job, and the failed rows are printed.
+func runImport(cfg *config.Config, l *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "only check the rows and report what they would do")
+	upsert := fs.Bool("upsert", false, "update books that already exist")
+	formatName := fs.String("format", "", "file format, csv or ndjson (default: from the file extension)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: import [-dry-run] [-upsert] [-format csv|ndjson] <file>")
+	}
+	path := fs.Arg(0)
+
+	name := *formatName
+	if name == "" {
+		name = filepath.Ext(path)
+	}
+	format, ok := imports.ParseFormat(name)
+	if !ok {
+		return fmt.Errorf("unknown file format %q, pass -format csv or -format ndjson", name)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	records, err := imports.Parse(format, data)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	ctx = middleware.WithLogger(ctx, l.With("component", "import"))
+	ctx = middleware.WithActor(ctx, "cli")
+
+	db, err := postgres.NewPool(ctx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	dbQuerier := repo.New(db)
+	importer := imports.NewImporter(books.NewService(dbQuerier, db), dbQuerier, db)
+
+	var total imports.Result
+	opts := imports.Options{DryRun: *dryRun, Upsert: *upsert}
+	err = importer.Run(ctx, records, 0, cfg.Imports.BatchSize, opts, func(_ *repo.Queries, res imports.Result) error {
+		total.Add(res)
+		l.Info("Import progress", "processed", total.Processed, "rows", len(records), "failed", total.Failed)
+		return nil
+	})
+	printImportResult(total, opts)
+	if err != nil {
+		return err
+	}
+	if total.Failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", total.Failed, len(records))
+	}
+	return nil
+}
+
+func printImportResult(res imports.Result, opts imports.Options) {
+	if opts.DryRun {
+		fmt.Println("Dry run, nothing was written.")
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "rows processed\t%d\n", res.Processed)
+	fmt.Fprintf(tw, "rows failed\t%d\n", res.Failed)
+	fmt.Fprintf(tw, "books created\t%d\n", res.BooksCreated)
+	fmt.Fprintf(tw, "books updated\t%d\n", res.BooksUpdated)
+	fmt.Fprintf(tw, "books existing\t%d\n", res.BooksExisting)
+	fmt.Fprintf(tw, "skus created\t%d\n", res.SKUsCreated)
+	tw.Flush()
+
+	if len(res.Errors) == 0 {
+		return
+	}
+	fmt.Println()
+	tw = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LINE\tISBN\tERROR")
+	for _, e := range res.Errors {
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", e.Line, e.ISBN, e.Message)
+	}
+	tw.Flush()
+}
@@ -11,14 +11,32 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/jackc/pgx/v5"
 	_ "github.com/nikallow/bookstores-api/docs"
+	"github.com/nikallow/bookstores-api/internal/adapters/postgres"
 	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/apikeys"
+	"github.com/nikallow/bookstores-api/internal/audit"
+	"github.com/nikallow/bookstores-api/internal/auth"
+	"github.com/nikallow/bookstores-api/internal/authors"
 	"github.com/nikallow/bookstores-api/internal/books"
 	"github.com/nikallow/bookstores-api/internal/config"
+	"github.com/nikallow/bookstores-api/internal/exports"
+	"github.com/nikallow/bookstores-api/internal/genres"
+	"github.com/nikallow/bookstores-api/internal/idempotency"
+	"github.com/nikallow/bookstores-api/internal/imports"
 	"github.com/nikallow/bookstores-api/internal/inventory"
 	"github.com/nikallow/bookstores-api/internal/logger"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/orders"
+	"github.com/nikallow/bookstores-api/internal/promotions"
+	"github.com/nikallow/bookstores-api/internal/publishers"
+	"github.com/nikallow/bookstores-api/internal/ratelimit"
+	"github.com/nikallow/bookstores-api/internal/rates"
+	"github.com/nikallow/bookstores-api/internal/reservations"
+	"github.com/nikallow/bookstores-api/internal/series"
 	"github.com/nikallow/bookstores-api/internal/stores"
+	"github.com/nikallow/bookstores-api/internal/tags"
+	"github.com/nikallow/bookstores-api/internal/transfers"
 )
 
 // @title			Bookstores API
@@ -26,6 +44,18 @@ import (
 // @description	Это REST API для сервиса сети книжных магазинов.
 // @host			localhost:8080
 // @BasePath		/
+// @security		BearerAuth
+// @security		APIKeyAuth
+//
+// @securityDefinitions.apikey	BearerAuth
+// @in							header
+// @name						Authorization
+// @description				JWT или API-ключ в формате "Bearer <token>".
+//
+// @securityDefinitions.apikey	APIKeyAuth
+// @in							header
+// @name						X-API-Key
+// @description				API-ключ машинного клиента.
 func main() {
 	// Config
 	configPath := os.Getenv("CONFIG_PATH")
@@ -46,37 +76,182 @@ func main() {
 	l := logger.SetupLogger(cfg)
 	l.Info("Logger initialized", "env", cfg.Env)
 
+	// Subcommands
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, l, os.Args[2:]); err != nil {
+			l.Error("Migration failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(cfg, l, os.Args[2:]); err != nil {
+			l.Error("Import failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(cfg, l, os.Args[2:]); err != nil {
+			l.Error("Export failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// PostgreSQL
+	if cfg.Database.AutoMigrate {
+		if err := autoMigrate(context.Background(), cfg, l); err != nil {
+			l.Error("Failed to apply migrations", "error", err)
+			os.Exit(1)
+		}
+	}
+
 	dbCtx, dbCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer dbCancel()
-	conn, err := pgx.Connect(dbCtx, cfg.Database.GetDSN())
+	db, err := postgres.NewPool(dbCtx, cfg.Database)
 	if err != nil {
 		l.Error("Failed to connect to database", "error", err)
 		os.Exit(1)
 	}
-	defer conn.Close(context.Background())
-	l.Info("Connected to database", "db_name", cfg.Database.DBName, "host", cfg.Database.Host)
+	defer db.Close()
+	l.Info("Connected to database",
+		"db_name", cfg.Database.DBName,
+		"host", cfg.Database.Host,
+		"max_conns", cfg.Database.MaxConns,
+		"min_conns", cfg.Database.MinConns)
 
 	// DI
-	dbQuerier := repo.New(conn)
+	dbQuerier := repo.New(db)
 
 	// Services and Handlers
-	storeService := stores.NewService(dbQuerier)
+	storeService := stores.NewService(dbQuerier, db)
 	storeHandler := stores.NewHandler(storeService)
 
-	booksService := books.NewService(dbQuerier)
-	booksHandler := books.NewHandler(booksService)
+	promotionsService := promotions.NewService(dbQuerier)
+	promotionsHandler := promotions.NewHandler(promotionsService)
 
-	inventoryService := inventory.NewService(dbQuerier, conn)
+	ratesService := rates.NewService(dbQuerier)
+	ratesHandler := rates.NewHandler(ratesService)
+
+	apiKeysService := apikeys.NewService(dbQuerier, db)
+	apiKeysHandler := apikeys.NewHandler(apiKeysService)
+
+	auditService := audit.NewService(dbQuerier)
+	auditHandler := audit.NewHandler(auditService)
+
+	booksService := books.NewService(dbQuerier, db)
+	booksHandler := books.NewHandler(booksService, promotionsService, ratesService)
+
+	authorsService := authors.NewService(dbQuerier, db)
+	authorsHandler := authors.NewHandler(authorsService)
+
+	publishersService := publishers.NewService(dbQuerier)
+	publishersHandler := publishers.NewHandler(publishersService)
+
+	genresService := genres.NewService(dbQuerier)
+	genresHandler := genres.NewHandler(genresService)
+
+	tagsService := tags.NewService(dbQuerier)
+	tagsHandler := tags.NewHandler(tagsService)
+
+	seriesService := series.NewService(dbQuerier)
+	seriesHandler := series.NewHandler(seriesService)
+
+	inventoryService := inventory.NewService(dbQuerier, db, cfg.Prices)
 	inventoryHandler := inventory.NewHandler(inventoryService)
 
+	ordersService := orders.NewService(dbQuerier, db)
+	ordersHandler := orders.NewHandler(ordersService)
+
+	reservationsService := reservations.NewService(dbQuerier, db, cfg.Reservations)
+	reservationsHandler := reservations.NewHandler(reservationsService)
+
+	transfersService := transfers.NewService(dbQuerier, db)
+	transfersHandler := transfers.NewHandler(transfersService)
+
+	importer := imports.NewImporter(booksService, dbQuerier, db)
+	importsService := imports.NewService(dbQuerier, importer, cfg.Imports)
+	importsHandler := imports.NewHandler(importsService, cfg.Imports)
+
+	exportsService := exports.NewService(db, cfg.Exports.MaxConcurrent)
+	exportsHandler := exports.NewHandler(exportsService)
+
+	authMiddleware, err := auth.New(dbQuerier, cfg.Auth)
+	if err != nil {
+		l.Error("Failed to set up authentication", "error", err)
+		os.Exit(1)
+	}
+
+	realIP, err := middleware.NewRealIP(cfg.Service.TrustedProxies)
+	if err != nil {
+		l.Error("Failed to set up trusted proxies", "error", err)
+		os.Exit(1)
+	}
+
+	rateLimiter, err := ratelimit.New(dbQuerier, cfg.RateLimit, l)
+	if err != nil {
+		l.Error("Failed to set up rate limiting", "error", err)
+		os.Exit(1)
+	}
+
+	// Import uploads are the largest request bodies the API accepts.
+	idempotencyMiddleware := idempotency.New(dbQuerier, cfg.Idempotency, imports.MaxBodySize(cfg.Imports))
+
 	apiDeps := &APIDependencies{
-		Logger:           l,
-		StoreHandler:     storeHandler,
-		BooksHandler:     booksHandler,
-		InventoryHandler: inventoryHandler,
+		Logger:              l,
+		DB:                  db,
+		RealIP:              realIP,
+		Auth:                authMiddleware,
+		Idempotency:         idempotencyMiddleware,
+		RateLimit:           rateLimiter,
+		StoreHandler:        storeHandler,
+		BooksHandler:        booksHandler,
+		AuthorsHandler:      authorsHandler,
+		PublishersHandler:   publishersHandler,
+		GenresHandler:       genresHandler,
+		TagsHandler:         tagsHandler,
+		SeriesHandler:       seriesHandler,
+		InventoryHandler:    inventoryHandler,
+		OrdersHandler:       ordersHandler,
+		ReservationsHandler: reservationsHandler,
+		TransfersHandler:    transfersHandler,
+		ImportsHandler:      importsHandler,
+		ExportsHandler:      exportsHandler,
+		PromotionsHandler:   promotionsHandler,
+		RatesHandler:        ratesHandler,
+		APIKeysHandler:      apiKeysHandler,
+		AuditHandler:        auditHandler,
 	}
 
+	// Background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	sweeperDone := make(chan struct{})
+	go func() {
+		defer close(sweeperDone)
+		reservations.NewSweeper(reservationsService, cfg.Reservations, l).Run(jobsCtx)
+	}()
+	importsDone := make(chan struct{})
+	go func() {
+		defer close(importsDone)
+		imports.NewWorker(importsService, cfg.Imports, l).Run(jobsCtx)
+	}()
+	pricesDone := make(chan struct{})
+	go func() {
+		defer close(pricesDone)
+		inventory.NewPriceScheduler(inventoryService, cfg.Prices, l).Run(jobsCtx)
+	}()
+	idempotencyDone := make(chan struct{})
+	go func() {
+		defer close(idempotencyDone)
+		idempotency.NewSweeper(dbQuerier, cfg.Idempotency, l).Run(jobsCtx)
+	}()
+	rateLimitDone := make(chan struct{})
+	go func() {
+		defer close(rateLimitDone)
+		rateLimiter.Run(jobsCtx)
+	}()
+
 	// Launch HTTP server
 	httpServer := NewHTTPServer(cfg, apiDeps)
 
@@ -102,6 +277,13 @@ func main() {
 	} else {
 		l.Info("Server gracefully stopped")
 	}
+
+	stopJobs()
+	<-sweeperDone
+	<-importsDone
+	<-pricesDone
+	<-idempotencyDone
+	<-rateLimitDone
 }
 
 func NewHTTPServer(cfg *config.Config, deps *APIDependencies) *http.Server {
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/nikallow/bookstores-api/internal/config"
+	"github.com/nikallow/bookstores-api/internal/database"
+)
+
+// runMigrate handles `migrate up|down|status|redo|version`.
+func runMigrate(cfg *config.Config, l *slog.Logger, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up|down|status|redo|version")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	migrator, err := database.NewMigrator(cfg.Database, l)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	return migrator.Run(ctx, args[0], os.Stdout)
+}
+
+// autoMigrate applies pending migrations at startup when database.auto_migrate is set.
+func autoMigrate(ctx context.Context, cfg *config.Config, l *slog.Logger) error {
+	migrator, err := database.NewMigrator(cfg.Database, l)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	return migrator.Up(ctx)
+}
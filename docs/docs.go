@@ -15,24 +15,35 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/books": {
+        "/api-keys": {
             "get": {
-                "description": "Возвращает список всех книг в глобальном каталоге.",
+                "description": "Возвращает действующие ключи (с include_inactive=true - также отозванные и истёкшие). Доступно только администратору.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "api-keys"
+                ],
+                "summary": "Список API-ключей",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Показать отозванные и истёкшие ключи",
+                        "name": "include_inactive",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Получить глобальный список книг",
                 "responses": {
                     "200": {
-                        "description": "Список книг",
+                        "description": "Ключи",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/books.BookResponse"
-                            }
+                            "$ref": "#/definitions/apikeys.APIKeyListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
@@ -44,7 +55,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Создаёт новую книгу в глобальном каталоге. Если книга с таким ISBN уже существует, обновляет ее данные.",
+                "description": "Выпускает ключ для машинного клиента. Ключ возвращается только в этом ответе, в БД хранится его хеш.\nБез store_uuids ключ действует во всех магазинах. Доступно только администратору.",
                 "consumes": [
                     "application/json"
                 ],
@@ -52,73 +63,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "api-keys"
                 ],
-                "summary": "Создать новую книгу",
+                "summary": "Создать API-ключ",
                 "parameters": [
                     {
-                        "description": "Данные для создания книги",
+                        "description": "Название, scopes, магазины и срок действия",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/books.CreateBookRequest"
+                            "$ref": "#/definitions/apikeys.CreateAPIKeyRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Инфо об книге",
+                        "description": "Ключ создан",
                         "schema": {
-                            "$ref": "#/definitions/books.BookResponse"
+                            "$ref": "#/definitions/apikeys.CreatedAPIKeyResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                        "description": "Bad request error",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/books/search": {
-            "get": {
-                "description": "Ищет книги по части названия или имени автора.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "books"
-                ],
-                "summary": "Поиск книг",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Поисковый запрос",
-                        "name": "q",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Список найденных книг",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/books.BookResponse"
-                            }
-                        }
                     },
-                    "400": {
-                        "description": "Bad request error",
+                    "404": {
+                        "description": "Магазин не найден",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -132,30 +105,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/books/{bookID}": {
+        "/api-keys/{keyUUID}": {
             "get": {
-                "description": "Возвращает информацию о книге по её ID.",
+                "description": "Возвращает ключ без секрета, в том числе время последнего использования. Доступно только администратору.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "api-keys"
                 ],
-                "summary": "Получить одну книгу",
+                "summary": "Получить API-ключ",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID книги",
-                        "name": "bookID",
+                        "type": "string",
+                        "description": "UUID ключа",
+                        "name": "keyUUID",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Инфо о книге",
+                        "description": "Ключ",
                         "schema": {
-                            "$ref": "#/definitions/books.BookResponse"
+                            "$ref": "#/definitions/apikeys.APIKeyResponse"
                         }
                     },
                     "400": {
@@ -165,7 +138,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Книга отсутствует",
+                        "description": "Ключ не найден",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -179,33 +152,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/books/{bookID}/availability": {
-            "get": {
-                "description": "Показывает, в каких магазинах, по какой цене и в каком количестве доступна книга.",
+        "/api-keys/{keyUUID}/revoke": {
+            "post": {
+                "description": "Ключ перестаёт действовать сразу. Доступно только администратору.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "books"
+                    "api-keys"
                 ],
-                "summary": "Доступность книги",
+                "summary": "Отозвать API-ключ",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID книги",
-                        "name": "bookID",
+                        "type": "string",
+                        "description": "UUID ключа",
+                        "name": "keyUUID",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Отозванный ключ",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/books.AvailabilityResponse"
-                            }
+                            "$ref": "#/definitions/apikeys.APIKeyResponse"
                         }
                     },
                     "400": {
@@ -215,7 +185,13 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Книга отсутствует",
+                        "description": "Ключ не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Ключ уже отозван или истёк",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -229,9 +205,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/skus": {
+        "/api-keys/{keyUUID}/rotate": {
             "post": {
-                "description": "Создает новую товарную позицию (SKU), связывая книгу с магазином, ценой и остатком.",
+                "description": "Выпускает новый ключ с теми же названием, scopes и магазинами. Старый ключ действует ещё grace_period_seconds\n(но не дольше своего срока), без него отзывается сразу. Тело запроса необязательно. Доступно только администратору.",
                 "consumes": [
                     "application/json"
                 ],
@@ -239,25 +215,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "skus"
+                    "api-keys"
                 ],
-                "summary": "Создать SKU",
+                "summary": "Перевыпустить API-ключ",
                 "parameters": [
                     {
-                        "description": "Данные для создания SKU",
+                        "type": "string",
+                        "description": "UUID ключа",
+                        "name": "keyUUID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Период перехода и срок действия нового ключа",
                         "name": "input",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/inventory.CreateSKURequest"
+                            "$ref": "#/definitions/apikeys.RotateAPIKeyRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "SKU успешно создан",
+                        "description": "Новый ключ",
                         "schema": {
-                            "$ref": "#/definitions/inventory.SKUResponse"
+                            "$ref": "#/definitions/apikeys.CreatedAPIKeyResponse"
                         }
                     },
                     "400": {
@@ -267,13 +249,13 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Книга или магазин не найдены",
+                        "description": "Ключ не найден",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "409": {
-                        "description": "SKU для этой книги в этом магазине уже существует",
+                        "description": "Ключ отозван или истёк",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -287,30 +269,83 @@ const docTemplate = `{
                 }
             }
         },
-        "/skus/{skuUUID}": {
+        "/audit": {
             "get": {
-                "description": "Возвращает детальную информацию о SKU (включая данные о книге) по его UUID.",
+                "description": "Возвращает страницу событий аудита: кто, когда и в каком запросе изменил магазин, книгу, SKU или цену.\nВ before и after - только изменившиеся поля. Доступно только администратору.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "skus"
+                    "audit"
                 ],
-                "summary": "Получить SKU",
+                "summary": "Журнал изменений",
                 "parameters": [
                     {
+                        "enum": [
+                            "store",
+                            "book",
+                            "sku",
+                            "sku_price"
+                        ],
                         "type": "string",
-                        "description": "UUID товарной позиции (SKU)",
-                        "name": "skuUUID",
-                        "in": "path",
-                        "required": true
+                        "description": "Тип сущности",
+                        "name": "entity_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID или UUID сущности",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC3339, включительно)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (RFC3339, не включительно)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Направление сортировки по времени",
+                        "name": "order",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Информация о SKU и связанной книге",
+                        "description": "Страница событий",
                         "schema": {
-                            "$ref": "#/definitions/inventory.SKUWithBookResponse"
+                            "$ref": "#/definitions/audit.EventListResponse"
                         }
                     },
                     "400": {
@@ -319,12 +354,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "SKU не найден",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -334,42 +363,53 @@ const docTemplate = `{
                 }
             }
         },
-        "/skus/{skuUUID}/price": {
-            "put": {
-                "description": "Устанавливает новую цену для существующей товарной позиции (SKU).",
-                "consumes": [
-                    "application/json"
-                ],
+        "/authors": {
+            "get": {
+                "description": "Возвращает страницу авторов, отсортированных по имени. Для следующей страницы передайте next_cursor из ответа.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "skus"
+                    "authors"
                 ],
-                "summary": "Обновить цену SKU",
+                "summary": "Получить список авторов",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "UUID товарной позиции (SKU)",
-                        "name": "skuUUID",
-                        "in": "path",
-                        "required": true
+                        "description": "Фильтр по имени (подстрока)",
+                        "name": "name",
+                        "in": "query"
                     },
                     {
-                        "description": "Новая цена",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/inventory.UpdateSKUPriceRequest"
-                        }
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Направление сортировки",
+                        "name": "order",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Обновленный SKU",
+                        "description": "Страница авторов",
                         "schema": {
-                            "$ref": "#/definitions/inventory.SKUResponse"
+                            "$ref": "#/definitions/authors.AuthorListResponse"
                         }
                     },
                     "400": {
@@ -378,12 +418,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "SKU не найден",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -391,11 +425,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/skus/{skuUUID}/stock-adjustments": {
+            },
             "post": {
-                "description": "Увеличивает или уменьшает количество товара на складе. Для уменьшения используйте отрицательное значение.",
+                "description": "Добавляет автора в справочник. Авторы связываются с книгами через поле authors книги.",
                 "consumes": [
                     "application/json"
                 ],
@@ -403,32 +435,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "skus"
+                    "authors"
                 ],
-                "summary": "Скорректировать остатки",
+                "summary": "Создать автора",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "UUID товарной позиции (SKU)",
-                        "name": "skuUUID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Количество для изменения",
+                        "description": "Данные автора",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/inventory.AdjustSKUStockRequest"
+                            "$ref": "#/definitions/authors.CreateAuthorRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Обновленный SKU",
+                    "201": {
+                        "description": "Автор создан",
                         "schema": {
-                            "$ref": "#/definitions/inventory.SKUResponse"
+                            "$ref": "#/definitions/authors.AuthorResponse"
                         }
                     },
                     "400": {
@@ -437,20 +462,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "SKU не найден",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Недостаточно товара для списания",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
                     "500": {
-                        "description": "Internal error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -458,24 +471,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/stores": {
+        "/authors/{authorID}": {
             "get": {
-                "description": "Возвращает список всех действующих магазинов.",
+                "description": "Возвращает автора по его ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "stores"
+                    "authors"
+                ],
+                "summary": "Получить автора",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID автора",
+                        "name": "authorID",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Получить список магазинов",
                 "responses": {
                     "200": {
-                        "description": "Список действующих магазинов",
+                        "description": "Инфо об авторе",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/stores.StoreResponse"
-                            }
+                            "$ref": "#/definitions/authors.AuthorResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Автор отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
@@ -486,8 +517,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Создаёт новую запись о магазине в базе данных.",
+            "put": {
+                "description": "Заменяет данные автора. Новое имя сразу попадает в строку author всех его книг.",
                 "consumes": [
                     "application/json"
                 ],
@@ -495,25 +526,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "stores"
+                    "authors"
                 ],
-                "summary": "Создать новый магазин",
+                "summary": "Обновить автора",
                 "parameters": [
                     {
-                        "description": "Данные для создания магазина",
+                        "type": "integer",
+                        "description": "ID автора",
+                        "name": "authorID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые данные автора",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/stores.CreateStoreRequest"
+                            "$ref": "#/definitions/authors.UpdateAuthorRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Магазин успешно создан",
+                    "200": {
+                        "description": "Обновлённый автор",
                         "schema": {
-                            "$ref": "#/definitions/stores.StoreResponse"
+                            "$ref": "#/definitions/authors.AuthorResponse"
                         }
                     },
                     "400": {
@@ -522,39 +560,125 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Автор отсутствует",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
-                    }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет автора, если он не связан ни с одной книгой.",
+                "tags": [
+                    "authors"
+                ],
+                "summary": "Удалить автора",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID автора",
+                        "name": "authorID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Автор удалён"
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Автор отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "У автора есть книги",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "/stores/{storeUUID}": {
+        "/authors/{authorID}/books": {
             "get": {
-                "description": "Возвращает детальную информацию о магазине по его UUID.",
+                "description": "Возвращает страницу книг автора, отсортированных по названию. role оставляет только книги, где автор выступает в этой роли.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "stores"
+                    "authors"
                 ],
-                "summary": "Получить информацию об одном магазине",
+                "summary": "Книги автора",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "UUID магазина",
-                        "name": "storeUUID",
+                        "type": "integer",
+                        "description": "ID автора",
+                        "name": "authorID",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "author",
+                            "translator",
+                            "illustrator"
+                        ],
+                        "type": "string",
+                        "description": "Роль автора в книге",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Направление сортировки",
+                        "name": "order",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Инфо о найденном магазине",
+                        "description": "Страница книг автора",
                         "schema": {
-                            "$ref": "#/definitions/stores.StoreResponse"
+                            "$ref": "#/definitions/books.BookListResponse"
                         }
                     },
                     "400": {
@@ -564,7 +688,133 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Искомый магазин отсутствует",
+                        "description": "Автор отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books": {
+            "get": {
+                "description": "Возвращает страницу книг из глобального каталога. Пагинация курсорная: для следующей страницы передайте next_cursor из ответа.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Получить глобальный список книг",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "title",
+                            "author",
+                            "publication_year",
+                            "series_volume",
+                            "created_at"
+                        ],
+                        "type": "string",
+                        "default": "title",
+                        "description": "Поле сортировки",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Направление сортировки",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по автору (подстрока)",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Год издания от",
+                        "name": "year_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Год издания до",
+                        "name": "year_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Жанр (включая поджанры)",
+                        "name": "genre_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тег",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Серия",
+                        "name": "series_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Десятилетие издания, например 1990",
+                        "name": "decade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Есть ли в наличии хотя бы в одном магазине",
+                        "name": "in_stock",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть фасеты по жанрам, авторам, десятилетиям и наличию",
+                        "name": "facets",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница книг",
+                        "schema": {
+                            "$ref": "#/definitions/books.BookListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -577,8 +827,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Обнавляет запись о магазине в базе данных.",
+            "post": {
+                "description": "Создаёт новую книгу в глобальном каталоге. Если книга с таким ISBN уже существует, возвращает 409 и её данные.\nС upsert=true существующая книга обновляется переданными полями (200).",
                 "consumes": [
                     "application/json"
                 ],
@@ -586,36 +836,94 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "stores"
+                    "books"
                 ],
-                "summary": "Обновить информацию о магазине",
+                "summary": "Создать новую книгу",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "UUID магазина",
-                        "name": "storeUUID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Данные для обновления информации о магазине",
+                        "description": "Данные для создания книги",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/stores.UpdateStoreRequest"
+                            "$ref": "#/definitions/books.CreateBookRequest"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Обновить книгу, если ISBN уже есть в каталоге",
+                        "name": "upsert",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Обновлённое инфо об обновлённом магазине",
+                        "description": "Книга обновлена (upsert)",
                         "schema": {
-                            "$ref": "#/definitions/stores.StoreResponse"
+                            "$ref": "#/definitions/books.BookResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Инфо об книге",
+                        "schema": {
+                            "$ref": "#/definitions/books.BookResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request error",
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Книга с таким ISBN уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/books.BookConflictResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/isbn/{isbn}": {
+            "get": {
+                "description": "Возвращает книгу по ISBN-10 или ISBN-13, дефисы и пробелы допускаются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Найти книгу по ISBN",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ISBN книги",
+                        "name": "isbn",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Инфо о книге",
+                        "schema": {
+                            "$ref": "#/definitions/books.BookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ISBN",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Книга отсутствует",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -628,24 +936,48 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Выполняет мягкое удаление магазина",
+            "put": {
+                "description": "Идемпотентная синхронизация каталога: создаёт книгу с этим ISBN (201) или полностью заменяет её данные (200).\nУдалённая книга с этим ISBN восстанавливается.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "stores"
+                    "books"
                 ],
-                "summary": "Удалить магазин из доступных",
+                "summary": "Создать или заменить книгу по ISBN",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "UUID магазина",
-                        "name": "storeUUID",
+                        "description": "ISBN книги",
+                        "name": "isbn",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Данные книги",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/books.UpsertBookRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Магазин удалён (деактивирован)"
+                    "200": {
+                        "description": "Книга обновлена",
+                        "schema": {
+                            "$ref": "#/definitions/books.BookResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Книга создана",
+                        "schema": {
+                            "$ref": "#/definitions/books.BookResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad request error",
@@ -661,135 +993,5403 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "books.AvailabilityResponse": {
-            "type": "object",
-            "properties": {
-                "price_in_kopeks": {
-                    "type": "integer"
-                },
-                "sku_uuid": {
-                    "type": "string"
-                },
-                "stock_count": {
+        },
+        "/books/search": {
+            "get": {
+                "description": "Полнотекстовый поиск по названию, автору, описанию и ISBN (русская и английская морфология) с нечётким совпадением для опечаток. Результаты отсортированы по релевантности.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Поиск книг",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по автору (подстрока)",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Год издания от",
+                        "name": "year_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Год издания до",
+                        "name": "year_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Жанр (включая поджанры)",
+                        "name": "genre_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тег",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Серия",
+                        "name": "series_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Десятилетие издания, например 1990",
+                        "name": "decade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Есть ли в наличии хотя бы в одном магазине",
+                        "name": "in_stock",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть фасеты по жанрам, авторам, десятилетиям и наличию",
+                        "name": "facets",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Найденные книги с релевантностью и фрагментами",
+                        "schema": {
+                            "$ref": "#/definitions/books.SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{bookID}": {
+            "get": {
+                "description": "Возвращает информацию о книге по её ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Получить инфо об одной книге",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID книги",
+                        "name": "bookID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Инфо о книге",
+                        "schema": {
+                            "$ref": "#/definitions/books.BookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Книга отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Полностью заменяет данные книги. Не переданные необязательные поля очищаются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Обновить книгу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID книги",
+                        "name": "bookID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые данные книги",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/books.UpdateBookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновлённая книга",
+                        "schema": {
+                            "$ref": "#/definitions/books.BookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Книга отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "ISBN занят другой книгой",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Мягко удаляет книгу: она пропадает из каталога, поиска и доступности. С cascade=true удаляются и её SKU.",
+                "tags": [
+                    "books"
+                ],
+                "summary": "Удалить книгу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID книги",
+                        "name": "bookID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Удалить также SKU книги во всех магазинах",
+                        "name": "cascade",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Книга удалена"
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Книга отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Часть запаса книги зарезервирована или в пути (cascade=true)",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Применяет JSON Merge Patch (RFC 7386) к книге: переданные поля заменяются, null очищает поле.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Частично обновить книгу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID книги",
+                        "name": "bookID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/books.UpdateBookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновлённая книга",
+                        "schema": {
+                            "$ref": "#/definitions/books.BookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Книга отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "ISBN занят другой книгой",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Неподдерживаемый Content-Type",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{bookID}/availability": {
+            "get": {
+                "description": "Показывает, в каких магазинах, по какой цене и в каком количестве доступна книга.\neffective_price_in_kopeks - цена одного экземпляра с учётом действующих акций, в минорных единицах валюты магазина.\nС параметром currency цены дополнительно пересчитываются в эту валюту по сохранённым курсам (поле converted).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Доступность книги",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID книги",
+                        "name": "bookID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Валюта для пересчёта цен (ISO 4217)",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/books.AvailabilityResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error или нет курса для пересчёта",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Книга отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/books/{bookID}/restore": {
+            "post": {
+                "description": "Отменяет мягкое удаление книги. SKU, удалённые вместе с ней (cascade), тоже восстанавливаются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "books"
+                ],
+                "summary": "Восстановить книгу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID книги",
+                        "name": "bookID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Восстановленная книга",
+                        "schema": {
+                            "$ref": "#/definitions/books.BookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Книга отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Книга не удалена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange-rates": {
+            "get": {
+                "description": "Возвращает все сохранённые курсы. Курс пары показывает, сколько единиц quote_currency стоит одна единица base_currency.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange-rates"
+                ],
+                "summary": "Получить курсы валют",
+                "responses": {
+                    "200": {
+                        "description": "Курсы валют",
+                        "schema": {
+                            "$ref": "#/definitions/rates.ExchangeRateListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange-rates/{base}/{quote}": {
+            "put": {
+                "description": "Создаёт или заменяет курс пары. Обратный курс, если он не задан отдельно, считается как 1/rate.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange-rates"
+                ],
+                "summary": "Задать курс валюты",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Базовая валюта (ISO 4217)",
+                        "name": "base",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Котируемая валюта (ISO 4217)",
+                        "name": "quote",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Курс",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rates.SetExchangeRateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Курс сохранён",
+                        "schema": {
+                            "$ref": "#/definitions/rates.ExchangeRateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет курс пары. Обратный курс, если он задан, остаётся.",
+                "tags": [
+                    "exchange-rates"
+                ],
+                "summary": "Удалить курс валюты",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Базовая валюта (ISO 4217)",
+                        "name": "base",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Котируемая валюта (ISO 4217)",
+                        "name": "quote",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Курс удалён"
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Курс не задан",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exports/books": {
+            "get": {
+                "description": "Потоково выгружает все неудалённые книги с издательством и серией.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Выгрузить каталог",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Формат файла",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файл выгрузки",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Уже идёт максимум выгрузок",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exports/inventory": {
+            "get": {
+                "description": "Потоково выгружает SKU магазина (или всех магазинов) с данными книг: цена, остаток, резерв и доступное количество.\nВсе страницы читаются в одной транзакции, так что выгрузка - согласованный снимок.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Выгрузить остатки и цены",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID магазина, без него - все магазины",
+                        "name": "store_uuid",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Формат файла",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файл выгрузки",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Магазин не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Уже идёт максимум выгрузок",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres": {
+            "get": {
+                "description": "Возвращает все жанры деревом: поджанры вложены в children, на каждом уровне сортировка по названию.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Дерево жанров",
+                "responses": {
+                    "200": {
+                        "description": "Жанры верхнего уровня с поджанрами",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/genres.GenreResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Добавляет жанр в дерево жанров. С parent_id жанр становится поджанром.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Создать жанр",
+                "parameters": [
+                    {
+                        "description": "Данные жанра",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genres.CreateGenreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Жанр создан",
+                        "schema": {
+                            "$ref": "#/definitions/genres.GenreResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "У родителя уже есть жанр с таким названием",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres/{genreID}": {
+            "get": {
+                "description": "Возвращает жанр по его ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Получить жанр",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID жанра",
+                        "name": "genreID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Инфо о жанре",
+                        "schema": {
+                            "$ref": "#/definitions/genres.GenreResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Жанр отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Переименовывает жанр или переносит его вместе с поджанрами. Без parent_id жанр становится жанром верхнего уровня.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Обновить жанр",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID жанра",
+                        "name": "genreID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые данные жанра",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/genres.UpdateGenreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновлённый жанр",
+                        "schema": {
+                            "$ref": "#/definitions/genres.GenreResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Жанр отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Конфликт названия или перенос в собственный поджанр",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет жанр без поджанров, к которому не отнесена ни одна книга.",
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Удалить жанр",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID жанра",
+                        "name": "genreID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Жанр удалён"
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Жанр отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "У жанра есть поджанры или книги",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/imports": {
+            "post": {
+                "description": "Принимает CSV или NDJSON с книгами и, по желанию, ценой и остатком в магазине (isbn, title, author, description, page_count, publication_year, store_uuid, price_in_kopeks, stock_count).\nФайл передаётся телом запроса или полем file в multipart/form-data. Строки обрабатываются в фоне пачками, статус - GET /imports/{importUUID}.",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Загрузить файл импорта",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Формат файла, если его не видно по Content-Type или имени",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только проверить строки, ничего не записывая",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Обновлять уже существующие книги",
+                        "name": "upsert",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Файл импорта",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Импорт поставлен в очередь",
+                        "schema": {
+                            "$ref": "#/definitions/imports.ImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/imports/{importUUID}": {
+            "get": {
+                "description": "Возвращает статус импорта и счётчики обработанных строк.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Статус импорта",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID импорта",
+                        "name": "importUUID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Импорт",
+                        "schema": {
+                            "$ref": "#/definitions/imports.ImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Импорт не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/imports/{importUUID}/errors": {
+            "get": {
+                "description": "CSV со строками файла, которые не удалось импортировать: номер строки, ISBN и причина.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Отчёт об ошибках импорта",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID импорта",
+                        "name": "importUUID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV с колонками line, isbn, message",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Импорт не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "get": {
+                "description": "Возвращает страницу заказов, отсортированных по времени создания. Позиции не включаются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Список заказов",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "paid",
+                            "fulfilled",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Фильтр по статусу",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Направление сортировки по времени",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница заказов",
+                        "schema": {
+                            "$ref": "#/definitions/orders.OrderListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает заказ из позиций (SKU и количество). Товар резервируется в одной транзакции,\nсумма считается по текущим ценам SKU. Повторяющиеся SKU объединяются.\nВсе SKU заказа должны быть в одной валюте, она же становится валютой заказа.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Создать заказ",
+                "parameters": [
+                    {
+                        "description": "Данные заказа",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/orders.CreateOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Заказ создан",
+                        "schema": {
+                            "$ref": "#/definitions/orders.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error или SKU в разных валютах",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "SKU не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Недостаточно товара",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{orderUUID}": {
+            "get": {
+                "description": "Возвращает заказ вместе с позициями.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Получить заказ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID заказа",
+                        "name": "orderUUID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Заказ",
+                        "schema": {
+                            "$ref": "#/definitions/orders.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Заказ не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{orderUUID}/cancel": {
+            "post": {
+                "description": "Отменяет заказ в статусе pending или paid: снимает резерв или возвращает проданный товар на склад.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Отменить заказ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID заказа",
+                        "name": "orderUUID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный заказ",
+                        "schema": {
+                            "$ref": "#/definitions/orders.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Заказ не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Переход из текущего статуса невозможен",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{orderUUID}/fulfill": {
+            "post": {
+                "description": "Переводит оплаченный заказ в статус fulfilled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Выполнить заказ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID заказа",
+                        "name": "orderUUID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный заказ",
+                        "schema": {
+                            "$ref": "#/definitions/orders.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Заказ не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Переход из текущего статуса невозможен",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{orderUUID}/pay": {
+            "post": {
+                "description": "Переводит заказ из статуса pending в paid и списывает зарезервированный товар со склада.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Оплатить заказ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID заказа",
+                        "name": "orderUUID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный заказ",
+                        "schema": {
+                            "$ref": "#/definitions/orders.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Заказ не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Переход из текущего статуса невозможен",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotions": {
+            "get": {
+                "description": "Возвращает страницу акций, отсортированных по ID. Для следующей страницы передайте next_cursor из ответа.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Получить список акций",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Только акции, действующие в этот момент (RFC3339)",
+                        "name": "active_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Только акции, ограниченные этим магазином",
+                        "name": "store_uuid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Направление сортировки",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница акций",
+                        "schema": {
+                            "$ref": "#/definitions/promotions.PromotionListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Магазин не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создаёт скидку в процентах (percent), фиксированную скидку в копейках (fixed) или акцию «купи X - получи Y бесплатно» (buy_x_get_y).\nАкция действует в окне starts_at - ends_at и на SKU, подходящие под все заданные области: магазин, книгу, автора, жанр (с поджанрами).\nФиксированная скидка задаётся в минорных единицах currency (по умолчанию - валюта магазина акции или RUB) и действует только на SKU в этой валюте.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Создать акцию",
+                "parameters": [
+                    {
+                        "description": "Данные акции",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/promotions.PromotionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Акция создана",
+                        "schema": {
+                            "$ref": "#/definitions/promotions.PromotionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Магазин, книга, автор или жанр не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotions/{promotionID}": {
+            "get": {
+                "description": "Возвращает акцию по её ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Получить акцию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID акции",
+                        "name": "promotionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Инфо об акции",
+                        "schema": {
+                            "$ref": "#/definitions/promotions.PromotionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Акция отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Полностью заменяет акцию. Не переданные необязательные поля очищаются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Обновить акцию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID акции",
+                        "name": "promotionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые данные акции",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/promotions.PromotionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновлённая акция",
+                        "schema": {
+                            "$ref": "#/definitions/promotions.PromotionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Акция, магазин, книга, автор или жанр не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет акцию. Цены SKU сразу пересчитываются без неё.",
+                "tags": [
+                    "promotions"
+                ],
+                "summary": "Удалить акцию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID акции",
+                        "name": "promotionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Акция удалена"
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Акция отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/publishers": {
+            "get": {
+                "description": "Возвращает страницу издательств, отсортированных по названию. Для следующей страницы передайте next_cursor из ответа.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "publishers"
+                ],
+                "summary": "Получить список издательств",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Фильтр по названию (подстрока)",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Направление сортировки",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница издательств",
+                        "schema": {
+                            "$ref": "#/definitions/publishers.PublisherListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Добавляет издательство в справочник. Книга ссылается на него полем publisher_id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "publishers"
+                ],
+                "summary": "Создать издательство",
+                "parameters": [
+                    {
+                        "description": "Данные издательства",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/publishers.CreatePublisherRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Издательство создано",
+                        "schema": {
+                            "$ref": "#/definitions/publishers.PublisherResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Издательство с таким названием уже есть",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/publishers/{publisherID}": {
+            "get": {
+                "description": "Возвращает издательство по его ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "publishers"
+                ],
+                "summary": "Получить издательство",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID издательства",
+                        "name": "publisherID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Инфо об издательстве",
+                        "schema": {
+                            "$ref": "#/definitions/publishers.PublisherResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Издательство отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Заменяет данные издательства. Не переданный website очищается.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "publishers"
+                ],
+                "summary": "Обновить издательство",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID издательства",
+                        "name": "publisherID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые данные издательства",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/publishers.UpdatePublisherRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновлённое издательство",
+                        "schema": {
+                            "$ref": "#/definitions/publishers.PublisherResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Издательство отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Издательство с таким названием уже есть",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет издательство, если на него не ссылается ни одна книга, включая удалённые.",
+                "tags": [
+                    "publishers"
+                ],
+                "summary": "Удалить издательство",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID издательства",
+                        "name": "publisherID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Издательство удалено"
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Издательство отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "У издательства есть книги",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{reservationUUID}": {
+            "get": {
+                "description": "Возвращает резерв и его статус.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Получить резерв",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID резерва",
+                        "name": "reservationUUID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Резерв",
+                        "schema": {
+                            "$ref": "#/definitions/reservations.ReservationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Резерв не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{reservationUUID}/confirm": {
+            "post": {
+                "description": "Списывает зарезервированный товар как продажу (sale).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Подтвердить резерв",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID резерва",
+                        "name": "reservationUUID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подтверждённый резерв",
+                        "schema": {
+                            "$ref": "#/definitions/reservations.ReservationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Резерв не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Резерв уже не активен или истёк",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{reservationUUID}/release": {
+            "post": {
+                "description": "Возвращает зарезервированный товар в доступный остаток.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Снять резерв",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID резерва",
+                        "name": "reservationUUID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Снятый резерв",
+                        "schema": {
+                            "$ref": "#/definitions/reservations.ReservationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Резерв не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Резерв уже не активен",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series": {
+            "get": {
+                "description": "Возвращает страницу серий, отсортированных по названию. Книги серии по порядку томов: GET /books?series_id=\u0026sort=series_volume.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Получить список серий",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Фильтр по названию (подстрока)",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Направление сортировки",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница серий",
+                        "schema": {
+                            "$ref": "#/definitions/series.SeriesListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Добавляет книжную серию. Книга входит в серию через поля series_id и series_volume.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Создать серию",
+                "parameters": [
+                    {
+                        "description": "Данные серии",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/series.CreateSeriesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Серия создана",
+                        "schema": {
+                            "$ref": "#/definitions/series.SeriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{seriesID}": {
+            "get": {
+                "description": "Возвращает серию по её ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Получить серию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID серии",
+                        "name": "seriesID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Инфо о серии",
+                        "schema": {
+                            "$ref": "#/definitions/series.SeriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Серия отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Заменяет данные серии. Не переданное описание очищается.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Обновить серию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID серии",
+                        "name": "seriesID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые данные серии",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/series.UpdateSeriesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновлённая серия",
+                        "schema": {
+                            "$ref": "#/definitions/series.SeriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Серия отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет серию, если в неё не входит ни одна книга, включая удалённые.",
+                "tags": [
+                    "series"
+                ],
+                "summary": "Удалить серию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID серии",
+                        "name": "seriesID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Серия удалена"
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Серия отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "В серии есть книги",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus": {
+            "post": {
+                "description": "Создает новую товарную позицию (SKU), связывая книгу с магазином, ценой и остатком.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skus"
+                ],
+                "summary": "Создать SKU",
+                "parameters": [
+                    {
+                        "description": "Данные для создания SKU",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/inventory.CreateSKURequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "SKU успешно создан",
+                        "schema": {
+                            "$ref": "#/definitions/inventory.SKUResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Книга или магазин не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "SKU для этой книги в этом магазине уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus/{skuUUID}": {
+            "get": {
+                "description": "Возвращает детальную информацию о SKU (включая данные о книге) по его UUID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skus"
+                ],
+                "summary": "Получить SKU",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID товарной позиции (SKU)",
+                        "name": "skuUUID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о SKU и связанной книге",
+                        "schema": {
+                            "$ref": "#/definitions/inventory.SKUWithBookResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия SKU для If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "SKU не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus/{skuUUID}/effective-price": {
+            "get": {
+                "description": "Возвращает цену quantity экземпляров SKU после действующих сейчас акций и список применённых акций со скидкой каждой.\nНесуммируемая акция конкурирует с остальными одна; все суммируемые объединяются: сначала проценты, затем фиксированные скидки,\nзатем лучшая из акций «купи X - получи Y». Побеждает вариант с наименьшей суммой, при равенстве - с акцией выше по приоритету (затем по меньшему ID).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skus"
+                ],
+                "summary": "Цена SKU с учётом акций",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID товарной позиции (SKU)",
+                        "name": "skuUUID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Количество экземпляров",
+                        "name": "quantity",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Цена с учётом акций",
+                        "schema": {
+                            "$ref": "#/definitions/promotions.EffectivePriceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "SKU не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus/{skuUUID}/movements": {
+            "get": {
+                "description": "Возвращает историю изменений остатка SKU: изменение, итоговый остаток, причину, автора и ID запроса.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skus"
+                ],
+                "summary": "Журнал движений SKU",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID товарной позиции (SKU)",
+                        "name": "skuUUID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC3339, включительно)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (RFC3339, не включительно)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Направление сортировки по времени",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница движений",
+                        "schema": {
+                            "$ref": "#/definitions/inventory.StockMovementListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "SKU не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus/{skuUUID}/price": {
+            "put": {
+                "description": "Устанавливает новую цену для существующей товарной позиции (SKU). Каждая цена записывается в историю цен.\nЕсли указан effective_at в будущем, цена планируется и применяется планировщиком в указанное время.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skus"
+                ],
+                "summary": "Обновить цену SKU",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID товарной позиции (SKU)",
+                        "name": "skuUUID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новая цена",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/inventory.UpdateSKUPriceRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag SKU, полученный ранее",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный SKU",
+                        "schema": {
+                            "$ref": "#/definitions/inventory.SKUResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Запланированная цена",
+                        "schema": {
+                            "$ref": "#/definitions/inventory.SKUPriceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "SKU не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "На это время уже запланирована другая цена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "SKU изменён другим запросом",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus/{skuUUID}/price-history": {
+            "get": {
+                "description": "Возвращает все цены SKU: действующую, прежние с периодом действия, запланированные и отменённые, с автором и ID запроса.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skus"
+                ],
+                "summary": "История цен SKU",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID товарной позиции (SKU)",
+                        "name": "skuUUID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "scheduled",
+                            "active",
+                            "superseded",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Фильтр по статусу",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Направление сортировки по времени создания",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница истории цен",
+                        "schema": {
+                            "$ref": "#/definitions/inventory.SKUPriceListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "SKU не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus/{skuUUID}/price-history/{priceUUID}/cancel": {
+            "post": {
+                "description": "Отменяет цену, которая ещё не вступила в силу. Запись остаётся в истории со статусом cancelled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skus"
+                ],
+                "summary": "Отменить запланированную цену",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID товарной позиции (SKU)",
+                        "name": "skuUUID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "UUID цены из истории",
+                        "name": "priceUUID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отменённая цена",
+                        "schema": {
+                            "$ref": "#/definitions/inventory.SKUPriceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "SKU или цена не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Цена уже применена или отменена",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus/{skuUUID}/reservations": {
+            "post": {
+                "description": "Резервирует экземпляры SKU на время (ttl_seconds, по умолчанию 30 минут). Зарезервированный товар\nостаётся в stock_count, но не входит в available, пока резерв не подтвердят, не снимут или он не истечёт.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Зарезервировать товар",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID товарной позиции (SKU)",
+                        "name": "skuUUID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Количество и время резерва",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/reservations.CreateReservationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Резерв создан",
+                        "schema": {
+                            "$ref": "#/definitions/reservations.ReservationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "SKU не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Недостаточно доступного товара",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/skus/{skuUUID}/stock-adjustments": {
+            "post": {
+                "description": "Увеличивает или уменьшает количество товара на складе. Для уменьшения используйте отрицательное значение.\nКаждая корректировка записывается в журнал движений с причиной (по умолчанию correction).\nreceipt и return только увеличивают остаток, sale и write_off только уменьшают, correction - в любую сторону.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "skus"
+                ],
+                "summary": "Скорректировать остатки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID товарной позиции (SKU)",
+                        "name": "skuUUID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Количество для изменения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/inventory.AdjustSKUStockRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag SKU, полученный ранее",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный SKU",
+                        "schema": {
+                            "$ref": "#/definitions/inventory.SKUResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "SKU не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Недостаточно товара для списания",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "SKU изменён другим запросом",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stores": {
+            "get": {
+                "description": "Возвращает страницу действующих магазинов. Для следующей страницы передайте next_cursor из ответа.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stores"
+                ],
+                "summary": "Получить список магазинов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "name",
+                            "created_at"
+                        ],
+                        "type": "string",
+                        "default": "name",
+                        "description": "Поле сортировки",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Направление сортировки",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница действующих магазинов",
+                        "schema": {
+                            "$ref": "#/definitions/stores.StoreListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создаёт новую запись о магазине в базе данных.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stores"
+                ],
+                "summary": "Создать новый магазин",
+                "parameters": [
+                    {
+                        "description": "Данные для создания магазина",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/stores.CreateStoreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Магазин успешно создан",
+                        "schema": {
+                            "$ref": "#/definitions/stores.StoreResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stores/{storeUUID}": {
+            "get": {
+                "description": "Возвращает детальную информацию о магазине по его UUID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stores"
+                ],
+                "summary": "Получить информацию об одном магазине",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID магазина",
+                        "name": "storeUUID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Инфо о найденном магазине",
+                        "schema": {
+                            "$ref": "#/definitions/stores.StoreResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Искомый магазин отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Обнавляет запись о магазине в базе данных.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stores"
+                ],
+                "summary": "Обновить информацию о магазине",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID магазина",
+                        "name": "storeUUID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные для обновления информации о магазине",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/stores.UpdateStoreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновлённое инфо об обновлённом магазине",
+                        "schema": {
+                            "$ref": "#/definitions/stores.StoreResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Искомый магазин отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Валюту нельзя сменить, пока у магазина есть SKU",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Выполняет мягкое удаление магазина",
+                "tags": [
+                    "stores"
+                ],
+                "summary": "Удалить магазин из доступных",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID магазина",
+                        "name": "storeUUID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Магазин удалён (деактивирован)"
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stores/{storeUUID}/skus": {
+            "get": {
+                "description": "Возвращает страницу SKU магазина вместе с данными о книгах.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stores"
+                ],
+                "summary": "Ассортимент магазина",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID магазина",
+                        "name": "storeUUID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только товары в наличии",
+                        "name": "in_stock",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальная цена в копейках",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальная цена в копейках",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по названию книги (подстрока)",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "title",
+                            "price",
+                            "stock"
+                        ],
+                        "type": "string",
+                        "default": "title",
+                        "description": "Поле сортировки",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Направление сортировки",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница SKU магазина",
+                        "schema": {
+                            "$ref": "#/definitions/inventory.SKUListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Магазин не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "Возвращает страницу тегов, отсортированных по названию, с числом книг у каждого. Для следующей страницы передайте next_cursor из ответа.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Получить список тегов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Начало названия (для автодополнения)",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Направление сортировки",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница тегов",
+                        "schema": {
+                            "$ref": "#/definitions/tags.TagListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Добавляет тег. Название приводится к нижнему регистру, пробелы по краям отбрасываются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Создать тег",
+                "parameters": [
+                    {
+                        "description": "Данные тега",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tags.CreateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Тег создан",
+                        "schema": {
+                            "$ref": "#/definitions/tags.TagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Тег с таким названием уже есть",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{tagID}": {
+            "get": {
+                "description": "Возвращает тег по его ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Получить тег",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID тега",
+                        "name": "tagID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Инфо о теге",
+                        "schema": {
+                            "$ref": "#/definitions/tags.TagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Тег отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Меняет название тега, книги остаются с ним связаны.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Переименовать тег",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID тега",
+                        "name": "tagID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новое название тега",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tags.UpdateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновлённый тег",
+                        "schema": {
+                            "$ref": "#/definitions/tags.TagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Тег отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Тег с таким названием уже есть",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет тег и снимает его со всех книг.",
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Удалить тег",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID тега",
+                        "name": "tagID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Тег удалён"
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Тег отсутствует",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers": {
+            "get": {
+                "description": "Возвращает страницу перемещений, отсортированных по времени создания.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Список перемещений",
+                "parameters": [
+                    {
+                        "enum": [
+                            "dispatched",
+                            "received",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Фильтр по статусу",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Магазин-отправитель или получатель",
+                        "name": "store_uuid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Направление сортировки по времени",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница перемещений",
+                        "schema": {
+                            "$ref": "#/definitions/transfers.TransferListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Магазин не найден",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Атомарно списывает экземпляры книги в магазине-отправителе и переводит их в пути (dispatched).\nЕсли в магазине-получателе нет SKU этой книги, он создаётся с ценой отправителя и нулевым остатком;\nесли магазины в разных валютах, SKU получателя нужно создать заранее.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Отправить товар в другой магазин",
+                "parameters": [
+                    {
+                        "description": "Книга, магазины и количество",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/transfers.CreateTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Перемещение создано",
+                        "schema": {
+                            "$ref": "#/definitions/transfers.TransferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Книга, магазин или SKU отправителя не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Недостаточно товара или у получателя нет SKU, а магазин в другой валюте",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/{transferUUID}": {
+            "get": {
+                "description": "Возвращает перемещение и его статус.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Получить перемещение",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID перемещения",
+                        "name": "transferUUID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Перемещение",
+                        "schema": {
+                            "$ref": "#/definitions/transfers.TransferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Перемещение не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/{transferUUID}/cancel": {
+            "post": {
+                "description": "Возвращает товар в пути на склад магазина-отправителя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Отменить перемещение",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID перемещения",
+                        "name": "transferUUID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отменённое перемещение",
+                        "schema": {
+                            "$ref": "#/definitions/transfers.TransferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Перемещение не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Перемещение уже принято или отменено",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/{transferUUID}/receive": {
+            "post": {
+                "description": "Зачисляет товар в пути на склад магазина-получателя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Принять перемещение",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UUID перемещения",
+                        "name": "transferUUID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Принятое перемещение",
+                        "schema": {
+                            "$ref": "#/definitions/transfers.TransferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Перемещение не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Перемещение уже принято или отменено",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apikeys.APIKeyListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apikeys.APIKeyResponse"
+                    }
+                }
+            }
+        },
+        "apikeys.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "bsk_1a2b3c4d5e6f"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "rotated_from": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "store_uuids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "apikeys.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "inventory:write"
+                    ]
+                },
+                "store_uuids": {
+                    "description": "Stores the key works in; without them it works in every store.",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "apikeys.CreatedAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "bsk_1a2b3c4d5e6f"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "rotated_from": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "store_uuids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "apikeys.RotateAPIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "Expiry of the new key, the one of the replaced key by default.",
+                    "type": "string"
+                },
+                "grace_period_seconds": {
+                    "description": "How long the replaced key keeps working so clients can switch over; 0 revokes it at once.",
+                    "type": "integer",
+                    "maximum": 2592000,
+                    "minimum": 0
+                }
+            }
+        },
+        "audit.EventListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/audit.EventResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "audit.EventResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "after": {
+                    "description": "Changed fields after the change.",
+                    "type": "object"
+                },
+                "before": {
+                    "description": "Changed fields before the change, absent for a created entity.",
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "string"
+                },
+                "entity_type": {
+                    "type": "string",
+                    "example": "store"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "authors.AuthorListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/authors.AuthorResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "authors.AuthorResponse": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "authors.CreateAuthorRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "authors.UpdateAuthorRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "books.AvailabilityResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer"
+                },
+                "converted": {
+                    "$ref": "#/definitions/books.ConvertedPriceResponse"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "effective_price_in_kopeks": {
+                    "type": "integer"
+                },
+                "price_in_kopeks": {
+                    "type": "integer"
+                },
+                "sku_uuid": {
+                    "type": "string"
+                },
+                "stock_count": {
+                    "type": "integer"
+                },
+                "store_name": {
+                    "type": "string"
+                },
+                "store_uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "books.BookAuthorRequest": {
+            "type": "object",
+            "required": [
+                "author_id"
+            ],
+            "properties": {
+                "author_id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "author",
+                        "translator",
+                        "illustrator"
+                    ]
+                }
+            }
+        },
+        "books.BookAuthorResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "books.BookConflictResponse": {
+            "type": "object",
+            "properties": {
+                "book": {
+                    "$ref": "#/definitions/books.BookResponse"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "books.BookGenreResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "books.BookListResponse": {
+            "type": "object",
+            "properties": {
+                "facets": {
+                    "$ref": "#/definitions/books.FacetsResponse"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/books.BookResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "books.BookPublisherResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "books.BookResponse": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/books.BookAuthorResponse"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/books.BookGenreResponse"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "isbn": {
+                    "type": "string"
+                },
+                "page_count": {
+                    "type": "integer"
+                },
+                "publication_year": {
+                    "type": "integer"
+                },
+                "publisher": {
+                    "$ref": "#/definitions/books.BookPublisherResponse"
+                },
+                "series": {
+                    "$ref": "#/definitions/books.BookSeriesResponse"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "books.BookSeriesResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "volume": {
+                    "type": "integer"
+                }
+            }
+        },
+        "books.ConvertedPriceResponse": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "KZT"
+                },
+                "effective_price_in_kopeks": {
+                    "type": "integer"
+                },
+                "price_in_kopeks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "books.CreateBookRequest": {
+            "type": "object",
+            "required": [
+                "isbn",
+                "tags",
+                "title"
+            ],
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "authors": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "$ref": "#/definitions/books.BookAuthorRequest"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "genre_ids": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "isbn": {
+                    "type": "string"
+                },
+                "page_count": {
+                    "type": "integer"
+                },
+                "publication_year": {
+                    "type": "integer"
+                },
+                "publisher_id": {
+                    "type": "integer"
+                },
+                "series_id": {
+                    "type": "integer"
+                },
+                "series_volume": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 30,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "books.FacetValueResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "books.FacetsResponse": {
+            "type": "object",
+            "properties": {
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/books.FacetValueResponse"
+                    }
+                },
+                "decades": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/books.FacetValueResponse"
+                    }
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/books.FacetValueResponse"
+                    }
+                },
+                "in_stock": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/books.FacetValueResponse"
+                    }
+                }
+            }
+        },
+        "books.SearchResponse": {
+            "type": "object",
+            "properties": {
+                "facets": {
+                    "$ref": "#/definitions/books.FacetsResponse"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/books.SearchResultResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "books.SearchResultResponse": {
+            "type": "object",
+            "properties": {
+                "book": {
+                    "$ref": "#/definitions/books.BookResponse"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "snippet": {
+                    "type": "string"
+                }
+            }
+        },
+        "books.UpdateBookRequest": {
+            "type": "object",
+            "required": [
+                "tags",
+                "title"
+            ],
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "authors": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "$ref": "#/definitions/books.BookAuthorRequest"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "genre_ids": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "isbn": {
+                    "type": "string"
+                },
+                "page_count": {
+                    "type": "integer"
+                },
+                "publication_year": {
+                    "type": "integer"
+                },
+                "publisher_id": {
+                    "type": "integer"
+                },
+                "series_id": {
+                    "type": "integer"
+                },
+                "series_volume": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 30,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "books.UpsertBookRequest": {
+            "type": "object",
+            "required": [
+                "tags",
+                "title"
+            ],
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "authors": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "$ref": "#/definitions/books.BookAuthorRequest"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "genre_ids": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "page_count": {
+                    "type": "integer"
+                },
+                "publication_year": {
+                    "type": "integer"
+                },
+                "publisher_id": {
+                    "type": "integer"
+                },
+                "series_id": {
+                    "type": "integer"
+                },
+                "series_volume": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 30,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "genres.CreateGenreRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "parent_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genres.GenreResponse": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/genres.GenreResponse"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "genres.UpdateGenreRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "parent_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "imports.ImportResponse": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "books_created": {
+                    "type": "integer"
+                },
+                "books_existing": {
+                    "type": "integer"
+                },
+                "books_updated": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "failed_rows": {
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "processed_rows": {
+                    "type": "integer"
+                },
+                "skus_created": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_rows": {
+                    "type": "integer"
+                },
+                "upsert": {
+                    "type": "boolean"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "inventory.AdjustSKUStockRequest": {
+            "type": "object",
+            "required": [
+                "change_by"
+            ],
+            "properties": {
+                "change_by": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "receipt",
+                        "sale",
+                        "return",
+                        "write_off",
+                        "correction"
+                    ]
+                }
+            }
+        },
+        "inventory.CreateSKURequest": {
+            "type": "object",
+            "required": [
+                "book_id",
+                "store_uuid"
+            ],
+            "properties": {
+                "book_id": {
+                    "type": "integer"
+                },
+                "price_in_kopeks": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "stock_count": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "store_uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "inventory.SKUListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/inventory.SKUWithBookResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "inventory.SKUPriceListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/inventory.SKUPriceResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "inventory.SKUPriceResponse": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "cancelled_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "effective_to": {
+                    "type": "string"
+                },
+                "price_in_kopeks": {
+                    "type": "integer"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "scheduled_for": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "scheduled",
+                        "active",
+                        "superseded",
+                        "cancelled"
+                    ]
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "inventory.SKUResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer"
+                },
+                "book_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "price_in_kopeks": {
+                    "type": "integer"
+                },
+                "reserved": {
+                    "type": "integer"
+                },
+                "stock_count": {
+                    "type": "integer"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "inventory.SKUWithBookResponse": {
+            "type": "object",
+            "properties": {
+                "book": {
+                    "$ref": "#/definitions/books.BookResponse"
+                },
+                "sku": {
+                    "$ref": "#/definitions/inventory.SKUResponse"
+                }
+            }
+        },
+        "inventory.StockMovementListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/inventory.StockMovementResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "inventory.StockMovementResponse": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "balance": {
                     "type": "integer"
                 },
-                "store_name": {
+                "created_at": {
+                    "type": "string"
+                },
+                "delta": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "inventory.UpdateSKUPriceRequest": {
+            "type": "object",
+            "properties": {
+                "effective_at": {
+                    "type": "string"
+                },
+                "new_price_in_kopeks": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "orders.CreateOrderItemRequest": {
+            "type": "object",
+            "required": [
+                "sku_uuid"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 10000
+                },
+                "sku_uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "orders.CreateOrderRequest": {
+            "type": "object",
+            "required": [
+                "customer_name",
+                "items"
+            ],
+            "properties": {
+                "customer_email": {
+                    "type": "string"
+                },
+                "customer_name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "items": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/orders.CreateOrderItemRequest"
+                    }
+                }
+            }
+        },
+        "orders.OrderItemResponse": {
+            "type": "object",
+            "properties": {
+                "book_id": {
+                    "type": "integer"
+                },
+                "price_in_kopeks": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku_uuid": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "orders.OrderListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/orders.OrderResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "orders.OrderResponse": {
+            "type": "object",
+            "properties": {
+                "cancelled_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "customer_email": {
+                    "type": "string"
+                },
+                "customer_name": {
+                    "type": "string"
+                },
+                "fulfilled_at": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/orders.OrderItemResponse"
+                    }
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_in_kopeks": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "promotions.AppliedPromotionResponse": {
+            "type": "object",
+            "properties": {
+                "discount_in_kopeks": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "promotions.EffectivePriceResponse": {
+            "type": "object",
+            "properties": {
+                "base_price_in_kopeks": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "free_quantity": {
+                    "type": "integer"
+                },
+                "promotions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/promotions.AppliedPromotionResponse"
+                    }
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku_uuid": {
+                    "type": "string"
+                },
+                "total_in_kopeks": {
+                    "type": "integer"
+                },
+                "unit_price_in_kopeks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "promotions.PromotionListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/promotions.PromotionResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "promotions.PromotionRequest": {
+            "type": "object",
+            "required": [
+                "kind",
+                "name"
+            ],
+            "properties": {
+                "amount_off_in_kopeks": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "author_id": {
+                    "type": "integer"
+                },
+                "book_id": {
+                    "type": "integer"
+                },
+                "buy_quantity": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "free_quantity": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "genre_id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "percent",
+                        "fixed",
+                        "buy_x_get_y"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "percent_off": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "stackable": {
+                    "type": "boolean"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "store_uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "promotions.PromotionResponse": {
+            "type": "object",
+            "properties": {
+                "amount_off_in_kopeks": {
+                    "type": "integer"
+                },
+                "author_id": {
+                    "type": "integer"
+                },
+                "book_id": {
+                    "type": "integer"
+                },
+                "buy_quantity": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "free_quantity": {
+                    "type": "integer"
+                },
+                "genre_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "percent",
+                        "fixed",
+                        "buy_x_get_y"
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                },
+                "percent_off": {
+                    "type": "integer"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "stackable": {
+                    "type": "boolean"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "store_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "publishers.CreatePublisherRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
                     "type": "string"
                 },
-                "store_uuid": {
+                "website": {
                     "type": "string"
                 }
             }
         },
-        "books.BookResponse": {
+        "publishers.PublisherListResponse": {
             "type": "object",
             "properties": {
-                "author": {
-                    "type": "string"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/publishers.PublisherResponse"
+                    }
                 },
-                "description": {
+                "next_cursor": {
                     "type": "string"
-                },
+                }
+            }
+        },
+        "publishers.PublisherResponse": {
+            "type": "object",
+            "properties": {
                 "id": {
                     "type": "integer"
                 },
-                "isbn": {
+                "name": {
                     "type": "string"
                 },
-                "page_count": {
-                    "type": "integer"
-                },
-                "publication_year": {
-                    "type": "integer"
-                },
-                "title": {
+                "website": {
                     "type": "string"
                 }
             }
         },
-        "books.CreateBookRequest": {
+        "publishers.UpdatePublisherRequest": {
             "type": "object",
             "required": [
-                "author",
-                "isbn",
-                "title"
+                "name"
             ],
             "properties": {
-                "author": {
+                "name": {
                     "type": "string"
                 },
-                "description": {
+                "website": {
                     "type": "string"
-                },
-                "isbn": {
+                }
+            }
+        },
+        "rates.ExchangeRateListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rates.ExchangeRateResponse"
+                    }
+                }
+            }
+        },
+        "rates.ExchangeRateResponse": {
+            "type": "object",
+            "properties": {
+                "actor": {
                     "type": "string"
                 },
-                "page_count": {
-                    "type": "integer"
+                "base_currency": {
+                    "type": "string",
+                    "example": "RUB"
                 },
-                "publication_year": {
-                    "type": "integer"
+                "quote_currency": {
+                    "type": "string",
+                    "example": "KZT"
                 },
-                "title": {
+                "rate": {
+                    "type": "string",
+                    "example": "5.4321"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "inventory.AdjustSKUStockRequest": {
+        "rates.SetExchangeRateRequest": {
             "type": "object",
+            "required": [
+                "rate"
+            ],
             "properties": {
-                "change_by": {
-                    "type": "integer"
-                },
-                "comment": {
-                    "type": "string"
+                "rate": {
+                    "description": "Units of the quote currency per unit of the base currency, as a decimal string.",
+                    "type": "string",
+                    "example": "5.4321"
                 }
             }
         },
-        "inventory.CreateSKURequest": {
+        "reservations.CreateReservationRequest": {
             "type": "object",
-            "required": [
-                "book_id",
-                "store_uuid"
-            ],
             "properties": {
-                "book_id": {
-                    "type": "integer"
+                "note": {
+                    "type": "string",
+                    "maxLength": 1000
                 },
-                "price_in_kopeks": {
-                    "type": "integer",
-                    "minimum": 0
-                },
-                "stock_count": {
-                    "type": "integer",
-                    "minimum": 0
+                "quantity": {
+                    "type": "integer"
                 },
-                "store_uuid": {
-                    "type": "string"
+                "ttl_seconds": {
+                    "type": "integer"
                 }
             }
         },
-        "inventory.SKUResponse": {
+        "reservations.ReservationResponse": {
             "type": "object",
             "properties": {
-                "book_id": {
-                    "type": "integer"
+                "actor": {
+                    "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "price_in_kopeks": {
-                    "type": "integer"
+                "expires_at": {
+                    "type": "string"
                 },
-                "stock_count": {
-                    "type": "integer"
+                "note": {
+                    "type": "string"
                 },
-                "store_id": {
+                "quantity": {
                     "type": "integer"
                 },
+                "sku_uuid": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
                 },
@@ -798,30 +6398,66 @@ const docTemplate = `{
                 }
             }
         },
-        "inventory.SKUWithBookResponse": {
+        "response.ErrorResponse": {
             "type": "object",
             "properties": {
-                "book": {
-                    "$ref": "#/definitions/books.BookResponse"
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "series.CreateSeriesRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
                 },
-                "sku": {
-                    "$ref": "#/definitions/inventory.SKUResponse"
+                "name": {
+                    "type": "string"
                 }
             }
         },
-        "inventory.UpdateSKUPriceRequest": {
+        "series.SeriesListResponse": {
             "type": "object",
             "properties": {
-                "new_price_in_kopeks": {
-                    "type": "integer",
-                    "minimum": 0
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/series.SeriesResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
                 }
             }
         },
-        "response.ErrorResponse": {
+        "series.SeriesResponse": {
             "type": "object",
             "properties": {
-                "error": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "series.UpdateSeriesRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
                     "type": "string"
                 }
             }
@@ -836,17 +6472,40 @@ const docTemplate = `{
                 "address": {
                     "type": "string"
                 },
+                "currency": {
+                    "description": "ISO 4217, RUB when omitted.",
+                    "type": "string",
+                    "example": "KZT"
+                },
                 "name": {
                     "type": "string"
                 }
             }
         },
+        "stores.StoreListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/stores.StoreResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
         "stores.StoreResponse": {
             "type": "object",
             "properties": {
                 "address": {
                     "type": "string"
                 },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -865,12 +6524,178 @@ const docTemplate = `{
                 "address": {
                     "type": "string"
                 },
+                "currency": {
+                    "description": "ISO 4217, unchanged when omitted. Can change only while the store has no SKUs.",
+                    "type": "string",
+                    "example": "KZT"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "tags.CreateTagRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "tags.TagListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tags.TagResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "tags.TagResponse": {
+            "type": "object",
+            "properties": {
+                "book_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "tags.UpdateTagRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
                 "name": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "transfers.CreateTransferRequest": {
+            "type": "object",
+            "required": [
+                "book_id",
+                "destination_store_uuid",
+                "source_store_uuid"
+            ],
+            "properties": {
+                "book_id": {
+                    "type": "integer"
+                },
+                "destination_store_uuid": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "source_store_uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "transfers.TransferListResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/transfers.TransferResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "transfers.TransferResponse": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "book_id": {
+                    "type": "integer"
+                },
+                "cancelled_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "destination_sku_uuid": {
+                    "type": "string"
+                },
+                "destination_store_uuid": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "source_sku_uuid": {
+                    "type": "string"
+                },
+                "source_store_uuid": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "uuid": {
                     "type": "string"
                 }
             }
         }
-    }
+    },
+    "securityDefinitions": {
+        "APIKeyAuth": {
+            "description": "API-ключ машинного клиента.",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "JWT или API-ключ в формате \"Bearer \u003ctoken\u003e\".",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
+    },
+    "security": [
+        {
+            "BearerAuth": []
+        },
+        {
+            "APIKeyAuth": []
+        }
+    ]
 }`
 
 // SwaggerInfo holds exported Swagger Info so clients can modify it
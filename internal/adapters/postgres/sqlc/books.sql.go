@@ -11,16 +11,141 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const bookFacets = `-- name: BookFacets :many
+WITH search AS (SELECT websearch_to_tsquery('russian', $2::text) ||
+                       websearch_to_tsquery('english', $2::text) AS tsq,
+                       $3::text                                   AS isbn),
+     matched AS (SELECT b.id, b.publication_year
+                 FROM books b,
+                      search
+                 WHERE b.deleted_at IS NULL
+                   AND ($2::text IS NULL
+                     OR books_search_vector(b.title, b.author, b.description, b.isbn) @@ search.tsq
+                     OR $2::text <% b.title
+                     OR $2::text <% b.author
+                     OR b.isbn = search.isbn)
+                   AND ($4::text IS NULL OR b.author ILIKE '%' || $4::text || '%')
+                   AND ($5::int IS NULL OR b.publication_year >= $5::int)
+                   AND ($6::int IS NULL OR b.publication_year <= $6::int)
+                   AND ($7::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                                          FROM book_genres bg
+                                                                          WHERE bg.book_id = b.id
+                                                                            AND bg.genre_id = ANY ($7::bigint[])))
+                   AND ($8::text IS NULL OR EXISTS (SELECT 1
+                                                                FROM book_tags bt
+                                                                         JOIN tags t ON bt.tag_id = t.id
+                                                                WHERE bt.book_id = b.id
+                                                                  AND t.name = $8::text))
+                   AND ($9::bigint IS NULL OR b.series_id = $9::bigint)
+                   AND ($10::int IS NULL OR b.publication_year BETWEEN $10::int AND $10::int + 9)
+                   AND ($11::bool IS NULL OR $11::bool = EXISTS (SELECT 1
+                                                                                                FROM skus s
+                                                                                                         JOIN stores st ON s.store_id = st.id
+                                                                                                WHERE s.book_id = b.id
+                                                                                                  AND s.deleted_at IS NULL
+                                                                                                  AND st.deleted_at IS NULL
+                                                                                                  AND s.stock_count > s.reserved_count))),
+     counts AS (SELECT 'genre'::text AS facet, g.id::text AS value, g.name AS label, count(*) AS count
+                FROM matched m
+                         JOIN book_genres bg ON bg.book_id = m.id
+                         JOIN genres g ON bg.genre_id = g.id
+                GROUP BY g.id, g.name
+                UNION ALL
+                SELECT 'author', a.id::text, a.name, count(DISTINCT m.id)
+                FROM matched m
+                         JOIN book_authors ba ON ba.book_id = m.id AND ba.role = 'author'
+                         JOIN authors a ON ba.author_id = a.id
+                GROUP BY a.id, a.name
+                UNION ALL
+                SELECT 'decade', (m.publication_year / 10 * 10)::text, (m.publication_year / 10 * 10)::text, count(*)
+                FROM matched m
+                WHERE m.publication_year IS NOT NULL
+                GROUP BY m.publication_year / 10 * 10
+                UNION ALL
+                SELECT 'in_stock', stock.in_stock::text, stock.in_stock::text, count(*)
+                FROM (SELECT EXISTS (SELECT 1
+                                     FROM skus s
+                                              JOIN stores st ON s.store_id = st.id
+                                     WHERE s.book_id = m.id
+                                       AND s.deleted_at IS NULL
+                                       AND st.deleted_at IS NULL
+                                       AND s.stock_count > s.reserved_count) AS in_stock
+                      FROM matched m) stock
+                GROUP BY stock.in_stock)
+SELECT ranked.facet::text AS facet, ranked.value::text AS value, ranked.label::text AS label, ranked.count::bigint AS count
+FROM (SELECT c.facet, c.value, c.label, c.count, row_number() OVER (PARTITION BY c.facet ORDER BY c.count DESC, c.label) AS n
+      FROM counts c) ranked
+WHERE ranked.n <= $1::int
+ORDER BY ranked.facet, ranked.count DESC, ranked.label
+`
+
+type BookFacetsParams struct {
+	FacetLimit int32       `json:"facet_limit"`
+	Query      pgtype.Text `json:"query"`
+	Isbn       pgtype.Text `json:"isbn"`
+	Author     pgtype.Text `json:"author"`
+	YearFrom   pgtype.Int4 `json:"year_from"`
+	YearTo     pgtype.Int4 `json:"year_to"`
+	GenreIds   []int64     `json:"genre_ids"`
+	Tag        pgtype.Text `json:"tag"`
+	SeriesID   pgtype.Int8 `json:"series_id"`
+	Decade     pgtype.Int4 `json:"decade"`
+	InStock    pgtype.Bool `json:"in_stock"`
+}
+
+type BookFacetsRow struct {
+	Facet string `json:"facet"`
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
+
+// Counts the books matching the filters, and the search query if given, per genre, author,
+// publication decade and stock availability. Each facet keeps its facet_limit largest values.
+// isbn is the query as a canonical ISBN-13, as in SearchBooks.
+func (q *Queries) BookFacets(ctx context.Context, arg BookFacetsParams) ([]BookFacetsRow, error) {
+	rows, err := q.db.Query(ctx, bookFacets,
+		arg.FacetLimit,
+		arg.Query,
+		arg.Isbn,
+		arg.Author,
+		arg.YearFrom,
+		arg.YearTo,
+		arg.GenreIds,
+		arg.Tag,
+		arg.SeriesID,
+		arg.Decade,
+		arg.InStock,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BookFacetsRow
+	for rows.Next() {
+		var i BookFacetsRow
+		if err := rows.Scan(
+			&i.Facet,
+			&i.Value,
+			&i.Label,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createBook = `-- name: CreateBook :one
-INSERT INTO books (isbn, title, author, description, page_count, publication_year)
-VALUES ($1, $2, $3, $4, $5, $6)
-ON CONFLICT (isbn)
-WHERE isbn IS NOT NULL DO
-UPDATE
-SET title      = EXCLUDED.title,
-    author     = EXCLUDED.author,
-    updated_at = now()
-RETURNING id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at
+INSERT INTO books (isbn, title, author, description, page_count, publication_year, publisher_id, series_id,
+                   series_volume)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (isbn) DO NOTHING
+RETURNING id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
 `
 
 type CreateBookParams struct {
@@ -30,6 +155,9 @@ type CreateBookParams struct {
 	Description     pgtype.Text `json:"description"`
 	PageCount       pgtype.Int4 `json:"page_count"`
 	PublicationYear pgtype.Int4 `json:"publication_year"`
+	PublisherID     pgtype.Int8 `json:"publisher_id"`
+	SeriesID        pgtype.Int8 `json:"series_id"`
+	SeriesVolume    pgtype.Int4 `json:"series_volume"`
 }
 
 func (q *Queries) CreateBook(ctx context.Context, arg CreateBookParams) (Book, error) {
@@ -40,6 +168,9 @@ func (q *Queries) CreateBook(ctx context.Context, arg CreateBookParams) (Book, e
 		arg.Description,
 		arg.PageCount,
 		arg.PublicationYear,
+		arg.PublisherID,
+		arg.SeriesID,
+		arg.SeriesVolume,
 	)
 	var i Book
 	err := row.Scan(
@@ -53,12 +184,15 @@ func (q *Queries) CreateBook(ctx context.Context, arg CreateBookParams) (Book, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.PublisherID,
+		&i.SeriesID,
+		&i.SeriesVolume,
 	)
 	return i, err
 }
 
 const getBookByID = `-- name: GetBookByID :one
-SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at
+SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
 FROM books
 WHERE id = $1
   AND deleted_at IS NULL
@@ -78,19 +212,187 @@ func (q *Queries) GetBookByID(ctx context.Context, id int64) (Book, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.PublisherID,
+		&i.SeriesID,
+		&i.SeriesVolume,
+	)
+	return i, err
+}
+
+const getBookByIDForUpdate = `-- name: GetBookByIDForUpdate :one
+SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
+FROM books
+WHERE id = $1
+  AND deleted_at IS NULL
+    FOR UPDATE
+`
+
+func (q *Queries) GetBookByIDForUpdate(ctx context.Context, id int64) (Book, error) {
+	row := q.db.QueryRow(ctx, getBookByIDForUpdate, id)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn,
+		&i.Title,
+		&i.Author,
+		&i.Description,
+		&i.PageCount,
+		&i.PublicationYear,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.PublisherID,
+		&i.SeriesID,
+		&i.SeriesVolume,
+	)
+	return i, err
+}
+
+const getBookByISBN = `-- name: GetBookByISBN :one
+SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
+FROM books
+WHERE isbn = $1
+  AND deleted_at IS NULL
+`
+
+func (q *Queries) GetBookByISBN(ctx context.Context, isbn pgtype.Text) (Book, error) {
+	row := q.db.QueryRow(ctx, getBookByISBN, isbn)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn,
+		&i.Title,
+		&i.Author,
+		&i.Description,
+		&i.PageCount,
+		&i.PublicationYear,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.PublisherID,
+		&i.SeriesID,
+		&i.SeriesVolume,
+	)
+	return i, err
+}
+
+const getBookByISBNForUpdate = `-- name: GetBookByISBNForUpdate :one
+SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
+FROM books
+WHERE isbn = $1
+    FOR UPDATE
+`
+
+// Soft-deleted books are included: an upsert brings them back.
+func (q *Queries) GetBookByISBNForUpdate(ctx context.Context, isbn pgtype.Text) (Book, error) {
+	row := q.db.QueryRow(ctx, getBookByISBNForUpdate, isbn)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn,
+		&i.Title,
+		&i.Author,
+		&i.Description,
+		&i.PageCount,
+		&i.PublicationYear,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.PublisherID,
+		&i.SeriesID,
+		&i.SeriesVolume,
 	)
 	return i, err
 }
 
-const listBooks = `-- name: ListBooks :many
-SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at
+const getDeletedBookByIDForUpdate = `-- name: GetDeletedBookByIDForUpdate :one
+SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
 FROM books
+WHERE id = $1
+  AND deleted_at IS NOT NULL
+    FOR UPDATE
+`
+
+func (q *Queries) GetDeletedBookByIDForUpdate(ctx context.Context, id int64) (Book, error) {
+	row := q.db.QueryRow(ctx, getDeletedBookByIDForUpdate, id)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn,
+		&i.Title,
+		&i.Author,
+		&i.Description,
+		&i.PageCount,
+		&i.PublicationYear,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.PublisherID,
+		&i.SeriesID,
+		&i.SeriesVolume,
+	)
+	return i, err
+}
+
+const listBooksByAuthorName = `-- name: ListBooksByAuthorName :many
+SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
+FROM books b
 WHERE deleted_at IS NULL
-ORDER BY title
+  AND ($1::text IS NULL OR b.author ILIKE '%' || $1::text || '%')
+  AND ($2::int IS NULL OR b.publication_year >= $2::int)
+  AND ($3::int IS NULL OR b.publication_year <= $3::int)
+  AND ($4::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY ($4::bigint[])))
+  AND ($5::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = $5::text))
+  AND ($6::bigint IS NULL OR b.series_id = $6::bigint)
+  AND ($7::int IS NULL OR b.publication_year BETWEEN $7::int AND $7::int + 9)
+  AND ($8::bool IS NULL OR $8::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND ($9::bigint IS NULL
+    OR (author, id) > ($10::text, $9::bigint))
+ORDER BY author, id
+LIMIT $11
 `
 
-func (q *Queries) ListBooks(ctx context.Context) ([]Book, error) {
-	rows, err := q.db.Query(ctx, listBooks)
+type ListBooksByAuthorNameParams struct {
+	Author     pgtype.Text `json:"author"`
+	YearFrom   pgtype.Int4 `json:"year_from"`
+	YearTo     pgtype.Int4 `json:"year_to"`
+	GenreIds   []int64     `json:"genre_ids"`
+	Tag        pgtype.Text `json:"tag"`
+	SeriesID   pgtype.Int8 `json:"series_id"`
+	Decade     pgtype.Int4 `json:"decade"`
+	InStock    pgtype.Bool `json:"in_stock"`
+	CursorID   pgtype.Int8 `json:"cursor_id"`
+	CursorText pgtype.Text `json:"cursor_text"`
+	PageLimit  int32       `json:"page_limit"`
+}
+
+func (q *Queries) ListBooksByAuthorName(ctx context.Context, arg ListBooksByAuthorNameParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listBooksByAuthorName,
+		arg.Author,
+		arg.YearFrom,
+		arg.YearTo,
+		arg.GenreIds,
+		arg.Tag,
+		arg.SeriesID,
+		arg.Decade,
+		arg.InStock,
+		arg.CursorID,
+		arg.CursorText,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -109,6 +411,9 @@ func (q *Queries) ListBooks(ctx context.Context) ([]Book, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.PublisherID,
+			&i.SeriesID,
+			&i.SeriesVolume,
 		); err != nil {
 			return nil, err
 		}
@@ -120,16 +425,338 @@ func (q *Queries) ListBooks(ctx context.Context) ([]Book, error) {
 	return items, nil
 }
 
-const searchBooks = `-- name: SearchBooks :many
-SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at
-FROM books
-WHERE (title ILIKE '%' || $1 || '%' OR author ILIKE '%' || $1 || '%')
-  AND deleted_at IS NULL
-LIMIT 10
+const listBooksByAuthorNameDesc = `-- name: ListBooksByAuthorNameDesc :many
+SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
+FROM books b
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR b.author ILIKE '%' || $1::text || '%')
+  AND ($2::int IS NULL OR b.publication_year >= $2::int)
+  AND ($3::int IS NULL OR b.publication_year <= $3::int)
+  AND ($4::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY ($4::bigint[])))
+  AND ($5::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = $5::text))
+  AND ($6::bigint IS NULL OR b.series_id = $6::bigint)
+  AND ($7::int IS NULL OR b.publication_year BETWEEN $7::int AND $7::int + 9)
+  AND ($8::bool IS NULL OR $8::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND ($9::bigint IS NULL
+    OR (author, id) < ($10::text, $9::bigint))
+ORDER BY author DESC, id DESC
+LIMIT $11
+`
+
+type ListBooksByAuthorNameDescParams struct {
+	Author     pgtype.Text `json:"author"`
+	YearFrom   pgtype.Int4 `json:"year_from"`
+	YearTo     pgtype.Int4 `json:"year_to"`
+	GenreIds   []int64     `json:"genre_ids"`
+	Tag        pgtype.Text `json:"tag"`
+	SeriesID   pgtype.Int8 `json:"series_id"`
+	Decade     pgtype.Int4 `json:"decade"`
+	InStock    pgtype.Bool `json:"in_stock"`
+	CursorID   pgtype.Int8 `json:"cursor_id"`
+	CursorText pgtype.Text `json:"cursor_text"`
+	PageLimit  int32       `json:"page_limit"`
+}
+
+func (q *Queries) ListBooksByAuthorNameDesc(ctx context.Context, arg ListBooksByAuthorNameDescParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listBooksByAuthorNameDesc,
+		arg.Author,
+		arg.YearFrom,
+		arg.YearTo,
+		arg.GenreIds,
+		arg.Tag,
+		arg.SeriesID,
+		arg.Decade,
+		arg.InStock,
+		arg.CursorID,
+		arg.CursorText,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn,
+			&i.Title,
+			&i.Author,
+			&i.Description,
+			&i.PageCount,
+			&i.PublicationYear,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.PublisherID,
+			&i.SeriesID,
+			&i.SeriesVolume,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBooksByCreatedAt = `-- name: ListBooksByCreatedAt :many
+SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
+FROM books b
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR b.author ILIKE '%' || $1::text || '%')
+  AND ($2::int IS NULL OR b.publication_year >= $2::int)
+  AND ($3::int IS NULL OR b.publication_year <= $3::int)
+  AND ($4::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY ($4::bigint[])))
+  AND ($5::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = $5::text))
+  AND ($6::bigint IS NULL OR b.series_id = $6::bigint)
+  AND ($7::int IS NULL OR b.publication_year BETWEEN $7::int AND $7::int + 9)
+  AND ($8::bool IS NULL OR $8::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND ($9::bigint IS NULL
+    OR (created_at, id) > ($10::timestamptz, $9::bigint))
+ORDER BY created_at, id
+LIMIT $11
+`
+
+type ListBooksByCreatedAtParams struct {
+	Author     pgtype.Text        `json:"author"`
+	YearFrom   pgtype.Int4        `json:"year_from"`
+	YearTo     pgtype.Int4        `json:"year_to"`
+	GenreIds   []int64            `json:"genre_ids"`
+	Tag        pgtype.Text        `json:"tag"`
+	SeriesID   pgtype.Int8        `json:"series_id"`
+	Decade     pgtype.Int4        `json:"decade"`
+	InStock    pgtype.Bool        `json:"in_stock"`
+	CursorID   pgtype.Int8        `json:"cursor_id"`
+	CursorTime pgtype.Timestamptz `json:"cursor_time"`
+	PageLimit  int32              `json:"page_limit"`
+}
+
+func (q *Queries) ListBooksByCreatedAt(ctx context.Context, arg ListBooksByCreatedAtParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listBooksByCreatedAt,
+		arg.Author,
+		arg.YearFrom,
+		arg.YearTo,
+		arg.GenreIds,
+		arg.Tag,
+		arg.SeriesID,
+		arg.Decade,
+		arg.InStock,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn,
+			&i.Title,
+			&i.Author,
+			&i.Description,
+			&i.PageCount,
+			&i.PublicationYear,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.PublisherID,
+			&i.SeriesID,
+			&i.SeriesVolume,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBooksByCreatedAtDesc = `-- name: ListBooksByCreatedAtDesc :many
+SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
+FROM books b
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR b.author ILIKE '%' || $1::text || '%')
+  AND ($2::int IS NULL OR b.publication_year >= $2::int)
+  AND ($3::int IS NULL OR b.publication_year <= $3::int)
+  AND ($4::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY ($4::bigint[])))
+  AND ($5::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = $5::text))
+  AND ($6::bigint IS NULL OR b.series_id = $6::bigint)
+  AND ($7::int IS NULL OR b.publication_year BETWEEN $7::int AND $7::int + 9)
+  AND ($8::bool IS NULL OR $8::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND ($9::bigint IS NULL
+    OR (created_at, id) < ($10::timestamptz, $9::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT $11
+`
+
+type ListBooksByCreatedAtDescParams struct {
+	Author     pgtype.Text        `json:"author"`
+	YearFrom   pgtype.Int4        `json:"year_from"`
+	YearTo     pgtype.Int4        `json:"year_to"`
+	GenreIds   []int64            `json:"genre_ids"`
+	Tag        pgtype.Text        `json:"tag"`
+	SeriesID   pgtype.Int8        `json:"series_id"`
+	Decade     pgtype.Int4        `json:"decade"`
+	InStock    pgtype.Bool        `json:"in_stock"`
+	CursorID   pgtype.Int8        `json:"cursor_id"`
+	CursorTime pgtype.Timestamptz `json:"cursor_time"`
+	PageLimit  int32              `json:"page_limit"`
+}
+
+func (q *Queries) ListBooksByCreatedAtDesc(ctx context.Context, arg ListBooksByCreatedAtDescParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listBooksByCreatedAtDesc,
+		arg.Author,
+		arg.YearFrom,
+		arg.YearTo,
+		arg.GenreIds,
+		arg.Tag,
+		arg.SeriesID,
+		arg.Decade,
+		arg.InStock,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn,
+			&i.Title,
+			&i.Author,
+			&i.Description,
+			&i.PageCount,
+			&i.PublicationYear,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.PublisherID,
+			&i.SeriesID,
+			&i.SeriesVolume,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBooksByPublicationYear = `-- name: ListBooksByPublicationYear :many
+SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
+FROM books b
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR b.author ILIKE '%' || $1::text || '%')
+  AND ($2::int IS NULL OR b.publication_year >= $2::int)
+  AND ($3::int IS NULL OR b.publication_year <= $3::int)
+  AND ($4::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY ($4::bigint[])))
+  AND ($5::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = $5::text))
+  AND ($6::bigint IS NULL OR b.series_id = $6::bigint)
+  AND ($7::int IS NULL OR b.publication_year BETWEEN $7::int AND $7::int + 9)
+  AND ($8::bool IS NULL OR $8::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND ($9::bigint IS NULL
+    OR (COALESCE(publication_year, 0), id) > ($10::int, $9::bigint))
+ORDER BY COALESCE(publication_year, 0), id
+LIMIT $11
 `
 
-func (q *Queries) SearchBooks(ctx context.Context, query pgtype.Text) ([]Book, error) {
-	rows, err := q.db.Query(ctx, searchBooks, query)
+type ListBooksByPublicationYearParams struct {
+	Author    pgtype.Text `json:"author"`
+	YearFrom  pgtype.Int4 `json:"year_from"`
+	YearTo    pgtype.Int4 `json:"year_to"`
+	GenreIds  []int64     `json:"genre_ids"`
+	Tag       pgtype.Text `json:"tag"`
+	SeriesID  pgtype.Int8 `json:"series_id"`
+	Decade    pgtype.Int4 `json:"decade"`
+	InStock   pgtype.Bool `json:"in_stock"`
+	CursorID  pgtype.Int8 `json:"cursor_id"`
+	CursorInt pgtype.Int4 `json:"cursor_int"`
+	PageLimit int32       `json:"page_limit"`
+}
+
+func (q *Queries) ListBooksByPublicationYear(ctx context.Context, arg ListBooksByPublicationYearParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listBooksByPublicationYear,
+		arg.Author,
+		arg.YearFrom,
+		arg.YearTo,
+		arg.GenreIds,
+		arg.Tag,
+		arg.SeriesID,
+		arg.Decade,
+		arg.InStock,
+		arg.CursorID,
+		arg.CursorInt,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -148,6 +775,9 @@ func (q *Queries) SearchBooks(ctx context.Context, query pgtype.Text) ([]Book, e
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.PublisherID,
+			&i.SeriesID,
+			&i.SeriesVolume,
 		); err != nil {
 			return nil, err
 		}
@@ -158,3 +788,826 @@ func (q *Queries) SearchBooks(ctx context.Context, query pgtype.Text) ([]Book, e
 	}
 	return items, nil
 }
+
+const listBooksByPublicationYearDesc = `-- name: ListBooksByPublicationYearDesc :many
+SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
+FROM books b
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR b.author ILIKE '%' || $1::text || '%')
+  AND ($2::int IS NULL OR b.publication_year >= $2::int)
+  AND ($3::int IS NULL OR b.publication_year <= $3::int)
+  AND ($4::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY ($4::bigint[])))
+  AND ($5::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = $5::text))
+  AND ($6::bigint IS NULL OR b.series_id = $6::bigint)
+  AND ($7::int IS NULL OR b.publication_year BETWEEN $7::int AND $7::int + 9)
+  AND ($8::bool IS NULL OR $8::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND ($9::bigint IS NULL
+    OR (COALESCE(publication_year, 0), id) < ($10::int, $9::bigint))
+ORDER BY COALESCE(publication_year, 0) DESC, id DESC
+LIMIT $11
+`
+
+type ListBooksByPublicationYearDescParams struct {
+	Author    pgtype.Text `json:"author"`
+	YearFrom  pgtype.Int4 `json:"year_from"`
+	YearTo    pgtype.Int4 `json:"year_to"`
+	GenreIds  []int64     `json:"genre_ids"`
+	Tag       pgtype.Text `json:"tag"`
+	SeriesID  pgtype.Int8 `json:"series_id"`
+	Decade    pgtype.Int4 `json:"decade"`
+	InStock   pgtype.Bool `json:"in_stock"`
+	CursorID  pgtype.Int8 `json:"cursor_id"`
+	CursorInt pgtype.Int4 `json:"cursor_int"`
+	PageLimit int32       `json:"page_limit"`
+}
+
+func (q *Queries) ListBooksByPublicationYearDesc(ctx context.Context, arg ListBooksByPublicationYearDescParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listBooksByPublicationYearDesc,
+		arg.Author,
+		arg.YearFrom,
+		arg.YearTo,
+		arg.GenreIds,
+		arg.Tag,
+		arg.SeriesID,
+		arg.Decade,
+		arg.InStock,
+		arg.CursorID,
+		arg.CursorInt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn,
+			&i.Title,
+			&i.Author,
+			&i.Description,
+			&i.PageCount,
+			&i.PublicationYear,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.PublisherID,
+			&i.SeriesID,
+			&i.SeriesVolume,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBooksBySeriesVolume = `-- name: ListBooksBySeriesVolume :many
+SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
+FROM books b
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR b.author ILIKE '%' || $1::text || '%')
+  AND ($2::int IS NULL OR b.publication_year >= $2::int)
+  AND ($3::int IS NULL OR b.publication_year <= $3::int)
+  AND ($4::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY ($4::bigint[])))
+  AND ($5::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = $5::text))
+  AND ($6::bigint IS NULL OR b.series_id = $6::bigint)
+  AND ($7::int IS NULL OR b.publication_year BETWEEN $7::int AND $7::int + 9)
+  AND ($8::bool IS NULL OR $8::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND ($9::bigint IS NULL
+    OR (COALESCE(series_volume, 0), id) > ($10::int, $9::bigint))
+ORDER BY COALESCE(series_volume, 0), id
+LIMIT $11
+`
+
+type ListBooksBySeriesVolumeParams struct {
+	Author    pgtype.Text `json:"author"`
+	YearFrom  pgtype.Int4 `json:"year_from"`
+	YearTo    pgtype.Int4 `json:"year_to"`
+	GenreIds  []int64     `json:"genre_ids"`
+	Tag       pgtype.Text `json:"tag"`
+	SeriesID  pgtype.Int8 `json:"series_id"`
+	Decade    pgtype.Int4 `json:"decade"`
+	InStock   pgtype.Bool `json:"in_stock"`
+	CursorID  pgtype.Int8 `json:"cursor_id"`
+	CursorInt pgtype.Int4 `json:"cursor_int"`
+	PageLimit int32       `json:"page_limit"`
+}
+
+func (q *Queries) ListBooksBySeriesVolume(ctx context.Context, arg ListBooksBySeriesVolumeParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listBooksBySeriesVolume,
+		arg.Author,
+		arg.YearFrom,
+		arg.YearTo,
+		arg.GenreIds,
+		arg.Tag,
+		arg.SeriesID,
+		arg.Decade,
+		arg.InStock,
+		arg.CursorID,
+		arg.CursorInt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn,
+			&i.Title,
+			&i.Author,
+			&i.Description,
+			&i.PageCount,
+			&i.PublicationYear,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.PublisherID,
+			&i.SeriesID,
+			&i.SeriesVolume,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBooksBySeriesVolumeDesc = `-- name: ListBooksBySeriesVolumeDesc :many
+SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
+FROM books b
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR b.author ILIKE '%' || $1::text || '%')
+  AND ($2::int IS NULL OR b.publication_year >= $2::int)
+  AND ($3::int IS NULL OR b.publication_year <= $3::int)
+  AND ($4::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY ($4::bigint[])))
+  AND ($5::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = $5::text))
+  AND ($6::bigint IS NULL OR b.series_id = $6::bigint)
+  AND ($7::int IS NULL OR b.publication_year BETWEEN $7::int AND $7::int + 9)
+  AND ($8::bool IS NULL OR $8::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND ($9::bigint IS NULL
+    OR (COALESCE(series_volume, 0), id) < ($10::int, $9::bigint))
+ORDER BY COALESCE(series_volume, 0) DESC, id DESC
+LIMIT $11
+`
+
+type ListBooksBySeriesVolumeDescParams struct {
+	Author    pgtype.Text `json:"author"`
+	YearFrom  pgtype.Int4 `json:"year_from"`
+	YearTo    pgtype.Int4 `json:"year_to"`
+	GenreIds  []int64     `json:"genre_ids"`
+	Tag       pgtype.Text `json:"tag"`
+	SeriesID  pgtype.Int8 `json:"series_id"`
+	Decade    pgtype.Int4 `json:"decade"`
+	InStock   pgtype.Bool `json:"in_stock"`
+	CursorID  pgtype.Int8 `json:"cursor_id"`
+	CursorInt pgtype.Int4 `json:"cursor_int"`
+	PageLimit int32       `json:"page_limit"`
+}
+
+func (q *Queries) ListBooksBySeriesVolumeDesc(ctx context.Context, arg ListBooksBySeriesVolumeDescParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listBooksBySeriesVolumeDesc,
+		arg.Author,
+		arg.YearFrom,
+		arg.YearTo,
+		arg.GenreIds,
+		arg.Tag,
+		arg.SeriesID,
+		arg.Decade,
+		arg.InStock,
+		arg.CursorID,
+		arg.CursorInt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn,
+			&i.Title,
+			&i.Author,
+			&i.Description,
+			&i.PageCount,
+			&i.PublicationYear,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.PublisherID,
+			&i.SeriesID,
+			&i.SeriesVolume,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBooksByTitle = `-- name: ListBooksByTitle :many
+SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
+FROM books b
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR b.author ILIKE '%' || $1::text || '%')
+  AND ($2::int IS NULL OR b.publication_year >= $2::int)
+  AND ($3::int IS NULL OR b.publication_year <= $3::int)
+  AND ($4::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY ($4::bigint[])))
+  AND ($5::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = $5::text))
+  AND ($6::bigint IS NULL OR b.series_id = $6::bigint)
+  AND ($7::int IS NULL OR b.publication_year BETWEEN $7::int AND $7::int + 9)
+  AND ($8::bool IS NULL OR $8::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND ($9::bigint IS NULL
+    OR (title, id) > ($10::text, $9::bigint))
+ORDER BY title, id
+LIMIT $11
+`
+
+type ListBooksByTitleParams struct {
+	Author     pgtype.Text `json:"author"`
+	YearFrom   pgtype.Int4 `json:"year_from"`
+	YearTo     pgtype.Int4 `json:"year_to"`
+	GenreIds   []int64     `json:"genre_ids"`
+	Tag        pgtype.Text `json:"tag"`
+	SeriesID   pgtype.Int8 `json:"series_id"`
+	Decade     pgtype.Int4 `json:"decade"`
+	InStock    pgtype.Bool `json:"in_stock"`
+	CursorID   pgtype.Int8 `json:"cursor_id"`
+	CursorText pgtype.Text `json:"cursor_text"`
+	PageLimit  int32       `json:"page_limit"`
+}
+
+func (q *Queries) ListBooksByTitle(ctx context.Context, arg ListBooksByTitleParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listBooksByTitle,
+		arg.Author,
+		arg.YearFrom,
+		arg.YearTo,
+		arg.GenreIds,
+		arg.Tag,
+		arg.SeriesID,
+		arg.Decade,
+		arg.InStock,
+		arg.CursorID,
+		arg.CursorText,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn,
+			&i.Title,
+			&i.Author,
+			&i.Description,
+			&i.PageCount,
+			&i.PublicationYear,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.PublisherID,
+			&i.SeriesID,
+			&i.SeriesVolume,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBooksByTitleDesc = `-- name: ListBooksByTitleDesc :many
+SELECT id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
+FROM books b
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR b.author ILIKE '%' || $1::text || '%')
+  AND ($2::int IS NULL OR b.publication_year >= $2::int)
+  AND ($3::int IS NULL OR b.publication_year <= $3::int)
+  AND ($4::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY ($4::bigint[])))
+  AND ($5::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = $5::text))
+  AND ($6::bigint IS NULL OR b.series_id = $6::bigint)
+  AND ($7::int IS NULL OR b.publication_year BETWEEN $7::int AND $7::int + 9)
+  AND ($8::bool IS NULL OR $8::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND ($9::bigint IS NULL
+    OR (title, id) < ($10::text, $9::bigint))
+ORDER BY title DESC, id DESC
+LIMIT $11
+`
+
+type ListBooksByTitleDescParams struct {
+	Author     pgtype.Text `json:"author"`
+	YearFrom   pgtype.Int4 `json:"year_from"`
+	YearTo     pgtype.Int4 `json:"year_to"`
+	GenreIds   []int64     `json:"genre_ids"`
+	Tag        pgtype.Text `json:"tag"`
+	SeriesID   pgtype.Int8 `json:"series_id"`
+	Decade     pgtype.Int4 `json:"decade"`
+	InStock    pgtype.Bool `json:"in_stock"`
+	CursorID   pgtype.Int8 `json:"cursor_id"`
+	CursorText pgtype.Text `json:"cursor_text"`
+	PageLimit  int32       `json:"page_limit"`
+}
+
+func (q *Queries) ListBooksByTitleDesc(ctx context.Context, arg ListBooksByTitleDescParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listBooksByTitleDesc,
+		arg.Author,
+		arg.YearFrom,
+		arg.YearTo,
+		arg.GenreIds,
+		arg.Tag,
+		arg.SeriesID,
+		arg.Decade,
+		arg.InStock,
+		arg.CursorID,
+		arg.CursorText,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn,
+			&i.Title,
+			&i.Author,
+			&i.Description,
+			&i.PageCount,
+			&i.PublicationYear,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.PublisherID,
+			&i.SeriesID,
+			&i.SeriesVolume,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreBook = `-- name: RestoreBook :one
+UPDATE books
+SET deleted_at = NULL,
+    updated_at = now()
+WHERE id = $1
+RETURNING id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
+`
+
+func (q *Queries) RestoreBook(ctx context.Context, id int64) (Book, error) {
+	row := q.db.QueryRow(ctx, restoreBook, id)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn,
+		&i.Title,
+		&i.Author,
+		&i.Description,
+		&i.PageCount,
+		&i.PublicationYear,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.PublisherID,
+		&i.SeriesID,
+		&i.SeriesVolume,
+	)
+	return i, err
+}
+
+const searchBooks = `-- name: SearchBooks :many
+WITH search AS (SELECT websearch_to_tsquery('russian', $4::text) ||
+                       websearch_to_tsquery('english', $4::text) AS tsq,
+                       $5::text                                  AS isbn,
+                       CASE
+                           WHEN $4::text ~ '[А-Яа-яЁё]' THEN 'russian'
+                           ELSE 'english' END::regconfig                      AS headline_config),
+     ranked AS (SELECT b.id, b.isbn, b.title, b.author, b.description, b.page_count, b.publication_year, b.created_at, b.updated_at, b.deleted_at, b.publisher_id, b.series_id, b.series_volume,
+                       (ts_rank_cd(books_search_vector(b.title, b.author, b.description, b.isbn), search.tsq) +
+                        GREATEST(word_similarity($4::text, b.title),
+                                 word_similarity($4::text, b.author)) +
+                        CASE WHEN b.isbn = search.isbn THEN 1 ELSE 0 END)::real AS rank
+                FROM books b,
+                     search
+                WHERE b.deleted_at IS NULL
+                  AND (books_search_vector(b.title, b.author, b.description, b.isbn) @@ search.tsq
+                    OR $4::text <% b.title
+                    OR $4::text <% b.author
+                    OR b.isbn = search.isbn)
+                  AND ($6::text IS NULL OR b.author ILIKE '%' || $6::text || '%')
+                  AND ($7::int IS NULL OR b.publication_year >= $7::int)
+                  AND ($8::int IS NULL OR b.publication_year <= $8::int)
+                  AND ($9::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                                         FROM book_genres bg
+                                                                         WHERE bg.book_id = b.id
+                                                                           AND bg.genre_id = ANY ($9::bigint[])))
+                  AND ($10::text IS NULL OR EXISTS (SELECT 1
+                                                               FROM book_tags bt
+                                                                        JOIN tags t ON bt.tag_id = t.id
+                                                               WHERE bt.book_id = b.id
+                                                                 AND t.name = $10::text))
+                  AND ($11::bigint IS NULL OR b.series_id = $11::bigint)
+                  AND ($12::int IS NULL OR b.publication_year BETWEEN $12::int AND $12::int + 9)
+                  AND ($13::bool IS NULL OR $13::bool = EXISTS (SELECT 1
+                                                                                               FROM skus s
+                                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                                               WHERE s.book_id = b.id
+                                                                                                 AND s.deleted_at IS NULL
+                                                                                                 AND st.deleted_at IS NULL
+                                                                                                 AND s.stock_count > s.reserved_count)))
+SELECT r.id,
+       r.isbn,
+       r.title,
+       r.author,
+       r.description,
+       r.page_count,
+       r.publication_year,
+       r.created_at,
+       r.updated_at,
+       r.deleted_at,
+       r.publisher_id,
+       r.series_id,
+       r.series_volume,
+       r.rank::real AS rank,
+       ts_headline(search.headline_config, concat_ws(' — ', r.title, r.author, r.description), search.tsq,
+                   'StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2')::text AS snippet
+FROM ranked r,
+     search
+WHERE $1::bigint IS NULL
+   OR r.rank < $2::real
+   OR (r.rank = $2::real AND r.id > $1::bigint)
+ORDER BY r.rank DESC, r.id
+LIMIT $3
+`
+
+type SearchBooksParams struct {
+	CursorID   pgtype.Int8   `json:"cursor_id"`
+	CursorRank pgtype.Float4 `json:"cursor_rank"`
+	PageLimit  int32         `json:"page_limit"`
+	Query      string        `json:"query"`
+	Isbn       pgtype.Text   `json:"isbn"`
+	Author     pgtype.Text   `json:"author"`
+	YearFrom   pgtype.Int4   `json:"year_from"`
+	YearTo     pgtype.Int4   `json:"year_to"`
+	GenreIds   []int64       `json:"genre_ids"`
+	Tag        pgtype.Text   `json:"tag"`
+	SeriesID   pgtype.Int8   `json:"series_id"`
+	Decade     pgtype.Int4   `json:"decade"`
+	InStock    pgtype.Bool   `json:"in_stock"`
+}
+
+type SearchBooksRow struct {
+	ID              int64              `json:"id"`
+	Isbn            pgtype.Text        `json:"isbn"`
+	Title           string             `json:"title"`
+	Author          string             `json:"author"`
+	Description     pgtype.Text        `json:"description"`
+	PageCount       pgtype.Int4        `json:"page_count"`
+	PublicationYear pgtype.Int4        `json:"publication_year"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
+	PublisherID     pgtype.Int8        `json:"publisher_id"`
+	SeriesID        pgtype.Int8        `json:"series_id"`
+	SeriesVolume    pgtype.Int4        `json:"series_volume"`
+	Rank            float32            `json:"rank"`
+	Snippet         string             `json:"snippet"`
+}
+
+// isbn is the query as a canonical ISBN-13, NULL if it isn't an ISBN. Snippets are highlighted
+// with the dictionary of the language the query is written in.
+func (q *Queries) SearchBooks(ctx context.Context, arg SearchBooksParams) ([]SearchBooksRow, error) {
+	rows, err := q.db.Query(ctx, searchBooks,
+		arg.CursorID,
+		arg.CursorRank,
+		arg.PageLimit,
+		arg.Query,
+		arg.Isbn,
+		arg.Author,
+		arg.YearFrom,
+		arg.YearTo,
+		arg.GenreIds,
+		arg.Tag,
+		arg.SeriesID,
+		arg.Decade,
+		arg.InStock,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchBooksRow
+	for rows.Next() {
+		var i SearchBooksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn,
+			&i.Title,
+			&i.Author,
+			&i.Description,
+			&i.PageCount,
+			&i.PublicationYear,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.PublisherID,
+			&i.SeriesID,
+			&i.SeriesVolume,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const softDeleteBook = `-- name: SoftDeleteBook :one
+UPDATE books
+SET deleted_at = now(),
+    updated_at = now()
+WHERE id = $1
+  AND deleted_at IS NULL
+RETURNING id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
+`
+
+func (q *Queries) SoftDeleteBook(ctx context.Context, id int64) (Book, error) {
+	row := q.db.QueryRow(ctx, softDeleteBook, id)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn,
+		&i.Title,
+		&i.Author,
+		&i.Description,
+		&i.PageCount,
+		&i.PublicationYear,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.PublisherID,
+		&i.SeriesID,
+		&i.SeriesVolume,
+	)
+	return i, err
+}
+
+const updateBook = `-- name: UpdateBook :one
+UPDATE books
+SET isbn             = $2,
+    title            = $3,
+    author           = $4,
+    description      = $5,
+    page_count       = $6,
+    publication_year = $7,
+    publisher_id     = $8,
+    series_id        = $9,
+    series_volume    = $10,
+    updated_at       = now()
+WHERE id = $1
+  AND deleted_at IS NULL
+RETURNING id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume
+`
+
+type UpdateBookParams struct {
+	ID              int64       `json:"id"`
+	Isbn            pgtype.Text `json:"isbn"`
+	Title           string      `json:"title"`
+	Author          string      `json:"author"`
+	Description     pgtype.Text `json:"description"`
+	PageCount       pgtype.Int4 `json:"page_count"`
+	PublicationYear pgtype.Int4 `json:"publication_year"`
+	PublisherID     pgtype.Int8 `json:"publisher_id"`
+	SeriesID        pgtype.Int8 `json:"series_id"`
+	SeriesVolume    pgtype.Int4 `json:"series_volume"`
+}
+
+func (q *Queries) UpdateBook(ctx context.Context, arg UpdateBookParams) (Book, error) {
+	row := q.db.QueryRow(ctx, updateBook,
+		arg.ID,
+		arg.Isbn,
+		arg.Title,
+		arg.Author,
+		arg.Description,
+		arg.PageCount,
+		arg.PublicationYear,
+		arg.PublisherID,
+		arg.SeriesID,
+		arg.SeriesVolume,
+	)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn,
+		&i.Title,
+		&i.Author,
+		&i.Description,
+		&i.PageCount,
+		&i.PublicationYear,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.PublisherID,
+		&i.SeriesID,
+		&i.SeriesVolume,
+	)
+	return i, err
+}
+
+const upsertBook = `-- name: UpsertBook :one
+INSERT INTO books (isbn, title, author, description, page_count, publication_year, publisher_id, series_id,
+                   series_volume)
+VALUES ($1, $2, $3, $4, $5,
+        $6, $7, $8, $9)
+ON CONFLICT (isbn) DO UPDATE
+    SET title            = EXCLUDED.title,
+        author           = EXCLUDED.author,
+        description      = CASE
+                               WHEN $10::bool THEN EXCLUDED.description
+                               ELSE COALESCE(EXCLUDED.description, books.description) END,
+        page_count       = CASE
+                               WHEN $10::bool THEN EXCLUDED.page_count
+                               ELSE COALESCE(EXCLUDED.page_count, books.page_count) END,
+        publication_year = CASE
+                               WHEN $10::bool THEN EXCLUDED.publication_year
+                               ELSE COALESCE(EXCLUDED.publication_year, books.publication_year) END,
+        publisher_id     = CASE
+                               WHEN $10::bool THEN EXCLUDED.publisher_id
+                               ELSE COALESCE(EXCLUDED.publisher_id, books.publisher_id) END,
+        series_id        = CASE
+                               WHEN $10::bool THEN EXCLUDED.series_id
+                               ELSE COALESCE(EXCLUDED.series_id, books.series_id) END,
+        series_volume    = CASE
+                               WHEN $10::bool THEN EXCLUDED.series_volume
+                               ELSE COALESCE(EXCLUDED.series_volume, books.series_volume) END,
+        deleted_at       = NULL,
+        updated_at       = now()
+RETURNING id, isbn, title, author, description, page_count, publication_year, created_at, updated_at, deleted_at, publisher_id, series_id, series_volume, (xmax = 0)::bool AS inserted
+`
+
+type UpsertBookParams struct {
+	Isbn            pgtype.Text `json:"isbn"`
+	Title           string      `json:"title"`
+	Author          string      `json:"author"`
+	Description     pgtype.Text `json:"description"`
+	PageCount       pgtype.Int4 `json:"page_count"`
+	PublicationYear pgtype.Int4 `json:"publication_year"`
+	PublisherID     pgtype.Int8 `json:"publisher_id"`
+	SeriesID        pgtype.Int8 `json:"series_id"`
+	SeriesVolume    pgtype.Int4 `json:"series_volume"`
+	Replace         bool        `json:"replace"`
+}
+
+type UpsertBookRow struct {
+	ID              int64              `json:"id"`
+	Isbn            pgtype.Text        `json:"isbn"`
+	Title           string             `json:"title"`
+	Author          string             `json:"author"`
+	Description     pgtype.Text        `json:"description"`
+	PageCount       pgtype.Int4        `json:"page_count"`
+	PublicationYear pgtype.Int4        `json:"publication_year"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
+	PublisherID     pgtype.Int8        `json:"publisher_id"`
+	SeriesID        pgtype.Int8        `json:"series_id"`
+	SeriesVolume    pgtype.Int4        `json:"series_volume"`
+	Inserted        bool               `json:"inserted"`
+}
+
+// Optional fields missing from the request keep their stored value unless replace is set.
+// An upsert also brings back a soft-deleted book with the same ISBN.
+func (q *Queries) UpsertBook(ctx context.Context, arg UpsertBookParams) (UpsertBookRow, error) {
+	row := q.db.QueryRow(ctx, upsertBook,
+		arg.Isbn,
+		arg.Title,
+		arg.Author,
+		arg.Description,
+		arg.PageCount,
+		arg.PublicationYear,
+		arg.PublisherID,
+		arg.SeriesID,
+		arg.SeriesVolume,
+		arg.Replace,
+	)
+	var i UpsertBookRow
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn,
+		&i.Title,
+		&i.Author,
+		&i.Description,
+		&i.PageCount,
+		&i.PublicationYear,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.PublisherID,
+		&i.SeriesID,
+		&i.SeriesVolume,
+		&i.Inserted,
+	)
+	return i, err
+}
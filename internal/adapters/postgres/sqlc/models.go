@@ -5,9 +5,397 @@
 package repo
 
 import (
+	"database/sql/driver"
+	"fmt"
+
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AuthorRole string
+
+const (
+	AuthorRoleAuthor      AuthorRole = "author"
+	AuthorRoleTranslator  AuthorRole = "translator"
+	AuthorRoleIllustrator AuthorRole = "illustrator"
+)
+
+func (e *AuthorRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AuthorRole(s)
+	case string:
+		*e = AuthorRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AuthorRole: %T", src)
+	}
+	return nil
+}
+
+type NullAuthorRole struct {
+	AuthorRole AuthorRole `json:"author_role"`
+	Valid      bool       `json:"valid"` // Valid is true if AuthorRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAuthorRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.AuthorRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AuthorRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAuthorRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AuthorRole), nil
+}
+
+type ImportFormat string
+
+const (
+	ImportFormatCsv    ImportFormat = "csv"
+	ImportFormatNdjson ImportFormat = "ndjson"
+)
+
+func (e *ImportFormat) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ImportFormat(s)
+	case string:
+		*e = ImportFormat(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ImportFormat: %T", src)
+	}
+	return nil
+}
+
+type NullImportFormat struct {
+	ImportFormat ImportFormat `json:"import_format"`
+	Valid        bool         `json:"valid"` // Valid is true if ImportFormat is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullImportFormat) Scan(value interface{}) error {
+	if value == nil {
+		ns.ImportFormat, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ImportFormat.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullImportFormat) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ImportFormat), nil
+}
+
+type ImportStatus string
+
+const (
+	ImportStatusPending   ImportStatus = "pending"
+	ImportStatusRunning   ImportStatus = "running"
+	ImportStatusCompleted ImportStatus = "completed"
+	ImportStatusFailed    ImportStatus = "failed"
+)
+
+func (e *ImportStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ImportStatus(s)
+	case string:
+		*e = ImportStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ImportStatus: %T", src)
+	}
+	return nil
+}
+
+type NullImportStatus struct {
+	ImportStatus ImportStatus `json:"import_status"`
+	Valid        bool         `json:"valid"` // Valid is true if ImportStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullImportStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ImportStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ImportStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullImportStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ImportStatus), nil
+}
+
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusFulfilled OrderStatus = "fulfilled"
+	OrderStatusCancelled OrderStatus = "cancelled"
+)
+
+func (e *OrderStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OrderStatus(s)
+	case string:
+		*e = OrderStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OrderStatus: %T", src)
+	}
+	return nil
+}
+
+type NullOrderStatus struct {
+	OrderStatus OrderStatus `json:"order_status"`
+	Valid       bool        `json:"valid"` // Valid is true if OrderStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOrderStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.OrderStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OrderStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOrderStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OrderStatus), nil
+}
+
+type PromotionKind string
+
+const (
+	PromotionKindPercent  PromotionKind = "percent"
+	PromotionKindFixed    PromotionKind = "fixed"
+	PromotionKindBuyXGetY PromotionKind = "buy_x_get_y"
+)
+
+func (e *PromotionKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PromotionKind(s)
+	case string:
+		*e = PromotionKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PromotionKind: %T", src)
+	}
+	return nil
+}
+
+type NullPromotionKind struct {
+	PromotionKind PromotionKind `json:"promotion_kind"`
+	Valid         bool          `json:"valid"` // Valid is true if PromotionKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPromotionKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.PromotionKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PromotionKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPromotionKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PromotionKind), nil
+}
+
+type ReservationStatus string
+
+const (
+	ReservationStatusActive    ReservationStatus = "active"
+	ReservationStatusConfirmed ReservationStatus = "confirmed"
+	ReservationStatusReleased  ReservationStatus = "released"
+	ReservationStatusExpired   ReservationStatus = "expired"
+)
+
+func (e *ReservationStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReservationStatus(s)
+	case string:
+		*e = ReservationStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReservationStatus: %T", src)
+	}
+	return nil
+}
+
+type NullReservationStatus struct {
+	ReservationStatus ReservationStatus `json:"reservation_status"`
+	Valid             bool              `json:"valid"` // Valid is true if ReservationStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReservationStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReservationStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReservationStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReservationStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReservationStatus), nil
+}
+
+type StockMovementReason string
+
+const (
+	StockMovementReasonReceipt    StockMovementReason = "receipt"
+	StockMovementReasonSale       StockMovementReason = "sale"
+	StockMovementReasonReturn     StockMovementReason = "return"
+	StockMovementReasonWriteOff   StockMovementReason = "write_off"
+	StockMovementReasonCorrection StockMovementReason = "correction"
+	StockMovementReasonTransfer   StockMovementReason = "transfer"
+)
+
+func (e *StockMovementReason) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = StockMovementReason(s)
+	case string:
+		*e = StockMovementReason(s)
+	default:
+		return fmt.Errorf("unsupported scan type for StockMovementReason: %T", src)
+	}
+	return nil
+}
+
+type NullStockMovementReason struct {
+	StockMovementReason StockMovementReason `json:"stock_movement_reason"`
+	Valid               bool                `json:"valid"` // Valid is true if StockMovementReason is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullStockMovementReason) Scan(value interface{}) error {
+	if value == nil {
+		ns.StockMovementReason, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.StockMovementReason.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullStockMovementReason) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.StockMovementReason), nil
+}
+
+type TransferStatus string
+
+const (
+	TransferStatusDispatched TransferStatus = "dispatched"
+	TransferStatusReceived   TransferStatus = "received"
+	TransferStatusCancelled  TransferStatus = "cancelled"
+)
+
+func (e *TransferStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TransferStatus(s)
+	case string:
+		*e = TransferStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TransferStatus: %T", src)
+	}
+	return nil
+}
+
+type NullTransferStatus struct {
+	TransferStatus TransferStatus `json:"transfer_status"`
+	Valid          bool           `json:"valid"` // Valid is true if TransferStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTransferStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.TransferStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TransferStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTransferStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TransferStatus), nil
+}
+
+type ApiKey struct {
+	ID          int64              `json:"id"`
+	Uuid        pgtype.UUID        `json:"uuid"`
+	Name        string             `json:"name"`
+	Prefix      string             `json:"prefix"`
+	SecretHash  string             `json:"secret_hash"`
+	Scopes      []string           `json:"scopes"`
+	StoreUuids  []pgtype.UUID      `json:"store_uuids"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt  pgtype.Timestamptz `json:"last_used_at"`
+	RevokedAt   pgtype.Timestamptz `json:"revoked_at"`
+	RotatedFrom pgtype.UUID        `json:"rotated_from"`
+	Actor       pgtype.Text        `json:"actor"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type AuditEvent struct {
+	ID         int64              `json:"id"`
+	EntityType string             `json:"entity_type"`
+	EntityID   string             `json:"entity_id"`
+	Action     string             `json:"action"`
+	Before     []byte             `json:"before"`
+	After      []byte             `json:"after"`
+	Actor      pgtype.Text        `json:"actor"`
+	RequestID  pgtype.Text        `json:"request_id"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type Author struct {
+	ID        int64              `json:"id"`
+	Name      string             `json:"name"`
+	Bio       pgtype.Text        `json:"bio"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Book struct {
 	ID              int64              `json:"id"`
 	Isbn            pgtype.Text        `json:"isbn"`
@@ -19,6 +407,153 @@ type Book struct {
 	CreatedAt       pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
 	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
+	PublisherID     pgtype.Int8        `json:"publisher_id"`
+	SeriesID        pgtype.Int8        `json:"series_id"`
+	SeriesVolume    pgtype.Int4        `json:"series_volume"`
+}
+
+type BookAuthor struct {
+	BookID   int64      `json:"book_id"`
+	AuthorID int64      `json:"author_id"`
+	Role     AuthorRole `json:"role"`
+	Position int32      `json:"position"`
+}
+
+type BookGenre struct {
+	BookID  int64 `json:"book_id"`
+	GenreID int64 `json:"genre_id"`
+}
+
+type BookTag struct {
+	BookID int64 `json:"book_id"`
+	TagID  int64 `json:"tag_id"`
+}
+
+type ExchangeRate struct {
+	BaseCurrency  string             `json:"base_currency"`
+	QuoteCurrency string             `json:"quote_currency"`
+	Rate          pgtype.Numeric     `json:"rate"`
+	Actor         pgtype.Text        `json:"actor"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Genre struct {
+	ID        int64              `json:"id"`
+	ParentID  pgtype.Int8        `json:"parent_id"`
+	Name      string             `json:"name"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type IdempotencyKey struct {
+	Actor           string             `json:"actor"`
+	Key             string             `json:"key"`
+	Method          string             `json:"method"`
+	Path            string             `json:"path"`
+	Fingerprint     string             `json:"fingerprint"`
+	StatusCode      pgtype.Int4        `json:"status_code"`
+	ResponseHeaders []byte             `json:"response_headers"`
+	ResponseBody    []byte             `json:"response_body"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	ExpiresAt       pgtype.Timestamptz `json:"expires_at"`
+}
+
+type Import struct {
+	ID            int64              `json:"id"`
+	Uuid          pgtype.UUID        `json:"uuid"`
+	Format        ImportFormat       `json:"format"`
+	DryRun        bool               `json:"dry_run"`
+	Upsert        bool               `json:"upsert"`
+	Status        ImportStatus       `json:"status"`
+	Attempt       int32              `json:"attempt"`
+	Payload       []byte             `json:"payload"`
+	TotalRows     int32              `json:"total_rows"`
+	ProcessedRows int32              `json:"processed_rows"`
+	FailedRows    int32              `json:"failed_rows"`
+	BooksCreated  int32              `json:"books_created"`
+	BooksUpdated  int32              `json:"books_updated"`
+	BooksExisting int32              `json:"books_existing"`
+	SkusCreated   int32              `json:"skus_created"`
+	Error         pgtype.Text        `json:"error"`
+	Actor         pgtype.Text        `json:"actor"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	StartedAt     pgtype.Timestamptz `json:"started_at"`
+	FinishedAt    pgtype.Timestamptz `json:"finished_at"`
+}
+
+type ImportError struct {
+	ImportID int64       `json:"import_id"`
+	Line     int32       `json:"line"`
+	Isbn     pgtype.Text `json:"isbn"`
+	Message  string      `json:"message"`
+}
+
+type Order struct {
+	ID            int64              `json:"id"`
+	Uuid          pgtype.UUID        `json:"uuid"`
+	Status        OrderStatus        `json:"status"`
+	CustomerName  string             `json:"customer_name"`
+	CustomerEmail pgtype.Text        `json:"customer_email"`
+	TotalInKopeks int64              `json:"total_in_kopeks"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	PaidAt        pgtype.Timestamptz `json:"paid_at"`
+	FulfilledAt   pgtype.Timestamptz `json:"fulfilled_at"`
+	CancelledAt   pgtype.Timestamptz `json:"cancelled_at"`
+	Currency      string             `json:"currency"`
+}
+
+type OrderItem struct {
+	ID            int64 `json:"id"`
+	OrderID       int64 `json:"order_id"`
+	SkuID         int64 `json:"sku_id"`
+	Quantity      int32 `json:"quantity"`
+	PriceInKopeks int32 `json:"price_in_kopeks"`
+}
+
+type Promotion struct {
+	ID                int64              `json:"id"`
+	Name              string             `json:"name"`
+	Kind              PromotionKind      `json:"kind"`
+	PercentOff        pgtype.Int4        `json:"percent_off"`
+	AmountOffInKopeks pgtype.Int4        `json:"amount_off_in_kopeks"`
+	BuyQuantity       pgtype.Int4        `json:"buy_quantity"`
+	FreeQuantity      pgtype.Int4        `json:"free_quantity"`
+	Priority          int32              `json:"priority"`
+	Stackable         bool               `json:"stackable"`
+	StartsAt          pgtype.Timestamptz `json:"starts_at"`
+	EndsAt            pgtype.Timestamptz `json:"ends_at"`
+	StoreID           pgtype.Int8        `json:"store_id"`
+	BookID            pgtype.Int8        `json:"book_id"`
+	AuthorID          pgtype.Int8        `json:"author_id"`
+	GenreID           pgtype.Int8        `json:"genre_id"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	Currency          pgtype.Text        `json:"currency"`
+}
+
+type Publisher struct {
+	ID        int64              `json:"id"`
+	Name      string             `json:"name"`
+	Website   pgtype.Text        `json:"website"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type RateLimitBucket struct {
+	Key       string             `json:"key"`
+	Tokens    float64            `json:"tokens"`
+	Allowed   bool               `json:"allowed"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Series struct {
+	ID          int64              `json:"id"`
+	Name        string             `json:"name"`
+	Description pgtype.Text        `json:"description"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
 }
 
 type Sku struct {
@@ -31,6 +566,49 @@ type Sku struct {
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
 	DeletedAt     pgtype.Timestamptz `json:"deleted_at"`
+	Version       int32              `json:"version"`
+	ReservedCount int32              `json:"reserved_count"`
+	Currency      string             `json:"currency"`
+}
+
+type SkuPrice struct {
+	ID            int64              `json:"id"`
+	Uuid          pgtype.UUID        `json:"uuid"`
+	SkuID         int64              `json:"sku_id"`
+	PriceInKopeks int32              `json:"price_in_kopeks"`
+	ScheduledFor  pgtype.Timestamptz `json:"scheduled_for"`
+	EffectiveFrom pgtype.Timestamptz `json:"effective_from"`
+	EffectiveTo   pgtype.Timestamptz `json:"effective_to"`
+	CancelledAt   pgtype.Timestamptz `json:"cancelled_at"`
+	Actor         pgtype.Text        `json:"actor"`
+	RequestID     pgtype.Text        `json:"request_id"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type SkuReservation struct {
+	ID        int64              `json:"id"`
+	Uuid      pgtype.UUID        `json:"uuid"`
+	SkuID     int64              `json:"sku_id"`
+	Quantity  int32              `json:"quantity"`
+	Status    ReservationStatus  `json:"status"`
+	Note      pgtype.Text        `json:"note"`
+	Actor     pgtype.Text        `json:"actor"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type StockMovement struct {
+	ID        int64               `json:"id"`
+	Uuid      pgtype.UUID         `json:"uuid"`
+	SkuID     int64               `json:"sku_id"`
+	Delta     int32               `json:"delta"`
+	Balance   int32               `json:"balance"`
+	Reason    StockMovementReason `json:"reason"`
+	Note      pgtype.Text         `json:"note"`
+	Actor     pgtype.Text         `json:"actor"`
+	RequestID pgtype.Text         `json:"request_id"`
+	CreatedAt pgtype.Timestamptz  `json:"created_at"`
 }
 
 type Store struct {
@@ -41,4 +619,27 @@ type Store struct {
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	Currency  string             `json:"currency"`
+}
+
+type Tag struct {
+	ID        int64              `json:"id"`
+	Name      string             `json:"name"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type Transfer struct {
+	ID               int64              `json:"id"`
+	Uuid             pgtype.UUID        `json:"uuid"`
+	BookID           int64              `json:"book_id"`
+	SourceSkuID      int64              `json:"source_sku_id"`
+	DestinationSkuID int64              `json:"destination_sku_id"`
+	Quantity         int32              `json:"quantity"`
+	Status           TransferStatus     `json:"status"`
+	Note             pgtype.Text        `json:"note"`
+	Actor            pgtype.Text        `json:"actor"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	ReceivedAt       pgtype.Timestamptz `json:"received_at"`
+	CancelledAt      pgtype.Timestamptz `json:"cancelled_at"`
 }
@@ -11,22 +11,210 @@ import (
 )
 
 type Querier interface {
+	ActivateSKUPrice(ctx context.Context, id int64) (SkuPrice, error)
+	AddBookAuthor(ctx context.Context, arg AddBookAuthorParams) error
+	AddBookGenres(ctx context.Context, arg AddBookGenresParams) error
+	AddBookTags(ctx context.Context, arg AddBookTagsParams) error
+	AddImportErrors(ctx context.Context, arg AddImportErrorsParams) error
+	AdjustSKUReserved(ctx context.Context, arg AdjustSKUReservedParams) (Sku, error)
 	AdjustSKUStock(ctx context.Context, arg AdjustSKUStockParams) (Sku, error)
+	// Must be the first statement of a transaction: all of its reads see the same snapshot.
+	BeginSnapshot(ctx context.Context) error
+	// Counts the books matching the filters, and the search query if given, per genre, author,
+	// publication decade and stock availability. Each facet keeps its facet_limit largest values.
+	// isbn is the query as a canonical ISBN-13, as in SearchBooks.
+	BookFacets(ctx context.Context, arg BookFacetsParams) ([]BookFacetsRow, error)
+	CancelSKUPrice(ctx context.Context, id int64) (SkuPrice, error)
+	// Takes the key of the actor for a new request. An expired key, or one whose request died
+	// before completing (claimed before stale_before), is taken over.
+	ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (IdempotencyKey, error)
+	// Takes the oldest pending import, or a running one whose worker stopped reporting progress.
+	ClaimImport(ctx context.Context, staleBefore pgtype.Timestamptz) (Import, error)
+	CloseCurrentSKUPrice(ctx context.Context, skuID int64) error
+	CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) error
+	CountDispatchedTransfersByBook(ctx context.Context, bookID int64) (int64, error)
+	CountStoresByUUIDs(ctx context.Context, storeUuids []pgtype.UUID) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) error
+	CreateAuthor(ctx context.Context, arg CreateAuthorParams) (Author, error)
 	CreateBook(ctx context.Context, arg CreateBookParams) (Book, error)
+	CreateGenre(ctx context.Context, arg CreateGenreParams) (Genre, error)
+	CreateImport(ctx context.Context, arg CreateImportParams) (CreateImportRow, error)
+	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
+	CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (OrderItem, error)
+	CreatePromotion(ctx context.Context, arg CreatePromotionParams) (Promotion, error)
+	CreatePublisher(ctx context.Context, arg CreatePublisherParams) (Publisher, error)
+	CreateReservation(ctx context.Context, arg CreateReservationParams) (SkuReservation, error)
 	CreateSKU(ctx context.Context, arg CreateSKUParams) (Sku, error)
+	CreateSKUPrice(ctx context.Context, arg CreateSKUPriceParams) (SkuPrice, error)
+	CreateSeries(ctx context.Context, arg CreateSeriesParams) (Series, error)
+	CreateStockMovement(ctx context.Context, arg CreateStockMovementParams) (StockMovement, error)
 	CreateStore(ctx context.Context, arg CreateStoreParams) (Store, error)
+	CreateTag(ctx context.Context, name string) (Tag, error)
+	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	DeleteAuthor(ctx context.Context, id int64) (int64, error)
+	DeleteBookAuthors(ctx context.Context, arg DeleteBookAuthorsParams) error
+	DeleteBookGenres(ctx context.Context, bookID int64) error
+	DeleteBookTags(ctx context.Context, bookID int64) error
+	DeleteExchangeRate(ctx context.Context, arg DeleteExchangeRateParams) (int64, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+	DeleteGenre(ctx context.Context, id int64) (int64, error)
+	DeleteIdleRateLimitBuckets(ctx context.Context, updatedAt pgtype.Timestamptz) (int64, error)
+	DeletePromotion(ctx context.Context, id int64) (int64, error)
+	DeletePublisher(ctx context.Context, id int64) (int64, error)
+	DeleteSeries(ctx context.Context, id int64) (int64, error)
+	DeleteTag(ctx context.Context, id int64) (int64, error)
+	// Shortens the life of a rotated key to the grace period; it never extends it.
+	ExpireAPIKey(ctx context.Context, arg ExpireAPIKeyParams) (ApiKey, error)
+	ExportBooks(ctx context.Context, arg ExportBooksParams) ([]ExportBooksRow, error)
+	ExportInventory(ctx context.Context, arg ExportInventoryParams) ([]ExportInventoryRow, error)
+	FindAuthorByName(ctx context.Context, lower string) (Author, error)
+	FinishImport(ctx context.Context, arg FinishImportParams) error
+	GetAPIKey(ctx context.Context, uuid pgtype.UUID) (ApiKey, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (ApiKey, error)
+	GetAPIKeyForUpdate(ctx context.Context, uuid pgtype.UUID) (ApiKey, error)
+	GetAuthorByID(ctx context.Context, id int64) (Author, error)
 	GetBookByID(ctx context.Context, id int64) (Book, error)
+	GetBookByIDForUpdate(ctx context.Context, id int64) (Book, error)
+	GetBookByISBN(ctx context.Context, isbn pgtype.Text) (Book, error)
+	// Soft-deleted books are included: an upsert brings them back.
+	GetBookByISBNForUpdate(ctx context.Context, isbn pgtype.Text) (Book, error)
+	GetDeletedBookByIDForUpdate(ctx context.Context, id int64) (Book, error)
+	GetExchangeRate(ctx context.Context, arg GetExchangeRateParams) (GetExchangeRateRow, error)
+	GetGenreByID(ctx context.Context, id int64) (Genre, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetImportByUUID(ctx context.Context, uuid pgtype.UUID) (GetImportByUUIDRow, error)
+	GetOrderByUUID(ctx context.Context, uuid pgtype.UUID) (Order, error)
+	GetOrderByUUIDForUpdate(ctx context.Context, uuid pgtype.UUID) (Order, error)
+	GetPromotionByID(ctx context.Context, id int64) (Promotion, error)
+	GetPublisherByID(ctx context.Context, id int64) (Publisher, error)
+	GetReservationByIDForUpdate(ctx context.Context, id int64) (SkuReservation, error)
+	GetReservationByUUID(ctx context.Context, uuid pgtype.UUID) (GetReservationByUUIDRow, error)
+	GetReservationStoreUUID(ctx context.Context, uuid pgtype.UUID) (pgtype.UUID, error)
 	GetSKUByBookAndStore(ctx context.Context, arg GetSKUByBookAndStoreParams) (Sku, error)
+	// Finds deleted SKUs too, so that work started before the deletion can be finished.
+	GetSKUByIDForUpdate(ctx context.Context, id int64) (Sku, error)
 	GetSKUByUUID(ctx context.Context, uuid pgtype.UUID) (GetSKUByUUIDRow, error)
+	GetSKUByUUIDForUpdate(ctx context.Context, uuid pgtype.UUID) (Sku, error)
+	GetSKUPriceByIDForUpdate(ctx context.Context, id int64) (SkuPrice, error)
+	GetSKUPriceByUUID(ctx context.Context, arg GetSKUPriceByUUIDParams) (SkuPrice, error)
+	GetSeriesByID(ctx context.Context, id int64) (Series, error)
 	GetStoreByUUID(ctx context.Context, uuid pgtype.UUID) (Store, error)
+	GetStoreByUUIDForUpdate(ctx context.Context, uuid pgtype.UUID) (Store, error)
+	GetTagByID(ctx context.Context, id int64) (Tag, error)
+	GetTransferByIDForUpdate(ctx context.Context, id int64) (Transfer, error)
+	GetTransferByUUID(ctx context.Context, uuid pgtype.UUID) (GetTransferByUUIDRow, error)
+	GetTransferStoreUUIDs(ctx context.Context, uuid pgtype.UUID) (GetTransferStoreUUIDsRow, error)
+	ListAPIKeys(ctx context.Context, includeInactive bool) ([]ApiKey, error)
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error)
+	ListAuditEventsDesc(ctx context.Context, arg ListAuditEventsDescParams) ([]AuditEvent, error)
+	ListAuthors(ctx context.Context, arg ListAuthorsParams) ([]Author, error)
+	ListAuthorsDesc(ctx context.Context, arg ListAuthorsDescParams) ([]Author, error)
+	ListBookAuthors(ctx context.Context, bookIds []int64) ([]ListBookAuthorsRow, error)
 	ListBookAvailability(ctx context.Context, bookID int64) ([]ListBookAvailabilityRow, error)
-	ListBooks(ctx context.Context) ([]Book, error)
-	ListSKUsInStore(ctx context.Context, storeID int64) ([]ListSKUsInStoreRow, error)
-	ListStores(ctx context.Context) ([]Store, error)
-	SearchBooks(ctx context.Context, query pgtype.Text) ([]Book, error)
-	SoftDeleteStore(ctx context.Context, uuid pgtype.UUID) error
+	// The genres of the books together with all of their ancestors.
+	ListBookGenreLineage(ctx context.Context, bookIds []int64) ([]ListBookGenreLineageRow, error)
+	ListBookGenres(ctx context.Context, bookIds []int64) ([]ListBookGenresRow, error)
+	ListBookTagIDs(ctx context.Context, bookID int64) ([]int64, error)
+	ListBookTags(ctx context.Context, bookIds []int64) ([]ListBookTagsRow, error)
+	ListBooksByAuthor(ctx context.Context, arg ListBooksByAuthorParams) ([]Book, error)
+	ListBooksByAuthorDesc(ctx context.Context, arg ListBooksByAuthorDescParams) ([]Book, error)
+	ListBooksByAuthorName(ctx context.Context, arg ListBooksByAuthorNameParams) ([]Book, error)
+	ListBooksByAuthorNameDesc(ctx context.Context, arg ListBooksByAuthorNameDescParams) ([]Book, error)
+	ListBooksByCreatedAt(ctx context.Context, arg ListBooksByCreatedAtParams) ([]Book, error)
+	ListBooksByCreatedAtDesc(ctx context.Context, arg ListBooksByCreatedAtDescParams) ([]Book, error)
+	ListBooksByPublicationYear(ctx context.Context, arg ListBooksByPublicationYearParams) ([]Book, error)
+	ListBooksByPublicationYearDesc(ctx context.Context, arg ListBooksByPublicationYearDescParams) ([]Book, error)
+	ListBooksBySeriesVolume(ctx context.Context, arg ListBooksBySeriesVolumeParams) ([]Book, error)
+	ListBooksBySeriesVolumeDesc(ctx context.Context, arg ListBooksBySeriesVolumeDescParams) ([]Book, error)
+	ListBooksByTitle(ctx context.Context, arg ListBooksByTitleParams) ([]Book, error)
+	ListBooksByTitleDesc(ctx context.Context, arg ListBooksByTitleDescParams) ([]Book, error)
+	// Promotions running at the given moment whose every scope is among the given ones;
+	// whether a promotion matches a particular SKU is decided by the caller.
+	ListCandidatePromotions(ctx context.Context, arg ListCandidatePromotionsParams) ([]Promotion, error)
+	ListDueSKUPrices(ctx context.Context, limit int32) ([]ListDueSKUPricesRow, error)
+	ListExchangeRates(ctx context.Context) ([]ListExchangeRatesRow, error)
+	// Holds on deleted SKUs expire too, or their copies would stay reserved after a restore.
+	ListExpiredReservations(ctx context.Context, limit int32) ([]ListExpiredReservationsRow, error)
+	// The genre itself and all of its descendants.
+	ListGenreSubtreeIDs(ctx context.Context, id int64) ([]int64, error)
+	ListGenres(ctx context.Context) ([]Genre, error)
+	ListImportErrors(ctx context.Context, importID int64) ([]ListImportErrorsRow, error)
+	ListOrderItems(ctx context.Context, orderID int64) ([]ListOrderItemsRow, error)
+	ListOrderSKUQuantities(ctx context.Context, orderID int64) ([]ListOrderSKUQuantitiesRow, error)
+	ListOrderStoreUUIDs(ctx context.Context, uuid pgtype.UUID) ([]pgtype.UUID, error)
+	ListOrders(ctx context.Context, arg ListOrdersParams) ([]Order, error)
+	ListOrdersDesc(ctx context.Context, arg ListOrdersDescParams) ([]Order, error)
+	ListPromotions(ctx context.Context, arg ListPromotionsParams) ([]Promotion, error)
+	ListPromotionsDesc(ctx context.Context, arg ListPromotionsDescParams) ([]Promotion, error)
+	ListPublishers(ctx context.Context, arg ListPublishersParams) ([]Publisher, error)
+	ListPublishersByIDs(ctx context.Context, ids []int64) ([]Publisher, error)
+	ListPublishersDesc(ctx context.Context, arg ListPublishersDescParams) ([]Publisher, error)
+	ListSKUPrices(ctx context.Context, arg ListSKUPricesParams) ([]SkuPrice, error)
+	ListSKUPricesDesc(ctx context.Context, arg ListSKUPricesDescParams) ([]SkuPrice, error)
+	// Stores touched by a request, resolved for store-scoped authorization. Soft-deleted rows
+	// are included: whether the request may go on is decided by its handler.
+	ListSKUStoreUUIDs(ctx context.Context, skuUuids []pgtype.UUID) ([]ListSKUStoreUUIDsRow, error)
+	ListSKUsInStoreByPrice(ctx context.Context, arg ListSKUsInStoreByPriceParams) ([]ListSKUsInStoreByPriceRow, error)
+	ListSKUsInStoreByPriceDesc(ctx context.Context, arg ListSKUsInStoreByPriceDescParams) ([]ListSKUsInStoreByPriceDescRow, error)
+	ListSKUsInStoreByStock(ctx context.Context, arg ListSKUsInStoreByStockParams) ([]ListSKUsInStoreByStockRow, error)
+	ListSKUsInStoreByStockDesc(ctx context.Context, arg ListSKUsInStoreByStockDescParams) ([]ListSKUsInStoreByStockDescRow, error)
+	// Titles live in books, so sorting by title sorts all SKUs of the store.
+	ListSKUsInStoreByTitle(ctx context.Context, arg ListSKUsInStoreByTitleParams) ([]ListSKUsInStoreByTitleRow, error)
+	ListSKUsInStoreByTitleDesc(ctx context.Context, arg ListSKUsInStoreByTitleDescParams) ([]ListSKUsInStoreByTitleDescRow, error)
+	ListSeries(ctx context.Context, arg ListSeriesParams) ([]Series, error)
+	ListSeriesByIDs(ctx context.Context, ids []int64) ([]Series, error)
+	ListSeriesDesc(ctx context.Context, arg ListSeriesDescParams) ([]Series, error)
+	ListStockMovements(ctx context.Context, arg ListStockMovementsParams) ([]StockMovement, error)
+	ListStockMovementsDesc(ctx context.Context, arg ListStockMovementsDescParams) ([]StockMovement, error)
+	ListStoresByCreatedAt(ctx context.Context, arg ListStoresByCreatedAtParams) ([]Store, error)
+	ListStoresByCreatedAtDesc(ctx context.Context, arg ListStoresByCreatedAtDescParams) ([]Store, error)
+	ListStoresByName(ctx context.Context, arg ListStoresByNameParams) ([]Store, error)
+	ListStoresByNameDesc(ctx context.Context, arg ListStoresByNameDescParams) ([]Store, error)
+	ListTags(ctx context.Context, arg ListTagsParams) ([]ListTagsRow, error)
+	ListTagsDesc(ctx context.Context, arg ListTagsDescParams) ([]ListTagsDescRow, error)
+	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]ListTransfersRow, error)
+	ListTransfersDesc(ctx context.Context, arg ListTransfersDescParams) ([]ListTransfersDescRow, error)
+	// Locks the live SKUs of a book in UUID order, the order every stock change takes them in.
+	LockBookSKUs(ctx context.Context, bookID int64) ([]Sku, error)
+	RecordImportProgress(ctx context.Context, arg RecordImportProgressParams) (int64, error)
+	// Recomputes the legacy books.author string from the linked authors of the given
+	// books, or of every book of the given author.
+	RefreshBookAuthorNames(ctx context.Context, arg RefreshBookAuthorNamesParams) error
+	ReleaseIdempotencyKey(ctx context.Context, arg ReleaseIdempotencyKeyParams) error
+	RequeueImport(ctx context.Context, arg RequeueImportParams) error
+	RestoreBook(ctx context.Context, id int64) (Book, error)
+	RestoreSKUsByBook(ctx context.Context, arg RestoreSKUsByBookParams) ([]Sku, error)
+	RevokeAPIKey(ctx context.Context, uuid pgtype.UUID) (ApiKey, error)
+	// isbn is the query as a canonical ISBN-13, NULL if it isn't an ISBN. Snippets are highlighted
+	// with the dictionary of the language the query is written in.
+	SearchBooks(ctx context.Context, arg SearchBooksParams) ([]SearchBooksRow, error)
+	SoftDeleteBook(ctx context.Context, id int64) (Book, error)
+	SoftDeleteSKUsByBook(ctx context.Context, arg SoftDeleteSKUsByBookParams) ([]Sku, error)
+	SoftDeleteStore(ctx context.Context, uuid pgtype.UUID) (Store, error)
+	// Refills the bucket for the time since it was last used and takes a token when a whole one is there;
+	// allowed tells whether it did. A new bucket starts full.
+	TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (TakeRateLimitTokenRow, error)
+	// Records a use of the key at most once a minute, so busy clients don't write on every request.
+	TouchAPIKey(ctx context.Context, id int64) error
+	UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (Author, error)
+	UpdateBook(ctx context.Context, arg UpdateBookParams) (Book, error)
+	UpdateGenre(ctx context.Context, arg UpdateGenreParams) (Genre, error)
+	UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (Order, error)
+	UpdatePromotion(ctx context.Context, arg UpdatePromotionParams) (Promotion, error)
+	UpdatePublisher(ctx context.Context, arg UpdatePublisherParams) (Publisher, error)
+	UpdateReservationStatus(ctx context.Context, arg UpdateReservationStatusParams) (SkuReservation, error)
 	UpdateSKUPrice(ctx context.Context, arg UpdateSKUPriceParams) (Sku, error)
+	UpdateSeries(ctx context.Context, arg UpdateSeriesParams) (Series, error)
 	UpdateStore(ctx context.Context, arg UpdateStoreParams) (Store, error)
+	UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error)
+	UpdateTransferStatus(ctx context.Context, arg UpdateTransferStatusParams) (Transfer, error)
+	// Optional fields missing from the request keep their stored value unless replace is set.
+	// An upsert also brings back a soft-deleted book with the same ISBN.
+	UpsertBook(ctx context.Context, arg UpsertBookParams) (UpsertBookRow, error)
+	UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) (UpsertExchangeRateRow, error)
+	// Returns the tags with the given names, creating the missing ones.
+	UpsertTags(ctx context.Context, names []string) ([]Tag, error)
 }
 
 var _ Querier = (*Queries)(nil)
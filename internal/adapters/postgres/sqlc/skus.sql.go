@@ -14,9 +14,11 @@ import (
 const adjustSKUStock = `-- name: AdjustSKUStock :one
 UPDATE skus
 SET stock_count = stock_count + $2,
+    version     = version + 1,
     updated_at  = now()
 WHERE uuid = $1
-RETURNING id, uuid, book_id, store_id, price_in_kopeks, stock_count, created_at, updated_at, deleted_at
+  AND stock_count + $2 >= reserved_count
+RETURNING id, uuid, book_id, store_id, price_in_kopeks, stock_count, created_at, updated_at, deleted_at, version, reserved_count, currency
 `
 
 type AdjustSKUStockParams struct {
@@ -37,21 +39,25 @@ func (q *Queries) AdjustSKUStock(ctx context.Context, arg AdjustSKUStockParams)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Version,
+		&i.ReservedCount,
+		&i.Currency,
 	)
 	return i, err
 }
 
 const createSKU = `-- name: CreateSKU :one
-INSERT INTO skus (book_id, store_id, price_in_kopeks, stock_count)
-VALUES ($1, $2, $3, $4)
-RETURNING id, uuid, book_id, store_id, price_in_kopeks, stock_count, created_at, updated_at, deleted_at
+INSERT INTO skus (book_id, store_id, price_in_kopeks, stock_count, currency)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, uuid, book_id, store_id, price_in_kopeks, stock_count, created_at, updated_at, deleted_at, version, reserved_count, currency
 `
 
 type CreateSKUParams struct {
-	BookID        int64 `json:"book_id"`
-	StoreID       int64 `json:"store_id"`
-	PriceInKopeks int32 `json:"price_in_kopeks"`
-	StockCount    int32 `json:"stock_count"`
+	BookID        int64  `json:"book_id"`
+	StoreID       int64  `json:"store_id"`
+	PriceInKopeks int32  `json:"price_in_kopeks"`
+	StockCount    int32  `json:"stock_count"`
+	Currency      string `json:"currency"`
 }
 
 func (q *Queries) CreateSKU(ctx context.Context, arg CreateSKUParams) (Sku, error) {
@@ -60,6 +66,7 @@ func (q *Queries) CreateSKU(ctx context.Context, arg CreateSKUParams) (Sku, erro
 		arg.StoreID,
 		arg.PriceInKopeks,
 		arg.StockCount,
+		arg.Currency,
 	)
 	var i Sku
 	err := row.Scan(
@@ -72,12 +79,15 @@ func (q *Queries) CreateSKU(ctx context.Context, arg CreateSKUParams) (Sku, erro
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Version,
+		&i.ReservedCount,
+		&i.Currency,
 	)
 	return i, err
 }
 
 const getSKUByBookAndStore = `-- name: GetSKUByBookAndStore :one
-SELECT id, uuid, book_id, store_id, price_in_kopeks, stock_count, created_at, updated_at, deleted_at
+SELECT id, uuid, book_id, store_id, price_in_kopeks, stock_count, created_at, updated_at, deleted_at, version, reserved_count, currency
 FROM skus
 WHERE book_id = $1
   AND store_id = $2
@@ -102,12 +112,43 @@ func (q *Queries) GetSKUByBookAndStore(ctx context.Context, arg GetSKUByBookAndS
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Version,
+		&i.ReservedCount,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const getSKUByIDForUpdate = `-- name: GetSKUByIDForUpdate :one
+SELECT id, uuid, book_id, store_id, price_in_kopeks, stock_count, created_at, updated_at, deleted_at, version, reserved_count, currency
+FROM skus
+WHERE id = $1
+    FOR UPDATE
+`
+
+// Finds deleted SKUs too, so that work started before the deletion can be finished.
+func (q *Queries) GetSKUByIDForUpdate(ctx context.Context, id int64) (Sku, error) {
+	row := q.db.QueryRow(ctx, getSKUByIDForUpdate, id)
+	var i Sku
+	err := row.Scan(
+		&i.ID,
+		&i.Uuid,
+		&i.BookID,
+		&i.StoreID,
+		&i.PriceInKopeks,
+		&i.StockCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
+		&i.ReservedCount,
+		&i.Currency,
 	)
 	return i, err
 }
 
 const getSKUByUUID = `-- name: GetSKUByUUID :one
-SELECT s.id, s.uuid, s.book_id, s.store_id, s.price_in_kopeks, s.stock_count, s.created_at, s.updated_at, s.deleted_at, b.id, b.isbn, b.title, b.author, b.description, b.page_count, b.publication_year, b.created_at, b.updated_at, b.deleted_at
+SELECT s.id, s.uuid, s.book_id, s.store_id, s.price_in_kopeks, s.stock_count, s.created_at, s.updated_at, s.deleted_at, s.version, s.reserved_count, s.currency, b.id, b.isbn, b.title, b.author, b.description, b.page_count, b.publication_year, b.created_at, b.updated_at, b.deleted_at, b.publisher_id, b.series_id, b.series_volume
 FROM skus s
          JOIN books b ON s.book_id = b.id
 WHERE s.uuid = $1
@@ -132,6 +173,9 @@ func (q *Queries) GetSKUByUUID(ctx context.Context, uuid pgtype.UUID) (GetSKUByU
 		&i.Sku.CreatedAt,
 		&i.Sku.UpdatedAt,
 		&i.Sku.DeletedAt,
+		&i.Sku.Version,
+		&i.Sku.ReservedCount,
+		&i.Sku.Currency,
 		&i.Book.ID,
 		&i.Book.Isbn,
 		&i.Book.Title,
@@ -142,12 +186,43 @@ func (q *Queries) GetSKUByUUID(ctx context.Context, uuid pgtype.UUID) (GetSKUByU
 		&i.Book.CreatedAt,
 		&i.Book.UpdatedAt,
 		&i.Book.DeletedAt,
+		&i.Book.PublisherID,
+		&i.Book.SeriesID,
+		&i.Book.SeriesVolume,
+	)
+	return i, err
+}
+
+const getSKUByUUIDForUpdate = `-- name: GetSKUByUUIDForUpdate :one
+SELECT id, uuid, book_id, store_id, price_in_kopeks, stock_count, created_at, updated_at, deleted_at, version, reserved_count, currency
+FROM skus
+WHERE uuid = $1
+  AND deleted_at IS NULL
+    FOR UPDATE
+`
+
+func (q *Queries) GetSKUByUUIDForUpdate(ctx context.Context, uuid pgtype.UUID) (Sku, error) {
+	row := q.db.QueryRow(ctx, getSKUByUUIDForUpdate, uuid)
+	var i Sku
+	err := row.Scan(
+		&i.ID,
+		&i.Uuid,
+		&i.BookID,
+		&i.StoreID,
+		&i.PriceInKopeks,
+		&i.StockCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
+		&i.ReservedCount,
+		&i.Currency,
 	)
 	return i, err
 }
 
 const listBookAvailability = `-- name: ListBookAvailability :many
-SELECT s.id, s.uuid, s.book_id, s.store_id, s.price_in_kopeks, s.stock_count, s.created_at, s.updated_at, s.deleted_at, st.id, st.uuid, st.name, st.address, st.created_at, st.updated_at, st.deleted_at
+SELECT s.id, s.uuid, s.book_id, s.store_id, s.price_in_kopeks, s.stock_count, s.created_at, s.updated_at, s.deleted_at, s.version, s.reserved_count, s.currency, st.id, st.uuid, st.name, st.address, st.created_at, st.updated_at, st.deleted_at, st.currency
 FROM skus s
          JOIN stores st ON s.store_id = st.id
 WHERE s.book_id = $1
@@ -179,6 +254,9 @@ func (q *Queries) ListBookAvailability(ctx context.Context, bookID int64) ([]Lis
 			&i.Sku.CreatedAt,
 			&i.Sku.UpdatedAt,
 			&i.Sku.DeletedAt,
+			&i.Sku.Version,
+			&i.Sku.ReservedCount,
+			&i.Sku.Currency,
 			&i.Store.ID,
 			&i.Store.Uuid,
 			&i.Store.Name,
@@ -186,6 +264,448 @@ func (q *Queries) ListBookAvailability(ctx context.Context, bookID int64) ([]Lis
 			&i.Store.CreatedAt,
 			&i.Store.UpdatedAt,
 			&i.Store.DeletedAt,
+			&i.Store.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSKUsInStoreByPrice = `-- name: ListSKUsInStoreByPrice :many
+SELECT s.id, s.uuid, s.book_id, s.store_id, s.price_in_kopeks, s.stock_count, s.created_at, s.updated_at, s.deleted_at, s.version, s.reserved_count, s.currency, b.id, b.isbn, b.title, b.author, b.description, b.page_count, b.publication_year, b.created_at, b.updated_at, b.deleted_at, b.publisher_id, b.series_id, b.series_volume
+FROM skus s
+         JOIN books b ON s.book_id = b.id
+WHERE s.store_id = $1
+  AND s.deleted_at IS NULL
+  AND b.deleted_at IS NULL
+  AND (NOT $2::bool OR s.stock_count > s.reserved_count)
+  AND ($3::int IS NULL OR s.price_in_kopeks >= $3::int)
+  AND ($4::int IS NULL OR s.price_in_kopeks <= $4::int)
+  AND ($5::text IS NULL OR b.title ILIKE '%' || $5::text || '%')
+  AND ($6::bigint IS NULL
+    OR (s.price_in_kopeks, s.id) > ($7::int, $6::bigint))
+ORDER BY s.price_in_kopeks, s.id
+LIMIT $8
+`
+
+type ListSKUsInStoreByPriceParams struct {
+	StoreID     int64       `json:"store_id"`
+	InStockOnly bool        `json:"in_stock_only"`
+	MinPrice    pgtype.Int4 `json:"min_price"`
+	MaxPrice    pgtype.Int4 `json:"max_price"`
+	Title       pgtype.Text `json:"title"`
+	CursorID    pgtype.Int8 `json:"cursor_id"`
+	CursorInt   pgtype.Int4 `json:"cursor_int"`
+	PageLimit   int32       `json:"page_limit"`
+}
+
+type ListSKUsInStoreByPriceRow struct {
+	Sku  Sku  `json:"sku"`
+	Book Book `json:"book"`
+}
+
+func (q *Queries) ListSKUsInStoreByPrice(ctx context.Context, arg ListSKUsInStoreByPriceParams) ([]ListSKUsInStoreByPriceRow, error) {
+	rows, err := q.db.Query(ctx, listSKUsInStoreByPrice,
+		arg.StoreID,
+		arg.InStockOnly,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.Title,
+		arg.CursorID,
+		arg.CursorInt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSKUsInStoreByPriceRow
+	for rows.Next() {
+		var i ListSKUsInStoreByPriceRow
+		if err := rows.Scan(
+			&i.Sku.ID,
+			&i.Sku.Uuid,
+			&i.Sku.BookID,
+			&i.Sku.StoreID,
+			&i.Sku.PriceInKopeks,
+			&i.Sku.StockCount,
+			&i.Sku.CreatedAt,
+			&i.Sku.UpdatedAt,
+			&i.Sku.DeletedAt,
+			&i.Sku.Version,
+			&i.Sku.ReservedCount,
+			&i.Sku.Currency,
+			&i.Book.ID,
+			&i.Book.Isbn,
+			&i.Book.Title,
+			&i.Book.Author,
+			&i.Book.Description,
+			&i.Book.PageCount,
+			&i.Book.PublicationYear,
+			&i.Book.CreatedAt,
+			&i.Book.UpdatedAt,
+			&i.Book.DeletedAt,
+			&i.Book.PublisherID,
+			&i.Book.SeriesID,
+			&i.Book.SeriesVolume,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSKUsInStoreByPriceDesc = `-- name: ListSKUsInStoreByPriceDesc :many
+SELECT s.id, s.uuid, s.book_id, s.store_id, s.price_in_kopeks, s.stock_count, s.created_at, s.updated_at, s.deleted_at, s.version, s.reserved_count, s.currency, b.id, b.isbn, b.title, b.author, b.description, b.page_count, b.publication_year, b.created_at, b.updated_at, b.deleted_at, b.publisher_id, b.series_id, b.series_volume
+FROM skus s
+         JOIN books b ON s.book_id = b.id
+WHERE s.store_id = $1
+  AND s.deleted_at IS NULL
+  AND b.deleted_at IS NULL
+  AND (NOT $2::bool OR s.stock_count > s.reserved_count)
+  AND ($3::int IS NULL OR s.price_in_kopeks >= $3::int)
+  AND ($4::int IS NULL OR s.price_in_kopeks <= $4::int)
+  AND ($5::text IS NULL OR b.title ILIKE '%' || $5::text || '%')
+  AND ($6::bigint IS NULL
+    OR (s.price_in_kopeks, s.id) < ($7::int, $6::bigint))
+ORDER BY s.price_in_kopeks DESC, s.id DESC
+LIMIT $8
+`
+
+type ListSKUsInStoreByPriceDescParams struct {
+	StoreID     int64       `json:"store_id"`
+	InStockOnly bool        `json:"in_stock_only"`
+	MinPrice    pgtype.Int4 `json:"min_price"`
+	MaxPrice    pgtype.Int4 `json:"max_price"`
+	Title       pgtype.Text `json:"title"`
+	CursorID    pgtype.Int8 `json:"cursor_id"`
+	CursorInt   pgtype.Int4 `json:"cursor_int"`
+	PageLimit   int32       `json:"page_limit"`
+}
+
+type ListSKUsInStoreByPriceDescRow struct {
+	Sku  Sku  `json:"sku"`
+	Book Book `json:"book"`
+}
+
+func (q *Queries) ListSKUsInStoreByPriceDesc(ctx context.Context, arg ListSKUsInStoreByPriceDescParams) ([]ListSKUsInStoreByPriceDescRow, error) {
+	rows, err := q.db.Query(ctx, listSKUsInStoreByPriceDesc,
+		arg.StoreID,
+		arg.InStockOnly,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.Title,
+		arg.CursorID,
+		arg.CursorInt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSKUsInStoreByPriceDescRow
+	for rows.Next() {
+		var i ListSKUsInStoreByPriceDescRow
+		if err := rows.Scan(
+			&i.Sku.ID,
+			&i.Sku.Uuid,
+			&i.Sku.BookID,
+			&i.Sku.StoreID,
+			&i.Sku.PriceInKopeks,
+			&i.Sku.StockCount,
+			&i.Sku.CreatedAt,
+			&i.Sku.UpdatedAt,
+			&i.Sku.DeletedAt,
+			&i.Sku.Version,
+			&i.Sku.ReservedCount,
+			&i.Sku.Currency,
+			&i.Book.ID,
+			&i.Book.Isbn,
+			&i.Book.Title,
+			&i.Book.Author,
+			&i.Book.Description,
+			&i.Book.PageCount,
+			&i.Book.PublicationYear,
+			&i.Book.CreatedAt,
+			&i.Book.UpdatedAt,
+			&i.Book.DeletedAt,
+			&i.Book.PublisherID,
+			&i.Book.SeriesID,
+			&i.Book.SeriesVolume,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSKUsInStoreByStock = `-- name: ListSKUsInStoreByStock :many
+SELECT s.id, s.uuid, s.book_id, s.store_id, s.price_in_kopeks, s.stock_count, s.created_at, s.updated_at, s.deleted_at, s.version, s.reserved_count, s.currency, b.id, b.isbn, b.title, b.author, b.description, b.page_count, b.publication_year, b.created_at, b.updated_at, b.deleted_at, b.publisher_id, b.series_id, b.series_volume
+FROM skus s
+         JOIN books b ON s.book_id = b.id
+WHERE s.store_id = $1
+  AND s.deleted_at IS NULL
+  AND b.deleted_at IS NULL
+  AND (NOT $2::bool OR s.stock_count > s.reserved_count)
+  AND ($3::int IS NULL OR s.price_in_kopeks >= $3::int)
+  AND ($4::int IS NULL OR s.price_in_kopeks <= $4::int)
+  AND ($5::text IS NULL OR b.title ILIKE '%' || $5::text || '%')
+  AND ($6::bigint IS NULL
+    OR (s.stock_count, s.id) > ($7::int, $6::bigint))
+ORDER BY s.stock_count, s.id
+LIMIT $8
+`
+
+type ListSKUsInStoreByStockParams struct {
+	StoreID     int64       `json:"store_id"`
+	InStockOnly bool        `json:"in_stock_only"`
+	MinPrice    pgtype.Int4 `json:"min_price"`
+	MaxPrice    pgtype.Int4 `json:"max_price"`
+	Title       pgtype.Text `json:"title"`
+	CursorID    pgtype.Int8 `json:"cursor_id"`
+	CursorInt   pgtype.Int4 `json:"cursor_int"`
+	PageLimit   int32       `json:"page_limit"`
+}
+
+type ListSKUsInStoreByStockRow struct {
+	Sku  Sku  `json:"sku"`
+	Book Book `json:"book"`
+}
+
+func (q *Queries) ListSKUsInStoreByStock(ctx context.Context, arg ListSKUsInStoreByStockParams) ([]ListSKUsInStoreByStockRow, error) {
+	rows, err := q.db.Query(ctx, listSKUsInStoreByStock,
+		arg.StoreID,
+		arg.InStockOnly,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.Title,
+		arg.CursorID,
+		arg.CursorInt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSKUsInStoreByStockRow
+	for rows.Next() {
+		var i ListSKUsInStoreByStockRow
+		if err := rows.Scan(
+			&i.Sku.ID,
+			&i.Sku.Uuid,
+			&i.Sku.BookID,
+			&i.Sku.StoreID,
+			&i.Sku.PriceInKopeks,
+			&i.Sku.StockCount,
+			&i.Sku.CreatedAt,
+			&i.Sku.UpdatedAt,
+			&i.Sku.DeletedAt,
+			&i.Sku.Version,
+			&i.Sku.ReservedCount,
+			&i.Sku.Currency,
+			&i.Book.ID,
+			&i.Book.Isbn,
+			&i.Book.Title,
+			&i.Book.Author,
+			&i.Book.Description,
+			&i.Book.PageCount,
+			&i.Book.PublicationYear,
+			&i.Book.CreatedAt,
+			&i.Book.UpdatedAt,
+			&i.Book.DeletedAt,
+			&i.Book.PublisherID,
+			&i.Book.SeriesID,
+			&i.Book.SeriesVolume,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSKUsInStoreByStockDesc = `-- name: ListSKUsInStoreByStockDesc :many
+SELECT s.id, s.uuid, s.book_id, s.store_id, s.price_in_kopeks, s.stock_count, s.created_at, s.updated_at, s.deleted_at, s.version, s.reserved_count, s.currency, b.id, b.isbn, b.title, b.author, b.description, b.page_count, b.publication_year, b.created_at, b.updated_at, b.deleted_at, b.publisher_id, b.series_id, b.series_volume
+FROM skus s
+         JOIN books b ON s.book_id = b.id
+WHERE s.store_id = $1
+  AND s.deleted_at IS NULL
+  AND b.deleted_at IS NULL
+  AND (NOT $2::bool OR s.stock_count > s.reserved_count)
+  AND ($3::int IS NULL OR s.price_in_kopeks >= $3::int)
+  AND ($4::int IS NULL OR s.price_in_kopeks <= $4::int)
+  AND ($5::text IS NULL OR b.title ILIKE '%' || $5::text || '%')
+  AND ($6::bigint IS NULL
+    OR (s.stock_count, s.id) < ($7::int, $6::bigint))
+ORDER BY s.stock_count DESC, s.id DESC
+LIMIT $8
+`
+
+type ListSKUsInStoreByStockDescParams struct {
+	StoreID     int64       `json:"store_id"`
+	InStockOnly bool        `json:"in_stock_only"`
+	MinPrice    pgtype.Int4 `json:"min_price"`
+	MaxPrice    pgtype.Int4 `json:"max_price"`
+	Title       pgtype.Text `json:"title"`
+	CursorID    pgtype.Int8 `json:"cursor_id"`
+	CursorInt   pgtype.Int4 `json:"cursor_int"`
+	PageLimit   int32       `json:"page_limit"`
+}
+
+type ListSKUsInStoreByStockDescRow struct {
+	Sku  Sku  `json:"sku"`
+	Book Book `json:"book"`
+}
+
+func (q *Queries) ListSKUsInStoreByStockDesc(ctx context.Context, arg ListSKUsInStoreByStockDescParams) ([]ListSKUsInStoreByStockDescRow, error) {
+	rows, err := q.db.Query(ctx, listSKUsInStoreByStockDesc,
+		arg.StoreID,
+		arg.InStockOnly,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.Title,
+		arg.CursorID,
+		arg.CursorInt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSKUsInStoreByStockDescRow
+	for rows.Next() {
+		var i ListSKUsInStoreByStockDescRow
+		if err := rows.Scan(
+			&i.Sku.ID,
+			&i.Sku.Uuid,
+			&i.Sku.BookID,
+			&i.Sku.StoreID,
+			&i.Sku.PriceInKopeks,
+			&i.Sku.StockCount,
+			&i.Sku.CreatedAt,
+			&i.Sku.UpdatedAt,
+			&i.Sku.DeletedAt,
+			&i.Sku.Version,
+			&i.Sku.ReservedCount,
+			&i.Sku.Currency,
+			&i.Book.ID,
+			&i.Book.Isbn,
+			&i.Book.Title,
+			&i.Book.Author,
+			&i.Book.Description,
+			&i.Book.PageCount,
+			&i.Book.PublicationYear,
+			&i.Book.CreatedAt,
+			&i.Book.UpdatedAt,
+			&i.Book.DeletedAt,
+			&i.Book.PublisherID,
+			&i.Book.SeriesID,
+			&i.Book.SeriesVolume,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSKUsInStoreByTitle = `-- name: ListSKUsInStoreByTitle :many
+SELECT s.id, s.uuid, s.book_id, s.store_id, s.price_in_kopeks, s.stock_count, s.created_at, s.updated_at, s.deleted_at, s.version, s.reserved_count, s.currency, b.id, b.isbn, b.title, b.author, b.description, b.page_count, b.publication_year, b.created_at, b.updated_at, b.deleted_at, b.publisher_id, b.series_id, b.series_volume
+FROM skus s
+         JOIN books b ON s.book_id = b.id
+WHERE s.store_id = $1
+  AND s.deleted_at IS NULL
+  AND b.deleted_at IS NULL
+  AND (NOT $2::bool OR s.stock_count > s.reserved_count)
+  AND ($3::int IS NULL OR s.price_in_kopeks >= $3::int)
+  AND ($4::int IS NULL OR s.price_in_kopeks <= $4::int)
+  AND ($5::text IS NULL OR b.title ILIKE '%' || $5::text || '%')
+  AND ($6::bigint IS NULL
+    OR (b.title, s.id) > ($7::text, $6::bigint))
+ORDER BY b.title, s.id
+LIMIT $8
+`
+
+type ListSKUsInStoreByTitleParams struct {
+	StoreID     int64       `json:"store_id"`
+	InStockOnly bool        `json:"in_stock_only"`
+	MinPrice    pgtype.Int4 `json:"min_price"`
+	MaxPrice    pgtype.Int4 `json:"max_price"`
+	Title       pgtype.Text `json:"title"`
+	CursorID    pgtype.Int8 `json:"cursor_id"`
+	CursorText  pgtype.Text `json:"cursor_text"`
+	PageLimit   int32       `json:"page_limit"`
+}
+
+type ListSKUsInStoreByTitleRow struct {
+	Sku  Sku  `json:"sku"`
+	Book Book `json:"book"`
+}
+
+// Titles live in books, so sorting by title sorts all SKUs of the store.
+func (q *Queries) ListSKUsInStoreByTitle(ctx context.Context, arg ListSKUsInStoreByTitleParams) ([]ListSKUsInStoreByTitleRow, error) {
+	rows, err := q.db.Query(ctx, listSKUsInStoreByTitle,
+		arg.StoreID,
+		arg.InStockOnly,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.Title,
+		arg.CursorID,
+		arg.CursorText,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSKUsInStoreByTitleRow
+	for rows.Next() {
+		var i ListSKUsInStoreByTitleRow
+		if err := rows.Scan(
+			&i.Sku.ID,
+			&i.Sku.Uuid,
+			&i.Sku.BookID,
+			&i.Sku.StoreID,
+			&i.Sku.PriceInKopeks,
+			&i.Sku.StockCount,
+			&i.Sku.CreatedAt,
+			&i.Sku.UpdatedAt,
+			&i.Sku.DeletedAt,
+			&i.Sku.Version,
+			&i.Sku.ReservedCount,
+			&i.Sku.Currency,
+			&i.Book.ID,
+			&i.Book.Isbn,
+			&i.Book.Title,
+			&i.Book.Author,
+			&i.Book.Description,
+			&i.Book.PageCount,
+			&i.Book.PublicationYear,
+			&i.Book.CreatedAt,
+			&i.Book.UpdatedAt,
+			&i.Book.DeletedAt,
+			&i.Book.PublisherID,
+			&i.Book.SeriesID,
+			&i.Book.SeriesVolume,
 		); err != nil {
 			return nil, err
 		}
@@ -197,28 +717,57 @@ func (q *Queries) ListBookAvailability(ctx context.Context, bookID int64) ([]Lis
 	return items, nil
 }
 
-const listSKUsInStore = `-- name: ListSKUsInStore :many
-SELECT s.id, s.uuid, s.book_id, s.store_id, s.price_in_kopeks, s.stock_count, s.created_at, s.updated_at, s.deleted_at, b.id, b.isbn, b.title, b.author, b.description, b.page_count, b.publication_year, b.created_at, b.updated_at, b.deleted_at
+const listSKUsInStoreByTitleDesc = `-- name: ListSKUsInStoreByTitleDesc :many
+SELECT s.id, s.uuid, s.book_id, s.store_id, s.price_in_kopeks, s.stock_count, s.created_at, s.updated_at, s.deleted_at, s.version, s.reserved_count, s.currency, b.id, b.isbn, b.title, b.author, b.description, b.page_count, b.publication_year, b.created_at, b.updated_at, b.deleted_at, b.publisher_id, b.series_id, b.series_volume
 FROM skus s
          JOIN books b ON s.book_id = b.id
 WHERE s.store_id = $1
   AND s.deleted_at IS NULL
+  AND b.deleted_at IS NULL
+  AND (NOT $2::bool OR s.stock_count > s.reserved_count)
+  AND ($3::int IS NULL OR s.price_in_kopeks >= $3::int)
+  AND ($4::int IS NULL OR s.price_in_kopeks <= $4::int)
+  AND ($5::text IS NULL OR b.title ILIKE '%' || $5::text || '%')
+  AND ($6::bigint IS NULL
+    OR (b.title, s.id) < ($7::text, $6::bigint))
+ORDER BY b.title DESC, s.id DESC
+LIMIT $8
 `
 
-type ListSKUsInStoreRow struct {
+type ListSKUsInStoreByTitleDescParams struct {
+	StoreID     int64       `json:"store_id"`
+	InStockOnly bool        `json:"in_stock_only"`
+	MinPrice    pgtype.Int4 `json:"min_price"`
+	MaxPrice    pgtype.Int4 `json:"max_price"`
+	Title       pgtype.Text `json:"title"`
+	CursorID    pgtype.Int8 `json:"cursor_id"`
+	CursorText  pgtype.Text `json:"cursor_text"`
+	PageLimit   int32       `json:"page_limit"`
+}
+
+type ListSKUsInStoreByTitleDescRow struct {
 	Sku  Sku  `json:"sku"`
 	Book Book `json:"book"`
 }
 
-func (q *Queries) ListSKUsInStore(ctx context.Context, storeID int64) ([]ListSKUsInStoreRow, error) {
-	rows, err := q.db.Query(ctx, listSKUsInStore, storeID)
+func (q *Queries) ListSKUsInStoreByTitleDesc(ctx context.Context, arg ListSKUsInStoreByTitleDescParams) ([]ListSKUsInStoreByTitleDescRow, error) {
+	rows, err := q.db.Query(ctx, listSKUsInStoreByTitleDesc,
+		arg.StoreID,
+		arg.InStockOnly,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.Title,
+		arg.CursorID,
+		arg.CursorText,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListSKUsInStoreRow
+	var items []ListSKUsInStoreByTitleDescRow
 	for rows.Next() {
-		var i ListSKUsInStoreRow
+		var i ListSKUsInStoreByTitleDescRow
 		if err := rows.Scan(
 			&i.Sku.ID,
 			&i.Sku.Uuid,
@@ -229,6 +778,9 @@ func (q *Queries) ListSKUsInStore(ctx context.Context, storeID int64) ([]ListSKU
 			&i.Sku.CreatedAt,
 			&i.Sku.UpdatedAt,
 			&i.Sku.DeletedAt,
+			&i.Sku.Version,
+			&i.Sku.ReservedCount,
+			&i.Sku.Currency,
 			&i.Book.ID,
 			&i.Book.Isbn,
 			&i.Book.Title,
@@ -239,6 +791,146 @@ func (q *Queries) ListSKUsInStore(ctx context.Context, storeID int64) ([]ListSKU
 			&i.Book.CreatedAt,
 			&i.Book.UpdatedAt,
 			&i.Book.DeletedAt,
+			&i.Book.PublisherID,
+			&i.Book.SeriesID,
+			&i.Book.SeriesVolume,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockBookSKUs = `-- name: LockBookSKUs :many
+SELECT id, uuid, book_id, store_id, price_in_kopeks, stock_count, created_at, updated_at, deleted_at, version, reserved_count, currency
+FROM skus
+WHERE book_id = $1
+  AND deleted_at IS NULL
+ORDER BY uuid
+    FOR UPDATE
+`
+
+// Locks the live SKUs of a book in UUID order, the order every stock change takes them in.
+func (q *Queries) LockBookSKUs(ctx context.Context, bookID int64) ([]Sku, error) {
+	rows, err := q.db.Query(ctx, lockBookSKUs, bookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Sku
+	for rows.Next() {
+		var i Sku
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.BookID,
+			&i.StoreID,
+			&i.PriceInKopeks,
+			&i.StockCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.ReservedCount,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreSKUsByBook = `-- name: RestoreSKUsByBook :many
+UPDATE skus
+SET deleted_at = NULL,
+    updated_at = now()
+WHERE book_id = $1
+  AND deleted_at = $2
+RETURNING id, uuid, book_id, store_id, price_in_kopeks, stock_count, created_at, updated_at, deleted_at, version, reserved_count, currency
+`
+
+type RestoreSKUsByBookParams struct {
+	BookID    int64              `json:"book_id"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+func (q *Queries) RestoreSKUsByBook(ctx context.Context, arg RestoreSKUsByBookParams) ([]Sku, error) {
+	rows, err := q.db.Query(ctx, restoreSKUsByBook, arg.BookID, arg.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Sku
+	for rows.Next() {
+		var i Sku
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.BookID,
+			&i.StoreID,
+			&i.PriceInKopeks,
+			&i.StockCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.ReservedCount,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const softDeleteSKUsByBook = `-- name: SoftDeleteSKUsByBook :many
+UPDATE skus
+SET deleted_at = $1,
+    updated_at = now()
+WHERE book_id = $2
+  AND deleted_at IS NULL
+RETURNING id, uuid, book_id, store_id, price_in_kopeks, stock_count, created_at, updated_at, deleted_at, version, reserved_count, currency
+`
+
+type SoftDeleteSKUsByBookParams struct {
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	BookID    int64              `json:"book_id"`
+}
+
+func (q *Queries) SoftDeleteSKUsByBook(ctx context.Context, arg SoftDeleteSKUsByBookParams) ([]Sku, error) {
+	rows, err := q.db.Query(ctx, softDeleteSKUsByBook, arg.DeletedAt, arg.BookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Sku
+	for rows.Next() {
+		var i Sku
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.BookID,
+			&i.StoreID,
+			&i.PriceInKopeks,
+			&i.StockCount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.ReservedCount,
+			&i.Currency,
 		); err != nil {
 			return nil, err
 		}
@@ -253,9 +945,10 @@ func (q *Queries) ListSKUsInStore(ctx context.Context, storeID int64) ([]ListSKU
 const updateSKUPrice = `-- name: UpdateSKUPrice :one
 UPDATE skus
 SET price_in_kopeks = $2,
+    version         = version + 1,
     updated_at      = now()
 WHERE uuid = $1
-RETURNING id, uuid, book_id, store_id, price_in_kopeks, stock_count, created_at, updated_at, deleted_at
+RETURNING id, uuid, book_id, store_id, price_in_kopeks, stock_count, created_at, updated_at, deleted_at, version, reserved_count, currency
 `
 
 type UpdateSKUPriceParams struct {
@@ -276,6 +969,9 @@ func (q *Queries) UpdateSKUPrice(ctx context.Context, arg UpdateSKUPriceParams)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Version,
+		&i.ReservedCount,
+		&i.Currency,
 	)
 	return i, err
 }
@@ -12,18 +12,19 @@ import (
 )
 
 const createStore = `-- name: CreateStore :one
-INSERT INTO stores (name, address)
-VALUES ($1, $2)
-RETURNING id, uuid, name, address, created_at, updated_at, deleted_at
+INSERT INTO stores (name, address, currency)
+VALUES ($1, $2, $3)
+RETURNING id, uuid, name, address, created_at, updated_at, deleted_at, currency
 `
 
 type CreateStoreParams struct {
-	Name    string `json:"name"`
-	Address string `json:"address"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	Currency string `json:"currency"`
 }
 
 func (q *Queries) CreateStore(ctx context.Context, arg CreateStoreParams) (Store, error) {
-	row := q.db.QueryRow(ctx, createStore, arg.Name, arg.Address)
+	row := q.db.QueryRow(ctx, createStore, arg.Name, arg.Address, arg.Currency)
 	var i Store
 	err := row.Scan(
 		&i.ID,
@@ -33,12 +34,13 @@ func (q *Queries) CreateStore(ctx context.Context, arg CreateStoreParams) (Store
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Currency,
 	)
 	return i, err
 }
 
 const getStoreByUUID = `-- name: GetStoreByUUID :one
-SELECT id, uuid, name, address, created_at, updated_at, deleted_at
+SELECT id, uuid, name, address, created_at, updated_at, deleted_at, currency
 FROM stores
 WHERE uuid = $1
   AND deleted_at IS NULL
@@ -55,19 +57,57 @@ func (q *Queries) GetStoreByUUID(ctx context.Context, uuid pgtype.UUID) (Store,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Currency,
 	)
 	return i, err
 }
 
-const listStores = `-- name: ListStores :many
-SELECT id, uuid, name, address, created_at, updated_at, deleted_at
+const getStoreByUUIDForUpdate = `-- name: GetStoreByUUIDForUpdate :one
+SELECT id, uuid, name, address, created_at, updated_at, deleted_at, currency
+FROM stores
+WHERE uuid = $1
+  AND deleted_at IS NULL
+    FOR UPDATE
+`
+
+func (q *Queries) GetStoreByUUIDForUpdate(ctx context.Context, uuid pgtype.UUID) (Store, error) {
+	row := q.db.QueryRow(ctx, getStoreByUUIDForUpdate, uuid)
+	var i Store
+	err := row.Scan(
+		&i.ID,
+		&i.Uuid,
+		&i.Name,
+		&i.Address,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const listStoresByCreatedAt = `-- name: ListStoresByCreatedAt :many
+SELECT id, uuid, name, address, created_at, updated_at, deleted_at, currency
 FROM stores
 WHERE deleted_at IS NULL
-ORDER BY name
+  AND ($1::bigint IS NULL
+    OR (created_at, id) > ($2::timestamptz, $1::bigint))
+ORDER BY created_at, id
+LIMIT $3
 `
 
-func (q *Queries) ListStores(ctx context.Context) ([]Store, error) {
-	rows, err := q.db.Query(ctx, listStores)
+type ListStoresByCreatedAtParams struct {
+	CursorID   pgtype.Int8        `json:"cursor_id"`
+	CursorTime pgtype.Timestamptz `json:"cursor_time"`
+	PageLimit  int32              `json:"page_limit"`
+}
+
+func (q *Queries) ListStoresByCreatedAt(ctx context.Context, arg ListStoresByCreatedAtParams) ([]Store, error) {
+	rows, err := q.db.Query(ctx, listStoresByCreatedAt,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -83,6 +123,7 @@ func (q *Queries) ListStores(ctx context.Context) ([]Store, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.Currency,
 		); err != nil {
 			return nil, err
 		}
@@ -94,35 +135,202 @@ func (q *Queries) ListStores(ctx context.Context) ([]Store, error) {
 	return items, nil
 }
 
-const softDeleteStore = `-- name: SoftDeleteStore :exec
+const listStoresByCreatedAtDesc = `-- name: ListStoresByCreatedAtDesc :many
+SELECT id, uuid, name, address, created_at, updated_at, deleted_at, currency
+FROM stores
+WHERE deleted_at IS NULL
+  AND ($1::bigint IS NULL
+    OR (created_at, id) < ($2::timestamptz, $1::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT $3
+`
+
+type ListStoresByCreatedAtDescParams struct {
+	CursorID   pgtype.Int8        `json:"cursor_id"`
+	CursorTime pgtype.Timestamptz `json:"cursor_time"`
+	PageLimit  int32              `json:"page_limit"`
+}
+
+func (q *Queries) ListStoresByCreatedAtDesc(ctx context.Context, arg ListStoresByCreatedAtDescParams) ([]Store, error) {
+	rows, err := q.db.Query(ctx, listStoresByCreatedAtDesc,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Store
+	for rows.Next() {
+		var i Store
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.Name,
+			&i.Address,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStoresByName = `-- name: ListStoresByName :many
+SELECT id, uuid, name, address, created_at, updated_at, deleted_at, currency
+FROM stores
+WHERE deleted_at IS NULL
+  AND ($1::bigint IS NULL
+    OR (name, id) > ($2::text, $1::bigint))
+ORDER BY name, id
+LIMIT $3
+`
+
+type ListStoresByNameParams struct {
+	CursorID   pgtype.Int8 `json:"cursor_id"`
+	CursorText pgtype.Text `json:"cursor_text"`
+	PageLimit  int32       `json:"page_limit"`
+}
+
+func (q *Queries) ListStoresByName(ctx context.Context, arg ListStoresByNameParams) ([]Store, error) {
+	rows, err := q.db.Query(ctx, listStoresByName,
+		arg.CursorID,
+		arg.CursorText,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Store
+	for rows.Next() {
+		var i Store
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.Name,
+			&i.Address,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStoresByNameDesc = `-- name: ListStoresByNameDesc :many
+SELECT id, uuid, name, address, created_at, updated_at, deleted_at, currency
+FROM stores
+WHERE deleted_at IS NULL
+  AND ($1::bigint IS NULL
+    OR (name, id) < ($2::text, $1::bigint))
+ORDER BY name DESC, id DESC
+LIMIT $3
+`
+
+type ListStoresByNameDescParams struct {
+	CursorID   pgtype.Int8 `json:"cursor_id"`
+	CursorText pgtype.Text `json:"cursor_text"`
+	PageLimit  int32       `json:"page_limit"`
+}
+
+func (q *Queries) ListStoresByNameDesc(ctx context.Context, arg ListStoresByNameDescParams) ([]Store, error) {
+	rows, err := q.db.Query(ctx, listStoresByNameDesc,
+		arg.CursorID,
+		arg.CursorText,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Store
+	for rows.Next() {
+		var i Store
+		if err := rows.Scan(
+			&i.ID,
+			&i.Uuid,
+			&i.Name,
+			&i.Address,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const softDeleteStore = `-- name: SoftDeleteStore :one
 UPDATE stores
 SET deleted_at = now()
 WHERE uuid = $1
+  AND deleted_at IS NULL
+RETURNING id, uuid, name, address, created_at, updated_at, deleted_at, currency
 `
 
-func (q *Queries) SoftDeleteStore(ctx context.Context, uuid pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, softDeleteStore, uuid)
-	return err
+func (q *Queries) SoftDeleteStore(ctx context.Context, uuid pgtype.UUID) (Store, error) {
+	row := q.db.QueryRow(ctx, softDeleteStore, uuid)
+	var i Store
+	err := row.Scan(
+		&i.ID,
+		&i.Uuid,
+		&i.Name,
+		&i.Address,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Currency,
+	)
+	return i, err
 }
 
 const updateStore = `-- name: UpdateStore :one
 UPDATE stores
 SET name       = $1,
     address    = $2,
+    currency   = COALESCE($3::text, currency),
     updated_at = now()
-WHERE uuid = $3
+WHERE uuid = $4
   AND deleted_at IS NULL
-RETURNING id, uuid, name, address, created_at, updated_at, deleted_at
+RETURNING id, uuid, name, address, created_at, updated_at, deleted_at, currency
 `
 
 type UpdateStoreParams struct {
-	Name    string      `json:"name"`
-	Address string      `json:"address"`
-	Uuid    pgtype.UUID `json:"uuid"`
+	Name     string      `json:"name"`
+	Address  string      `json:"address"`
+	Currency pgtype.Text `json:"currency"`
+	Uuid     pgtype.UUID `json:"uuid"`
 }
 
 func (q *Queries) UpdateStore(ctx context.Context, arg UpdateStoreParams) (Store, error) {
-	row := q.db.QueryRow(ctx, updateStore, arg.Name, arg.Address, arg.Uuid)
+	row := q.db.QueryRow(ctx, updateStore,
+		arg.Name,
+		arg.Address,
+		arg.Currency,
+		arg.Uuid,
+	)
 	var i Store
 	err := row.Scan(
 		&i.ID,
@@ -132,6 +340,7 @@ func (q *Queries) UpdateStore(ctx context.Context, arg UpdateStoreParams) (Store
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.Currency,
 	)
 	return i, err
 }
@@ -0,0 +1,262 @@
+package apikeys
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/response"
+)
+
+type Handler struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// CreateAPIKey
+//
+//	@Summary		Создать API-ключ
+//	@Description	Выпускает ключ для машинного клиента. Ключ возвращается только в этом ответе, в БД хранится его хеш.
+//	@Description	Без store_uuids ключ действует во всех магазинах. Доступно только администратору.
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		CreateAPIKeyRequest		true	"Название, scopes, магазины и срок действия"
+//	@Success		201		{object}	CreatedAPIKeyResponse	"Ключ создан"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"Магазин не найден"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/api-keys [post]
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read create api key request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed for create api key request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	key, secret, err := h.service.Create(r.Context(), req)
+	if err != nil {
+		writeKeyError(w, r, err)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusCreated, CreatedAPIKeyResponse{APIKeyResponse: toAPIKeyResponse(key), Key: secret})
+}
+
+// ListAPIKeys
+//
+//	@Summary		Список API-ключей
+//	@Description	Возвращает действующие ключи (с include_inactive=true - также отозванные и истёкшие). Доступно только администратору.
+//	@Tags			api-keys
+//	@Produce		json
+//	@Param			include_inactive	query		bool					false	"Показать отозванные и истёкшие ключи"
+//	@Success		200					{object}	APIKeyListResponse		"Ключи"
+//	@Failure		400					{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		500					{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/api-keys [get]
+func (h *Handler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	var includeInactive bool
+	if s := r.URL.Query().Get("include_inactive"); s != "" {
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			response.WriteError(w, r, http.StatusBadRequest, "Invalid include_inactive")
+			return
+		}
+		includeInactive = v
+	}
+
+	keys, err := h.service.List(r.Context(), includeInactive)
+	if err != nil {
+		log.Error("Failed to list api keys", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	resp := make([]APIKeyResponse, len(keys))
+	for i, key := range keys {
+		resp[i] = toAPIKeyResponse(key)
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, APIKeyListResponse{Items: resp})
+}
+
+// GetAPIKey
+//
+//	@Summary		Получить API-ключ
+//	@Description	Возвращает ключ без секрета, в том числе время последнего использования. Доступно только администратору.
+//	@Tags			api-keys
+//	@Produce		json
+//	@Param			keyUUID	path		string					true	"UUID ключа"
+//	@Success		200		{object}	APIKeyResponse			"Ключ"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"Ключ не найден"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/api-keys/{keyUUID} [get]
+func (h *Handler) GetAPIKey(w http.ResponseWriter, r *http.Request) {
+	keyUUID, ok := parseKeyUUID(w, r)
+	if !ok {
+		return
+	}
+
+	key, err := h.service.Get(r.Context(), keyUUID)
+	if err != nil {
+		writeKeyError(w, r, err)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toAPIKeyResponse(key))
+}
+
+// RevokeAPIKey
+//
+//	@Summary		Отозвать API-ключ
+//	@Description	Ключ перестаёт действовать сразу. Доступно только администратору.
+//	@Tags			api-keys
+//	@Produce		json
+//	@Param			keyUUID	path		string					true	"UUID ключа"
+//	@Success		200		{object}	APIKeyResponse			"Отозванный ключ"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"Ключ не найден"
+//	@Failure		409		{object}	response.ErrorResponse	"Ключ уже отозван или истёк"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/api-keys/{keyUUID}/revoke [post]
+func (h *Handler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	keyUUID, ok := parseKeyUUID(w, r)
+	if !ok {
+		return
+	}
+
+	key, err := h.service.Revoke(r.Context(), keyUUID)
+	if err != nil {
+		writeKeyError(w, r, err)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toAPIKeyResponse(key))
+}
+
+// RotateAPIKey
+//
+//	@Summary		Перевыпустить API-ключ
+//	@Description	Выпускает новый ключ с теми же названием, scopes и магазинами. Старый ключ действует ещё grace_period_seconds
+//	@Description	(но не дольше своего срока), без него отзывается сразу. Тело запроса необязательно. Доступно только администратору.
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			keyUUID	path		string					true	"UUID ключа"
+//	@Param			input	body		RotateAPIKeyRequest		false	"Период перехода и срок действия нового ключа"
+//	@Success		201		{object}	CreatedAPIKeyResponse	"Новый ключ"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"Ключ не найден"
+//	@Failure		409		{object}	response.ErrorResponse	"Ключ отозван или истёк"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/api-keys/{keyUUID}/rotate [post]
+func (h *Handler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	keyUUID, ok := parseKeyUUID(w, r)
+	if !ok {
+		return
+	}
+
+	var req RotateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		log.Warn("Failed to read rotate api key request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed for rotate api key request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	key, secret, err := h.service.Rotate(r.Context(), keyUUID, req)
+	if err != nil {
+		writeKeyError(w, r, err)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusCreated, CreatedAPIKeyResponse{APIKeyResponse: toAPIKeyResponse(key), Key: secret})
+}
+
+func writeKeyError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrKeyNotFound), errors.Is(err, ErrStoreNotFound):
+		response.WriteError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrKeyInactive):
+		response.WriteError(w, r, http.StatusConflict, err.Error())
+	case errors.Is(err, ErrInvalidScope), errors.Is(err, ErrInvalidExpiry):
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+	default:
+		middleware.LoggerFromContext(r.Context()).Error("API key operation failed", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func parseKeyUUID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	keyUUID, err := uuid.Parse(chi.URLParam(r, "keyUUID"))
+	if err != nil {
+		middleware.LoggerFromContext(r.Context()).Warn("Invalid api key UUID format", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid api key uuid format")
+		return uuid.Nil, false
+	}
+	return keyUUID, true
+}
+
+func toAPIKeyResponse(key repo.ApiKey) APIKeyResponse {
+	resp := APIKeyResponse{
+		UUID:       key.Uuid.Bytes,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  timestamptzToTimep(key.ExpiresAt),
+		LastUsedAt: timestamptzToTimep(key.LastUsedAt),
+		RevokedAt:  timestamptzToTimep(key.RevokedAt),
+		CreatedAt:  key.CreatedAt.Time,
+	}
+	for _, store := range key.StoreUuids {
+		resp.StoreUUIDs = append(resp.StoreUUIDs, store.Bytes)
+	}
+	if key.RotatedFrom.Valid {
+		rotatedFrom := uuid.UUID(key.RotatedFrom.Bytes)
+		resp.RotatedFrom = &rotatedFrom
+	}
+	if key.Actor.Valid {
+		resp.Actor = &key.Actor.String
+	}
+	return resp
+}
+
+func timestamptzToTimep(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
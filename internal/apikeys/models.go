@@ -0,0 +1,46 @@
+package apikeys
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name"   validate:"required,max=255"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,required" example:"inventory:write"`
+	// Stores the key works in; without them it works in every store.
+	StoreUUIDs []uuid.UUID `json:"store_uuids,omitempty" validate:"omitempty,max=100"`
+	ExpiresAt  *time.Time  `json:"expires_at,omitempty"`
+}
+
+type RotateAPIKeyRequest struct {
+	// How long the replaced key keeps working so clients can switch over; 0 revokes it at once.
+	GracePeriodSeconds int32 `json:"grace_period_seconds,omitempty" validate:"gte=0,lte=2592000"`
+	// Expiry of the new key, the one of the replaced key by default.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type APIKeyResponse struct {
+	UUID        uuid.UUID   `json:"uuid"`
+	Name        string      `json:"name"`
+	Prefix      string      `json:"prefix"      example:"bsk_1a2b3c4d5e6f"`
+	Scopes      []string    `json:"scopes"`
+	StoreUUIDs  []uuid.UUID `json:"store_uuids,omitempty"`
+	ExpiresAt   *time.Time  `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time  `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time  `json:"revoked_at,omitempty"`
+	RotatedFrom *uuid.UUID  `json:"rotated_from,omitempty"`
+	Actor       *string     `json:"actor,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse is the only response carrying the key itself; it isn't stored and can't be shown again.
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type APIKeyListResponse struct {
+	Items []APIKeyResponse `json:"items"`
+}
@@ -0,0 +1,227 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nikallow/bookstores-api/internal/adapters/postgres"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/auth"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+)
+
+var (
+	ErrKeyNotFound   = errors.New("api key not found")
+	ErrKeyInactive   = errors.New("api key is revoked or expired")
+	ErrInvalidScope  = errors.New("unknown scope")
+	ErrInvalidExpiry = errors.New("expires_at must be in the future")
+	ErrStoreNotFound = errors.New("store not found")
+)
+
+type Service interface {
+	// Create returns the new key together with the key itself, which is not stored.
+	Create(ctx context.Context, req CreateAPIKeyRequest) (repo.ApiKey, string, error)
+	List(ctx context.Context, includeInactive bool) ([]repo.ApiKey, error)
+	Get(ctx context.Context, keyUUID uuid.UUID) (repo.ApiKey, error)
+	Revoke(ctx context.Context, keyUUID uuid.UUID) (repo.ApiKey, error)
+	// Rotate issues a key with the name, scopes and stores of the given one, which keeps working
+	// for the grace period.
+	Rotate(ctx context.Context, keyUUID uuid.UUID, req RotateAPIKeyRequest) (repo.ApiKey, string, error)
+}
+
+type service struct {
+	repo repo.Querier
+	db   postgres.TxBeginner
+}
+
+func NewService(repo repo.Querier, db postgres.TxBeginner) Service {
+	return &service{repo: repo, db: db}
+}
+
+// Create - POST /api-keys
+func (s *service) Create(ctx context.Context, req CreateAPIKeyRequest) (repo.ApiKey, string, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	for _, scope := range req.Scopes {
+		if !auth.Scope(scope).Valid() {
+			return repo.ApiKey{}, "", fmt.Errorf("%w: %q", ErrInvalidScope, scope)
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return repo.ApiKey{}, "", ErrInvalidExpiry
+	}
+	stores, err := s.checkStores(ctx, req.StoreUUIDs)
+	if err != nil {
+		return repo.ApiKey{}, "", err
+	}
+
+	key, prefix, hash := auth.GenerateAPIKey()
+	row, err := s.repo.CreateAPIKey(ctx, repo.CreateAPIKeyParams{
+		Name:       req.Name,
+		Prefix:     prefix,
+		SecretHash: hash,
+		Scopes:     req.Scopes,
+		StoreUuids: stores,
+		ExpiresAt:  timeToPgTimestamptzp(req.ExpiresAt),
+		Actor:      stringToPgText(middleware.ActorFromContext(ctx)),
+	})
+	if err != nil {
+		log.Error("Failed to create api key", "error", err)
+		return repo.ApiKey{}, "", fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	log.Info("API key created", "key", row.Prefix, "scopes", row.Scopes)
+	return row, key, nil
+}
+
+// List - GET /api-keys
+func (s *service) List(ctx context.Context, includeInactive bool) ([]repo.ApiKey, error) {
+	keys, err := s.repo.ListAPIKeys(ctx, includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Get - GET /api-keys/{keyUUID}
+func (s *service) Get(ctx context.Context, keyUUID uuid.UUID) (repo.ApiKey, error) {
+	key, err := s.repo.GetAPIKey(ctx, pgtype.UUID{Bytes: keyUUID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.ApiKey{}, ErrKeyNotFound
+		}
+		return repo.ApiKey{}, fmt.Errorf("failed to get api key: %w", err)
+	}
+	return key, nil
+}
+
+// Revoke - POST /api-keys/{keyUUID}/revoke
+func (s *service) Revoke(ctx context.Context, keyUUID uuid.UUID) (repo.ApiKey, error) {
+	key, err := s.repo.RevokeAPIKey(ctx, pgtype.UUID{Bytes: keyUUID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			if _, err := s.Get(ctx, keyUUID); err != nil {
+				return repo.ApiKey{}, err
+			}
+			return repo.ApiKey{}, ErrKeyInactive
+		}
+		return repo.ApiKey{}, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	middleware.LoggerFromContext(ctx).Info("API key revoked", "key", key.Prefix)
+	return key, nil
+}
+
+// Rotate - POST /api-keys/{keyUUID}/rotate
+func (s *service) Rotate(ctx context.Context, keyUUID uuid.UUID, req RotateAPIKeyRequest) (repo.ApiKey, string, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return repo.ApiKey{}, "", ErrInvalidExpiry
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.ApiKey{}, "", err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+
+	old, err := qtx.GetAPIKeyForUpdate(ctx, pgtype.UUID{Bytes: keyUUID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.ApiKey{}, "", ErrKeyNotFound
+		}
+		return repo.ApiKey{}, "", fmt.Errorf("failed to get api key: %w", err)
+	}
+	if !isActive(old) {
+		return repo.ApiKey{}, "", ErrKeyInactive
+	}
+
+	expiresAt := old.ExpiresAt
+	if req.ExpiresAt != nil {
+		expiresAt = timeToPgTimestamptzp(req.ExpiresAt)
+	}
+
+	key, prefix, hash := auth.GenerateAPIKey()
+	row, err := qtx.CreateAPIKey(ctx, repo.CreateAPIKeyParams{
+		Name:        old.Name,
+		Prefix:      prefix,
+		SecretHash:  hash,
+		Scopes:      old.Scopes,
+		StoreUuids:  old.StoreUuids,
+		ExpiresAt:   expiresAt,
+		RotatedFrom: old.Uuid,
+		Actor:       stringToPgText(middleware.ActorFromContext(ctx)),
+	})
+	if err != nil {
+		log.Error("Failed to create rotated api key", "error", err, "key", old.Prefix)
+		return repo.ApiKey{}, "", fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	if req.GracePeriodSeconds == 0 {
+		_, err = qtx.RevokeAPIKey(ctx, old.Uuid)
+	} else {
+		_, err = qtx.ExpireAPIKey(ctx, repo.ExpireAPIKeyParams{
+			Uuid:      old.Uuid,
+			ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(time.Duration(req.GracePeriodSeconds) * time.Second), Valid: true},
+		})
+	}
+	if err != nil {
+		log.Error("Failed to retire rotated api key", "error", err, "key", old.Prefix)
+		return repo.ApiKey{}, "", fmt.Errorf("failed to retire api key: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return repo.ApiKey{}, "", err
+	}
+
+	log.Info("API key rotated", "key", old.Prefix, "new_key", row.Prefix, "grace_period_seconds", req.GracePeriodSeconds)
+	return row, key, nil
+}
+
+// checkStores makes sure every store of a key restriction exists and drops duplicates.
+func (s *service) checkStores(ctx context.Context, storeUUIDs []uuid.UUID) ([]pgtype.UUID, error) {
+	if len(storeUUIDs) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[uuid.UUID]bool, len(storeUUIDs))
+	stores := make([]pgtype.UUID, 0, len(storeUUIDs))
+	for _, id := range storeUUIDs {
+		if !seen[id] {
+			seen[id] = true
+			stores = append(stores, pgtype.UUID{Bytes: id, Valid: true})
+		}
+	}
+
+	count, err := s.repo.CountStoresByUUIDs(ctx, stores)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check stores: %w", err)
+	}
+	if count != int64(len(stores)) {
+		return nil, ErrStoreNotFound
+	}
+	return stores, nil
+}
+
+func isActive(key repo.ApiKey) bool {
+	return !key.RevokedAt.Valid && (!key.ExpiresAt.Valid || key.ExpiresAt.Time.After(time.Now()))
+}
+
+func timeToPgTimestamptzp(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{Valid: false}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
+
+func stringToPgText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
@@ -0,0 +1,124 @@
+// Package audit records who changed what in stores, books and inventory, and lists those records.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	appMiddleware "github.com/nikallow/bookstores-api/internal/middleware"
+)
+
+// Action is the kind of change an event records.
+type Action string
+
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionDelete  Action = "delete"
+	ActionRestore Action = "restore"
+)
+
+// Types of the audited entities.
+const (
+	EntityStore    = "store"
+	EntityBook     = "book"
+	EntitySKU      = "sku"
+	EntitySKUPrice = "sku_price"
+)
+
+// ignoredFields change with every write and would only add noise to a diff.
+var ignoredFields = map[string]bool{"updated_at": true}
+
+// Record adds an event for a change of an entity. It runs within the transaction q is bound to,
+// so the event is kept exactly when the change is. before is nil for a created entity; both are
+// compared by their JSON fields and only the fields that differ are stored. An update that
+// changes nothing isn't recorded.
+func Record(ctx context.Context, q *repo.Queries, entityType, entityID string, action Action, before, after any) error {
+	beforeJSON, afterJSON, err := diff(before, after)
+	if err != nil {
+		return fmt.Errorf("failed to diff %s %s: %w", entityType, entityID, err)
+	}
+	if beforeJSON == nil && afterJSON == nil {
+		return nil
+	}
+
+	return q.CreateAuditEvent(ctx, repo.CreateAuditEventParams{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     string(action),
+		Before:     beforeJSON,
+		After:      afterJSON,
+		Actor:      stringToPgText(appMiddleware.ActorFromContext(ctx)),
+		RequestID:  stringToPgText(middleware.GetReqID(ctx)),
+	})
+}
+
+// diff returns the fields of before and after whose values differ, nil when none do.
+// The before side is nil for a nil before.
+func diff(before, after any) ([]byte, []byte, error) {
+	b, err := fields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	a, err := fields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changedBefore := make(map[string]json.RawMessage)
+	changedAfter := make(map[string]json.RawMessage)
+	for k, v := range a {
+		if old, ok := b[k]; !ok || !bytes.Equal(old, v) {
+			changedAfter[k] = v
+			if ok {
+				changedBefore[k] = old
+			}
+		}
+	}
+	for k, old := range b {
+		if _, ok := a[k]; !ok {
+			changedBefore[k] = old
+		}
+	}
+	if len(changedBefore) == 0 && len(changedAfter) == 0 {
+		return nil, nil, nil
+	}
+
+	var beforeJSON, afterJSON []byte
+	if before != nil {
+		if beforeJSON, err = json.Marshal(changedBefore); err != nil {
+			return nil, nil, err
+		}
+	}
+	if afterJSON, err = json.Marshal(changedAfter); err != nil {
+		return nil, nil, err
+	}
+	return beforeJSON, afterJSON, nil
+}
+
+func fields(v any) (map[string]json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	for k := range ignoredFields {
+		delete(m, k)
+	}
+	return m, nil
+}
+
+func stringToPgText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
@@ -0,0 +1,127 @@
+package audit
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+	"github.com/nikallow/bookstores-api/internal/response"
+)
+
+type Handler struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// ListEvents
+//
+//	@Summary		Журнал изменений
+//	@Description	Возвращает страницу событий аудита: кто, когда и в каком запросе изменил магазин, книгу, SKU или цену.
+//	@Description	В before и after - только изменившиеся поля. Доступно только администратору.
+//	@Tags			audit
+//	@Produce		json
+//	@Param			entity_type	query		string					false	"Тип сущности"	Enums(store, book, sku, sku_price)
+//	@Param			entity_id	query		string					false	"ID или UUID сущности"
+//	@Param			actor		query		string					false	"Автор изменения"
+//	@Param			from		query		string					false	"Начало периода (RFC3339, включительно)"
+//	@Param			to			query		string					false	"Конец периода (RFC3339, не включительно)"
+//	@Param			limit		query		int						false	"Размер страницы (1-100)"	default(20)
+//	@Param			cursor		query		string					false	"Курсор следующей страницы"
+//	@Param			order		query		string					false	"Направление сортировки по времени"	Enums(asc, desc)	default(asc)
+//	@Success		200			{object}	EventListResponse		"Страница событий"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/audit [get]
+func (h *Handler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	query := r.URL.Query()
+	page, err := pagination.FromQuery(query, "created_at")
+	if err != nil {
+		log.Warn("Invalid list audit events parameters", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := ListEventsParams{Request: page}
+	if entityType := query.Get("entity_type"); entityType != "" {
+		if err := h.validate.Var(entityType, "oneof=store book sku sku_price"); err != nil {
+			response.WriteError(w, r, http.StatusBadRequest, "Invalid entity_type")
+			return
+		}
+		params.EntityType = &entityType
+	}
+	if entityID := query.Get("entity_id"); entityID != "" {
+		params.EntityID = &entityID
+	}
+	if actor := query.Get("actor"); actor != "" {
+		params.Actor = &actor
+	}
+	if params.From, err = parseTimeQuery(query.Get("from")); err != nil {
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid from, expected RFC3339")
+		return
+	}
+	if params.To, err = parseTimeQuery(query.Get("to")); err != nil {
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid to, expected RFC3339")
+		return
+	}
+
+	events, nextCursor, err := h.service.List(r.Context(), params)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error("Failed to list audit events", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	resp := make([]EventResponse, len(events))
+	for i, e := range events {
+		resp[i] = toEventResponse(e)
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, EventListResponse{Items: resp, NextCursor: nextCursor})
+}
+
+func toEventResponse(e repo.AuditEvent) EventResponse {
+	resp := EventResponse{
+		ID:         e.ID,
+		EntityType: e.EntityType,
+		EntityID:   e.EntityID,
+		Action:     e.Action,
+		Before:     e.Before,
+		After:      e.After,
+		CreatedAt:  e.CreatedAt.Time,
+	}
+	if e.Actor.Valid {
+		resp.Actor = &e.Actor.String
+	}
+	if e.RequestID.Valid {
+		resp.RequestID = &e.RequestID.String
+	}
+	return resp
+}
+
+func parseTimeQuery(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
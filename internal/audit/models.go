@@ -0,0 +1,36 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/nikallow/bookstores-api/internal/pagination"
+)
+
+type ListEventsParams struct {
+	pagination.Request
+	EntityType *string
+	EntityID   *string
+	Actor      *string
+	From       *time.Time
+	To         *time.Time
+}
+
+type EventResponse struct {
+	ID         int64  `json:"id"`
+	EntityType string `json:"entity_type" example:"store"`
+	EntityID   string `json:"entity_id"`
+	Action     string `json:"action"      example:"update"`
+	// Changed fields before the change, absent for a created entity.
+	Before json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	// Changed fields after the change.
+	After     json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	Actor     *string         `json:"actor,omitempty"`
+	RequestID *string         `json:"request_id,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type EventListResponse struct {
+	Items      []EventResponse `json:"items"`
+	NextCursor *string         `json:"next_cursor"`
+}
@@ -0,0 +1,84 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+)
+
+type Service interface {
+	List(ctx context.Context, params ListEventsParams) ([]repo.AuditEvent, *string, error)
+}
+
+type service struct {
+	repo repo.Querier
+}
+
+func NewService(repo repo.Querier) Service {
+	return &service{repo: repo}
+}
+
+// List - GET /audit
+func (s *service) List(ctx context.Context, params ListEventsParams) ([]repo.AuditEvent, *string, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	cursor, err := params.DecodeCursor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queryParams := repo.ListAuditEventsParams{
+		EntityType: stringToPgTextp(params.EntityType),
+		EntityID:   stringToPgTextp(params.EntityID),
+		Actor:      stringToPgTextp(params.Actor),
+		FromTime:   timeToPgTimestamptzp(params.From),
+		ToTime:     timeToPgTimestamptzp(params.To),
+		PageLimit:  params.QueryLimit(),
+	}
+	if cursor != nil {
+		t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: bad timestamp", pagination.ErrInvalidCursor)
+		}
+		queryParams.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+		queryParams.CursorTime = pgtype.Timestamptz{Time: t, Valid: true}
+	}
+
+	var events []repo.AuditEvent
+	if params.Desc {
+		events, err = s.repo.ListAuditEventsDesc(ctx, repo.ListAuditEventsDescParams(queryParams))
+	} else {
+		events, err = s.repo.ListAuditEvents(ctx, queryParams)
+	}
+	if err != nil {
+		log.Error("Failed to list audit events", "error", err)
+		return nil, nil, err
+	}
+
+	events, hasMore := pagination.Trim(events, params.Request)
+	if !hasMore {
+		return events, nil, nil
+	}
+	last := events[len(events)-1]
+	next := params.NextCursor(last.CreatedAt.Time.Format(time.RFC3339Nano), last.ID)
+	return events, &next, nil
+}
+
+func stringToPgTextp(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{Valid: false}
+	}
+	return pgtype.Text{String: *s, Valid: true}
+}
+
+func timeToPgTimestamptzp(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{Valid: false}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	appMiddleware "github.com/nikallow/bookstores-api/internal/middleware"
+)
+
+// APIKeyHeader carries an API key; it may also be sent as a bearer token.
+const APIKeyHeader = "X-API-Key"
+
+const (
+	// apiKeyMarker starts every API key, telling it apart from a JWT.
+	apiKeyMarker = "bsk_"
+	// An API key is its prefix, an underscore and the secret: bsk_<12 hex>_<64 hex>.
+	apiKeyIDBytes     = 6
+	apiKeySecretBytes = 32
+	apiKeyPrefixLen   = len(apiKeyMarker) + 2*apiKeyIDBytes
+	apiKeyLen         = apiKeyPrefixLen + 1 + 2*apiKeySecretBytes
+)
+
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// GenerateAPIKey returns a new API key, the prefix identifying it and the hash to store instead of it.
+func GenerateAPIKey() (key, prefix, hash string) {
+	b := make([]byte, apiKeyIDBytes+apiKeySecretBytes)
+	// crypto/rand.Read never fails.
+	rand.Read(b)
+	prefix = apiKeyMarker + hex.EncodeToString(b[:apiKeyIDBytes])
+	key = prefix + "_" + hex.EncodeToString(b[apiKeyIDBytes:])
+	return key, prefix, HashAPIKey(key)
+}
+
+// HashAPIKey is the hash an API key is stored as. The secret is random, so a plain SHA-256 is enough.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func isAPIKey(token string) bool {
+	return strings.HasPrefix(token, apiKeyMarker)
+}
+
+func (m *Middleware) authenticateKey(ctx context.Context, key string) (Principal, error) {
+	if len(key) != apiKeyLen || !isAPIKey(key) || key[apiKeyPrefixLen] != '_' {
+		return Principal{}, fmt.Errorf("%w: malformed", ErrInvalidAPIKey)
+	}
+	prefix := key[:apiKeyPrefixLen]
+
+	row, err := m.repo.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Principal{}, fmt.Errorf("%w: unknown key %s", ErrInvalidAPIKey, prefix)
+		}
+		return Principal{}, fmt.Errorf("failed to get api key: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(HashAPIKey(key)), []byte(row.SecretHash)) != 1 {
+		return Principal{}, fmt.Errorf("%w: wrong secret for %s", ErrInvalidAPIKey, prefix)
+	}
+	if row.RevokedAt.Valid {
+		return Principal{}, fmt.Errorf("%w: %s is revoked", ErrInvalidAPIKey, prefix)
+	}
+	if row.ExpiresAt.Valid && !time.Now().Before(row.ExpiresAt.Time) {
+		return Principal{}, fmt.Errorf("%w: %s has expired", ErrInvalidAPIKey, prefix)
+	}
+
+	if err := m.repo.TouchAPIKey(ctx, row.ID); err != nil {
+		appMiddleware.LoggerFromContext(ctx).Warn("Failed to record api key use", "error", err, "key", prefix)
+	}
+
+	principal := Principal{Subject: prefix, KeyPrefix: prefix}
+	for _, s := range row.Scopes {
+		principal.Scopes = append(principal.Scopes, Scope(s))
+	}
+	for _, s := range row.StoreUuids {
+		principal.Stores = append(principal.Stores, s.Bytes)
+	}
+	return principal, nil
+}
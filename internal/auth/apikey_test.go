@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+)
+
+// apiKeys is a repo holding API keys by prefix and counting their uses.
+type apiKeys struct {
+	repo.Querier
+	keys    map[string]repo.ApiKey
+	touched map[int64]int
+}
+
+func (k *apiKeys) GetAPIKeyByPrefix(_ context.Context, prefix string) (repo.ApiKey, error) {
+	key, ok := k.keys[prefix]
+	if !ok {
+		return repo.ApiKey{}, pgx.ErrNoRows
+	}
+	return key, nil
+}
+
+func (k *apiKeys) TouchAPIKey(_ context.Context, id int64) error {
+	k.touched[id]++
+	return nil
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	store := uuid.New()
+	keys := &apiKeys{keys: make(map[string]repo.ApiKey), touched: make(map[int64]int)}
+	issue := func(id int64, change func(*repo.ApiKey)) string {
+		key, prefix, hash := GenerateAPIKey()
+		row := repo.ApiKey{
+			ID:         id,
+			Prefix:     prefix,
+			SecretHash: hash,
+			Scopes:     []string{string(ScopeInventoryWrite)},
+			StoreUuids: []pgtype.UUID{{Bytes: store, Valid: true}},
+		}
+		if change != nil {
+			change(&row)
+		}
+		keys.keys[prefix] = row
+		return key
+	}
+
+	valid := issue(1, nil)
+	expiring := issue(2, func(k *repo.ApiKey) {
+		k.ExpiresAt = pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true}
+	})
+	expired := issue(3, func(k *repo.ApiKey) {
+		k.ExpiresAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Second), Valid: true}
+	})
+	revoked := issue(4, func(k *repo.ApiKey) {
+		k.RevokedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	})
+	unknown, _, _ := GenerateAPIKey()
+	wrongSecret := valid[:apiKeyPrefixLen+1] + unknown[apiKeyPrefixLen+1:]
+
+	m := &Middleware{repo: keys}
+
+	tests := []struct {
+		name       string
+		header     string
+		value      string
+		wantStatus int
+		wantKey    int64
+	}{
+		{"api key header", APIKeyHeader, valid, http.StatusOK, 1},
+		{"bearer token", "Authorization", "Bearer " + valid, http.StatusOK, 1},
+		{"not yet expired", APIKeyHeader, expiring, http.StatusOK, 2},
+		{"expired", APIKeyHeader, expired, http.StatusUnauthorized, 0},
+		{"revoked", APIKeyHeader, revoked, http.StatusUnauthorized, 0},
+		{"unknown", APIKeyHeader, unknown, http.StatusUnauthorized, 0},
+		{"wrong secret", APIKeyHeader, wrongSecret, http.StatusUnauthorized, 0},
+		{"malformed", "Authorization", "Bearer " + valid[:len(valid)-1], http.StatusUnauthorized, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clear(keys.touched)
+			var principal Principal
+			handler := m.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				principal, _ = PrincipalFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(tt.header, tt.value)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if len(keys.touched) != 0 {
+					t.Errorf("rejected key was recorded as used: %v", keys.touched)
+				}
+				return
+			}
+			if keys.touched[tt.wantKey] != 1 {
+				t.Errorf("key %d used %d times, want 1", tt.wantKey, keys.touched[tt.wantKey])
+			}
+			if !principal.IsAPIKey() || principal.Subject != principal.KeyPrefix || principal.Role != "" {
+				t.Errorf("principal = %+v, want an api key", principal)
+			}
+			if !slices.Equal(principal.Scopes, []Scope{ScopeInventoryWrite}) || !slices.Equal(principal.Stores, []uuid.UUID{store}) {
+				t.Errorf("principal = %+v, want the scopes and stores of the key", principal)
+			}
+		})
+	}
+}
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, hash := GenerateAPIKey()
+	if len(key) != apiKeyLen || !isAPIKey(key) || key[:apiKeyPrefixLen] != prefix {
+		t.Errorf("GenerateAPIKey() = %q with prefix %q", key, prefix)
+	}
+	if hash != HashAPIKey(key) {
+		t.Errorf("hash = %q, want HashAPIKey(key)", hash)
+	}
+	if again, _, _ := GenerateAPIKey(); again == key {
+		t.Error("GenerateAPIKey() returned the same key twice")
+	}
+}
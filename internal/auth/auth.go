@@ -0,0 +1,207 @@
+// Package auth authenticates requests with bearer JWTs or API keys and authorizes them by role, scope and store.
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/config"
+	appMiddleware "github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/response"
+)
+
+var (
+	ErrMissingToken = errors.New("missing bearer token")
+	ErrInvalidToken = errors.New("invalid bearer token")
+)
+
+// claims are the claims of an access token besides the registered ones.
+type claims struct {
+	jwt.RegisteredClaims
+	Role   Role     `json:"role"`
+	Stores []string `json:"stores,omitempty"`
+}
+
+// Middleware authenticates requests and resolves the stores they touch.
+type Middleware struct {
+	repo   repo.Querier
+	keys   keySet
+	parser *jwt.Parser
+}
+
+func New(repo repo.Querier, cfg config.AuthConfig) (*Middleware, error) {
+	keys, err := loadKeys(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(keys.methods()),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	return &Middleware{
+		repo:   repo,
+		keys:   keys,
+		parser: jwt.NewParser(opts...),
+	}, nil
+}
+
+// Authenticate requires a valid bearer token or API key and stores its principal in the request
+// context, next to a logger carrying its subject. The subject becomes the actor of the request.
+func (m *Middleware) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log := appMiddleware.LoggerFromContext(r.Context())
+
+		principal, err := m.authenticate(r)
+		switch {
+		case errors.Is(err, ErrMissingToken):
+			log.Warn("Authentication failed", "error", err)
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			response.WriteError(w, r, http.StatusUnauthorized, ErrMissingToken.Error())
+			return
+		case errors.Is(err, ErrInvalidToken), errors.Is(err, ErrInvalidAPIKey):
+			log.Warn("Authentication failed", "error", err)
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			msg := ErrInvalidToken.Error()
+			if errors.Is(err, ErrInvalidAPIKey) {
+				msg = ErrInvalidAPIKey.Error()
+			}
+			response.WriteError(w, r, http.StatusUnauthorized, msg)
+			return
+		case err != nil:
+			log.Error("Failed to authenticate request", "error", err)
+			response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+
+		ctx := WithPrincipal(r.Context(), principal)
+		ctx = appMiddleware.WithActor(ctx, principal.Subject)
+		ctx = appMiddleware.WithLogger(ctx, log.With("subject", principal.Subject))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (m *Middleware) authenticate(r *http.Request) (Principal, error) {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return m.authenticateKey(r.Context(), key)
+	}
+
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	token = strings.TrimSpace(token)
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return Principal{}, ErrMissingToken
+	}
+	if isAPIKey(token) {
+		return m.authenticateKey(r.Context(), token)
+	}
+
+	var c claims
+	if _, err := m.parser.ParseWithClaims(token, &c, m.keys.keyfunc); err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	if c.Subject == "" {
+		return Principal{}, fmt.Errorf("%w: no subject", ErrInvalidToken)
+	}
+	if !c.Role.valid() {
+		return Principal{}, fmt.Errorf("%w: unknown role %q", ErrInvalidToken, c.Role)
+	}
+
+	principal := Principal{Subject: c.Subject, Role: c.Role}
+	for _, s := range c.Stores {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return Principal{}, fmt.Errorf("%w: bad store %q", ErrInvalidToken, s)
+		}
+		principal.Stores = append(principal.Stores, id)
+	}
+	return principal, nil
+}
+
+// Require lets through tokens having one of the roles and API keys having the scope; admins always pass.
+func Require(scope Scope, roles ...Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, _ := PrincipalFromContext(r.Context())
+			if !principal.Allows(scope, roles...) {
+				forbid(w, r, principal)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireAdmin lets through admins only, never API keys.
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, _ := PrincipalFromContext(r.Context())
+		if principal.IsAPIKey() || principal.Role != RoleAdmin {
+			forbid(w, r, principal)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireStore is Require in every store the request touches; a request touching all stores is
+// for callers working in every store only. When the stores can't be resolved, e.g. the SKU
+// doesn't exist, the request goes on and its handler rejects it.
+func RequireStore(resolve StoreResolver, scope Scope, roles ...Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, _ := PrincipalFromContext(r.Context())
+			if !principal.Allows(scope, roles...) {
+				forbid(w, r, principal)
+				return
+			}
+			if principal.AllStores() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			stores, err := resolve(r)
+			if errors.Is(err, errUnresolved) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if errors.Is(err, errBodyTooLarge) {
+				response.WriteError(w, r, http.StatusRequestEntityTooLarge, "Request body is too large")
+				return
+			}
+			if err != nil {
+				appMiddleware.LoggerFromContext(r.Context()).Error("Failed to resolve request stores", "error", err)
+				response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+				return
+			}
+			if len(stores) == 0 {
+				forbid(w, r, principal)
+				return
+			}
+			for _, store := range stores {
+				if !principal.CanAccessStore(store, scope, roles...) {
+					forbid(w, r, principal)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func forbid(w http.ResponseWriter, r *http.Request, principal Principal) {
+	appMiddleware.LoggerFromContext(r.Context()).Warn("Access denied", "role", principal.Role, "scopes", principal.Scopes)
+	response.WriteError(w, r, http.StatusForbidden, "Forbidden")
+}
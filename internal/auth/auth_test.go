@@ -0,0 +1,331 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/config"
+	appMiddleware "github.com/nikallow/bookstores-api/internal/middleware"
+)
+
+var secret = strings.Repeat("s", minSecretLength)
+
+// skuStores is a repo that knows the store of every SKU in it.
+type skuStores struct {
+	repo.Querier
+	stores map[uuid.UUID]uuid.UUID
+}
+
+func (s skuStores) ListSKUStoreUUIDs(_ context.Context, skus []pgtype.UUID) ([]repo.ListSKUStoreUUIDsRow, error) {
+	var rows []repo.ListSKUStoreUUIDsRow
+	for _, sku := range skus {
+		if store, ok := s.stores[sku.Bytes]; ok {
+			rows = append(rows, repo.ListSKUStoreUUIDsRow{
+				SkuUuid:   sku,
+				StoreUuid: pgtype.UUID{Bytes: store, Valid: true},
+			})
+		}
+	}
+	return rows, nil
+}
+
+func token(t *testing.T, method jwt.SigningMethod, key any, kid string, c jwt.MapClaims) string {
+	t.Helper()
+	tok := jwt.NewWithClaims(method, c)
+	if kid != "" {
+		tok.Header["kid"] = kid
+	}
+	signed, err := tok.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+// writeJWKS writes the public half of key to a JWKS file under the key ID and returns its path.
+func writeJWKS(t *testing.T, kid string, key *rsa.PrivateKey) string {
+	t.Helper()
+	data, err := json.Marshal(map[string][]jwk{"keys": {{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAuthenticate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := New(nil, config.AuthConfig{
+		HS256Secret: secret,
+		JWKSFile:    writeJWKS(t, "key-1", rsaKey),
+		Issuer:      "bookstores",
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	store := uuid.New()
+	exp := time.Now().Add(time.Hour).Unix()
+	claims := func(extra jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{"sub": "clerk@example.com", "iss": "bookstores", "exp": exp, "role": "clerk", "stores": []string{store.String()}}
+		for k, v := range extra {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+	hs256 := func(c jwt.MapClaims) string {
+		return "Bearer " + token(t, jwt.SigningMethodHS256, []byte(secret), "", c)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantChallenge string
+	}{
+		{"hs256", hs256(claims(nil)), http.StatusOK, ""},
+		{"rs256 from the jwks", "Bearer " + token(t, jwt.SigningMethodRS256, rsaKey, "key-1", claims(nil)), http.StatusOK, ""},
+		{"lowercase scheme", "bearer " + token(t, jwt.SigningMethodHS256, []byte(secret), "", claims(nil)), http.StatusOK, ""},
+		{"no header", "", http.StatusUnauthorized, "Bearer"},
+		{"other scheme", "Basic dXNlcjpwYXNz", http.StatusUnauthorized, "Bearer"},
+		{"expired", hs256(claims(jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()})), http.StatusUnauthorized, `Bearer error="invalid_token"`},
+		{"no expiry", hs256(claims(jwt.MapClaims{"exp": nil})), http.StatusUnauthorized, `Bearer error="invalid_token"`},
+		{"other issuer", hs256(claims(jwt.MapClaims{"iss": "elsewhere"})), http.StatusUnauthorized, `Bearer error="invalid_token"`},
+		{"no subject", hs256(claims(jwt.MapClaims{"sub": nil})), http.StatusUnauthorized, `Bearer error="invalid_token"`},
+		{"unknown role", hs256(claims(jwt.MapClaims{"role": "owner"})), http.StatusUnauthorized, `Bearer error="invalid_token"`},
+		{"bad store", hs256(claims(jwt.MapClaims{"stores": []string{"main street"}})), http.StatusUnauthorized, `Bearer error="invalid_token"`},
+		{
+			name:          "other secret",
+			authorization: "Bearer " + token(t, jwt.SigningMethodHS256, []byte(strings.Repeat("x", minSecretLength)), "", claims(nil)),
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			name:          "unknown key id",
+			authorization: "Bearer " + token(t, jwt.SigningMethodRS256, rsaKey, "key-2", claims(nil)),
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			name:          "unsigned",
+			authorization: "Bearer " + token(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", claims(nil)),
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: `Bearer error="invalid_token"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var principal Principal
+			var actor string
+			handler := m.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				principal, _ = PrincipalFromContext(r.Context())
+				actor = appMiddleware.ActorFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got != tt.wantChallenge {
+				t.Errorf("WWW-Authenticate = %q, want %q", got, tt.wantChallenge)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if principal.Subject != "clerk@example.com" || principal.Role != RoleClerk || !slices.Equal(principal.Stores, []uuid.UUID{store}) {
+				t.Errorf("principal = %+v", principal)
+			}
+			if actor != principal.Subject {
+				t.Errorf("actor = %q, want the subject %q", actor, principal.Subject)
+			}
+		})
+	}
+}
+
+func TestNewRequiresKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.AuthConfig
+	}{
+		{"no keys", config.AuthConfig{}},
+		{"short secret", config.AuthConfig{HS256Secret: "secret"}},
+		{"missing key file", config.AuthConfig{RS256KeyFile: filepath.Join(t.TempDir(), "key.pem")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(nil, tt.cfg); err == nil {
+				t.Error("New() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestRequire(t *testing.T) {
+	tests := []struct {
+		name       string
+		principal  Principal
+		wantStatus int
+	}{
+		{"admin", Principal{Role: RoleAdmin}, http.StatusOK},
+		{"role allowed", Principal{Role: RoleStoreManager}, http.StatusOK},
+		{"role not allowed", Principal{Role: RoleClerk}, http.StatusForbidden},
+		{"read only", Principal{Role: RoleReadOnly}, http.StatusForbidden},
+		{"anonymous", Principal{}, http.StatusForbidden},
+		{"api key with the scope", Principal{KeyPrefix: "bsk_1", Scopes: []Scope{ScopeOrdersWrite, ScopeStoresWrite}}, http.StatusOK},
+		{"api key without the scope", Principal{KeyPrefix: "bsk_1", Scopes: []Scope{ScopeOrdersWrite}}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Require(ScopeStoresWrite, RoleStoreManager)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req = req.WithContext(WithPrincipal(req.Context(), tt.principal))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRequireAdmin(t *testing.T) {
+	tests := []struct {
+		name       string
+		principal  Principal
+		wantStatus int
+	}{
+		{"admin", Principal{Role: RoleAdmin}, http.StatusOK},
+		{"store manager", Principal{Role: RoleStoreManager}, http.StatusForbidden},
+		{"api key with every scope", Principal{KeyPrefix: "bsk_1", Scopes: []Scope{
+			ScopeCatalogWrite, ScopeStoresWrite, ScopeInventoryWrite, ScopeOrdersWrite, ScopePricingWrite, ScopeExportsRead,
+		}}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := RequireAdmin(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req = req.WithContext(WithPrincipal(req.Context(), tt.principal))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRequireStore(t *testing.T) {
+	own, other := uuid.New(), uuid.New()
+	ownSKU, otherSKU := uuid.New(), uuid.New()
+	m := &Middleware{repo: skuStores{stores: map[uuid.UUID]uuid.UUID{ownSKU: own, otherSKU: other}}}
+
+	type body struct {
+		StoreUUID uuid.UUID `json:"store_uuid"`
+	}
+	bodyStore := BodyStore(func(b body) uuid.UUID { return b.StoreUUID })
+
+	manager := Principal{Subject: "manager", Role: RoleStoreManager, Stores: []uuid.UUID{own}}
+	clerk := Principal{Subject: "clerk", Role: RoleClerk, Stores: []uuid.UUID{own}}
+	admin := Principal{Subject: "admin", Role: RoleAdmin}
+	storeKey := Principal{KeyPrefix: "bsk_1", Scopes: []Scope{ScopeInventoryWrite}, Stores: []uuid.UUID{own}}
+	globalKey := Principal{KeyPrefix: "bsk_2", Scopes: []Scope{ScopeInventoryWrite}}
+	unscopedKey := Principal{KeyPrefix: "bsk_3", Scopes: []Scope{ScopeOrdersWrite}}
+
+	tests := []struct {
+		name       string
+		principal  Principal
+		route      string
+		resolve    StoreResolver
+		target     string
+		body       string
+		wantStatus int
+	}{
+		{"own store", manager, "/stores/{storeUUID}", PathStore("storeUUID"), "/stores/" + own.String(), "", http.StatusOK},
+		{"other store", manager, "/stores/{storeUUID}", PathStore("storeUUID"), "/stores/" + other.String(), "", http.StatusForbidden},
+		{"admin in any store", admin, "/stores/{storeUUID}", PathStore("storeUUID"), "/stores/" + other.String(), "", http.StatusOK},
+		{"role not allowed", clerk, "/stores/{storeUUID}", PathStore("storeUUID"), "/stores/" + own.String(), "", http.StatusForbidden},
+		{"unresolved goes to the handler", manager, "/stores/{storeUUID}", PathStore("storeUUID"), "/stores/main", "", http.StatusOK},
+		{"query store", manager, "/export", QueryStore("store_uuid"), "/export?store_uuid=" + own.String(), "", http.StatusOK},
+		{"all stores are for admins", manager, "/export", QueryStore("store_uuid"), "/export", "", http.StatusForbidden},
+		{"all stores as admin", admin, "/export", QueryStore("store_uuid"), "/export", "", http.StatusOK},
+		{"every store", manager, "/stores", EveryStore, "/stores", "", http.StatusForbidden},
+		{"sku in own store", manager, "/skus/{skuUUID}", m.SKUStore, "/skus/" + ownSKU.String(), "", http.StatusOK},
+		{"sku in other store", manager, "/skus/{skuUUID}", m.SKUStore, "/skus/" + otherSKU.String(), "", http.StatusForbidden},
+		{"unknown sku goes to the handler", manager, "/skus/{skuUUID}", m.SKUStore, "/skus/" + uuid.NewString(), "", http.StatusOK},
+		{"body store", manager, "/skus", bodyStore, "/skus", `{"store_uuid":"` + own.String() + `"}`, http.StatusOK},
+		{"body in other store", manager, "/skus", bodyStore, "/skus", `{"store_uuid":"` + other.String() + `"}`, http.StatusForbidden},
+		{"body too large", manager, "/skus", bodyStore, "/skus", strings.Repeat(" ", maxResolveBody+1), http.StatusRequestEntityTooLarge},
+		{"api key in its store", storeKey, "/skus/{skuUUID}", m.SKUStore, "/skus/" + ownSKU.String(), "", http.StatusOK},
+		{"api key in another store", storeKey, "/skus/{skuUUID}", m.SKUStore, "/skus/" + otherSKU.String(), "", http.StatusForbidden},
+		{"api key restricted to stores", storeKey, "/stores", EveryStore, "/stores", "", http.StatusForbidden},
+		{"unrestricted api key", globalKey, "/skus/{skuUUID}", m.SKUStore, "/skus/" + otherSKU.String(), "", http.StatusOK},
+		{"api key without the scope", unscopedKey, "/skus/{skuUUID}", m.SKUStore, "/skus/" + ownSKU.String(), "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handled string
+			r := chi.NewRouter()
+			r.Use(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), tt.principal)))
+				})
+			})
+			r.With(RequireStore(tt.resolve, ScopeInventoryWrite, RoleStoreManager)).Handle(tt.route, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				handled = string(body)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			// The handler reads the body the resolver peeked at.
+			if rec.Code == http.StatusOK && handled != tt.body {
+				t.Errorf("handler got body %q, want %q", handled, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nikallow/bookstores-api/internal/config"
+)
+
+// minSecretLength is the shortest HS256 secret accepted, the size of the SHA-256 output.
+const minSecretLength = 32
+
+// keySet holds the keys tokens are verified with.
+type keySet struct {
+	secret []byte
+	// rsa keys by key ID; the key of the PEM file has an empty ID and verifies tokens without a kid.
+	rsa map[string]*rsa.PublicKey
+}
+
+func loadKeys(cfg config.AuthConfig) (keySet, error) {
+	keys := keySet{rsa: make(map[string]*rsa.PublicKey)}
+
+	if cfg.HS256Secret != "" {
+		if len(cfg.HS256Secret) < minSecretLength {
+			return keySet{}, fmt.Errorf("hs256 secret must be at least %d bytes", minSecretLength)
+		}
+		keys.secret = []byte(cfg.HS256Secret)
+	}
+
+	if cfg.RS256KeyFile != "" {
+		data, err := os.ReadFile(cfg.RS256KeyFile)
+		if err != nil {
+			return keySet{}, fmt.Errorf("failed to read rs256 key file: %w", err)
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return keySet{}, fmt.Errorf("failed to parse rs256 key file: %w", err)
+		}
+		keys.rsa[""] = key
+	}
+
+	if cfg.JWKSFile != "" {
+		data, err := os.ReadFile(cfg.JWKSFile)
+		if err != nil {
+			return keySet{}, fmt.Errorf("failed to read jwks file: %w", err)
+		}
+		jwks, err := parseJWKS(data)
+		if err != nil {
+			return keySet{}, fmt.Errorf("failed to parse jwks file: %w", err)
+		}
+		for kid, key := range jwks {
+			keys.rsa[kid] = key
+		}
+	}
+
+	if keys.secret == nil && len(keys.rsa) == 0 {
+		return keySet{}, errors.New("no token verification keys configured")
+	}
+	return keys, nil
+}
+
+// methods are the signing algorithms there are keys for.
+func (k keySet) methods() []string {
+	var methods []string
+	if k.secret != nil {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(k.rsa) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	return methods
+}
+
+// keyfunc picks the key for a token whose algorithm the parser has already checked.
+func (k keySet) keyfunc(t *jwt.Token) (any, error) {
+	if _, ok := t.Method.(*jwt.SigningMethodHMAC); ok {
+		return k.secret, nil
+	}
+	kid, _ := t.Header["kid"].(string)
+	key, ok := k.rsa[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// parseJWKS reads the RSA signing keys of a JSON Web Key Set (RFC 7517) by key ID.
+// Keys of other types or for encryption are skipped.
+func parseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") || (k.Alg != "" && k.Alg != "RS256") {
+			continue
+		}
+		if k.Kid == "" {
+			return nil, errors.New("rsa key without kid")
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: bad modulus: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: bad exponent: %w", k.Kid, err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("key %q: bad exponent", k.Kid)
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no rsa signing keys")
+	}
+	return keys, nil
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Role is what the caller may do, carried in the role claim of the token.
+type Role string
+
+const (
+	// RoleAdmin may do anything in any store.
+	RoleAdmin Role = "admin"
+	// RoleStoreManager runs the stores listed in the token: SKUs, prices, stock, transfers and orders.
+	RoleStoreManager Role = "store_manager"
+	// RoleClerk works in the stores listed in the token: stock, reservations and orders.
+	RoleClerk Role = "clerk"
+	// RoleReadOnly may only read.
+	RoleReadOnly Role = "read_only"
+)
+
+func (r Role) valid() bool {
+	switch r {
+	case RoleAdmin, RoleStoreManager, RoleClerk, RoleReadOnly:
+		return true
+	}
+	return false
+}
+
+// Scope is what an API key may do besides reading, one of the scopes it was issued with.
+type Scope string
+
+const (
+	// ScopeCatalogWrite changes books, authors, publishers, genres, tags and series and runs imports.
+	ScopeCatalogWrite Scope = "catalog:write"
+	// ScopeStoresWrite creates, updates and deletes stores.
+	ScopeStoresWrite Scope = "stores:write"
+	// ScopeInventoryWrite changes SKUs, prices and stock, reservations and transfers.
+	ScopeInventoryWrite Scope = "inventory:write"
+	// ScopeOrdersWrite creates orders and moves them through their statuses.
+	ScopeOrdersWrite Scope = "orders:write"
+	// ScopePricingWrite changes promotions and exchange rates.
+	ScopePricingWrite Scope = "pricing:write"
+	// ScopeExportsRead exports the inventory.
+	ScopeExportsRead Scope = "exports:read"
+)
+
+// Valid tells whether the scope is a known one.
+func (s Scope) Valid() bool {
+	switch s {
+	case ScopeCatalogWrite, ScopeStoresWrite, ScopeInventoryWrite, ScopeOrdersWrite, ScopePricingWrite, ScopeExportsRead:
+		return true
+	}
+	return false
+}
+
+// Principal is the authenticated caller of a request: a user with a token or a client with an API key.
+type Principal struct {
+	Subject string
+	// Role of a token; API keys have none.
+	Role Role
+	// Scopes of an API key; tokens have none.
+	Scopes []Scope
+	// Stores the caller works in: those of a store manager or a clerk, or the stores an API key is
+	// restricted to. An API key without stores works in every store.
+	Stores []uuid.UUID
+	// KeyPrefix identifies the API key the caller authenticated with, empty for tokens.
+	KeyPrefix string
+}
+
+// IsAPIKey tells whether the caller authenticated with an API key.
+func (p Principal) IsAPIKey() bool {
+	return p.KeyPrefix != ""
+}
+
+// Allows tells whether a token has one of the roles or an API key has the scope. An admin is allowed everything.
+func (p Principal) Allows(scope Scope, roles ...Role) bool {
+	if p.IsAPIKey() {
+		return slices.Contains(p.Scopes, scope)
+	}
+	return p.Role == RoleAdmin || slices.Contains(roles, p.Role)
+}
+
+// AllStores tells whether the caller works in every store: an admin or an API key without a store restriction.
+func (p Principal) AllStores() bool {
+	if p.IsAPIKey() {
+		return len(p.Stores) == 0
+	}
+	return p.Role == RoleAdmin
+}
+
+// CanAccessStore tells whether the caller is allowed the scope or one of the roles in the store.
+func (p Principal) CanAccessStore(store uuid.UUID, scope Scope, roles ...Role) bool {
+	if !p.Allows(scope, roles...) {
+		return false
+	}
+	return p.AllStores() || slices.Contains(p.Stores, store)
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the caller of the request, false if it is not authenticated.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
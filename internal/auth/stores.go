@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+)
+
+// maxResolveBody bounds the request body read to find the stores it touches.
+const maxResolveBody = 1 << 20
+
+var (
+	// errUnresolved means the stores of a request can't be known, e.g. its SKU doesn't exist;
+	// its handler is left to reject it.
+	errUnresolved   = errors.New("request stores are unresolved")
+	errBodyTooLarge = errors.New("request body is too large")
+)
+
+// StoreResolver returns the stores a request touches; none means all stores.
+type StoreResolver func(r *http.Request) ([]uuid.UUID, error)
+
+// EveryStore touches all stores, e.g. creating one, so only callers working in every store pass.
+func EveryStore(*http.Request) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+// PathStore is the store whose UUID is the URL parameter.
+func PathStore(param string) StoreResolver {
+	return func(r *http.Request) ([]uuid.UUID, error) {
+		id, err := uuid.Parse(chi.URLParam(r, param))
+		if err != nil {
+			return nil, errUnresolved
+		}
+		return []uuid.UUID{id}, nil
+	}
+}
+
+// QueryStore is the store whose UUID is the query parameter, all stores without it.
+func QueryStore(param string) StoreResolver {
+	return func(r *http.Request) ([]uuid.UUID, error) {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			return nil, nil
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, errUnresolved
+		}
+		return []uuid.UUID{id}, nil
+	}
+}
+
+// BodyStore is the store the JSON request body names. The body is decoded into the request
+// type of the handler, so both read it the same way.
+func BodyStore[T any](store func(T) uuid.UUID) StoreResolver {
+	return func(r *http.Request) ([]uuid.UUID, error) {
+		var body T
+		if err := peekJSON(r, &body); err != nil {
+			return nil, err
+		}
+		return []uuid.UUID{store(body)}, nil
+	}
+}
+
+// BodySKUStores are the stores of the SKUs the JSON request body names.
+func BodySKUStores[T any](m *Middleware, skus func(T) []uuid.UUID) StoreResolver {
+	return func(r *http.Request) ([]uuid.UUID, error) {
+		var body T
+		if err := peekJSON(r, &body); err != nil {
+			return nil, err
+		}
+		return m.skuStores(r, skus(body))
+	}
+}
+
+// SKUStore is the store of the SKU in the skuUUID URL parameter.
+func (m *Middleware) SKUStore(r *http.Request) ([]uuid.UUID, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "skuUUID"))
+	if err != nil {
+		return nil, errUnresolved
+	}
+	return m.skuStores(r, []uuid.UUID{id})
+}
+
+// ReservationStore is the store of the SKU held by the reservation in the reservationUUID URL parameter.
+func (m *Middleware) ReservationStore(r *http.Request) ([]uuid.UUID, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "reservationUUID"))
+	if err != nil {
+		return nil, errUnresolved
+	}
+	store, err := m.repo.GetReservationStoreUUID(r.Context(), pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errUnresolved
+		}
+		return nil, err
+	}
+	return []uuid.UUID{store.Bytes}, nil
+}
+
+// TransferSource is the sending store of the transfer in the transferUUID URL parameter.
+func (m *Middleware) TransferSource(r *http.Request) ([]uuid.UUID, error) {
+	stores, err := m.transferStores(r)
+	if err != nil {
+		return nil, err
+	}
+	return []uuid.UUID{stores.SourceStoreUuid.Bytes}, nil
+}
+
+// TransferDestination is the receiving store of the transfer in the transferUUID URL parameter.
+func (m *Middleware) TransferDestination(r *http.Request) ([]uuid.UUID, error) {
+	stores, err := m.transferStores(r)
+	if err != nil {
+		return nil, err
+	}
+	return []uuid.UUID{stores.DestinationStoreUuid.Bytes}, nil
+}
+
+// OrderStores are the stores of the SKUs of the order in the orderUUID URL parameter.
+func (m *Middleware) OrderStores(r *http.Request) ([]uuid.UUID, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "orderUUID"))
+	if err != nil {
+		return nil, errUnresolved
+	}
+	rows, err := m.repo.ListOrderStoreUUIDs(r.Context(), pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errUnresolved
+	}
+	stores := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		stores[i] = row.Bytes
+	}
+	return stores, nil
+}
+
+func (m *Middleware) skuStores(r *http.Request, ids []uuid.UUID) ([]uuid.UUID, error) {
+	if len(ids) == 0 {
+		return nil, errUnresolved
+	}
+	params := make([]pgtype.UUID, len(ids))
+	for i, id := range ids {
+		params[i] = pgtype.UUID{Bytes: id, Valid: true}
+	}
+	rows, err := m.repo.ListSKUStoreUUIDs(r.Context(), params)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[uuid.UUID]bool, len(rows))
+	stores := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		found[row.SkuUuid.Bytes] = true
+		stores = append(stores, row.StoreUuid.Bytes)
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return nil, errUnresolved
+		}
+	}
+	return stores, nil
+}
+
+func (m *Middleware) transferStores(r *http.Request) (repo.GetTransferStoreUUIDsRow, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "transferUUID"))
+	if err != nil {
+		return repo.GetTransferStoreUUIDsRow{}, errUnresolved
+	}
+	stores, err := m.repo.GetTransferStoreUUIDs(r.Context(), pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.GetTransferStoreUUIDsRow{}, errUnresolved
+		}
+		return repo.GetTransferStoreUUIDsRow{}, err
+	}
+	return stores, nil
+}
+
+// peekJSON decodes the request body the way handlers do and puts it back for the handler.
+// A body the handler can't decode either leaves the request unresolved.
+func peekJSON(r *http.Request, v any) error {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxResolveBody+1))
+	if err != nil {
+		return err
+	}
+	if len(body) > maxResolveBody {
+		return errBodyTooLarge
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(v); err != nil {
+		return errUnresolved
+	}
+	return nil
+}
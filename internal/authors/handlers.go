@@ -0,0 +1,243 @@
+package authors
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+	"github.com/nikallow/bookstores-api/internal/response"
+)
+
+type Handler struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// CreateAuthor
+//
+//	@Summary		Создать автора
+//	@Description	Добавляет автора в справочник. Авторы связываются с книгами через поле authors книги.
+//	@Tags			authors
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		CreateAuthorRequest		true	"Данные автора"
+//	@Success		201		{object}	AuthorResponse			"Автор создан"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/authors [post]
+func (h *Handler) CreateAuthor(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	var req CreateAuthorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read create author request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed for create author request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	author, err := h.service.Create(r.Context(), req)
+	if err != nil {
+		log.Error("Failed to create author", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusCreated, toAuthorResponse(author))
+}
+
+// ListAuthors
+//
+//	@Summary		Получить список авторов
+//	@Description	Возвращает страницу авторов, отсортированных по имени. Для следующей страницы передайте next_cursor из ответа.
+//	@Tags			authors
+//	@Produce		json
+//	@Param			name	query		string					false	"Фильтр по имени (подстрока)"
+//	@Param			limit	query		int						false	"Размер страницы (1-100)"	default(20)
+//	@Param			cursor	query		string					false	"Курсор следующей страницы"
+//	@Param			order	query		string					false	"Направление сортировки"	Enums(asc, desc)	default(asc)
+//	@Success		200		{object}	AuthorListResponse		"Страница авторов"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/authors [get]
+func (h *Handler) ListAuthors(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	query := r.URL.Query()
+	page, err := pagination.FromQuery(query, authorSorts...)
+	if err != nil {
+		log.Warn("Invalid list authors parameters", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := ListAuthorsParams{Request: page}
+	if name := query.Get("name"); name != "" {
+		params.Name = &name
+	}
+
+	authors, nextCursor, err := h.service.List(r.Context(), params)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error("Failed to list authors", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	resp := make([]AuthorResponse, len(authors))
+	for i, a := range authors {
+		resp[i] = toAuthorResponse(a)
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, AuthorListResponse{Items: resp, NextCursor: nextCursor})
+}
+
+// GetAuthor
+//
+//	@Summary		Получить автора
+//	@Description	Возвращает автора по его ID.
+//	@Tags			authors
+//	@Produce		json
+//	@Param			authorID	path		int						true	"ID автора"
+//	@Success		200			{object}	AuthorResponse			"Инфо об авторе"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Автор отсутствует"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/authors/{authorID} [get]
+func (h *Handler) GetAuthor(w http.ResponseWriter, r *http.Request) {
+	authorID, ok := parseAuthorID(w, r)
+	if !ok {
+		return
+	}
+
+	author, err := h.service.GetByID(r.Context(), authorID)
+	if err != nil {
+		writeAuthorError(w, r, err, authorID)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toAuthorResponse(author))
+}
+
+// UpdateAuthor
+//
+//	@Summary		Обновить автора
+//	@Description	Заменяет данные автора. Новое имя сразу попадает в строку author всех его книг.
+//	@Tags			authors
+//	@Accept			json
+//	@Produce		json
+//	@Param			authorID	path		int						true	"ID автора"
+//	@Param			input		body		UpdateAuthorRequest		true	"Новые данные автора"
+//	@Success		200			{object}	AuthorResponse			"Обновлённый автор"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Автор отсутствует"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/authors/{authorID} [put]
+func (h *Handler) UpdateAuthor(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	authorID, ok := parseAuthorID(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateAuthorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read update author request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed for update author request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	author, err := h.service.Update(r.Context(), authorID, req)
+	if err != nil {
+		writeAuthorError(w, r, err, authorID)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toAuthorResponse(author))
+}
+
+// DeleteAuthor
+//
+//	@Summary		Удалить автора
+//	@Description	Удаляет автора, если он не связан ни с одной книгой.
+//	@Tags			authors
+//	@Param			authorID	path	int	true	"ID автора"
+//	@Success		204			"Автор удалён"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Автор отсутствует"
+//	@Failure		409			{object}	response.ErrorResponse	"У автора есть книги"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/authors/{authorID} [delete]
+func (h *Handler) DeleteAuthor(w http.ResponseWriter, r *http.Request) {
+	authorID, ok := parseAuthorID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), authorID); err != nil {
+		writeAuthorError(w, r, err, authorID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAuthorError(w http.ResponseWriter, r *http.Request, err error, authorID int64) {
+	switch {
+	case errors.Is(err, ErrAuthorNotFound):
+		response.WriteError(w, r, http.StatusNotFound, "Author not found")
+	case errors.Is(err, ErrAuthorHasBooks):
+		response.WriteError(w, r, http.StatusConflict, err.Error())
+	default:
+		middleware.LoggerFromContext(r.Context()).Error("Author operation failed", "error", err, "author_id", authorID)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func parseAuthorID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	authorIDStr := chi.URLParam(r, "authorID")
+	authorID, err := strconv.ParseInt(authorIDStr, 10, 64)
+	if err != nil {
+		middleware.LoggerFromContext(r.Context()).Warn("Invalid author ID format", "author_id", authorIDStr)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid author ID format")
+		return 0, false
+	}
+	return authorID, true
+}
+
+func toAuthorResponse(author repo.Author) AuthorResponse {
+	resp := AuthorResponse{
+		ID:   author.ID,
+		Name: author.Name,
+	}
+	if author.Bio.Valid {
+		resp.Bio = &author.Bio.String
+	}
+	return resp
+}
@@ -0,0 +1,33 @@
+package authors
+
+import "github.com/nikallow/bookstores-api/internal/pagination"
+
+// Sort fields accepted by GET /authors, the first one is the default.
+var authorSorts = []string{"name"}
+
+type CreateAuthorRequest struct {
+	Name string  `json:"name"          validate:"required"`
+	Bio  *string `json:"bio,omitempty"`
+}
+
+// UpdateAuthorRequest replaces the author; a new name is propagated to the author string of their books.
+type UpdateAuthorRequest struct {
+	Name string  `json:"name" validate:"required"`
+	Bio  *string `json:"bio"`
+}
+
+type ListAuthorsParams struct {
+	pagination.Request
+	Name *string
+}
+
+type AuthorListResponse struct {
+	Items      []AuthorResponse `json:"items"`
+	NextCursor *string          `json:"next_cursor"`
+}
+
+type AuthorResponse struct {
+	ID   int64   `json:"id"`
+	Name string  `json:"name"`
+	Bio  *string `json:"bio,omitempty"`
+}
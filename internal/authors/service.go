@@ -0,0 +1,174 @@
+package authors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nikallow/bookstores-api/internal/adapters/postgres"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/books"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+)
+
+var (
+	ErrAuthorNotFound = books.ErrAuthorNotFound
+	ErrAuthorHasBooks = errors.New("author is linked to books")
+)
+
+// pgForeignKeyViolation is the SQLSTATE of a failed REFERENCES constraint, e.g. book_authors.author_id.
+const pgForeignKeyViolation = "23503"
+
+type Service interface {
+	Create(ctx context.Context, params CreateAuthorRequest) (repo.Author, error)
+	List(ctx context.Context, params ListAuthorsParams) ([]repo.Author, *string, error)
+	GetByID(ctx context.Context, id int64) (repo.Author, error)
+	Update(ctx context.Context, id int64, params UpdateAuthorRequest) (repo.Author, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type service struct {
+	repo repo.Querier
+	db   postgres.TxBeginner
+}
+
+func NewService(repo repo.Querier, db postgres.TxBeginner) Service {
+	return &service{repo: repo, db: db}
+}
+
+func (s *service) Create(ctx context.Context, params CreateAuthorRequest) (repo.Author, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	author, err := s.repo.CreateAuthor(ctx, repo.CreateAuthorParams{
+		Name: params.Name,
+		Bio:  stringToPgTextp(params.Bio),
+	})
+	if err != nil {
+		log.Error("Failed to create author", "error", err)
+		return repo.Author{}, fmt.Errorf("failed to create author: %w", err)
+	}
+
+	log.Info("Author created successfully", "author_id", author.ID)
+	return author, nil
+}
+
+func (s *service) List(ctx context.Context, params ListAuthorsParams) ([]repo.Author, *string, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	cursor, err := params.DecodeCursor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queryParams := repo.ListAuthorsParams{
+		Name:      stringToPgTextp(params.Name),
+		PageLimit: params.QueryLimit(),
+	}
+	if cursor != nil {
+		queryParams.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+		queryParams.CursorText = pgtype.Text{String: cursor.Value, Valid: true}
+	}
+
+	var authors []repo.Author
+	if params.Desc {
+		authors, err = s.repo.ListAuthorsDesc(ctx, repo.ListAuthorsDescParams(queryParams))
+	} else {
+		authors, err = s.repo.ListAuthors(ctx, queryParams)
+	}
+	if err != nil {
+		log.Error("Failed to list authors", "error", err)
+		return nil, nil, fmt.Errorf("failed to list authors: %w", err)
+	}
+
+	authors, hasMore := pagination.Trim(authors, params.Request)
+	if !hasMore {
+		return authors, nil, nil
+	}
+	last := authors[len(authors)-1]
+	next := params.NextCursor(last.Name, last.ID)
+	return authors, &next, nil
+}
+
+func (s *service) GetByID(ctx context.Context, id int64) (repo.Author, error) {
+	author, err := s.repo.GetAuthorByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Author{}, ErrAuthorNotFound
+		}
+		return repo.Author{}, fmt.Errorf("failed to get author: %w", err)
+	}
+	return author, nil
+}
+
+// Update renames the author and rewrites the author string of every linked book in one transaction.
+func (s *service) Update(ctx context.Context, id int64, params UpdateAuthorRequest) (repo.Author, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.Author{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+
+	author, err := qtx.UpdateAuthor(ctx, repo.UpdateAuthorParams{
+		ID:   id,
+		Name: params.Name,
+		Bio:  stringToPgTextp(params.Bio),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Author{}, ErrAuthorNotFound
+		}
+		log.Error("Failed to update author", "error", err, "author_id", id)
+		return repo.Author{}, fmt.Errorf("failed to update author: %w", err)
+	}
+
+	err = qtx.RefreshBookAuthorNames(ctx, repo.RefreshBookAuthorNamesParams{
+		AuthorID: pgtype.Int8{Int64: id, Valid: true},
+	})
+	if err != nil {
+		log.Error("Failed to refresh author names of books", "error", err, "author_id", id)
+		return repo.Author{}, fmt.Errorf("failed to update author: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return repo.Author{}, err
+	}
+
+	log.Info("Author updated successfully", "author_id", id)
+	return author, nil
+}
+
+// Delete removes an author that no book refers to.
+func (s *service) Delete(ctx context.Context, id int64) error {
+	log := middleware.LoggerFromContext(ctx)
+
+	deleted, err := s.repo.DeleteAuthor(ctx, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+			return ErrAuthorHasBooks
+		}
+		log.Error("Failed to delete author", "error", err, "author_id", id)
+		return fmt.Errorf("failed to delete author: %w", err)
+	}
+	if deleted == 0 {
+		return ErrAuthorNotFound
+	}
+
+	log.Info("Author deleted successfully", "author_id", id)
+	return nil
+}
+
+func stringToPgTextp(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{Valid: false}
+	}
+	return pgtype.Text{String: *s, Valid: true}
+}
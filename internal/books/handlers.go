@@ -1,45 +1,85 @@
 package books
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgtype"
 	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
 	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/money"
+	"github.com/nikallow/bookstores-api/internal/pagination"
 	"github.com/nikallow/bookstores-api/internal/response"
 )
 
+// PriceQuoter returns the price of one copy of each SKU after promotions, keyed by SKU ID.
+type PriceQuoter interface {
+	EffectivePrices(ctx context.Context, skus []repo.Sku) (map[int64]int32, error)
+}
+
+// Converter converts an amount into another currency at the stored exchange rates.
+type Converter interface {
+	Convert(ctx context.Context, m money.Money, to money.Currency) (money.Money, error)
+}
+
 type Handler struct {
-	service  Service
-	validate *validator.Validate
+	service   Service
+	prices    PriceQuoter
+	converter Converter
+	validate  *validator.Validate
 }
 
-func NewHandler(service Service) *Handler {
+func NewHandler(service Service, prices PriceQuoter, converter Converter) *Handler {
+	validate := validator.New()
+	registerISBNValidation(validate)
+
 	return &Handler{
-		service:  service,
-		validate: validator.New(),
+		service:   service,
+		prices:    prices,
+		converter: converter,
+		validate:  validate,
 	}
 }
 
 // CreateBook
 //
 //	@Summary		Создать новую книгу
-//	@Description	Создаёт новую книгу в глобальном каталоге. Если книга с таким ISBN уже существует, обновляет ее данные.
+//	@Description	Создаёт новую книгу в глобальном каталоге. Если книга с таким ISBN уже существует, возвращает 409 и её данные.
+//	@Description	С upsert=true существующая книга обновляется переданными полями (200).
 //	@Tags			books
 //	@Accept			json
 //	@Produce		json
 //	@Param			input	body		CreateBookRequest	true	"Данные для создания книги"
+//	@Param			upsert	query		bool				false	"Обновить книгу, если ISBN уже есть в каталоге"
+//	@Success		200		{object}	BookResponse		"Книга обновлена (upsert)"
 //	@Success		201		{object}	BookResponse		"Инфо об книге"
 //	@Failure		400		{object}	response.ErrorResponse
+//	@Failure		409		{object}	BookConflictResponse	"Книга с таким ISBN уже существует"
 //	@Failure		500		{object}	response.ErrorResponse
 //	@Router			/books [post]
 func (h *Handler) CreateBook(w http.ResponseWriter, r *http.Request) {
 	log := middleware.LoggerFromContext(r.Context())
 
+	var upsert bool
+	if v := r.URL.Query().Get("upsert"); v != "" {
+		var err error
+		if upsert, err = strconv.ParseBool(v); err != nil {
+			response.WriteError(w, r, http.StatusBadRequest, "invalid upsert")
+			return
+		}
+	}
+
 	var req CreateBookRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Warn("Failed to read create book request", "error", err)
@@ -52,41 +92,280 @@ func (h *Handler) CreateBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if upsert {
+		h.upsert(w, r, req, false)
+		return
+	}
+
 	book, err := h.service.Create(r.Context(), req)
 	if err != nil {
-		log.Error("Failed to create book", "error", err)
-		response.WriteError(w, r, http.StatusInternalServerError, "failed to create book")
+		switch {
+		case errors.Is(err, ErrBookAlreadyExists):
+			resp, err := h.bookResponses(r.Context(), book)
+			if err != nil {
+				log.Error("Failed to load book relations", "error", err, "book_id", book.ID)
+				response.WriteError(w, r, http.StatusInternalServerError, "failed to create book")
+				return
+			}
+			response.WriteJSON(w, r, http.StatusConflict, BookConflictResponse{
+				Error: ErrBookAlreadyExists.Error(),
+				Book:  resp[0],
+			})
+		case errors.Is(err, ErrBookDeleted):
+			response.WriteError(w, r, http.StatusConflict, err.Error())
+		case IsInputError(err):
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		default:
+			log.Error("Failed to create book", "error", err)
+			response.WriteError(w, r, http.StatusInternalServerError, "failed to create book")
+		}
+		return
+	}
+
+	h.writeBook(w, r, http.StatusCreated, book)
+}
+
+// GetBookByISBN
+//
+//	@Summary		Найти книгу по ISBN
+//	@Description	Возвращает книгу по ISBN-10 или ISBN-13, дефисы и пробелы допускаются.
+//	@Tags			books
+//	@Produce		json
+//	@Param			isbn	path		string					true	"ISBN книги"
+//	@Success		200		{object}	BookResponse			"Инфо о книге"
+//	@Failure		400		{object}	response.ErrorResponse	"Некорректный ISBN"
+//	@Failure		404		{object}	response.ErrorResponse	"Книга отсутствует"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/books/isbn/{isbn} [get]
+func (h *Handler) GetBookByISBN(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	isbn := chi.URLParam(r, "isbn")
+	book, err := h.service.GetByISBN(r.Context(), isbn)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidISBN):
+			response.WriteError(w, r, http.StatusBadRequest, "Invalid isbn")
+		case errors.Is(err, ErrBookNotFound):
+			response.WriteError(w, r, http.StatusNotFound, "Book not found")
+		default:
+			log.Error("Failed to get book by isbn", "error", err, "isbn", isbn)
+			response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		}
 		return
 	}
 
-	response.WriteJSON(w, r, http.StatusCreated, ToBookResponse(book))
+	h.writeBook(w, r, http.StatusOK, book)
+}
+
+// UpsertBookByISBN
+//
+//	@Summary		Создать или заменить книгу по ISBN
+//	@Description	Идемпотентная синхронизация каталога: создаёт книгу с этим ISBN (201) или полностью заменяет её данные (200).
+//	@Description	Удалённая книга с этим ISBN восстанавливается.
+//	@Tags			books
+//	@Accept			json
+//	@Produce		json
+//	@Param			isbn	path		string					true	"ISBN книги"
+//	@Param			input	body		UpsertBookRequest		true	"Данные книги"
+//	@Success		200		{object}	BookResponse			"Книга обновлена"
+//	@Success		201		{object}	BookResponse			"Книга создана"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/books/isbn/{isbn} [put]
+func (h *Handler) UpsertBookByISBN(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	isbn := chi.URLParam(r, "isbn")
+	if err := h.validate.Var(isbn, "required,isbn"); err != nil {
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid isbn")
+		return
+	}
+
+	var req UpsertBookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read upsert book request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed for upsert book request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.upsert(w, r, CreateBookRequest{
+		ISBN:            &isbn,
+		Title:           req.Title,
+		Author:          req.Author,
+		Authors:         req.Authors,
+		PublisherID:     req.PublisherID,
+		GenreIDs:        req.GenreIDs,
+		Tags:            req.Tags,
+		SeriesID:        req.SeriesID,
+		SeriesVolume:    req.SeriesVolume,
+		Description:     req.Description,
+		PageCount:       req.PageCount,
+		PublicationYear: req.PublicationYear,
+	}, true)
+}
+
+func (h *Handler) upsert(w http.ResponseWriter, r *http.Request, req CreateBookRequest, replace bool) {
+	book, created, err := h.service.Upsert(r.Context(), req, replace)
+	if IsInputError(err) {
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		middleware.LoggerFromContext(r.Context()).Error("Failed to upsert book", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "failed to upsert book")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	h.writeBook(w, r, status, book)
 }
 
 // ListBooks
 //
 //	@Summary		Получить глобальный список книг
-//	@Description	Возвращает список всех книг в глобальном каталоге.
+//	@Description	Возвращает страницу книг из глобального каталога. Пагинация курсорная: для следующей страницы передайте next_cursor из ответа.
 //	@Tags			books
 //	@Produce		json
-//	@Success		200	{array}		BookResponse			"Список книг"
-//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Param			limit		query		int						false	"Размер страницы (1-100)"	default(20)
+//	@Param			cursor		query		string					false	"Курсор следующей страницы"
+//	@Param			sort		query		string					false	"Поле сортировки"			Enums(title, author, publication_year, series_volume, created_at)	default(title)
+//	@Param			order		query		string					false	"Направление сортировки"	Enums(asc, desc)													default(asc)
+//	@Param			author		query		string					false	"Фильтр по автору (подстрока)"
+//	@Param			year_from	query		int						false	"Год издания от"
+//	@Param			year_to		query		int						false	"Год издания до"
+//	@Param			genre_id	query		int						false	"Жанр (включая поджанры)"
+//	@Param			tag			query		string					false	"Тег"
+//	@Param			series_id	query		int						false	"Серия"
+//	@Param			decade		query		int						false	"Десятилетие издания, например 1990"
+//	@Param			in_stock	query		bool					false	"Есть ли в наличии хотя бы в одном магазине"
+//	@Param			facets		query		bool					false	"Вернуть фасеты по жанрам, авторам, десятилетиям и наличию"
+//	@Success		200			{object}	BookListResponse		"Страница книг"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
 //	@Router			/books [get]
 func (h *Handler) ListBooks(w http.ResponseWriter, r *http.Request) {
 	log := middleware.LoggerFromContext(r.Context())
 
-	books, err := h.service.List(r.Context())
+	query := r.URL.Query()
+	pageReq, err := pagination.FromQuery(query, bookSorts...)
 	if err != nil {
+		log.Warn("Invalid list books parameters", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filters, withFacets, err := parseBookFilters(query)
+	if err != nil {
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	params := ListBooksParams{Request: pageReq, BookFilters: filters}
+
+	books, nextCursor, err := h.service.List(r.Context(), params)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) || errors.Is(err, ErrGenreNotFound) {
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
 		log.Error("Failed to list books", "error", err)
 		response.WriteError(w, r, http.StatusInternalServerError, "failed to list books")
 		return
 	}
 
-	resp := make([]BookResponse, len(books))
-	for i, b := range books {
-		resp[i] = ToBookResponse(b)
+	resp, err := h.bookResponses(r.Context(), books...)
+	if err != nil {
+		log.Error("Failed to load book relations", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "failed to list books")
+		return
 	}
 
-	response.WriteJSON(w, r, http.StatusOK, resp)
+	list := BookListResponse{Items: resp, NextCursor: nextCursor}
+	if withFacets {
+		if list.Facets, err = h.facets(r.Context(), nil, filters); err != nil {
+			log.Error("Failed to count book facets", "error", err)
+			response.WriteError(w, r, http.StatusInternalServerError, "failed to list books")
+			return
+		}
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, list)
+}
+
+// ListAuthorBooks
+//
+//	@Summary		Книги автора
+//	@Description	Возвращает страницу книг автора, отсортированных по названию. role оставляет только книги, где автор выступает в этой роли.
+//	@Tags			authors
+//	@Produce		json
+//	@Param			authorID	path		int						true	"ID автора"
+//	@Param			role		query		string					false	"Роль автора в книге"		Enums(author, translator, illustrator)
+//	@Param			limit		query		int						false	"Размер страницы (1-100)"	default(20)
+//	@Param			cursor		query		string					false	"Курсор следующей страницы"
+//	@Param			order		query		string					false	"Направление сортировки"	Enums(asc, desc)	default(asc)
+//	@Success		200			{object}	BookListResponse		"Страница книг автора"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Автор отсутствует"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/authors/{authorID}/books [get]
+func (h *Handler) ListAuthorBooks(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	authorIDStr := chi.URLParam(r, "authorID")
+	authorID, err := strconv.ParseInt(authorIDStr, 10, 64)
+	if err != nil {
+		log.Warn("Invalid author ID format", "author_id", authorIDStr)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid author ID format")
+		return
+	}
+
+	query := r.URL.Query()
+	page, err := pagination.FromQuery(query, "title")
+	if err != nil {
+		log.Warn("Invalid list author books parameters", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var role *string
+	if v := query.Get("role"); v != "" {
+		if err := h.validate.Var(v, "oneof=author translator illustrator"); err != nil {
+			response.WriteError(w, r, http.StatusBadRequest, "Invalid role")
+			return
+		}
+		role = &v
+	}
+
+	books, nextCursor, err := h.service.ListByAuthor(r.Context(), authorID, role, page)
+	if err != nil {
+		switch {
+		case errors.Is(err, pagination.ErrInvalidCursor):
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		case errors.Is(err, ErrAuthorNotFound):
+			response.WriteError(w, r, http.StatusNotFound, "Author not found")
+		default:
+			log.Error("Failed to list author books", "error", err, "author_id", authorID)
+			response.WriteError(w, r, http.StatusInternalServerError, "failed to list books")
+		}
+		return
+	}
+
+	resp, err := h.bookResponses(r.Context(), books...)
+	if err != nil {
+		log.Error("Failed to load book relations", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "failed to list books")
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, BookListResponse{Items: resp, NextCursor: nextCursor})
 }
 
 // GetBook
@@ -123,56 +402,111 @@ func (h *Handler) GetBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.WriteJSON(w, r, http.StatusOK, ToBookResponse(book))
+	h.writeBook(w, r, http.StatusOK, book)
 }
 
 // SearchBooks
 //
 //	@Summary		Поиск книг
-//	@Description	Ищет книги по части названия или имени автора.
+//	@Description	Полнотекстовый поиск по названию, автору, описанию и ISBN (русская и английская морфология) с нечётким совпадением для опечаток. Результаты отсортированы по релевантности.
 //	@Tags			books
 //	@Produce		json
-//	@Param			q	query		string					true	"Поисковый запрос"
-//	@Success		200	{array}		BookResponse			"Список найденных книг"
-//	@Failure		400	{object}	response.ErrorResponse	"Bad request error"
-//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Param			q			query		string					true	"Поисковый запрос"
+//	@Param			limit		query		int						false	"Размер страницы (1-100)"	default(20)
+//	@Param			cursor		query		string					false	"Курсор следующей страницы"
+//	@Param			author		query		string					false	"Фильтр по автору (подстрока)"
+//	@Param			year_from	query		int						false	"Год издания от"
+//	@Param			year_to		query		int						false	"Год издания до"
+//	@Param			genre_id	query		int						false	"Жанр (включая поджанры)"
+//	@Param			tag			query		string					false	"Тег"
+//	@Param			series_id	query		int						false	"Серия"
+//	@Param			decade		query		int						false	"Десятилетие издания, например 1990"
+//	@Param			in_stock	query		bool					false	"Есть ли в наличии хотя бы в одном магазине"
+//	@Param			facets		query		bool					false	"Вернуть фасеты по жанрам, авторам, десятилетиям и наличию"
+//	@Success		200			{object}	SearchResponse			"Найденные книги с релевантностью и фрагментами"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
 //	@Router			/books/search [get]
 func (h *Handler) SearchBooks(w http.ResponseWriter, r *http.Request) {
 	log := middleware.LoggerFromContext(r.Context())
 
-	query := r.URL.Query().Get("q")
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
 	if query == "" {
 		log.Error("No search query")
 		response.WriteError(w, r, http.StatusBadRequest, "query parameter 'q' is required")
 		return
 	}
 
-	books, err := h.service.Search(r.Context(), query)
+	page, err := pagination.FromQuery(r.URL.Query(), "relevance")
+	if err != nil {
+		log.Warn("Invalid search parameters", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filters, withFacets, err := parseBookFilters(r.URL.Query())
+	if err != nil {
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, nextCursor, err := h.service.Search(r.Context(), query, filters, page)
 	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) || errors.Is(err, ErrGenreNotFound) {
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
 		log.Error("Failed to search books", "error", err)
 		response.WriteError(w, r, http.StatusInternalServerError, "failed to search books")
 		return
 	}
 
-	resp := make([]BookResponse, len(books))
-	for i, b := range books {
-		resp[i] = ToBookResponse(b)
+	books := make([]repo.Book, len(results))
+	for i, res := range results {
+		books[i] = res.Book
+	}
+	bookResps, err := h.bookResponses(r.Context(), books...)
+	if err != nil {
+		log.Error("Failed to load book relations", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "failed to search books")
+		return
 	}
 
-	response.WriteJSON(w, r, http.StatusOK, resp)
+	resp := make([]SearchResultResponse, len(results))
+	for i, res := range results {
+		resp[i] = SearchResultResponse{
+			Book:    bookResps[i],
+			Rank:    res.Rank,
+			Snippet: res.Snippet,
+		}
+	}
+
+	search := SearchResponse{Items: resp, NextCursor: nextCursor}
+	if withFacets {
+		if search.Facets, err = h.facets(r.Context(), &query, filters); err != nil {
+			log.Error("Failed to count book facets", "error", err)
+			response.WriteError(w, r, http.StatusInternalServerError, "failed to search books")
+			return
+		}
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, search)
 }
 
 // GetBookAvailability
 //
 //	@Summary		Доступность книги
 //	@Description	Показывает, в каких магазинах, по какой цене и в каком количестве доступна книга.
+//	@Description	effective_price_in_kopeks - цена одного экземпляра с учётом действующих акций, в минорных единицах валюты магазина.
+//	@Description	С параметром currency цены дополнительно пересчитываются в эту валюту по сохранённым курсам (поле converted).
 //	@Tags			books
 //	@Produce		json
-//	@Param			bookID	path		int	true	"ID книги"
-//	@Success		200		{array}		AvailabilityResponse
-//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
-//	@Failure		404		{object}	response.ErrorResponse	"Книга отсутствует"
-//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Param			bookID		path		int		true	"ID книги"
+//	@Param			currency	query		string	false	"Валюта для пересчёта цен (ISO 4217)"
+//	@Success		200			{array}		AvailabilityResponse
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error или нет курса для пересчёта"
+//	@Failure		404			{object}	response.ErrorResponse	"Книга отсутствует"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
 //	@Router			/books/{bookID}/availability [get]
 func (h *Handler) GetBookAvailability(w http.ResponseWriter, r *http.Request) {
 	log := middleware.LoggerFromContext(r.Context())
@@ -184,6 +518,14 @@ func (h *Handler) GetBookAvailability(w http.ResponseWriter, r *http.Request) {
 		response.WriteError(w, r, http.StatusBadRequest, "Invalid book ID format")
 		return
 	}
+	var target money.Currency
+	if c := r.URL.Query().Get("currency"); c != "" {
+		target, err = money.ParseCurrency(c)
+		if err != nil {
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
 
 	availability, err := h.service.GetAvailability(r.Context(), bookID)
 	if err != nil {
@@ -197,20 +539,361 @@ func (h *Handler) GetBookAvailability(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	skus := make([]repo.Sku, len(availability))
+	for i, a := range availability {
+		skus[i] = a.Sku
+	}
+	prices, err := h.prices.EffectivePrices(r.Context(), skus)
+	if err != nil {
+		log.Error("Failed to get effective prices", "error", err, "book_id", bookID)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
 	resp := make([]AvailabilityResponse, len(availability))
 	for i, a := range availability {
 		resp[i] = AvailabilityResponse{
-			StoreUUID:     a.Store.Uuid.Bytes,
-			StoreName:     a.Store.Name,
-			SkuUUID:       a.Sku.Uuid.Bytes,
-			PriceInKopeks: a.Sku.PriceInKopeks,
-			StockCount:    a.Sku.StockCount,
+			StoreUUID:              a.Store.Uuid.Bytes,
+			StoreName:              a.Store.Name,
+			SkuUUID:                a.Sku.Uuid.Bytes,
+			Currency:               a.Sku.Currency,
+			PriceInKopeks:          a.Sku.PriceInKopeks,
+			EffectivePriceInKopeks: prices[a.Sku.ID],
+			StockCount:             a.Sku.StockCount,
+			Available:              a.Sku.StockCount - a.Sku.ReservedCount,
+		}
+		if target == "" {
+			continue
 		}
+		converted, err := h.convertPrices(r.Context(), resp[i], target)
+		if err != nil {
+			if errors.Is(err, money.ErrNoExchangeRate) {
+				response.WriteError(w, r, http.StatusBadRequest, err.Error())
+				return
+			}
+			log.Error("Failed to convert prices", "error", err, "book_id", bookID, "currency", target)
+			response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+		resp[i].Converted = &converted
 	}
 
 	response.WriteJSON(w, r, http.StatusOK, resp)
 }
 
+// UpdateBook
+//
+//	@Summary		Обновить книгу
+//	@Description	Полностью заменяет данные книги. Не переданные необязательные поля очищаются.
+//	@Tags			books
+//	@Accept			json
+//	@Produce		json
+//	@Param			bookID	path		int						true	"ID книги"
+//	@Param			input	body		UpdateBookRequest		true	"Новые данные книги"
+//	@Success		200		{object}	BookResponse			"Обновлённая книга"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"Книга отсутствует"
+//	@Failure		409		{object}	response.ErrorResponse	"ISBN занят другой книгой"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/books/{bookID} [put]
+func (h *Handler) UpdateBook(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	bookID, ok := parseBookID(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateBookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read update book request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	h.update(w, r, bookID, req)
+}
+
+// PatchBook
+//
+//	@Summary		Частично обновить книгу
+//	@Description	Применяет JSON Merge Patch (RFC 7386) к книге: переданные поля заменяются, null очищает поле.
+//	@Tags			books
+//	@Accept			json
+//	@Accept			application/merge-patch+json
+//	@Produce		json
+//	@Param			bookID	path		int						true	"ID книги"
+//	@Param			input	body		UpdateBookRequest		true	"Изменяемые поля"
+//	@Success		200		{object}	BookResponse			"Обновлённая книга"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"Книга отсутствует"
+//	@Failure		409		{object}	response.ErrorResponse	"ISBN занят другой книгой"
+//	@Failure		415		{object}	response.ErrorResponse	"Неподдерживаемый Content-Type"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/books/{bookID} [patch]
+func (h *Handler) PatchBook(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	bookID, ok := parseBookID(w, r)
+	if !ok {
+		return
+	}
+
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType != "" && contentType != "application/json" && contentType != "application/merge-patch+json" {
+		response.WriteError(w, r, http.StatusUnsupportedMediaType, "expected application/merge-patch+json")
+		return
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Warn("Failed to read patch book request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	book, err := h.service.Patch(r.Context(), bookID, func(current UpdateBookRequest) (UpdateBookRequest, error) {
+		doc, err := json.Marshal(current)
+		if err != nil {
+			return UpdateBookRequest{}, err
+		}
+		merged, err := mergePatch(doc, patch)
+		if err != nil {
+			log.Warn("Invalid merge patch", "error", err)
+			return UpdateBookRequest{}, ErrInvalidPatch
+		}
+
+		var req UpdateBookRequest
+		dec := json.NewDecoder(bytes.NewReader(merged))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			log.Warn("Merge patch produced an invalid book", "error", err)
+			return UpdateBookRequest{}, fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+		}
+		if err := h.validate.Struct(req); err != nil {
+			log.Warn("Validation failed for patched book", "error", err)
+			return UpdateBookRequest{}, fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+		}
+		return req, nil
+	})
+	if err != nil {
+		h.writeBookError(w, r, err, bookID)
+		return
+	}
+
+	h.writeBook(w, r, http.StatusOK, book)
+}
+
+// DeleteBook
+//
+//	@Summary		Удалить книгу
+//	@Description	Мягко удаляет книгу: она пропадает из каталога, поиска и доступности. С cascade=true удаляются и её SKU.
+//	@Tags			books
+//	@Param			bookID	path	int		true	"ID книги"
+//	@Param			cascade	query	bool	false	"Удалить также SKU книги во всех магазинах"
+//	@Success		204		"Книга удалена"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"Книга отсутствует"
+//	@Failure		409		{object}	response.ErrorResponse	"Часть запаса книги зарезервирована или в пути (cascade=true)"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/books/{bookID} [delete]
+func (h *Handler) DeleteBook(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := parseBookID(w, r)
+	if !ok {
+		return
+	}
+
+	var cascade bool
+	if v := r.URL.Query().Get("cascade"); v != "" {
+		var err error
+		if cascade, err = strconv.ParseBool(v); err != nil {
+			response.WriteError(w, r, http.StatusBadRequest, "Invalid cascade")
+			return
+		}
+	}
+
+	if err := h.service.Delete(r.Context(), bookID, cascade); err != nil {
+		h.writeBookError(w, r, err, bookID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestoreBook
+//
+//	@Summary		Восстановить книгу
+//	@Description	Отменяет мягкое удаление книги. SKU, удалённые вместе с ней (cascade), тоже восстанавливаются.
+//	@Tags			books
+//	@Produce		json
+//	@Param			bookID	path		int						true	"ID книги"
+//	@Success		200		{object}	BookResponse			"Восстановленная книга"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"Книга отсутствует"
+//	@Failure		409		{object}	response.ErrorResponse	"Книга не удалена"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/books/{bookID}/restore [post]
+func (h *Handler) RestoreBook(w http.ResponseWriter, r *http.Request) {
+	bookID, ok := parseBookID(w, r)
+	if !ok {
+		return
+	}
+
+	book, err := h.service.Restore(r.Context(), bookID)
+	if err != nil {
+		h.writeBookError(w, r, err, bookID)
+		return
+	}
+
+	h.writeBook(w, r, http.StatusOK, book)
+}
+
+func (h *Handler) update(w http.ResponseWriter, r *http.Request, bookID int64, req UpdateBookRequest) {
+	if err := h.validate.Struct(req); err != nil {
+		middleware.LoggerFromContext(r.Context()).Warn("Validation failed for update book request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	book, err := h.service.Update(r.Context(), bookID, req)
+	if err != nil {
+		h.writeBookError(w, r, err, bookID)
+		return
+	}
+
+	h.writeBook(w, r, http.StatusOK, book)
+}
+
+// writeBook responds with the book together with its authors and publisher.
+func (h *Handler) writeBook(w http.ResponseWriter, r *http.Request, status int, book repo.Book) {
+	resp, err := h.bookResponses(r.Context(), book)
+	if err != nil {
+		h.writeBookError(w, r, err, book.ID)
+		return
+	}
+	response.WriteJSON(w, r, status, resp[0])
+}
+
+func (h *Handler) bookResponses(ctx context.Context, books ...repo.Book) ([]BookResponse, error) {
+	relations, err := h.service.Relations(ctx, books...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]BookResponse, len(books))
+	for i, b := range books {
+		resp[i] = ToBookResponse(b)
+		rel := relations[b.ID]
+		for _, a := range rel.Authors {
+			resp[i].Authors = append(resp[i].Authors, BookAuthorResponse{
+				ID:   a.Author.ID,
+				Name: a.Author.Name,
+				Role: string(a.Role),
+			})
+		}
+		if rel.Publisher != nil {
+			resp[i].Publisher = &BookPublisherResponse{ID: rel.Publisher.ID, Name: rel.Publisher.Name}
+		}
+		for _, g := range rel.Genres {
+			resp[i].Genres = append(resp[i].Genres, BookGenreResponse{ID: g.ID, Name: g.Name})
+		}
+		resp[i].Tags = rel.Tags
+		if rel.Series != nil {
+			resp[i].Series = &BookSeriesResponse{ID: rel.Series.ID, Name: rel.Series.Name}
+			if b.SeriesVolume.Valid {
+				resp[i].Series.Volume = &b.SeriesVolume.Int32
+			}
+		}
+	}
+	return resp, nil
+}
+
+func (h *Handler) facets(ctx context.Context, query *string, filters BookFilters) (*FacetsResponse, error) {
+	rows, err := h.service.Facets(ctx, query, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	facets := &FacetsResponse{
+		Genres:  []FacetValueResponse{},
+		Authors: []FacetValueResponse{},
+		Decades: []FacetValueResponse{},
+		InStock: []FacetValueResponse{},
+	}
+	for _, row := range rows {
+		value := FacetValueResponse{Value: row.Value, Label: row.Label, Count: row.Count}
+		switch row.Facet {
+		case "genre":
+			facets.Genres = append(facets.Genres, value)
+		case "author":
+			facets.Authors = append(facets.Authors, value)
+		case "decade":
+			facets.Decades = append(facets.Decades, value)
+		case "in_stock":
+			facets.InStock = append(facets.InStock, value)
+		}
+	}
+	return facets, nil
+}
+
+// convertPrices converts the base and effective price of an availability entry.
+func (h *Handler) convertPrices(ctx context.Context, a AvailabilityResponse, to money.Currency) (ConvertedPriceResponse, error) {
+	from := money.Currency(a.Currency)
+	price, err := h.converter.Convert(ctx, money.New(int64(a.PriceInKopeks), from), to)
+	if err != nil {
+		return ConvertedPriceResponse{}, err
+	}
+	effective, err := h.converter.Convert(ctx, money.New(int64(a.EffectivePriceInKopeks), from), to)
+	if err != nil {
+		return ConvertedPriceResponse{}, err
+	}
+	return ConvertedPriceResponse{
+		Currency:               string(to),
+		PriceInKopeks:          price.Amount,
+		EffectivePriceInKopeks: effective.Amount,
+	}, nil
+}
+
+func (h *Handler) writeBookError(w http.ResponseWriter, r *http.Request, err error, bookID int64) {
+	switch {
+	case errors.Is(err, ErrBookNotFound):
+		response.WriteError(w, r, http.StatusNotFound, "Book not found")
+	case IsInputError(err):
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrISBNTaken), errors.Is(err, ErrBookNotDeleted), errors.Is(err, ErrBookStockInUse):
+		response.WriteError(w, r, http.StatusConflict, err.Error())
+	default:
+		middleware.LoggerFromContext(r.Context()).Error("Book operation failed", "error", err, "book_id", bookID)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func parseBookID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	bookIDStr := chi.URLParam(r, "bookID")
+	bookID, err := strconv.ParseInt(bookIDStr, 10, 64)
+	if err != nil {
+		middleware.LoggerFromContext(r.Context()).Warn("Invalid book ID format", "book_id", bookIDStr)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid book ID format")
+		return 0, false
+	}
+	return bookID, true
+}
+
+func toUpdateBookRequest(book repo.Book) UpdateBookRequest {
+	resp := ToBookResponse(book)
+	return UpdateBookRequest{
+		ISBN:            resp.ISBN,
+		Title:           resp.Title,
+		Author:          resp.Author,
+		PublisherID:     int64p(book.PublisherID),
+		SeriesID:        int64p(book.SeriesID),
+		SeriesVolume:    int32p(book.SeriesVolume),
+		Description:     resp.Description,
+		PageCount:       resp.PageCount,
+		PublicationYear: resp.PublicationYear,
+	}
+}
+
 func ToBookResponse(book repo.Book) BookResponse {
 	resp := BookResponse{
 		ID:     book.ID,
@@ -231,3 +914,80 @@ func ToBookResponse(book repo.Book) BookResponse {
 	}
 	return resp
 }
+
+func int64p(i pgtype.Int8) *int64 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int64
+}
+
+func int32p(i pgtype.Int4) *int32 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int32
+}
+
+// parseBookFilters reads the catalog filters shared by the list and the search, and ?facets.
+func parseBookFilters(query url.Values) (BookFilters, bool, error) {
+	var filters BookFilters
+	var err error
+
+	if author := query.Get("author"); author != "" {
+		filters.Author = &author
+	}
+	if tag := query.Get("tag"); tag != "" {
+		filters.Tag = &tag
+	}
+	if filters.YearFrom, err = parseInt32Query(query.Get("year_from")); err != nil {
+		return BookFilters{}, false, errors.New("Invalid year_from")
+	}
+	if filters.YearTo, err = parseInt32Query(query.Get("year_to")); err != nil {
+		return BookFilters{}, false, errors.New("Invalid year_to")
+	}
+	if filters.Decade, err = parseInt32Query(query.Get("decade")); err != nil || (filters.Decade != nil && *filters.Decade%10 != 0) {
+		return BookFilters{}, false, errors.New("Invalid decade")
+	}
+	if v := query.Get("genre_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return BookFilters{}, false, errors.New("Invalid genre_id")
+		}
+		filters.GenreID = &id
+	}
+	if v := query.Get("series_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return BookFilters{}, false, errors.New("Invalid series_id")
+		}
+		filters.SeriesID = &id
+	}
+	if v := query.Get("in_stock"); v != "" {
+		inStock, err := strconv.ParseBool(v)
+		if err != nil {
+			return BookFilters{}, false, errors.New("Invalid in_stock")
+		}
+		filters.InStock = &inStock
+	}
+
+	var withFacets bool
+	if v := query.Get("facets"); v != "" {
+		if withFacets, err = strconv.ParseBool(v); err != nil {
+			return BookFilters{}, false, errors.New("Invalid facets")
+		}
+	}
+	return filters, withFacets, nil
+}
+
+func parseInt32Query(value string) (*int32, error) {
+	if value == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	i := int32(v)
+	return &i, nil
+}
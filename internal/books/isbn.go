@@ -0,0 +1,93 @@
+package books
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var ErrInvalidISBN = errors.New("invalid isbn")
+
+// NormalizeISBN strips hyphens and spaces from an ISBN-10 or ISBN-13, verifies its
+// check digit and returns the canonical ISBN-13 that is stored in books.isbn.
+func NormalizeISBN(isbn string) (string, error) {
+	digits := strings.Map(func(r rune) rune {
+		switch r {
+		case '-', ' ':
+			return -1
+		case 'x':
+			return 'X'
+		}
+		return r
+	}, isbn)
+
+	switch len(digits) {
+	case 10:
+		if !validISBN10(digits) {
+			return "", ErrInvalidISBN
+		}
+		body := "978" + digits[:9]
+		return body + string(isbn13CheckDigit(body)), nil
+	case 13:
+		if !isDigits(digits) || isbn13CheckDigit(digits[:12]) != digits[12] {
+			return "", ErrInvalidISBN
+		}
+		return digits, nil
+	default:
+		return "", ErrInvalidISBN
+	}
+}
+
+// registerISBNValidation overrides the validator's built-in isbn tag, which rejects
+// hyphenated input, with NormalizeISBN.
+func registerISBNValidation(v *validator.Validate) {
+	err := v.RegisterValidation("isbn", func(fl validator.FieldLevel) bool {
+		_, err := NormalizeISBN(fl.Field().String())
+		return err == nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func validISBN10(s string) bool {
+	if !isDigits(s[:9]) {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(s[i]-'0') * (10 - i)
+	}
+	switch c := s[9]; {
+	case c == 'X':
+		sum += 10
+	case c >= '0' && c <= '9':
+		sum += int(c - '0')
+	default:
+		return false
+	}
+	return sum%11 == 0
+}
+
+// isbn13CheckDigit computes the check digit for the first 12 digits of an ISBN-13.
+func isbn13CheckDigit(s string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := int(s[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
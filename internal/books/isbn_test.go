@@ -0,0 +1,71 @@
+package books
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"isbn-13", "9780306406157", "9780306406157", false},
+		{"isbn-13 with hyphens", "978-0-306-40615-7", "9780306406157", false},
+		{"isbn-13 with spaces", "978 0 306 40615 7", "9780306406157", false},
+		{"isbn-10", "0306406152", "9780306406157", false},
+		{"isbn-10 with hyphens", "0-306-40615-2", "9780306406157", false},
+		{"isbn-10 check digit X", "080442957X", "9780804429573", false},
+		{"isbn-10 lowercase x", "0-8044-2957-x", "9780804429573", false},
+		{"isbn-13 bad check digit", "9780306406158", "", true},
+		{"isbn-10 bad check digit", "0306406153", "", true},
+		{"X inside isbn-10", "03064X6152", "", true},
+		{"X in isbn-13", "978030640615X", "", true},
+		{"letters", "978030640615a", "", true},
+		{"too short", "030640615", "", true},
+		{"too long", "97803064061570", "", true},
+		{"empty", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeISBN(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidISBN) {
+					t.Fatalf("NormalizeISBN(%q) error = %v, want %v", tt.in, err, ErrInvalidISBN)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeISBN(%q) error = %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeISBN(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestISBNValidation(t *testing.T) {
+	v := validator.New()
+	registerISBNValidation(v)
+
+	tests := []struct {
+		in    string
+		valid bool
+	}{
+		{"978-0-306-40615-7", true},
+		{"0-8044-2957-x", true},
+		{"978-0-306-40615-8", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if err := v.Var(tt.in, "isbn"); (err == nil) != tt.valid {
+				t.Errorf("validate %q: error = %v, want valid = %t", tt.in, err, tt.valid)
+			}
+		})
+	}
+}
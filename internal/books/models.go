@@ -1,30 +1,196 @@
 package books
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+)
 
+// Sort fields accepted by GET /books, the first one is the default.
+var bookSorts = []string{"title", "author", "publication_year", "series_volume", "created_at"}
+
+// BookAuthorRequest links a book to an author; the order in the list is the display order.
+type BookAuthorRequest struct {
+	AuthorID int64  `json:"author_id"      validate:"required"`
+	Role     string `json:"role,omitempty" validate:"omitempty,oneof=author translator illustrator" enums:"author,translator,illustrator"`
+}
+
+// CreateBookRequest takes either structured authors or the legacy author string,
+// which is linked to an existing author with the same name or to a new one.
 type CreateBookRequest struct {
-	ISBN            *string `json:"isbn,omitempty" validate:"required"`
-	Title           string  `json:"title" validate:"required"`
-	Author          string  `json:"author" validate:"required"`
-	Description     *string `json:"description,omitempty"`
-	PageCount       *int32  `json:"page_count,omitempty" validate:"omitempty,gt=0"`
-	PublicationYear *int32  `json:"publication_year,omitempty"`
+	ISBN            *string             `json:"isbn,omitempty" validate:"required,isbn"`
+	Title           string              `json:"title" validate:"required"`
+	Author          string              `json:"author,omitempty" validate:"required_without=Authors"`
+	Authors         []BookAuthorRequest `json:"authors,omitempty" validate:"omitempty,max=20,dive"`
+	PublisherID     *int64              `json:"publisher_id,omitempty"`
+	GenreIDs        []int64             `json:"genre_ids,omitempty" validate:"omitempty,max=10,dive,gt=0"`
+	Tags            []string            `json:"tags,omitempty" validate:"omitempty,max=30,dive,required,max=50"`
+	SeriesID        *int64              `json:"series_id,omitempty"`
+	SeriesVolume    *int32              `json:"series_volume,omitempty" validate:"omitempty,gt=0,excluded_without=SeriesID"`
+	Description     *string             `json:"description,omitempty"`
+	PageCount       *int32              `json:"page_count,omitempty" validate:"omitempty,gt=0"`
+	PublicationYear *int32              `json:"publication_year,omitempty"`
+}
+
+// UpsertBookRequest is the body of PUT /books/isbn/{isbn}, the ISBN comes from the path.
+type UpsertBookRequest struct {
+	Title           string              `json:"title"                      validate:"required"`
+	Author          string              `json:"author,omitempty"           validate:"required_without=Authors"`
+	Authors         []BookAuthorRequest `json:"authors,omitempty"          validate:"omitempty,max=20,dive"`
+	PublisherID     *int64              `json:"publisher_id,omitempty"`
+	GenreIDs        []int64             `json:"genre_ids,omitempty"        validate:"omitempty,max=10,dive,gt=0"`
+	Tags            []string            `json:"tags,omitempty"             validate:"omitempty,max=30,dive,required,max=50"`
+	SeriesID        *int64              `json:"series_id,omitempty"`
+	SeriesVolume    *int32              `json:"series_volume,omitempty"    validate:"omitempty,gt=0,excluded_without=SeriesID"`
+	Description     *string             `json:"description,omitempty"`
+	PageCount       *int32              `json:"page_count,omitempty"       validate:"omitempty,gt=0"`
+	PublicationYear *int32              `json:"publication_year,omitempty"`
+}
+
+// UpdateBookRequest replaces every editable field of a book: omitted optional fields are cleared.
+// PATCH requests are merged onto the current book and then validated as an UpdateBookRequest.
+// Without authors the links are kept, unless the author string changes; genres and tags
+// are kept when omitted and cleared by an empty list.
+type UpdateBookRequest struct {
+	ISBN            *string             `json:"isbn"              validate:"omitempty,isbn"`
+	Title           string              `json:"title"             validate:"required"`
+	Author          string              `json:"author"            validate:"required_without=Authors"`
+	Authors         []BookAuthorRequest `json:"authors,omitempty" validate:"omitempty,max=20,dive"`
+	PublisherID     *int64              `json:"publisher_id"`
+	GenreIDs        []int64             `json:"genre_ids,omitempty" validate:"omitempty,max=10,dive,gt=0"`
+	Tags            []string            `json:"tags,omitempty"      validate:"omitempty,max=30,dive,required,max=50"`
+	SeriesID        *int64              `json:"series_id"`
+	SeriesVolume    *int32              `json:"series_volume"       validate:"omitempty,gt=0,excluded_without=SeriesID"`
+	Description     *string             `json:"description"`
+	PageCount       *int32              `json:"page_count"        validate:"omitempty,gt=0"`
+	PublicationYear *int32              `json:"publication_year"`
+}
+
+// BookFilters narrow GET /books, the search and the facets. GenreID matches its subgenres too.
+type BookFilters struct {
+	Author   *string
+	YearFrom *int32
+	YearTo   *int32
+	GenreID  *int64
+	Tag      *string
+	SeriesID *int64
+	Decade   *int32
+	InStock  *bool
+}
+
+type ListBooksParams struct {
+	pagination.Request
+	BookFilters
+}
+
+// BookRelations are everything linked to a book: authors, publisher, genres, tags and series.
+type BookRelations struct {
+	Authors   []repo.ListBookAuthorsRow
+	Publisher *repo.Publisher
+	Genres    []repo.Genre
+	Tags      []string
+	Series    *repo.Series
+}
+
+// SearchResult is a book found by Search together with its relevance.
+type SearchResult struct {
+	Book    repo.Book
+	Rank    float32
+	Snippet string
+}
+
+type BookListResponse struct {
+	Items      []BookResponse  `json:"items"`
+	NextCursor *string         `json:"next_cursor"`
+	Facets     *FacetsResponse `json:"facets,omitempty"`
+}
+
+// FacetsResponse counts the books matching the request, over all pages, per filter value.
+type FacetsResponse struct {
+	Genres  []FacetValueResponse `json:"genres"`
+	Authors []FacetValueResponse `json:"authors"`
+	Decades []FacetValueResponse `json:"decades"`
+	InStock []FacetValueResponse `json:"in_stock"`
 }
 
+// FacetValueResponse is a filter value, e.g. a genre ID, with its label and the number of books.
+type FacetValueResponse struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
+
+// BookResponse keeps Author, the names of the authors joined by commas, for older clients.
 type BookResponse struct {
-	ID              int64   `json:"id"`
-	ISBN            *string `json:"isbn,omitempty"`
-	Title           string  `json:"title"`
-	Author          string  `json:"author"`
-	Description     *string `json:"description,omitempty"`
-	PageCount       *int32  `json:"page_count,omitempty"`
-	PublicationYear *int32  `json:"publication_year,omitempty"`
+	ID              int64                  `json:"id"`
+	ISBN            *string                `json:"isbn,omitempty"`
+	Title           string                 `json:"title"`
+	Author          string                 `json:"author"`
+	Authors         []BookAuthorResponse   `json:"authors,omitempty"`
+	Publisher       *BookPublisherResponse `json:"publisher,omitempty"`
+	Genres          []BookGenreResponse    `json:"genres,omitempty"`
+	Tags            []string               `json:"tags,omitempty"`
+	Series          *BookSeriesResponse    `json:"series,omitempty"`
+	Description     *string                `json:"description,omitempty"`
+	PageCount       *int32                 `json:"page_count,omitempty"`
+	PublicationYear *int32                 `json:"publication_year,omitempty"`
+}
+
+type BookAuthorResponse struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+type BookPublisherResponse struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type BookGenreResponse struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type BookSeriesResponse struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Volume *int32 `json:"volume,omitempty"`
+}
+
+// BookConflictResponse is returned by POST /books when the ISBN is already in the catalog.
+type BookConflictResponse struct {
+	Error string       `json:"error"`
+	Book  BookResponse `json:"book"`
+}
+
+type SearchResultResponse struct {
+	Book    BookResponse `json:"book"`
+	Rank    float32      `json:"rank"`
+	Snippet string       `json:"snippet"`
+}
+
+type SearchResponse struct {
+	Items      []SearchResultResponse `json:"items"`
+	NextCursor *string                `json:"next_cursor"`
+	Facets     *FacetsResponse        `json:"facets,omitempty"`
 }
 
 type AvailabilityResponse struct {
-	StoreUUID     uuid.UUID `json:"store_uuid"`
-	StoreName     string    `json:"store_name"`
-	SkuUUID       uuid.UUID `json:"sku_uuid"`
-	PriceInKopeks int32     `json:"price_in_kopeks"`
-	StockCount    int32     `json:"stock_count"`
+	StoreUUID              uuid.UUID               `json:"store_uuid"`
+	StoreName              string                  `json:"store_name"`
+	SkuUUID                uuid.UUID               `json:"sku_uuid"`
+	Currency               string                  `json:"currency" example:"RUB"`
+	PriceInKopeks          int32                   `json:"price_in_kopeks"`
+	EffectivePriceInKopeks int32                   `json:"effective_price_in_kopeks"`
+	StockCount             int32                   `json:"stock_count"`
+	Available              int32                   `json:"available"`
+	Converted              *ConvertedPriceResponse `json:"converted,omitempty"`
+}
+
+// ConvertedPriceResponse holds the prices of an availability entry in the requested currency.
+type ConvertedPriceResponse struct {
+	Currency               string `json:"currency" example:"KZT"`
+	PriceInKopeks          int64  `json:"price_in_kopeks"`
+	EffectivePriceInKopeks int64  `json:"effective_price_in_kopeks"`
 }
@@ -0,0 +1,311 @@
+package books
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+)
+
+var (
+	ErrAuthorNotFound    = errors.New("author not found")
+	ErrPublisherNotFound = errors.New("publisher not found")
+	ErrGenreNotFound     = errors.New("genre not found")
+	ErrSeriesNotFound    = errors.New("series not found")
+	ErrNoPrimaryAuthor   = errors.New("at least one author must have the author role")
+	ErrDuplicateAuthor   = errors.New("author is listed twice with the same role")
+	ErrBlankTag          = errors.New("tag must not be blank")
+)
+
+// pgForeignKeyViolation is the SQLSTATE of a failed REFERENCES constraint.
+const pgForeignKeyViolation = "23503"
+
+// Relations loads the authors, publishers, genres, tags and series of the books, one query each.
+func (s *service) Relations(ctx context.Context, books ...repo.Book) (map[int64]BookRelations, error) {
+	relations := make(map[int64]BookRelations, len(books))
+	if len(books) == 0 {
+		return relations, nil
+	}
+
+	bookIDs := make([]int64, len(books))
+	var publisherIDs, seriesIDs []int64
+	for i, b := range books {
+		bookIDs[i] = b.ID
+		if b.PublisherID.Valid {
+			publisherIDs = append(publisherIDs, b.PublisherID.Int64)
+		}
+		if b.SeriesID.Valid {
+			seriesIDs = append(seriesIDs, b.SeriesID.Int64)
+		}
+	}
+
+	authors, err := s.repo.ListBookAuthors(ctx, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range authors {
+		rel := relations[a.BookID]
+		rel.Authors = append(rel.Authors, a)
+		relations[a.BookID] = rel
+	}
+
+	genres, err := s.repo.ListBookGenres(ctx, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range genres {
+		rel := relations[g.BookID]
+		rel.Genres = append(rel.Genres, g.Genre)
+		relations[g.BookID] = rel
+	}
+
+	tags, err := s.repo.ListBookTags(ctx, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		rel := relations[t.BookID]
+		rel.Tags = append(rel.Tags, t.Name)
+		relations[t.BookID] = rel
+	}
+
+	if len(publisherIDs) > 0 {
+		publishers, err := s.repo.ListPublishersByIDs(ctx, publisherIDs)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[int64]*repo.Publisher, len(publishers))
+		for i := range publishers {
+			byID[publishers[i].ID] = &publishers[i]
+		}
+		for _, b := range books {
+			if b.PublisherID.Valid {
+				rel := relations[b.ID]
+				rel.Publisher = byID[b.PublisherID.Int64]
+				relations[b.ID] = rel
+			}
+		}
+	}
+
+	if len(seriesIDs) > 0 {
+		series, err := s.repo.ListSeriesByIDs(ctx, seriesIDs)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[int64]*repo.Series, len(series))
+		for i := range series {
+			byID[series[i].ID] = &series[i]
+		}
+		for _, b := range books {
+			if b.SeriesID.Valid {
+				rel := relations[b.ID]
+				rel.Series = byID[b.SeriesID.Int64]
+				relations[b.ID] = rel
+			}
+		}
+	}
+	return relations, nil
+}
+
+// checkAuthors rejects author lists that cannot be stored before anything is written.
+func checkAuthors(authors []BookAuthorRequest) error {
+	if len(authors) == 0 {
+		return nil
+	}
+
+	type link struct {
+		authorID int64
+		role     repo.AuthorRole
+	}
+	seen := make(map[link]bool, len(authors))
+	hasAuthor := false
+	for _, a := range authors {
+		role := authorRole(a.Role)
+		if seen[link{a.AuthorID, role}] {
+			return ErrDuplicateAuthor
+		}
+		seen[link{a.AuthorID, role}] = true
+		hasAuthor = hasAuthor || role == repo.AuthorRoleAuthor
+	}
+	if !hasAuthor {
+		return ErrNoPrimaryAuthor
+	}
+	return nil
+}
+
+// syncAuthors links a just written book to its authors and returns the book as stored.
+//
+// Structured authors replace every link and the author string is recomputed from them.
+// Without them the author string wins: when it no longer matches the linked authors it is
+// linked to the author with that name, created if needed. Translators and illustrators stay.
+func syncAuthors(ctx context.Context, q repo.Querier, book repo.Book, authors []BookAuthorRequest) (repo.Book, error) {
+	if len(authors) == 0 {
+		return book, linkAuthorName(ctx, q, book)
+	}
+
+	if err := q.DeleteBookAuthors(ctx, repo.DeleteBookAuthorsParams{BookID: book.ID}); err != nil {
+		return repo.Book{}, err
+	}
+	for i, a := range authors {
+		err := q.AddBookAuthor(ctx, repo.AddBookAuthorParams{
+			BookID:   book.ID,
+			AuthorID: a.AuthorID,
+			Role:     authorRole(a.Role),
+			Position: int32(i),
+		})
+		if err != nil {
+			if relErr := relationError(err); relErr != nil {
+				return repo.Book{}, relErr
+			}
+			return repo.Book{}, err
+		}
+	}
+	if err := q.RefreshBookAuthorNames(ctx, repo.RefreshBookAuthorNamesParams{BookIds: []int64{book.ID}}); err != nil {
+		return repo.Book{}, err
+	}
+	return q.GetBookByID(ctx, book.ID)
+}
+
+func linkAuthorName(ctx context.Context, q repo.Querier, book repo.Book) error {
+	links, err := q.ListBookAuthors(ctx, []int64{book.ID})
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, l := range links {
+		if l.Role == repo.AuthorRoleAuthor {
+			names = append(names, l.Author.Name)
+		}
+	}
+	if strings.Join(names, ", ") == book.Author {
+		return nil
+	}
+
+	author, err := q.FindAuthorByName(ctx, book.Author)
+	if errors.Is(err, pgx.ErrNoRows) {
+		author, err = q.CreateAuthor(ctx, repo.CreateAuthorParams{Name: book.Author})
+	}
+	if err != nil {
+		return err
+	}
+
+	err = q.DeleteBookAuthors(ctx, repo.DeleteBookAuthorsParams{
+		BookID: book.ID,
+		Role:   repo.NullAuthorRole{AuthorRole: repo.AuthorRoleAuthor, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	return q.AddBookAuthor(ctx, repo.AddBookAuthorParams{
+		BookID:   book.ID,
+		AuthorID: author.ID,
+		Role:     repo.AuthorRoleAuthor,
+	})
+}
+
+// syncCategories replaces the genres and the tags of a book. A nil list leaves them as they are.
+// Tags are matched case-insensitively and created on first use.
+func syncCategories(ctx context.Context, q repo.Querier, bookID int64, genreIDs []int64, tags []string) error {
+	if genreIDs != nil {
+		if err := q.DeleteBookGenres(ctx, bookID); err != nil {
+			return err
+		}
+		err := q.AddBookGenres(ctx, repo.AddBookGenresParams{BookID: bookID, GenreIds: genreIDs})
+		if err != nil {
+			if relErr := relationError(err); relErr != nil {
+				return relErr
+			}
+			return err
+		}
+	}
+
+	if tags == nil {
+		return nil
+	}
+	if err := q.DeleteBookTags(ctx, bookID); err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		if names[i] = NormalizeTag(t); names[i] == "" {
+			return ErrBlankTag
+		}
+	}
+	stored, err := q.UpsertTags(ctx, names)
+	if err != nil {
+		return err
+	}
+	tagIDs := make([]int64, len(stored))
+	for i, t := range stored {
+		tagIDs[i] = t.ID
+	}
+	return q.AddBookTags(ctx, repo.AddBookTagsParams{BookID: bookID, TagIds: tagIDs})
+}
+
+// NormalizeTag is the stored form of a tag name.
+func NormalizeTag(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// relationError maps a reference to a missing author, publisher, genre or series to its error,
+// and returns nil for any other error.
+func relationError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != pgForeignKeyViolation {
+		return nil
+	}
+	switch pgErr.ConstraintName {
+	case "book_authors_author_id_fkey":
+		return ErrAuthorNotFound
+	case "books_publisher_id_fkey":
+		return ErrPublisherNotFound
+	case "book_genres_genre_id_fkey":
+		return ErrGenreNotFound
+	case "books_series_id_fkey":
+		return ErrSeriesNotFound
+	}
+	return nil
+}
+
+func authorRole(role string) repo.AuthorRole {
+	if role == "" {
+		return repo.AuthorRoleAuthor
+	}
+	return repo.AuthorRole(role)
+}
+
+// genreIDs expands the genre filter to the genre and all of its subgenres.
+func (s *service) genreIDs(ctx context.Context, genreID *int64) ([]int64, error) {
+	if genreID == nil {
+		return nil, nil
+	}
+	ids, err := s.repo.ListGenreSubtreeIDs(ctx, *genreID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, ErrGenreNotFound
+	}
+	return ids, nil
+}
+
+func boolToPgBoolp(b *bool) pgtype.Bool {
+	if b == nil {
+		return pgtype.Bool{Valid: false}
+	}
+	return pgtype.Bool{Bool: *b, Valid: true}
+}
+
+func int64ToPgInt8p(i *int64) pgtype.Int8 {
+	if i == nil {
+		return pgtype.Int8{Valid: false}
+	}
+	return pgtype.Int8{Int64: *i, Valid: true}
+}
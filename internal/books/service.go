@@ -3,51 +3,297 @@ package books
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nikallow/bookstores-api/internal/adapters/postgres"
 	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/audit"
 	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/pagination"
 )
 
-var ErrBookNotFound = errors.New("book not found")
+var (
+	ErrBookNotFound      = errors.New("book not found")
+	ErrBookNotDeleted    = errors.New("book is not deleted")
+	ErrBookAlreadyExists = errors.New("book with this isbn already exists")
+	ErrBookDeleted       = errors.New("book with this isbn is deleted, restore it or upsert")
+	ErrISBNTaken         = errors.New("another book has this isbn")
+	ErrBookStockInUse    = errors.New("book has reserved stock or transfers in transit, settle them before deleting its skus")
+	ErrInvalidPatch      = errors.New("invalid merge patch")
+)
+
+// pgUniqueViolation is the SQLSTATE of a failed UNIQUE constraint, e.g. books.isbn.
+const pgUniqueViolation = "23505"
+
+// facetLimit is the number of values returned per facet, the most frequent first.
+const facetLimit = 20
+
+// IsInputError reports errors caused by the book data in the request, not by the server.
+func IsInputError(err error) bool {
+	return errors.Is(err, ErrInvalidISBN) ||
+		errors.Is(err, ErrAuthorNotFound) ||
+		errors.Is(err, ErrPublisherNotFound) ||
+		errors.Is(err, ErrGenreNotFound) ||
+		errors.Is(err, ErrSeriesNotFound) ||
+		errors.Is(err, ErrBlankTag) ||
+		errors.Is(err, ErrNoPrimaryAuthor) ||
+		errors.Is(err, ErrDuplicateAuthor)
+}
 
 type Service interface {
 	Create(ctx context.Context, params CreateBookRequest) (repo.Book, error)
-	List(ctx context.Context) ([]repo.Book, error)
+	Upsert(ctx context.Context, params CreateBookRequest, replace bool) (repo.Book, bool, error)
+	List(ctx context.Context, params ListBooksParams) ([]repo.Book, *string, error)
 	GetByID(ctx context.Context, id int64) (repo.Book, error)
-	Search(ctx context.Context, query string) ([]repo.Book, error)
+	GetByISBN(ctx context.Context, isbn string) (repo.Book, error)
+	ListByAuthor(ctx context.Context, authorID int64, role *string, page pagination.Request) ([]repo.Book, *string, error)
+	Search(ctx context.Context, query string, filters BookFilters, page pagination.Request) ([]SearchResult, *string, error)
+	Facets(ctx context.Context, query *string, filters BookFilters) ([]repo.BookFacetsRow, error)
 	GetAvailability(ctx context.Context, bookID int64) ([]repo.ListBookAvailabilityRow, error)
+	Update(ctx context.Context, id int64, params UpdateBookRequest) (repo.Book, error)
+	Patch(ctx context.Context, id int64, apply func(current UpdateBookRequest) (UpdateBookRequest, error)) (repo.Book, error)
+	Delete(ctx context.Context, id int64, cascade bool) error
+	Restore(ctx context.Context, id int64) (repo.Book, error)
+	Relations(ctx context.Context, books ...repo.Book) (map[int64]BookRelations, error)
 }
 
 type service struct {
 	repo repo.Querier
+	db   postgres.TxBeginner
 }
 
-func NewService(repo repo.Querier) Service {
-	return &service{repo: repo}
+func NewService(repo repo.Querier, db postgres.TxBeginner) Service {
+	return &service{repo: repo, db: db}
 }
 
+// Create - POST /books
+//
+// If a book with the same ISBN exists, it is returned together with ErrBookAlreadyExists.
 func (s *service) Create(ctx context.Context, params CreateBookRequest) (repo.Book, error) {
 	log := middleware.LoggerFromContext(ctx)
 
-	book, err := s.repo.CreateBook(ctx, repo.CreateBookParams{
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.Book{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	book, err := CreateBook(ctx, repo.New(tx), params)
+	if err != nil {
+		return book, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return repo.Book{}, err
+	}
+
+	log.Info("Book created successfully", "book_id", book.ID)
+	return book, nil
+}
+
+// CreateBook is Create on q, for callers that make the book part of a larger transaction.
+func CreateBook(ctx context.Context, q *repo.Queries, params CreateBookRequest) (repo.Book, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	isbn, err := normalizeISBNp(params.ISBN)
+	if err != nil {
+		return repo.Book{}, err
+	}
+	params.ISBN = isbn
+	if err := checkAuthors(params.Authors); err != nil {
+		return repo.Book{}, err
+	}
+
+	book, err := q.CreateBook(ctx, repo.CreateBookParams{
 		Isbn:            stringToPgTextp(params.ISBN),
 		Title:           params.Title,
 		Author:          params.Author,
 		Description:     stringToPgTextp(params.Description),
 		PageCount:       int32ToPgInt4p(params.PageCount),
 		PublicationYear: int32ToPgInt4p(params.PublicationYear),
+		PublisherID:     int64ToPgInt8p(params.PublisherID),
+		SeriesID:        int64ToPgInt8p(params.SeriesID),
+		SeriesVolume:    int32ToPgInt4p(params.SeriesVolume),
 	})
+	if err == nil {
+		if book, err = syncAuthors(ctx, q, book, params.Authors); err != nil {
+			return repo.Book{}, err
+		}
+		if err := syncCategories(ctx, q, book.ID, params.GenreIDs, params.Tags); err != nil {
+			return repo.Book{}, err
+		}
+		if err := recordBook(ctx, q, audit.ActionCreate, nil, book); err != nil {
+			return repo.Book{}, err
+		}
+		return book, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		if relErr := relationError(err); relErr != nil {
+			return repo.Book{}, relErr
+		}
+		log.Error("Failed to create book", "error", err)
+		return repo.Book{}, err
+	}
+
+	existing, err := q.GetBookByISBN(ctx, stringToPgTextp(params.ISBN))
 	if err != nil {
-		log.Error("Failed to create or update book", "error", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Book{}, ErrBookDeleted
+		}
 		return repo.Book{}, err
 	}
-	return book, nil
+	return existing, ErrBookAlreadyExists
+}
+
+// Upsert creates the book or updates the one with the same ISBN and reports whether
+// it was created. Without replace, optional fields absent from params are kept.
+func (s *service) Upsert(ctx context.Context, params CreateBookRequest, replace bool) (repo.Book, bool, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.Book{}, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	book, created, err := UpsertBook(ctx, repo.New(tx), params, replace)
+	if err != nil {
+		return repo.Book{}, false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return repo.Book{}, false, err
+	}
+
+	log.Info("Book upserted successfully", "book_id", book.ID, "created", created)
+	return book, created, nil
+}
+
+// UpsertBook is Upsert on q, see CreateBook.
+func UpsertBook(ctx context.Context, q *repo.Queries, params CreateBookRequest, replace bool) (repo.Book, bool, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	isbn, err := normalizeISBNp(params.ISBN)
+	if err != nil {
+		return repo.Book{}, false, err
+	}
+	params.ISBN = isbn
+	if err := checkAuthors(params.Authors); err != nil {
+		return repo.Book{}, false, err
+	}
+
+	var before *bookSnapshot
+	existing, err := q.GetBookByISBNForUpdate(ctx, stringToPgTextp(params.ISBN))
+	switch {
+	case err == nil:
+		snapshot, err := snapshotBook(ctx, q, existing)
+		if err != nil {
+			return repo.Book{}, false, err
+		}
+		before = &snapshot
+	case !errors.Is(err, pgx.ErrNoRows):
+		log.Error("Failed to get book for upsert", "error", err)
+		return repo.Book{}, false, err
+	}
+
+	row, err := q.UpsertBook(ctx, repo.UpsertBookParams{
+		Isbn:            stringToPgTextp(params.ISBN),
+		Title:           params.Title,
+		Author:          params.Author,
+		Description:     stringToPgTextp(params.Description),
+		PageCount:       int32ToPgInt4p(params.PageCount),
+		PublicationYear: int32ToPgInt4p(params.PublicationYear),
+		PublisherID:     int64ToPgInt8p(params.PublisherID),
+		SeriesID:        int64ToPgInt8p(params.SeriesID),
+		SeriesVolume:    int32ToPgInt4p(params.SeriesVolume),
+		Replace:         replace,
+	})
+	if err != nil {
+		if relErr := relationError(err); relErr != nil {
+			return repo.Book{}, false, relErr
+		}
+		log.Error("Failed to upsert book", "error", err)
+		return repo.Book{}, false, err
+	}
+
+	book := repo.Book{
+		ID:              row.ID,
+		Isbn:            row.Isbn,
+		Title:           row.Title,
+		Author:          row.Author,
+		Description:     row.Description,
+		PageCount:       row.PageCount,
+		PublicationYear: row.PublicationYear,
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+		DeletedAt:       row.DeletedAt,
+		PublisherID:     row.PublisherID,
+		SeriesID:        row.SeriesID,
+		SeriesVolume:    row.SeriesVolume,
+	}
+	if book, err = syncAuthors(ctx, q, book, params.Authors); err != nil {
+		return repo.Book{}, false, err
+	}
+	if err := syncCategories(ctx, q, book.ID, params.GenreIDs, params.Tags); err != nil {
+		return repo.Book{}, false, err
+	}
+	action := audit.ActionUpdate
+	if row.Inserted {
+		action = audit.ActionCreate
+	}
+	if err := recordBook(ctx, q, action, before, book); err != nil {
+		return repo.Book{}, false, err
+	}
+	return book, row.Inserted, nil
 }
 
-func (s *service) List(ctx context.Context) ([]repo.Book, error) {
-	return s.repo.ListBooks(ctx)
+// List returns a page of books and the cursor of the next page, nil on the last one.
+func (s *service) List(ctx context.Context, params ListBooksParams) ([]repo.Book, *string, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	cursor, err := params.DecodeCursor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	genreIDs, err := s.genreIDs(ctx, params.GenreID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queryParams := repo.ListBooksByTitleParams{
+		Author:    stringToPgTextp(params.Author),
+		YearFrom:  int32ToPgInt4p(params.YearFrom),
+		YearTo:    int32ToPgInt4p(params.YearTo),
+		GenreIds:  genreIDs,
+		Tag:       tagToPgTextp(params.Tag),
+		SeriesID:  int64ToPgInt8p(params.SeriesID),
+		Decade:    int32ToPgInt4p(params.Decade),
+		InStock:   boolToPgBoolp(params.InStock),
+		PageLimit: params.QueryLimit(),
+	}
+	books, err := s.listBooks(ctx, params, cursor, queryParams)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			return nil, nil, err
+		}
+		log.Error("Failed to list books", "error", err)
+		return nil, nil, err
+	}
+
+	books, hasMore := pagination.Trim(books, params.Request)
+	if !hasMore {
+		return books, nil, nil
+	}
+	last := books[len(books)-1]
+	next := params.NextCursor(bookSortValue(last, params.SortBy), last.ID)
+	return books, &next, nil
 }
 
 func (s *service) GetByID(ctx context.Context, id int64) (repo.Book, error) {
@@ -61,8 +307,171 @@ func (s *service) GetByID(ctx context.Context, id int64) (repo.Book, error) {
 	return book, nil
 }
 
-func (s *service) Search(ctx context.Context, query string) ([]repo.Book, error) {
-	return s.repo.SearchBooks(ctx, pgtype.Text{String: query, Valid: true})
+// GetByISBN looks a book up by ISBN-10 or ISBN-13 in any formatting.
+func (s *service) GetByISBN(ctx context.Context, isbn string) (repo.Book, error) {
+	canonical, err := NormalizeISBN(isbn)
+	if err != nil {
+		return repo.Book{}, err
+	}
+
+	book, err := s.repo.GetBookByISBN(ctx, pgtype.Text{String: canonical, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Book{}, ErrBookNotFound
+		}
+		return repo.Book{}, err
+	}
+	return book, nil
+}
+
+// ListByAuthor returns a page of the books linked to the author, optionally only in one role.
+func (s *service) ListByAuthor(ctx context.Context, authorID int64, role *string, page pagination.Request) ([]repo.Book, *string, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	cursor, err := page.DecodeCursor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := s.repo.GetAuthorByID(ctx, authorID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrAuthorNotFound
+		}
+		return nil, nil, err
+	}
+
+	params := repo.ListBooksByAuthorParams{
+		AuthorID:  authorID,
+		PageLimit: page.QueryLimit(),
+	}
+	if role != nil {
+		params.Role = repo.NullAuthorRole{AuthorRole: repo.AuthorRole(*role), Valid: true}
+	}
+	if cursor != nil {
+		params.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+		params.CursorText = pgtype.Text{String: cursor.Value, Valid: true}
+	}
+
+	var books []repo.Book
+	if page.Desc {
+		books, err = s.repo.ListBooksByAuthorDesc(ctx, repo.ListBooksByAuthorDescParams(params))
+	} else {
+		books, err = s.repo.ListBooksByAuthor(ctx, params)
+	}
+	if err != nil {
+		log.Error("Failed to list books by author", "error", err, "author_id", authorID)
+		return nil, nil, err
+	}
+
+	books, hasMore := pagination.Trim(books, page)
+	if !hasMore {
+		return books, nil, nil
+	}
+	last := books[len(books)-1]
+	next := page.NextCursor(last.Title, last.ID)
+	return books, &next, nil
+}
+
+// Search ranks books by full-text match on title, author, description and ISBN,
+// falling back to trigram similarity so that typos still find something.
+func (s *service) Search(ctx context.Context, query string, filters BookFilters, page pagination.Request) ([]SearchResult, *string, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	cursor, err := page.DecodeCursor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	genreIDs, err := s.genreIDs(ctx, filters.GenreID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	params := repo.SearchBooksParams{
+		Query:     query,
+		Isbn:      searchISBN(&query),
+		Author:    stringToPgTextp(filters.Author),
+		YearFrom:  int32ToPgInt4p(filters.YearFrom),
+		YearTo:    int32ToPgInt4p(filters.YearTo),
+		GenreIds:  genreIDs,
+		Tag:       tagToPgTextp(filters.Tag),
+		SeriesID:  int64ToPgInt8p(filters.SeriesID),
+		Decade:    int32ToPgInt4p(filters.Decade),
+		InStock:   boolToPgBoolp(filters.InStock),
+		PageLimit: page.QueryLimit(),
+	}
+	if cursor != nil {
+		rank, err := strconv.ParseFloat(cursor.Value, 32)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: bad rank", pagination.ErrInvalidCursor)
+		}
+		params.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+		params.CursorRank = pgtype.Float4{Float32: float32(rank), Valid: true}
+	}
+
+	rows, err := s.repo.SearchBooks(ctx, params)
+	if err != nil {
+		log.Error("Failed to search books", "error", err, "query", query)
+		return nil, nil, err
+	}
+
+	rows, hasMore := pagination.Trim(rows, page)
+	results := make([]SearchResult, len(rows))
+	for i, row := range rows {
+		results[i] = SearchResult{
+			Book: repo.Book{
+				ID:              row.ID,
+				Isbn:            row.Isbn,
+				Title:           row.Title,
+				Author:          row.Author,
+				Description:     row.Description,
+				PageCount:       row.PageCount,
+				PublicationYear: row.PublicationYear,
+				CreatedAt:       row.CreatedAt,
+				UpdatedAt:       row.UpdatedAt,
+				DeletedAt:       row.DeletedAt,
+				PublisherID:     row.PublisherID,
+				SeriesID:        row.SeriesID,
+				SeriesVolume:    row.SeriesVolume,
+			},
+			Rank:    row.Rank,
+			Snippet: row.Snippet,
+		}
+	}
+	if !hasMore {
+		return results, nil, nil
+	}
+	last := rows[len(rows)-1]
+	next := page.NextCursor(strconv.FormatFloat(float64(last.Rank), 'g', -1, 32), last.ID)
+	return results, &next, nil
+}
+
+// Facets counts the books matching the filters, and the search query if given, per genre,
+// author, decade and availability across all pages of the result.
+func (s *service) Facets(ctx context.Context, query *string, filters BookFilters) ([]repo.BookFacetsRow, error) {
+	genreIDs, err := s.genreIDs(ctx, filters.GenreID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.BookFacets(ctx, repo.BookFacetsParams{
+		FacetLimit: facetLimit,
+		Query:      stringToPgTextp(query),
+		Isbn:       searchISBN(query),
+		Author:     stringToPgTextp(filters.Author),
+		YearFrom:   int32ToPgInt4p(filters.YearFrom),
+		YearTo:     int32ToPgInt4p(filters.YearTo),
+		GenreIds:   genreIDs,
+		Tag:        tagToPgTextp(filters.Tag),
+		SeriesID:   int64ToPgInt8p(filters.SeriesID),
+		Decade:     int32ToPgInt4p(filters.Decade),
+		InStock:    boolToPgBoolp(filters.InStock),
+	})
+	if err != nil {
+		middleware.LoggerFromContext(ctx).Error("Failed to count book facets", "error", err)
+		return nil, err
+	}
+	return rows, nil
 }
 
 func (s *service) GetAvailability(ctx context.Context, bookID int64) ([]repo.ListBookAvailabilityRow, error) {
@@ -72,6 +481,435 @@ func (s *service) GetAvailability(ctx context.Context, bookID int64) ([]repo.Lis
 	return s.repo.ListBookAvailability(ctx, bookID)
 }
 
+// Update - PUT /books/{bookID}
+func (s *service) Update(ctx context.Context, id int64, params UpdateBookRequest) (repo.Book, error) {
+	return s.update(ctx, id, func(UpdateBookRequest) (UpdateBookRequest, error) {
+		return params, nil
+	})
+}
+
+// Patch - PATCH /books/{bookID}
+//
+// apply gets the book as it is and returns it changed; the book stays locked in between,
+// so a concurrent update can't be overwritten with the fields it had before.
+func (s *service) Patch(ctx context.Context, id int64, apply func(current UpdateBookRequest) (UpdateBookRequest, error)) (repo.Book, error) {
+	return s.update(ctx, id, apply)
+}
+
+func (s *service) update(ctx context.Context, id int64, apply func(current UpdateBookRequest) (UpdateBookRequest, error)) (repo.Book, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.Book{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+
+	existing, err := qtx.GetBookByIDForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Book{}, ErrBookNotFound
+		}
+		log.Error("Failed to get book for update", "error", err, "book_id", id)
+		return repo.Book{}, err
+	}
+	before, err := snapshotBook(ctx, qtx, existing)
+	if err != nil {
+		return repo.Book{}, err
+	}
+
+	params, err := apply(toUpdateBookRequest(existing))
+	if err != nil {
+		return repo.Book{}, err
+	}
+	isbn, err := normalizeISBNp(params.ISBN)
+	if err != nil {
+		return repo.Book{}, err
+	}
+	params.ISBN = isbn
+	if err := checkAuthors(params.Authors); err != nil {
+		return repo.Book{}, err
+	}
+
+	book, err := qtx.UpdateBook(ctx, repo.UpdateBookParams{
+		ID:              id,
+		Isbn:            stringToPgTextp(params.ISBN),
+		Title:           params.Title,
+		Author:          params.Author,
+		Description:     stringToPgTextp(params.Description),
+		PageCount:       int32ToPgInt4p(params.PageCount),
+		PublicationYear: int32ToPgInt4p(params.PublicationYear),
+		PublisherID:     int64ToPgInt8p(params.PublisherID),
+		SeriesID:        int64ToPgInt8p(params.SeriesID),
+		SeriesVolume:    int32ToPgInt4p(params.SeriesVolume),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Book{}, ErrBookNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return repo.Book{}, ErrISBNTaken
+		}
+		if relErr := relationError(err); relErr != nil {
+			return repo.Book{}, relErr
+		}
+		log.Error("Failed to update book", "error", err, "book_id", id)
+		return repo.Book{}, err
+	}
+
+	if book, err = syncAuthors(ctx, qtx, book, params.Authors); err != nil {
+		return repo.Book{}, err
+	}
+	if err := syncCategories(ctx, qtx, book.ID, params.GenreIDs, params.Tags); err != nil {
+		return repo.Book{}, err
+	}
+	if err := recordBook(ctx, qtx, audit.ActionUpdate, &before, book); err != nil {
+		return repo.Book{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return repo.Book{}, err
+	}
+
+	log.Info("Book updated successfully", "book_id", book.ID)
+	return book, nil
+}
+
+// Delete - DELETE /books/{bookID}
+//
+// The book is hidden from the catalog, search and availability. With cascade its SKUs
+// are soft-deleted too, stamped with the same deleted_at so Restore brings back exactly them.
+// The cascade is refused while stock of the book is held by reservations or pending orders
+// or is in transit, since nothing could settle it once its SKUs are gone.
+func (s *service) Delete(ctx context.Context, id int64, cascade bool) error {
+	log := middleware.LoggerFromContext(ctx)
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+
+	if cascade {
+		if err := checkStockSettled(ctx, qtx, id); err != nil {
+			return err
+		}
+	}
+
+	book, err := qtx.SoftDeleteBook(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrBookNotFound
+		}
+		log.Error("Failed to soft delete book", "error", err, "book_id", id)
+		return err
+	}
+
+	// Soft deletion changes nothing else, so the book before it is the deleted one without deleted_at.
+	before, err := snapshotBook(ctx, qtx, book)
+	if err != nil {
+		return err
+	}
+	before.DeletedAt = pgtype.Timestamptz{Valid: false}
+	if err := recordBook(ctx, qtx, audit.ActionDelete, &before, book); err != nil {
+		return err
+	}
+
+	var skus []repo.Sku
+	if cascade {
+		skus, err = qtx.SoftDeleteSKUsByBook(ctx, repo.SoftDeleteSKUsByBookParams{
+			DeletedAt: book.DeletedAt,
+			BookID:    book.ID,
+		})
+		if err != nil {
+			log.Error("Failed to soft delete book skus", "error", err, "book_id", id)
+			return err
+		}
+	}
+	for _, sku := range skus {
+		skuBefore := sku
+		skuBefore.DeletedAt = pgtype.Timestamptz{Valid: false}
+		if err := recordSKU(ctx, qtx, audit.ActionDelete, skuBefore, sku); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	log.Info("Book soft-deleted successfully", "book_id", id, "skus_deleted", len(skus))
+	return nil
+}
+
+// checkStockSettled locks the live SKUs of the book, so that no reservation, order or
+// transfer can take their stock until the transaction ends, and fails with
+// ErrBookStockInUse if some of it is already taken.
+func checkStockSettled(ctx context.Context, q *repo.Queries, bookID int64) error {
+	skus, err := q.LockBookSKUs(ctx, bookID)
+	if err != nil {
+		return err
+	}
+	for _, sku := range skus {
+		if sku.ReservedCount > 0 {
+			return ErrBookStockInUse
+		}
+	}
+
+	inTransit, err := q.CountDispatchedTransfersByBook(ctx, bookID)
+	if err != nil {
+		return err
+	}
+	if inTransit > 0 {
+		return ErrBookStockInUse
+	}
+	return nil
+}
+
+// Restore - POST /books/{bookID}/restore
+func (s *service) Restore(ctx context.Context, id int64) (repo.Book, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.Book{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+
+	deleted, err := qtx.GetDeletedBookByIDForUpdate(ctx, id)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return repo.Book{}, err
+		}
+		if _, err := s.GetByID(ctx, id); err != nil {
+			return repo.Book{}, err
+		}
+		return repo.Book{}, ErrBookNotDeleted
+	}
+
+	skus, err := qtx.RestoreSKUsByBook(ctx, repo.RestoreSKUsByBookParams{
+		BookID:    id,
+		DeletedAt: deleted.DeletedAt,
+	})
+	if err != nil {
+		log.Error("Failed to restore book skus", "error", err, "book_id", id)
+		return repo.Book{}, err
+	}
+
+	for _, sku := range skus {
+		skuBefore := sku
+		skuBefore.DeletedAt = deleted.DeletedAt
+		if err := recordSKU(ctx, qtx, audit.ActionRestore, skuBefore, sku); err != nil {
+			return repo.Book{}, err
+		}
+	}
+
+	before, err := snapshotBook(ctx, qtx, deleted)
+	if err != nil {
+		return repo.Book{}, err
+	}
+	book, err := qtx.RestoreBook(ctx, id)
+	if err != nil {
+		log.Error("Failed to restore book", "error", err, "book_id", id)
+		return repo.Book{}, err
+	}
+	if err := recordBook(ctx, qtx, audit.ActionRestore, &before, book); err != nil {
+		return repo.Book{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return repo.Book{}, err
+	}
+
+	log.Info("Book restored successfully", "book_id", id, "skus_restored", len(skus))
+	return book, nil
+}
+
+// bookSnapshot is the state of a book the audit compares: its row and the IDs of its authors,
+// genres and tags, so that a change of the relations alone is recorded too.
+type bookSnapshot struct {
+	repo.Book
+	AuthorIDs []int64 `json:"author_ids"`
+	GenreIDs  []int64 `json:"genre_ids"`
+	TagIDs    []int64 `json:"tag_ids"`
+}
+
+func snapshotBook(ctx context.Context, q *repo.Queries, book repo.Book) (bookSnapshot, error) {
+	snapshot := bookSnapshot{Book: book}
+
+	authors, err := q.ListBookAuthors(ctx, []int64{book.ID})
+	if err != nil {
+		return bookSnapshot{}, err
+	}
+	for _, a := range authors {
+		snapshot.AuthorIDs = append(snapshot.AuthorIDs, a.Author.ID)
+	}
+	genres, err := q.ListBookGenres(ctx, []int64{book.ID})
+	if err != nil {
+		return bookSnapshot{}, err
+	}
+	for _, g := range genres {
+		snapshot.GenreIDs = append(snapshot.GenreIDs, g.Genre.ID)
+	}
+	tags, err := q.ListBookTagIDs(ctx, book.ID)
+	if err != nil {
+		return bookSnapshot{}, err
+	}
+	snapshot.TagIDs = tags
+	return snapshot, nil
+}
+
+// recordBook adds the audit event of a change of a book with its relations as they are now;
+// before is nil for a created one.
+func recordBook(ctx context.Context, q *repo.Queries, action audit.Action, before *bookSnapshot, after repo.Book) error {
+	snapshot, err := snapshotBook(ctx, q, after)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		return audit.Record(ctx, q, audit.EntityBook, strconv.FormatInt(after.ID, 10), action, nil, snapshot)
+	}
+	return audit.Record(ctx, q, audit.EntityBook, strconv.FormatInt(after.ID, 10), action, *before, snapshot)
+}
+
+// recordSKU adds the audit event of a SKU deleted or restored together with its book.
+func recordSKU(ctx context.Context, q *repo.Queries, action audit.Action, before, after repo.Sku) error {
+	return audit.Record(ctx, q, audit.EntitySKU, uuid.UUID(after.Uuid.Bytes).String(), action, before, after)
+}
+
+// bookSortValue is the cursor value of a book; books without a year or volume sort as 0.
+func bookSortValue(b repo.Book, sortBy string) string {
+	switch sortBy {
+	case "author":
+		return b.Author
+	case "publication_year":
+		return strconv.Itoa(int(b.PublicationYear.Int32))
+	case "series_volume":
+		return strconv.Itoa(int(b.SeriesVolume.Int32))
+	case "created_at":
+		return b.CreatedAt.Time.Format(time.RFC3339Nano)
+	default:
+		return b.Title
+	}
+}
+
+// listBooks runs the list query of the sort and direction of params. filters holds the filters
+// and the page size, the sort value of the cursor is typed per sort.
+func (s *service) listBooks(ctx context.Context, params ListBooksParams, cursor *pagination.Cursor, filters repo.ListBooksByTitleParams) ([]repo.Book, error) {
+	if cursor != nil {
+		filters.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+	}
+
+	switch params.SortBy {
+	case "publication_year", "series_volume":
+		queryParams := repo.ListBooksByPublicationYearParams{
+			Author:    filters.Author,
+			YearFrom:  filters.YearFrom,
+			YearTo:    filters.YearTo,
+			GenreIds:  filters.GenreIds,
+			Tag:       filters.Tag,
+			SeriesID:  filters.SeriesID,
+			Decade:    filters.Decade,
+			InStock:   filters.InStock,
+			CursorID:  filters.CursorID,
+			PageLimit: filters.PageLimit,
+		}
+		if cursor != nil {
+			n, err := strconv.ParseInt(cursor.Value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("%w: bad %s", pagination.ErrInvalidCursor, cursor.SortBy)
+			}
+			queryParams.CursorInt = pgtype.Int4{Int32: int32(n), Valid: true}
+		}
+		switch {
+		case params.SortBy == "publication_year" && params.Desc:
+			return s.repo.ListBooksByPublicationYearDesc(ctx, repo.ListBooksByPublicationYearDescParams(queryParams))
+		case params.SortBy == "publication_year":
+			return s.repo.ListBooksByPublicationYear(ctx, queryParams)
+		case params.Desc:
+			return s.repo.ListBooksBySeriesVolumeDesc(ctx, repo.ListBooksBySeriesVolumeDescParams(queryParams))
+		default:
+			return s.repo.ListBooksBySeriesVolume(ctx, repo.ListBooksBySeriesVolumeParams(queryParams))
+		}
+	case "created_at":
+		queryParams := repo.ListBooksByCreatedAtParams{
+			Author:    filters.Author,
+			YearFrom:  filters.YearFrom,
+			YearTo:    filters.YearTo,
+			GenreIds:  filters.GenreIds,
+			Tag:       filters.Tag,
+			SeriesID:  filters.SeriesID,
+			Decade:    filters.Decade,
+			InStock:   filters.InStock,
+			CursorID:  filters.CursorID,
+			PageLimit: filters.PageLimit,
+		}
+		if cursor != nil {
+			t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: bad timestamp", pagination.ErrInvalidCursor)
+			}
+			queryParams.CursorTime = pgtype.Timestamptz{Time: t, Valid: true}
+		}
+		if params.Desc {
+			return s.repo.ListBooksByCreatedAtDesc(ctx, repo.ListBooksByCreatedAtDescParams(queryParams))
+		}
+		return s.repo.ListBooksByCreatedAt(ctx, queryParams)
+	default:
+		if cursor != nil {
+			filters.CursorText = pgtype.Text{String: cursor.Value, Valid: true}
+		}
+		switch {
+		case params.SortBy == "author" && params.Desc:
+			return s.repo.ListBooksByAuthorNameDesc(ctx, repo.ListBooksByAuthorNameDescParams(filters))
+		case params.SortBy == "author":
+			return s.repo.ListBooksByAuthorName(ctx, repo.ListBooksByAuthorNameParams(filters))
+		case params.Desc:
+			return s.repo.ListBooksByTitleDesc(ctx, repo.ListBooksByTitleDescParams(filters))
+		default:
+			return s.repo.ListBooksByTitle(ctx, filters)
+		}
+	}
+}
+
+func normalizeISBNp(isbn *string) (*string, error) {
+	if isbn == nil {
+		return nil, nil
+	}
+	canonical, err := NormalizeISBN(*isbn)
+	if err != nil {
+		return nil, err
+	}
+	return &canonical, nil
+}
+
+// searchISBN is the search query as the ISBN-13 stored in books.isbn, so that a book is found by
+// its ISBN-10 or a hyphenated ISBN too. It is NULL if the query isn't a valid ISBN.
+func searchISBN(query *string) pgtype.Text {
+	if query == nil {
+		return pgtype.Text{Valid: false}
+	}
+	isbn, err := NormalizeISBN(*query)
+	if err != nil {
+		return pgtype.Text{Valid: false}
+	}
+	return pgtype.Text{String: isbn, Valid: true}
+}
+
+func tagToPgTextp(tag *string) pgtype.Text {
+	if tag == nil {
+		return pgtype.Text{Valid: false}
+	}
+	return pgtype.Text{String: NormalizeTag(*tag), Valid: true}
+}
+
 func stringToPgTextp(s *string) pgtype.Text {
 	if s == nil {
 		return pgtype.Text{Valid: false}
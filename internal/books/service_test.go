@@ -0,0 +1,81 @@
+package books
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/database/dbtest"
+)
+
+func TestPatch(t *testing.T) {
+	db := dbtest.New(t)
+	svc := NewService(repo.New(db), db)
+	ctx := context.Background()
+
+	t.Run("concurrent patches keep each other's fields", func(t *testing.T) {
+		bookID := dbtest.CreateBook(t, db)
+
+		started := make(chan struct{})
+		done := make(chan error)
+		go func() {
+			_, err := svc.Patch(ctx, bookID, func(current UpdateBookRequest) (UpdateBookRequest, error) {
+				close(started)
+				time.Sleep(100 * time.Millisecond)
+				current.Title = "New title"
+				return current, nil
+			})
+			done <- err
+		}()
+
+		<-started
+		description := "New description"
+		if _, err := svc.Patch(ctx, bookID, func(current UpdateBookRequest) (UpdateBookRequest, error) {
+			current.Description = &description
+			return current, nil
+		}); err != nil {
+			t.Fatalf("Patch() error = %v", err)
+		}
+		if err := <-done; err != nil {
+			t.Fatalf("Patch() error = %v", err)
+		}
+
+		book, err := svc.GetByID(ctx, bookID)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if book.Title != "New title" || book.Description.String != description {
+			t.Errorf("book = %q, %q; want %q, %q", book.Title, book.Description.String, "New title", description)
+		}
+	})
+
+	t.Run("failed patch changes nothing", func(t *testing.T) {
+		bookID := dbtest.CreateBook(t, db)
+
+		_, err := svc.Patch(ctx, bookID, func(current UpdateBookRequest) (UpdateBookRequest, error) {
+			return current, ErrInvalidPatch
+		})
+		if !errors.Is(err, ErrInvalidPatch) {
+			t.Fatalf("Patch() error = %v, want %v", err, ErrInvalidPatch)
+		}
+
+		book, err := svc.GetByID(ctx, bookID)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if book.Title != "Test book" {
+			t.Errorf("Title = %q, want %q", book.Title, "Test book")
+		}
+	})
+
+	t.Run("missing book", func(t *testing.T) {
+		_, err := svc.Patch(ctx, -1, func(current UpdateBookRequest) (UpdateBookRequest, error) {
+			return current, nil
+		})
+		if !errors.Is(err, ErrBookNotFound) {
+			t.Errorf("Patch() error = %v, want %v", err, ErrBookNotFound)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -15,30 +16,119 @@ const (
 )
 
 type Config struct {
-	Env      Environment    `yaml:"env"      env:"ENV" env-default:"local"`
-	Logger   LoggerConfig   `yaml:"logger"   env-prefix:"LOG_"`
-	Service  ServiceConfig  `yaml:"service"  env-prefix:"SERVICE_"`
-	Database DatabaseConfig `yaml:"database" env-prefix:"DB_"`
+	Env          Environment        `yaml:"env"          env:"ENV" env-default:"local"`
+	Logger       LoggerConfig       `yaml:"logger"       env-prefix:"LOG_"`
+	Service      ServiceConfig      `yaml:"service"      env-prefix:"SERVICE_"`
+	Database     DatabaseConfig     `yaml:"database"     env-prefix:"DB_"`
+	Idempotency  IdempotencyConfig  `yaml:"idempotency"  env-prefix:"IDEMPOTENCY_"`
+	Reservations ReservationsConfig `yaml:"reservations" env-prefix:"RESERVATIONS_"`
+	Imports      ImportsConfig      `yaml:"imports"      env-prefix:"IMPORTS_"`
+	Exports      ExportsConfig      `yaml:"exports"      env-prefix:"EXPORTS_"`
+	Prices       PricesConfig       `yaml:"prices"       env-prefix:"PRICES_"`
+	Auth         AuthConfig         `yaml:"auth"         env-prefix:"AUTH_"`
+	RateLimit    RateLimitConfig    `yaml:"rate_limit"   env-prefix:"RATE_LIMIT_"`
 }
 
 type LoggerConfig struct {
 	Level string `yaml:"level" env:"LEVEL" env-default:"info"`
 }
 
+// ServiceConfig: TrustedProxies are the addresses or CIDR prefixes of the reverse proxies whose
+// X-Real-IP and X-Forwarded-For headers name the client; requests from anywhere else are known
+// by the address of their connection.
 type ServiceConfig struct {
-	Name string `yaml:"name" env:"NAME" env-default:"bookstores-api"`
-	Host string `yaml:"host" env:"HOST" env-default:"0.0.0.0"`
-	Port string `yaml:"port" env:"PORT" env-default:"8080"`
+	Name           string   `yaml:"name"            env:"NAME"            env-default:"bookstores-api"`
+	Host           string   `yaml:"host"            env:"HOST"            env-default:"0.0.0.0"`
+	Port           string   `yaml:"port"            env:"PORT"            env-default:"8080"`
+	TrustedProxies []string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"`
 }
 
 type DatabaseConfig struct {
-	Host     string `yaml:"host"      env:"HOST"      env-default:"localhost"`
-	Port     string `yaml:"port"      env:"PORT"      env-default:"5432"`
-	User     string `yaml:"user"      env:"USER"      env-default:"postgres"`
-	Password string `yaml:"password"  env:"PASSWORD"  env-default:"postgres"`
-	DBName   string `yaml:"db_name"   env:"DB_NAME"   env-default:"bookstores"`
-	SSLMode  string `yaml:"ssl_mode"  env:"SSL_MODE"  env-default:"disable"`
-	MaxConns int    `yaml:"max_conns" env:"MAX_CONNS" env-default:"10"`
+	Host              string        `yaml:"host"                env:"HOST"                env-default:"localhost"`
+	Port              string        `yaml:"port"                env:"PORT"                env-default:"5432"`
+	User              string        `yaml:"user"                env:"USER"                env-default:"postgres"`
+	Password          string        `yaml:"password"            env:"PASSWORD"            env-default:"postgres"`
+	DBName            string        `yaml:"db_name"             env:"DB_NAME"             env-default:"bookstores"`
+	SSLMode           string        `yaml:"ssl_mode"            env:"SSL_MODE"            env-default:"disable"`
+	MaxConns          int           `yaml:"max_conns"           env:"MAX_CONNS"           env-default:"10"`
+	MinConns          int           `yaml:"min_conns"           env:"MIN_CONNS"           env-default:"2"`
+	MaxConnLifetime   time.Duration `yaml:"max_conn_lifetime"   env:"MAX_CONN_LIFETIME"   env-default:"1h"`
+	MaxConnIdleTime   time.Duration `yaml:"max_conn_idle_time"  env:"MAX_CONN_IDLE_TIME"  env-default:"30m"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period" env:"HEALTH_CHECK_PERIOD" env-default:"1m"`
+	AcquireTimeout    time.Duration `yaml:"acquire_timeout"     env:"ACQUIRE_TIMEOUT"     env-default:"5s"`
+	AutoMigrate       bool          `yaml:"auto_migrate"        env:"AUTO_MIGRATE"        env-default:"false"`
+}
+
+// IdempotencyConfig: TTL is how long a stored response is replayed, LockTimeout is
+// after how long the key of a request that never finished may be taken over. The sweeper
+// deletes expired keys every SweepInterval.
+type IdempotencyConfig struct {
+	TTL           time.Duration `yaml:"ttl"            env:"TTL"            env-default:"24h"`
+	LockTimeout   time.Duration `yaml:"lock_timeout"   env:"LOCK_TIMEOUT"   env-default:"2m"`
+	SweepInterval time.Duration `yaml:"sweep_interval" env:"SWEEP_INTERVAL" env-default:"10m"`
+}
+
+// ReservationsConfig: DefaultTTL and MaxTTL bound how long a hold lives, the sweeper
+// expires stale holds every SweepInterval, at most SweepBatch per run.
+type ReservationsConfig struct {
+	DefaultTTL    time.Duration `yaml:"default_ttl"    env:"DEFAULT_TTL"    env-default:"30m"`
+	MaxTTL        time.Duration `yaml:"max_ttl"        env:"MAX_TTL"        env-default:"72h"`
+	SweepInterval time.Duration `yaml:"sweep_interval" env:"SWEEP_INTERVAL" env-default:"1m"`
+	SweepBatch    int32         `yaml:"sweep_batch"    env:"SWEEP_BATCH"    env-default:"100"`
+}
+
+// ImportsConfig: uploads larger than MaxFileSize are rejected. The worker looks for new imports
+// every PollInterval, applies and saves BatchSize rows per transaction and takes over an import whose
+// progress has not moved for StaleAfter.
+type ImportsConfig struct {
+	MaxFileSize  int64         `yaml:"max_file_size" env:"MAX_FILE_SIZE" env-default:"10485760"`
+	PollInterval time.Duration `yaml:"poll_interval" env:"POLL_INTERVAL" env-default:"5s"`
+	BatchSize    int           `yaml:"batch_size"    env:"BATCH_SIZE"    env-default:"100"`
+	StaleAfter   time.Duration `yaml:"stale_after"   env:"STALE_AFTER"   env-default:"5m"`
+}
+
+// ExportsConfig: every running export holds a database connection for as long as it streams,
+// so at most MaxConcurrent run at a time and the others are refused.
+type ExportsConfig struct {
+	MaxConcurrent int `yaml:"max_concurrent" env:"MAX_CONCURRENT" env-default:"2"`
+}
+
+// PricesConfig: the scheduler applies scheduled prices that are due every ApplyInterval,
+// at most ApplyBatch per run.
+type PricesConfig struct {
+	ApplyInterval time.Duration `yaml:"apply_interval" env:"APPLY_INTERVAL" env-default:"1m"`
+	ApplyBatch    int32         `yaml:"apply_batch"    env:"APPLY_BATCH"    env-default:"100"`
+}
+
+// AuthConfig: bearer tokens are verified with HS256Secret, the RS256 public key in the PEM file
+// RS256KeyFile and the RSA keys of the JWKS file JWKSFile, whichever are set; at least one must be.
+// The iss and aud claims are checked when Issuer and Audience are set, Leeway allows for clock skew.
+type AuthConfig struct {
+	HS256Secret  string        `yaml:"hs256_secret"   env:"HS256_SECRET"`
+	RS256KeyFile string        `yaml:"rs256_key_file" env:"RS256_KEY_FILE"`
+	JWKSFile     string        `yaml:"jwks_file"      env:"JWKS_FILE"`
+	Issuer       string        `yaml:"issuer"         env:"ISSUER"`
+	Audience     string        `yaml:"audience"       env:"AUDIENCE"`
+	Leeway       time.Duration `yaml:"leeway"         env:"LEEWAY"         env-default:"30s"`
+}
+
+// RateLimitConfig: every client has a token bucket per route group holding up to Burst requests
+// and refilled at Rate requests per second. The IP limit counts every request by the address of the
+// client before authentication; Search and Exports limits apply on top of the Default one.
+// Store keeps the buckets in "memory" of a single replica or in "postgres" to share them between
+// replicas; buckets idle for IdleTTL are dropped.
+type RateLimitConfig struct {
+	Enabled      bool          `yaml:"enabled"       env:"ENABLED"       env-default:"true"`
+	Store        string        `yaml:"store"         env:"STORE"         env-default:"memory"`
+	IPRate       float64       `yaml:"ip_rate"       env:"IP_RATE"       env-default:"20"`
+	IPBurst      int           `yaml:"ip_burst"      env:"IP_BURST"      env-default:"100"`
+	DefaultRate  float64       `yaml:"default_rate"  env:"DEFAULT_RATE"  env-default:"10"`
+	DefaultBurst int           `yaml:"default_burst" env:"DEFAULT_BURST" env-default:"40"`
+	SearchRate   float64       `yaml:"search_rate"   env:"SEARCH_RATE"   env-default:"2"`
+	SearchBurst  int           `yaml:"search_burst"  env:"SEARCH_BURST"  env-default:"10"`
+	ExportsRate  float64       `yaml:"exports_rate"  env:"EXPORTS_RATE"  env-default:"0.1"`
+	ExportsBurst int           `yaml:"exports_burst" env:"EXPORTS_BURST" env-default:"3"`
+	IdleTTL      time.Duration `yaml:"idle_ttl"      env:"IDLE_TTL"      env-default:"10m"`
 }
 
 func Load(configPath string) (*Config, error) {
@@ -0,0 +1,162 @@
+// Package dbtest gives integration tests a database of their own with every migration applied.
+// It connects to the Postgres server named by the TEST_DB_* variables, which mirror the DB_* ones;
+// without TEST_DB_HOST the tests using it are skipped.
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nikallow/bookstores-api/internal/adapters/postgres"
+	"github.com/nikallow/bookstores-api/internal/config"
+	"github.com/nikallow/bookstores-api/internal/database"
+)
+
+// New creates a database for the test, migrates it and drops it once the test is done.
+func New(t testing.TB) *postgres.DB {
+	t.Helper()
+	if os.Getenv("TEST_DB_HOST") == "" {
+		t.Skip("TEST_DB_HOST is not set")
+	}
+
+	var env struct {
+		Database config.DatabaseConfig `env-prefix:"TEST_DB_"`
+	}
+	if err := cleanenv.ReadEnv(&env); err != nil {
+		t.Fatalf("failed to read test database config: %v", err)
+	}
+	cfg := env.Database
+	serverDSN := cfg.GetDSN()
+	ctx := context.Background()
+
+	server, err := pgx.Connect(ctx, serverDSN)
+	if err != nil {
+		t.Fatalf("failed to connect to test database server: %v", err)
+	}
+	defer server.Close(ctx)
+
+	cfg.DBName = fmt.Sprintf("bookstores_test_%d", time.Now().UnixNano())
+	if _, err := server.Exec(ctx, "CREATE DATABASE "+cfg.DBName); err != nil {
+		t.Fatalf("failed to create test database: %v", err)
+	}
+	t.Cleanup(func() {
+		server, err := pgx.Connect(ctx, serverDSN)
+		if err != nil {
+			t.Errorf("failed to connect to test database server: %v", err)
+			return
+		}
+		defer server.Close(ctx)
+		if _, err := server.Exec(ctx, "DROP DATABASE IF EXISTS "+cfg.DBName+" WITH (FORCE)"); err != nil {
+			t.Errorf("failed to drop test database: %v", err)
+		}
+	})
+
+	migrator, err := database.NewMigrator(cfg, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("failed to create migrator: %v", err)
+	}
+	defer migrator.Close()
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+
+	db, err := postgres.NewPool(ctx, cfg)
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	t.Cleanup(db.Close)
+	return db
+}
+
+// CreateStore inserts a store and returns its ID and UUID.
+func CreateStore(t testing.TB, db *postgres.DB) (int64, uuid.UUID) {
+	t.Helper()
+	var (
+		id  int64
+		pid pgtype.UUID
+	)
+	err := db.QueryRow(context.Background(),
+		"INSERT INTO stores (name, address) VALUES ('Test store', 'Test address') RETURNING id, uuid",
+	).Scan(&id, &pid)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	return id, pid.Bytes
+}
+
+// CreateBook inserts a book and returns its ID.
+func CreateBook(t testing.TB, db *postgres.DB) int64 {
+	t.Helper()
+	var id int64
+	err := db.QueryRow(context.Background(),
+		"INSERT INTO books (title, author) VALUES ('Test book', 'Test author') RETURNING id",
+	).Scan(&id)
+	if err != nil {
+		t.Fatalf("failed to create book: %v", err)
+	}
+	return id
+}
+
+// CreateSKU inserts a SKU of the book in the store, bypassing the stock ledger, and returns its UUID.
+func CreateSKU(t testing.TB, db *postgres.DB, bookID, storeID int64, price, stock int32) uuid.UUID {
+	t.Helper()
+	var pid pgtype.UUID
+	err := db.QueryRow(context.Background(),
+		"INSERT INTO skus (book_id, store_id, price_in_kopeks, stock_count) VALUES ($1, $2, $3, $4) RETURNING uuid",
+		bookID, storeID, price, stock,
+	).Scan(&pid)
+	if err != nil {
+		t.Fatalf("failed to create sku: %v", err)
+	}
+	return pid.Bytes
+}
+
+// Stock returns the stock count of a SKU.
+func Stock(t testing.TB, db *postgres.DB, sku uuid.UUID) int32 {
+	t.Helper()
+	var stock int32
+	err := db.QueryRow(context.Background(),
+		"SELECT stock_count FROM skus WHERE uuid = $1", pgtype.UUID{Bytes: sku, Valid: true},
+	).Scan(&stock)
+	if err != nil {
+		t.Fatalf("failed to get sku stock: %v", err)
+	}
+	return stock
+}
+
+// Reserved returns the number of copies of a SKU held by reservations.
+func Reserved(t testing.TB, db *postgres.DB, sku uuid.UUID) int32 {
+	t.Helper()
+	var reserved int32
+	err := db.QueryRow(context.Background(),
+		"SELECT reserved_count FROM skus WHERE uuid = $1", pgtype.UUID{Bytes: sku, Valid: true},
+	).Scan(&reserved)
+	if err != nil {
+		t.Fatalf("failed to get sku reserved count: %v", err)
+	}
+	return reserved
+}
+
+// Movements returns the reasons of the stock movements of a SKU in the order they were made.
+func Movements(t testing.TB, db *postgres.DB, sku uuid.UUID) []string {
+	t.Helper()
+	rows, err := db.Query(context.Background(),
+		"SELECT m.reason::text FROM stock_movements m JOIN skus s ON s.id = m.sku_id WHERE s.uuid = $1 ORDER BY m.id", pgtype.UUID{Bytes: sku, Valid: true},
+	)
+	if err != nil {
+		t.Fatalf("failed to list stock movements: %v", err)
+	}
+	reasons, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		t.Fatalf("failed to list stock movements: %v", err)
+	}
+	return reasons
+}
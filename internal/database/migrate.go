@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"text/tabwriter"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/nikallow/bookstores-api/internal/config"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+//go:embed migrations/*.sql
+var embedMigrations embed.FS
+
+var ErrUnknownCommand = errors.New("unknown migrate command, expected one of: up, down, status, redo, version")
+
+// Migrator applies the embedded goose migrations. Every operation holds a Postgres
+// advisory lock, so several replicas migrating at startup don't race each other.
+type Migrator struct {
+	provider *goose.Provider
+	log      *slog.Logger
+}
+
+func NewMigrator(cfg config.DatabaseConfig, log *slog.Logger) (*Migrator, error) {
+	migrations, err := fs.Sub(embedMigrations, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedded migrations: %w", err)
+	}
+
+	db, err := sql.Open("pgx", cfg.GetDSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create migration locker: %w", err)
+	}
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, migrations,
+		goose.WithSessionLocker(locker),
+		goose.WithSlog(log),
+	)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create migration provider: %w", err)
+	}
+
+	return &Migrator{provider: provider, log: log}, nil
+}
+
+func (m *Migrator) Close() error {
+	return m.provider.Close()
+}
+
+// Run executes one of the migrate subcommands: up, down, status, redo, version.
+func (m *Migrator) Run(ctx context.Context, command string, out io.Writer) error {
+	switch command {
+	case "up":
+		return m.Up(ctx)
+	case "down":
+		return m.Down(ctx)
+	case "redo":
+		return m.Redo(ctx)
+	case "status":
+		return m.Status(ctx, out)
+	case "version":
+		version, err := m.provider.GetDBVersion(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get database version: %w", err)
+		}
+		fmt.Fprintf(out, "version: %d\n", version)
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownCommand, command)
+	}
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up(ctx context.Context) error {
+	results, err := m.provider.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	if len(results) == 0 {
+		m.log.Info("No pending migrations")
+	}
+	for _, res := range results {
+		m.log.Info("Migration applied", "result", res.String())
+	}
+	return nil
+}
+
+// Down rolls back the most recently applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	res, err := m.provider.Down(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to roll back migration: %w", err)
+	}
+	m.log.Info("Migration rolled back", "result", res.String())
+	return nil
+}
+
+// Redo rolls back the most recently applied migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) error {
+	if err := m.Down(ctx); err != nil {
+		return err
+	}
+	res, err := m.provider.UpByOne(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to reapply migration: %w", err)
+	}
+	m.log.Info("Migration applied", "result", res.String())
+	return nil
+}
+
+func (m *Migrator) Status(ctx context.Context, out io.Writer) error {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get migration status: %w", err)
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tSTATE\tAPPLIED AT\tFILE")
+	for _, st := range statuses {
+		appliedAt := "-"
+		if !st.AppliedAt.IsZero() {
+			appliedAt = st.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", st.Source.Version, st.State, appliedAt, st.Source.Path)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE FUNCTION books_search_vector(title TEXT, author TEXT, description TEXT, isbn TEXT) RETURNS tsvector
+    LANGUAGE sql
+    IMMUTABLE
+    PARALLEL SAFE
+AS
+$$
+SELECT setweight(to_tsvector('russian', title), 'A') ||
+       setweight(to_tsvector('english', title), 'A') ||
+       setweight(to_tsvector('simple', coalesce(isbn, '')), 'A') ||
+       setweight(to_tsvector('russian', author), 'B') ||
+       setweight(to_tsvector('english', author), 'B') ||
+       setweight(to_tsvector('russian', coalesce(description, '')), 'C') ||
+       setweight(to_tsvector('english', coalesce(description, '')), 'C')
+$$;
+
+CREATE INDEX books_search_idx ON books USING GIN (books_search_vector(title, author, description, isbn))
+    WHERE deleted_at IS NULL;
+CREATE INDEX books_title_trgm_idx ON books USING GIN (title gin_trgm_ops) WHERE deleted_at IS NULL;
+CREATE INDEX books_author_trgm_idx ON books USING GIN (author gin_trgm_ops) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS books_author_trgm_idx;
+DROP INDEX IF EXISTS books_title_trgm_idx;
+DROP INDEX IF EXISTS books_search_idx;
+DROP FUNCTION IF EXISTS books_search_vector(TEXT, TEXT, TEXT, TEXT);
+-- +goose StatementEnd
@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE stock_movement_reason AS ENUM ('receipt', 'sale', 'return', 'write_off', 'correction', 'transfer');
+
+CREATE TABLE stock_movements
+(
+    id         BIGSERIAL PRIMARY KEY,
+    uuid       UUID                  NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    sku_id     BIGINT                NOT NULL REFERENCES skus (id),
+    delta      INTEGER               NOT NULL,
+    balance    INTEGER               NOT NULL CHECK (balance >= 0),
+    reason     stock_movement_reason NOT NULL,
+    note       TEXT                  NULL,
+    actor      TEXT                  NULL,
+    request_id TEXT                  NULL,
+    created_at TIMESTAMPTZ           NOT NULL        DEFAULT now()
+);
+
+CREATE INDEX stock_movements_sku_created_idx ON stock_movements (sku_id, created_at, id);
+
+-- The ledger is append-only: corrections are recorded as new movements.
+CREATE FUNCTION forbid_stock_movement_changes() RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+BEGIN
+    RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$;
+
+CREATE TRIGGER stock_movements_immutable
+    BEFORE UPDATE OR DELETE
+    ON stock_movements
+    FOR EACH ROW
+EXECUTE FUNCTION forbid_stock_movement_changes();
+
+INSERT INTO stock_movements (sku_id, delta, balance, reason, note)
+SELECT id, stock_count, stock_count, 'correction', 'opening balance'
+FROM skus;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS stock_movements;
+DROP FUNCTION IF EXISTS forbid_stock_movement_changes();
+DROP TYPE IF EXISTS stock_movement_reason;
+-- +goose StatementEnd
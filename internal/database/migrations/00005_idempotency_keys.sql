@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Keys are chosen by clients, so each caller gets its own key space.
+CREATE TABLE idempotency_keys
+(
+    actor            TEXT        NOT NULL,
+    key              TEXT        NOT NULL,
+    method           TEXT        NOT NULL,
+    path             TEXT        NOT NULL,
+    fingerprint      TEXT        NOT NULL,
+    status_code      INTEGER     NULL,
+    response_headers JSONB       NULL,
+    response_body    BYTEA       NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at       TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (actor, key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS idempotency_keys;
+-- +goose StatementEnd
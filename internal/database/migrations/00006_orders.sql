@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE order_status AS ENUM ('pending', 'paid', 'fulfilled', 'cancelled');
+
+CREATE TABLE orders
+(
+    id              BIGSERIAL PRIMARY KEY,
+    uuid            UUID         NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    status          order_status NOT NULL        DEFAULT 'pending',
+    customer_name   TEXT         NOT NULL,
+    customer_email  TEXT         NULL,
+    total_in_kopeks BIGINT       NOT NULL CHECK (total_in_kopeks >= 0),
+    created_at      TIMESTAMPTZ  NOT NULL        DEFAULT now(),
+    updated_at      TIMESTAMPTZ  NOT NULL        DEFAULT now(),
+    paid_at         TIMESTAMPTZ  NULL,
+    fulfilled_at    TIMESTAMPTZ  NULL,
+    cancelled_at    TIMESTAMPTZ  NULL
+);
+
+CREATE INDEX orders_created_at_idx ON orders (created_at, id);
+
+CREATE TABLE order_items
+(
+    id              BIGSERIAL PRIMARY KEY,
+    order_id        BIGINT  NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    sku_id          BIGINT  NOT NULL REFERENCES skus (id),
+    quantity        INTEGER NOT NULL CHECK (quantity > 0),
+    price_in_kopeks INTEGER NOT NULL CHECK (price_in_kopeks >= 0),
+    UNIQUE (order_id, sku_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS orders;
+DROP TYPE IF EXISTS order_status;
+-- +goose StatementEnd
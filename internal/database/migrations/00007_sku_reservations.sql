@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE reservation_status AS ENUM ('active', 'confirmed', 'released', 'expired');
+
+ALTER TABLE skus
+    ADD COLUMN reserved_count INTEGER NOT NULL DEFAULT 0,
+    ADD CONSTRAINT skus_reserved_count_check CHECK (reserved_count >= 0 AND reserved_count <= stock_count);
+
+CREATE TABLE sku_reservations
+(
+    id         BIGSERIAL PRIMARY KEY,
+    uuid       UUID               NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    sku_id     BIGINT             NOT NULL REFERENCES skus (id),
+    quantity   INTEGER            NOT NULL CHECK (quantity > 0),
+    status     reservation_status NOT NULL        DEFAULT 'active',
+    note       TEXT               NULL,
+    actor      TEXT               NULL,
+    expires_at TIMESTAMPTZ        NOT NULL,
+    created_at TIMESTAMPTZ        NOT NULL        DEFAULT now(),
+    updated_at TIMESTAMPTZ        NOT NULL        DEFAULT now()
+);
+
+CREATE INDEX sku_reservations_sku_id_idx ON sku_reservations (sku_id);
+CREATE INDEX sku_reservations_active_expires_at_idx ON sku_reservations (expires_at) WHERE status = 'active';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS sku_reservations;
+ALTER TABLE skus
+    DROP CONSTRAINT IF EXISTS skus_reserved_count_check,
+    DROP COLUMN IF EXISTS reserved_count;
+DROP TYPE IF EXISTS reservation_status;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE transfer_status AS ENUM ('dispatched', 'received', 'cancelled');
+
+CREATE TABLE transfers
+(
+    id                 BIGSERIAL PRIMARY KEY,
+    uuid               UUID            NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    book_id            BIGINT          NOT NULL REFERENCES books (id),
+    source_sku_id      BIGINT          NOT NULL REFERENCES skus (id),
+    destination_sku_id BIGINT          NOT NULL REFERENCES skus (id),
+    quantity           INTEGER         NOT NULL CHECK (quantity > 0),
+    status             transfer_status NOT NULL        DEFAULT 'dispatched',
+    note               TEXT            NULL,
+    actor              TEXT            NULL,
+    created_at         TIMESTAMPTZ     NOT NULL        DEFAULT now(),
+    updated_at         TIMESTAMPTZ     NOT NULL        DEFAULT now(),
+    received_at        TIMESTAMPTZ     NULL,
+    cancelled_at       TIMESTAMPTZ     NULL,
+    CHECK (source_sku_id <> destination_sku_id)
+);
+
+CREATE INDEX transfers_created_at_idx ON transfers (created_at, id);
+CREATE INDEX transfers_source_sku_id_idx ON transfers (source_sku_id);
+CREATE INDEX transfers_destination_sku_id_idx ON transfers (destination_sku_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS transfers;
+DROP TYPE IF EXISTS transfer_status;
+-- +goose StatementEnd
@@ -0,0 +1,65 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE author_role AS ENUM ('author', 'translator', 'illustrator');
+
+CREATE TABLE authors
+(
+    id         BIGSERIAL PRIMARY KEY,
+    name       TEXT        NOT NULL,
+    bio        TEXT        NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX authors_name_idx ON authors (name, id);
+CREATE INDEX authors_lower_name_idx ON authors (lower(name));
+
+CREATE TABLE publishers
+(
+    id         BIGSERIAL PRIMARY KEY,
+    name       TEXT        NOT NULL UNIQUE,
+    website    TEXT        NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- The unique name index can't serve ORDER BY name, id.
+CREATE INDEX publishers_name_id_idx ON publishers (name, id);
+
+-- Authors of a book in display order. The same person may appear with several roles.
+CREATE TABLE book_authors
+(
+    book_id   BIGINT      NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    author_id BIGINT      NOT NULL REFERENCES authors (id),
+    role      author_role NOT NULL DEFAULT 'author',
+    position  INTEGER     NOT NULL DEFAULT 0,
+    PRIMARY KEY (book_id, author_id, role)
+);
+
+CREATE INDEX book_authors_author_id_idx ON book_authors (author_id);
+
+ALTER TABLE books
+    ADD COLUMN publisher_id BIGINT NULL REFERENCES publishers (id);
+
+CREATE INDEX books_publisher_id_idx ON books (publisher_id);
+
+-- Every distinct author string becomes an author; merging spelling variants is left to editors.
+INSERT INTO authors (name)
+SELECT DISTINCT author
+FROM books;
+
+INSERT INTO book_authors (book_id, author_id, role, position)
+SELECT b.id, a.id, 'author', 0
+FROM books b
+         JOIN authors a ON a.name = b.author;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE books
+    DROP COLUMN IF EXISTS publisher_id;
+DROP TABLE IF EXISTS book_authors;
+DROP TABLE IF EXISTS publishers;
+DROP TABLE IF EXISTS authors;
+DROP TYPE IF EXISTS author_role;
+-- +goose StatementEnd
@@ -0,0 +1,75 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE genres
+(
+    id         BIGSERIAL PRIMARY KEY,
+    parent_id  BIGINT      NULL REFERENCES genres (id),
+    name       TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Sibling genres have distinct names; top-level genres are siblings of each other.
+CREATE UNIQUE INDEX genres_parent_name_idx ON genres (COALESCE(parent_id, 0), lower(name));
+CREATE INDEX genres_parent_id_idx ON genres (parent_id);
+
+CREATE TABLE book_genres
+(
+    book_id  BIGINT NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    genre_id BIGINT NOT NULL REFERENCES genres (id),
+    PRIMARY KEY (book_id, genre_id)
+);
+
+CREATE INDEX book_genres_genre_id_idx ON book_genres (genre_id);
+
+-- Tags are free-form and stored lower-cased, so "Фэнтези" and "фэнтези" are one tag.
+CREATE TABLE tags
+(
+    id         BIGSERIAL PRIMARY KEY,
+    name       TEXT        NOT NULL UNIQUE CHECK (name = lower(btrim(name)) AND name <> ''),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX tags_name_id_idx ON tags (name, id);
+
+CREATE TABLE book_tags
+(
+    book_id BIGINT NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    tag_id  BIGINT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (book_id, tag_id)
+);
+
+CREATE INDEX book_tags_tag_id_idx ON book_tags (tag_id);
+
+CREATE TABLE series
+(
+    id          BIGSERIAL PRIMARY KEY,
+    name        TEXT        NOT NULL,
+    description TEXT        NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX series_name_idx ON series (name, id);
+
+ALTER TABLE books
+    ADD COLUMN series_id     BIGINT  NULL REFERENCES series (id),
+    ADD COLUMN series_volume INTEGER NULL CHECK (series_volume > 0),
+    ADD CONSTRAINT books_series_volume_series_check CHECK (series_volume IS NULL OR series_id IS NOT NULL);
+
+CREATE INDEX books_series_idx ON books (series_id, series_volume);
+CREATE INDEX books_series_volume_id_idx ON books (COALESCE(series_volume, 0), id) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE books
+    DROP CONSTRAINT IF EXISTS books_series_volume_series_check,
+    DROP COLUMN IF EXISTS series_volume,
+    DROP COLUMN IF EXISTS series_id;
+DROP TABLE IF EXISTS series;
+DROP TABLE IF EXISTS book_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS book_genres;
+DROP TABLE IF EXISTS genres;
+-- +goose StatementEnd
@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE import_status AS ENUM ('pending', 'running', 'completed', 'failed');
+CREATE TYPE import_format AS ENUM ('csv', 'ndjson');
+
+CREATE TABLE imports
+(
+    id             BIGSERIAL PRIMARY KEY,
+    uuid           UUID          NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    format         import_format NOT NULL,
+    dry_run        BOOLEAN       NOT NULL        DEFAULT false,
+    upsert         BOOLEAN       NOT NULL        DEFAULT false,
+    status         import_status NOT NULL        DEFAULT 'pending',
+    -- Bumped by every worker that claims the import, so that a worker that was taken over
+    -- can tell its writes apart.
+    attempt        INTEGER       NOT NULL        DEFAULT 0,
+    -- The uploaded file, dropped once the import has finished.
+    payload        BYTEA         NULL,
+    total_rows     INTEGER       NOT NULL        DEFAULT 0,
+    processed_rows INTEGER       NOT NULL        DEFAULT 0,
+    failed_rows    INTEGER       NOT NULL        DEFAULT 0,
+    books_created  INTEGER       NOT NULL        DEFAULT 0,
+    books_updated  INTEGER       NOT NULL        DEFAULT 0,
+    books_existing INTEGER       NOT NULL        DEFAULT 0,
+    skus_created   INTEGER       NOT NULL        DEFAULT 0,
+    error          TEXT          NULL,
+    actor          TEXT          NULL,
+    created_at     TIMESTAMPTZ   NOT NULL        DEFAULT now(),
+    updated_at     TIMESTAMPTZ   NOT NULL        DEFAULT now(),
+    started_at     TIMESTAMPTZ   NULL,
+    finished_at    TIMESTAMPTZ   NULL
+);
+
+CREATE INDEX imports_pending_idx ON imports (id) WHERE status IN ('pending', 'running');
+
+CREATE TABLE import_errors
+(
+    import_id  BIGINT  NOT NULL REFERENCES imports (id) ON DELETE CASCADE,
+    line       INTEGER NOT NULL,
+    isbn       TEXT    NULL,
+    message    TEXT    NOT NULL,
+    PRIMARY KEY (import_id, line)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS import_errors;
+DROP TABLE IF EXISTS imports;
+DROP TYPE IF EXISTS import_format;
+DROP TYPE IF EXISTS import_status;
+-- +goose StatementEnd
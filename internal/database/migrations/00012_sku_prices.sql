@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE sku_prices
+(
+    id              BIGSERIAL PRIMARY KEY,
+    uuid            UUID        NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    sku_id          BIGINT      NOT NULL REFERENCES skus (id),
+    price_in_kopeks INTEGER     NOT NULL CHECK (price_in_kopeks >= 0),
+    scheduled_for   TIMESTAMPTZ NULL,
+    effective_from  TIMESTAMPTZ NULL,
+    effective_to    TIMESTAMPTZ NULL,
+    cancelled_at    TIMESTAMPTZ NULL,
+    actor           TEXT        NULL,
+    request_id      TEXT        NULL,
+    created_at      TIMESTAMPTZ NOT NULL        DEFAULT now(),
+    CHECK (effective_from IS NOT NULL OR scheduled_for IS NOT NULL),
+    CHECK (effective_from IS NULL OR cancelled_at IS NULL)
+);
+
+CREATE INDEX sku_prices_sku_created_idx ON sku_prices (sku_id, created_at, id);
+-- A SKU has one current price and at most one pending price per moment.
+CREATE UNIQUE INDEX sku_prices_current_idx ON sku_prices (sku_id)
+    WHERE effective_from IS NOT NULL AND effective_to IS NULL;
+CREATE UNIQUE INDEX sku_prices_scheduled_idx ON sku_prices (sku_id, scheduled_for)
+    WHERE effective_from IS NULL AND cancelled_at IS NULL;
+CREATE INDEX sku_prices_due_idx ON sku_prices (scheduled_for)
+    WHERE effective_from IS NULL AND cancelled_at IS NULL;
+
+INSERT INTO sku_prices (sku_id, price_in_kopeks, effective_from)
+SELECT id, price_in_kopeks, now()
+FROM skus;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS sku_prices;
+-- +goose StatementEnd
@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE promotion_kind AS ENUM ('percent', 'fixed', 'buy_x_get_y');
+
+-- A promotion applies to SKUs matching every scope it sets; without scopes it applies to the whole catalog.
+-- Removing the author, genre or book a promotion is scoped to removes the promotion as well.
+CREATE TABLE promotions
+(
+    id                   BIGSERIAL PRIMARY KEY,
+    name                 TEXT           NOT NULL,
+    kind                 promotion_kind NOT NULL,
+    percent_off          INTEGER        NULL CHECK (percent_off BETWEEN 1 AND 100),
+    amount_off_in_kopeks INTEGER        NULL CHECK (amount_off_in_kopeks > 0),
+    buy_quantity         INTEGER        NULL CHECK (buy_quantity > 0),
+    free_quantity        INTEGER        NULL CHECK (free_quantity > 0),
+    priority             INTEGER        NOT NULL DEFAULT 0,
+    stackable            BOOLEAN        NOT NULL DEFAULT false,
+    starts_at            TIMESTAMPTZ    NULL,
+    ends_at              TIMESTAMPTZ    NULL,
+    store_id             BIGINT         NULL REFERENCES stores (id),
+    book_id              BIGINT         NULL REFERENCES books (id) ON DELETE CASCADE,
+    author_id            BIGINT         NULL REFERENCES authors (id) ON DELETE CASCADE,
+    genre_id             BIGINT         NULL REFERENCES genres (id) ON DELETE CASCADE,
+    created_at           TIMESTAMPTZ    NOT NULL DEFAULT now(),
+    updated_at           TIMESTAMPTZ    NOT NULL DEFAULT now(),
+    CONSTRAINT promotions_kind_check CHECK (
+        (kind = 'percent' AND percent_off IS NOT NULL
+            AND amount_off_in_kopeks IS NULL AND buy_quantity IS NULL AND free_quantity IS NULL)
+        OR (kind = 'fixed' AND amount_off_in_kopeks IS NOT NULL
+            AND percent_off IS NULL AND buy_quantity IS NULL AND free_quantity IS NULL)
+        OR (kind = 'buy_x_get_y' AND buy_quantity IS NOT NULL AND free_quantity IS NOT NULL
+            AND percent_off IS NULL AND amount_off_in_kopeks IS NULL)),
+    CONSTRAINT promotions_window_check CHECK (starts_at IS NULL OR ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX promotions_window_idx ON promotions (starts_at, ends_at);
+CREATE INDEX promotions_store_id_idx ON promotions (store_id);
+CREATE INDEX promotions_book_id_idx ON promotions (book_id);
+CREATE INDEX promotions_author_id_idx ON promotions (author_id);
+CREATE INDEX promotions_genre_id_idx ON promotions (genre_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS promotions;
+DROP TYPE IF EXISTS promotion_kind;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every amount is kept in minor units of a currency (ISO 4217); the *_in_kopeks columns keep their
+-- names for compatibility. Existing data is in roubles.
+ALTER TABLE stores
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'RUB' CHECK (currency ~ '^[A-Z]{3}$'),
+    ADD CONSTRAINT stores_id_currency_key UNIQUE (id, currency);
+
+-- A SKU is priced in the currency of its store; the currency of a store can't change while it has SKUs.
+ALTER TABLE skus
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'RUB' CHECK (currency ~ '^[A-Z]{3}$'),
+    ADD CONSTRAINT skus_store_currency_fkey FOREIGN KEY (store_id, currency) REFERENCES stores (id, currency);
+
+ALTER TABLE orders
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'RUB' CHECK (currency ~ '^[A-Z]{3}$');
+
+-- A fixed amount off only applies to SKUs priced in the same currency.
+ALTER TABLE promotions
+    ADD COLUMN currency TEXT NULL CHECK (currency ~ '^[A-Z]{3}$');
+UPDATE promotions
+SET currency = 'RUB'
+WHERE kind = 'fixed';
+ALTER TABLE promotions
+    ADD CONSTRAINT promotions_currency_check CHECK ((kind = 'fixed') = (currency IS NOT NULL));
+
+-- rate is how many units of quote_currency one unit of base_currency buys.
+CREATE TABLE exchange_rates
+(
+    base_currency  TEXT           NOT NULL CHECK (base_currency ~ '^[A-Z]{3}$'),
+    quote_currency TEXT           NOT NULL CHECK (quote_currency ~ '^[A-Z]{3}$'),
+    rate           NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    actor          TEXT           NULL,
+    updated_at     TIMESTAMPTZ    NOT NULL DEFAULT now(),
+    PRIMARY KEY (base_currency, quote_currency),
+    CHECK (base_currency <> quote_currency)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS exchange_rates;
+ALTER TABLE promotions
+    DROP COLUMN IF EXISTS currency;
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS currency;
+ALTER TABLE skus
+    DROP COLUMN IF EXISTS currency;
+ALTER TABLE stores
+    DROP COLUMN IF EXISTS currency;
+-- +goose StatementEnd
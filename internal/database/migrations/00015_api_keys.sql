@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Credentials of machine clients. Only a SHA-256 hash of the secret is stored; the prefix is the
+-- public part of the key that identifies it in lists and logs. Without store_uuids the key works in every store.
+CREATE TABLE api_keys
+(
+    id           BIGSERIAL PRIMARY KEY,
+    uuid         UUID        NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    name         TEXT        NOT NULL,
+    prefix       TEXT        NOT NULL UNIQUE,
+    secret_hash  TEXT        NOT NULL,
+    scopes       TEXT[]      NOT NULL CHECK (cardinality(scopes) > 0),
+    store_uuids  UUID[]      NULL CHECK (cardinality(store_uuids) > 0),
+    expires_at   TIMESTAMPTZ NULL,
+    last_used_at TIMESTAMPTZ NULL,
+    revoked_at   TIMESTAMPTZ NULL,
+    rotated_from UUID        NULL REFERENCES api_keys (uuid),
+    actor        TEXT        NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX api_keys_created_at_idx ON api_keys (created_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_keys;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Append-only record of changes to stores, books and inventory, written in the transaction of the change.
+-- before and after hold only the fields that changed: before is NULL for a created entity.
+CREATE TABLE audit_events
+(
+    id          BIGSERIAL PRIMARY KEY,
+    entity_type TEXT        NOT NULL,
+    entity_id   TEXT        NOT NULL,
+    action      TEXT        NOT NULL,
+    before      JSONB       NULL,
+    after       JSONB       NULL,
+    actor       TEXT        NULL,
+    request_id  TEXT        NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_events_created_at_idx ON audit_events (created_at, id);
+CREATE INDEX audit_events_entity_idx ON audit_events (entity_type, entity_id, created_at);
+CREATE INDEX audit_events_actor_idx ON audit_events (actor, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_events;
+-- +goose StatementEnd
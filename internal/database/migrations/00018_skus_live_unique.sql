@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A soft-deleted SKU doesn't keep a new one from being created in its place.
+ALTER TABLE skus
+    DROP CONSTRAINT skus_book_id_store_id_key;
+
+CREATE UNIQUE INDEX skus_book_id_store_id_key ON skus (book_id, store_id) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Fails while a deleted SKU and its replacement are both kept.
+DROP INDEX IF EXISTS skus_book_id_store_id_key;
+
+ALTER TABLE skus
+    ADD CONSTRAINT skus_book_id_store_id_key UNIQUE (book_id, store_id);
+-- +goose StatementEnd
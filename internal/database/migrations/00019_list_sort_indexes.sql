@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- One (column, id) index of live rows per list sort.
+CREATE INDEX stores_name_id_idx ON stores (name, id) WHERE deleted_at IS NULL;
+CREATE INDEX stores_created_at_id_idx ON stores (created_at, id) WHERE deleted_at IS NULL;
+
+CREATE INDEX books_title_id_idx ON books (title, id) WHERE deleted_at IS NULL;
+CREATE INDEX books_author_id_idx ON books (author, id) WHERE deleted_at IS NULL;
+CREATE INDEX books_publication_year_id_idx ON books (COALESCE(publication_year, 0), id) WHERE deleted_at IS NULL;
+CREATE INDEX books_created_at_id_idx ON books (created_at, id) WHERE deleted_at IS NULL;
+
+CREATE INDEX skus_store_price_id_idx ON skus (store_id, price_in_kopeks, id) WHERE deleted_at IS NULL;
+CREATE INDEX skus_store_stock_id_idx ON skus (store_id, stock_count, id) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS skus_store_stock_id_idx;
+DROP INDEX IF EXISTS skus_store_price_id_idx;
+DROP INDEX IF EXISTS books_created_at_id_idx;
+DROP INDEX IF EXISTS books_publication_year_id_idx;
+DROP INDEX IF EXISTS books_author_id_idx;
+DROP INDEX IF EXISTS books_title_id_idx;
+DROP INDEX IF EXISTS stores_created_at_id_idx;
+DROP INDEX IF EXISTS stores_name_id_idx;
+-- +goose StatementEnd
@@ -0,0 +1,88 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Books saved before ISBNs were normalized may hold an ISBN-10 or a hyphenated one; lookups
+-- only find the ISBN-13 form, so those are rewritten the way books.NormalizeISBN does it.
+CREATE FUNCTION pg_temp.canonical_isbn(isbn TEXT) RETURNS TEXT
+    LANGUAGE plpgsql
+    IMMUTABLE
+AS
+$$
+DECLARE
+    digits TEXT    := upper(translate(isbn, '- ', ''));
+    body   TEXT;
+    total  INTEGER := 0;
+BEGIN
+    IF digits ~ '^[0-9]{9}[0-9X]$' THEN
+        FOR i IN 1..9
+            LOOP
+                total := total + substr(digits, i, 1)::INTEGER * (11 - i);
+            END LOOP;
+        total := total + CASE WHEN right(digits, 1) = 'X' THEN 10 ELSE right(digits, 1)::INTEGER END;
+        IF total % 11 <> 0 THEN
+            RETURN NULL;
+        END IF;
+        body := '978' || left(digits, 9);
+    ELSIF digits ~ '^[0-9]{13}$' THEN
+        body := left(digits, 12);
+    ELSE
+        RETURN NULL;
+    END IF;
+
+    total := 0;
+    FOR i IN 1..12
+        LOOP
+            total := total + substr(body, i, 1)::INTEGER * CASE WHEN i % 2 = 0 THEN 3 ELSE 1 END;
+        END LOOP;
+    body := body || ((10 - total % 10) % 10)::TEXT;
+
+    IF length(digits) = 13 AND body <> digits THEN
+        RETURN NULL;
+    END IF;
+    RETURN body;
+END;
+$$;
+
+-- Two books that turn out to share an ISBN have to be merged by hand, so the migration stops and
+-- names them. An ISBN that isn't valid at all is left as it is and only reported.
+DO
+$$
+DECLARE
+    collisions TEXT;
+    invalid    TEXT;
+BEGIN
+    SELECT string_agg(format('%s (books %s)', canonical, ids), '; ' ORDER BY canonical)
+    INTO collisions
+    FROM (SELECT pg_temp.canonical_isbn(isbn) AS canonical, string_agg(id::TEXT, ', ' ORDER BY id) AS ids
+          FROM books
+          WHERE isbn IS NOT NULL
+          GROUP BY 1
+          HAVING count(*) > 1) AS shared
+    WHERE canonical IS NOT NULL;
+    IF collisions IS NOT NULL THEN
+        RAISE EXCEPTION 'books share an ISBN once it is converted to ISBN-13: %', collisions;
+    END IF;
+
+    SELECT string_agg(format('%s (book %s)', isbn, id), '; ' ORDER BY id)
+    INTO invalid
+    FROM books
+    WHERE isbn IS NOT NULL
+      AND pg_temp.canonical_isbn(isbn) IS NULL;
+    IF invalid IS NOT NULL THEN
+        RAISE WARNING 'invalid ISBNs left unchanged: %', invalid;
+    END IF;
+
+    UPDATE books
+    SET isbn       = pg_temp.canonical_isbn(isbn),
+        updated_at = now()
+    WHERE pg_temp.canonical_isbn(isbn) <> isbn;
+END;
+$$;
+
+DROP FUNCTION pg_temp.canonical_isbn(TEXT);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- The original spelling of a converted ISBN isn't kept, and the ISBN-13 form is valid on its own.
+SELECT 1;
+-- +goose StatementEnd
@@ -0,0 +1,56 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (name, prefix, secret_hash, scopes, store_uuids, expires_at, rotated_from, actor)
+VALUES (sqlc.arg(name), sqlc.arg(prefix), sqlc.arg(secret_hash), sqlc.arg(scopes)::text[],
+        sqlc.narg(store_uuids)::uuid[], sqlc.narg(expires_at), sqlc.narg(rotated_from), sqlc.narg(actor))
+RETURNING *;
+
+-- name: GetAPIKey :one
+SELECT *
+FROM api_keys
+WHERE uuid = $1;
+
+-- name: GetAPIKeyForUpdate :one
+SELECT *
+FROM api_keys
+WHERE uuid = $1
+    FOR UPDATE;
+
+-- name: GetAPIKeyByPrefix :one
+SELECT *
+FROM api_keys
+WHERE prefix = $1;
+
+-- name: ListAPIKeys :many
+SELECT *
+FROM api_keys
+WHERE sqlc.arg(include_inactive)::boolean
+   OR (revoked_at IS NULL AND (expires_at IS NULL OR expires_at > now()))
+ORDER BY created_at, id;
+
+-- name: RevokeAPIKey :one
+UPDATE api_keys
+SET revoked_at = now()
+WHERE uuid = $1
+  AND revoked_at IS NULL
+RETURNING *;
+
+-- name: ExpireAPIKey :one
+-- Shortens the life of a rotated key to the grace period; it never extends it.
+UPDATE api_keys
+SET expires_at = LEAST(COALESCE(expires_at, sqlc.arg(expires_at)), sqlc.arg(expires_at))
+WHERE uuid = $1
+  AND revoked_at IS NULL
+RETURNING *;
+
+-- name: TouchAPIKey :exec
+-- Records a use of the key at most once a minute, so busy clients don't write on every request.
+UPDATE api_keys
+SET last_used_at = now()
+WHERE id = $1
+  AND (last_used_at IS NULL OR last_used_at < now() - INTERVAL '1 minute');
+
+-- name: CountStoresByUUIDs :one
+SELECT count(*)
+FROM stores
+WHERE uuid = ANY (sqlc.arg(store_uuids)::uuid[])
+  AND deleted_at IS NULL;
@@ -0,0 +1,32 @@
+-- Stores touched by a request, resolved for store-scoped authorization. Soft-deleted rows
+-- are included: whether the request may go on is decided by its handler.
+
+-- name: ListSKUStoreUUIDs :many
+SELECT s.uuid AS sku_uuid, st.uuid AS store_uuid
+FROM skus s
+         JOIN stores st ON s.store_id = st.id
+WHERE s.uuid = ANY (sqlc.arg(sku_uuids)::uuid[]);
+
+-- name: GetReservationStoreUUID :one
+SELECT st.uuid
+FROM sku_reservations r
+         JOIN skus s ON r.sku_id = s.id
+         JOIN stores st ON s.store_id = st.id
+WHERE r.uuid = $1;
+
+-- name: GetTransferStoreUUIDs :one
+SELECT src.uuid AS source_store_uuid, dst.uuid AS destination_store_uuid
+FROM transfers t
+         JOIN skus ss ON t.source_sku_id = ss.id
+         JOIN stores src ON ss.store_id = src.id
+         JOIN skus ds ON t.destination_sku_id = ds.id
+         JOIN stores dst ON ds.store_id = dst.id
+WHERE t.uuid = $1;
+
+-- name: ListOrderStoreUUIDs :many
+SELECT DISTINCT st.uuid
+FROM orders o
+         JOIN order_items oi ON oi.order_id = o.id
+         JOIN skus s ON oi.sku_id = s.id
+         JOIN stores st ON s.store_id = st.id
+WHERE o.uuid = $1;
@@ -0,0 +1,104 @@
+-- name: CreateAuthor :one
+INSERT INTO authors (name, bio)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetAuthorByID :one
+SELECT *
+FROM authors
+WHERE id = $1;
+
+-- name: FindAuthorByName :one
+SELECT *
+FROM authors
+WHERE lower(name) = lower($1)
+ORDER BY id
+LIMIT 1;
+
+-- name: ListAuthors :many
+SELECT *
+FROM authors
+WHERE (sqlc.narg(name)::text IS NULL OR name ILIKE '%' || sqlc.narg(name)::text || '%')
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (name, id) > (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY name, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListAuthorsDesc :many
+SELECT *
+FROM authors
+WHERE (sqlc.narg(name)::text IS NULL OR name ILIKE '%' || sqlc.narg(name)::text || '%')
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (name, id) < (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY name DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: UpdateAuthor :one
+UPDATE authors
+SET name       = $2,
+    bio        = $3,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteAuthor :execrows
+DELETE
+FROM authors
+WHERE id = $1;
+
+-- name: ListBooksByAuthor :many
+SELECT *
+FROM books b
+WHERE b.deleted_at IS NULL
+  AND EXISTS (SELECT 1
+              FROM book_authors ba
+              WHERE ba.book_id = b.id
+                AND ba.author_id = sqlc.arg(author_id)
+                AND (sqlc.narg(role)::author_role IS NULL OR ba.role = sqlc.narg(role)::author_role))
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (b.title, b.id) > (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY b.title, b.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListBooksByAuthorDesc :many
+SELECT *
+FROM books b
+WHERE b.deleted_at IS NULL
+  AND EXISTS (SELECT 1
+              FROM book_authors ba
+              WHERE ba.book_id = b.id
+                AND ba.author_id = sqlc.arg(author_id)
+                AND (sqlc.narg(role)::author_role IS NULL OR ba.role = sqlc.narg(role)::author_role))
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (b.title, b.id) < (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY b.title DESC, b.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListBookAuthors :many
+SELECT ba.book_id, ba.role, ba.position, sqlc.embed(a)
+FROM book_authors ba
+         JOIN authors a ON ba.author_id = a.id
+WHERE ba.book_id = ANY (sqlc.arg(book_ids)::bigint[])
+ORDER BY ba.book_id, ba.position, ba.role;
+
+-- name: DeleteBookAuthors :exec
+DELETE
+FROM book_authors
+WHERE book_id = sqlc.arg(book_id)
+  AND (sqlc.narg(role)::author_role IS NULL OR role = sqlc.narg(role)::author_role);
+
+-- name: AddBookAuthor :exec
+INSERT INTO book_authors (book_id, author_id, role, position)
+VALUES ($1, $2, $3, $4);
+
+-- name: RefreshBookAuthorNames :exec
+-- Recomputes the legacy books.author string from the linked authors of the given
+-- books, or of every book of the given author.
+UPDATE books b
+SET author = COALESCE((SELECT string_agg(a.name, ', ' ORDER BY ba.position)
+                       FROM book_authors ba
+                                JOIN authors a ON ba.author_id = a.id
+                       WHERE ba.book_id = b.id
+                         AND ba.role = 'author'), b.author)
+WHERE b.id = ANY (sqlc.arg(book_ids)::bigint[])
+   OR b.id IN (SELECT book_id FROM book_authors WHERE author_id = sqlc.narg(author_id)::bigint);
@@ -1,19 +1,354 @@
 -- name: CreateBook :one
-INSERT INTO books (isbn, title, author, description, page_count, publication_year)
-VALUES ($1, $2, $3, $4, $5, $6)
-ON CONFLICT (isbn)
-WHERE isbn IS NOT NULL DO
-UPDATE
-SET title      = EXCLUDED.title,
-    author     = EXCLUDED.author,
-    updated_at = now()
+INSERT INTO books (isbn, title, author, description, page_count, publication_year, publisher_id, series_id,
+                   series_volume)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (isbn) DO NOTHING
 RETURNING *;
 
--- name: ListBooks :many
+-- name: UpsertBook :one
+-- Optional fields missing from the request keep their stored value unless replace is set.
+-- An upsert also brings back a soft-deleted book with the same ISBN.
+INSERT INTO books (isbn, title, author, description, page_count, publication_year, publisher_id, series_id,
+                   series_volume)
+VALUES (sqlc.arg(isbn), sqlc.arg(title), sqlc.arg(author), sqlc.narg(description), sqlc.narg(page_count),
+        sqlc.narg(publication_year), sqlc.narg(publisher_id), sqlc.narg(series_id), sqlc.narg(series_volume))
+ON CONFLICT (isbn) DO UPDATE
+    SET title            = EXCLUDED.title,
+        author           = EXCLUDED.author,
+        description      = CASE
+                               WHEN sqlc.arg(replace)::bool THEN EXCLUDED.description
+                               ELSE COALESCE(EXCLUDED.description, books.description) END,
+        page_count       = CASE
+                               WHEN sqlc.arg(replace)::bool THEN EXCLUDED.page_count
+                               ELSE COALESCE(EXCLUDED.page_count, books.page_count) END,
+        publication_year = CASE
+                               WHEN sqlc.arg(replace)::bool THEN EXCLUDED.publication_year
+                               ELSE COALESCE(EXCLUDED.publication_year, books.publication_year) END,
+        publisher_id     = CASE
+                               WHEN sqlc.arg(replace)::bool THEN EXCLUDED.publisher_id
+                               ELSE COALESCE(EXCLUDED.publisher_id, books.publisher_id) END,
+        series_id        = CASE
+                               WHEN sqlc.arg(replace)::bool THEN EXCLUDED.series_id
+                               ELSE COALESCE(EXCLUDED.series_id, books.series_id) END,
+        series_volume    = CASE
+                               WHEN sqlc.arg(replace)::bool THEN EXCLUDED.series_volume
+                               ELSE COALESCE(EXCLUDED.series_volume, books.series_volume) END,
+        deleted_at       = NULL,
+        updated_at       = now()
+RETURNING *, (xmax = 0)::bool AS inserted;
+
+-- name: GetBookByISBN :one
+SELECT *
+FROM books
+WHERE isbn = $1
+  AND deleted_at IS NULL;
+
+-- name: GetBookByISBNForUpdate :one
+-- Soft-deleted books are included: an upsert brings them back.
 SELECT *
 FROM books
+WHERE isbn = $1
+    FOR UPDATE;
+
+-- name: ListBooksByTitle :many
+SELECT *
+FROM books b
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(author)::text IS NULL OR b.author ILIKE '%' || sqlc.narg(author)::text || '%')
+  AND (sqlc.narg(year_from)::int IS NULL OR b.publication_year >= sqlc.narg(year_from)::int)
+  AND (sqlc.narg(year_to)::int IS NULL OR b.publication_year <= sqlc.narg(year_to)::int)
+  AND (sqlc.narg(genre_ids)::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY (sqlc.narg(genre_ids)::bigint[])))
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = sqlc.narg(tag)::text))
+  AND (sqlc.narg(series_id)::bigint IS NULL OR b.series_id = sqlc.narg(series_id)::bigint)
+  AND (sqlc.narg(decade)::int IS NULL OR b.publication_year BETWEEN sqlc.narg(decade)::int AND sqlc.narg(decade)::int + 9)
+  AND (sqlc.narg(in_stock)::bool IS NULL OR sqlc.narg(in_stock)::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (title, id) > (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY title, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListBooksByTitleDesc :many
+SELECT *
+FROM books b
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(author)::text IS NULL OR b.author ILIKE '%' || sqlc.narg(author)::text || '%')
+  AND (sqlc.narg(year_from)::int IS NULL OR b.publication_year >= sqlc.narg(year_from)::int)
+  AND (sqlc.narg(year_to)::int IS NULL OR b.publication_year <= sqlc.narg(year_to)::int)
+  AND (sqlc.narg(genre_ids)::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY (sqlc.narg(genre_ids)::bigint[])))
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = sqlc.narg(tag)::text))
+  AND (sqlc.narg(series_id)::bigint IS NULL OR b.series_id = sqlc.narg(series_id)::bigint)
+  AND (sqlc.narg(decade)::int IS NULL OR b.publication_year BETWEEN sqlc.narg(decade)::int AND sqlc.narg(decade)::int + 9)
+  AND (sqlc.narg(in_stock)::bool IS NULL OR sqlc.narg(in_stock)::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (title, id) < (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY title DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListBooksByAuthorName :many
+SELECT *
+FROM books b
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(author)::text IS NULL OR b.author ILIKE '%' || sqlc.narg(author)::text || '%')
+  AND (sqlc.narg(year_from)::int IS NULL OR b.publication_year >= sqlc.narg(year_from)::int)
+  AND (sqlc.narg(year_to)::int IS NULL OR b.publication_year <= sqlc.narg(year_to)::int)
+  AND (sqlc.narg(genre_ids)::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY (sqlc.narg(genre_ids)::bigint[])))
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = sqlc.narg(tag)::text))
+  AND (sqlc.narg(series_id)::bigint IS NULL OR b.series_id = sqlc.narg(series_id)::bigint)
+  AND (sqlc.narg(decade)::int IS NULL OR b.publication_year BETWEEN sqlc.narg(decade)::int AND sqlc.narg(decade)::int + 9)
+  AND (sqlc.narg(in_stock)::bool IS NULL OR sqlc.narg(in_stock)::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (author, id) > (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY author, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListBooksByAuthorNameDesc :many
+SELECT *
+FROM books b
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(author)::text IS NULL OR b.author ILIKE '%' || sqlc.narg(author)::text || '%')
+  AND (sqlc.narg(year_from)::int IS NULL OR b.publication_year >= sqlc.narg(year_from)::int)
+  AND (sqlc.narg(year_to)::int IS NULL OR b.publication_year <= sqlc.narg(year_to)::int)
+  AND (sqlc.narg(genre_ids)::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY (sqlc.narg(genre_ids)::bigint[])))
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = sqlc.narg(tag)::text))
+  AND (sqlc.narg(series_id)::bigint IS NULL OR b.series_id = sqlc.narg(series_id)::bigint)
+  AND (sqlc.narg(decade)::int IS NULL OR b.publication_year BETWEEN sqlc.narg(decade)::int AND sqlc.narg(decade)::int + 9)
+  AND (sqlc.narg(in_stock)::bool IS NULL OR sqlc.narg(in_stock)::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (author, id) < (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY author DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListBooksByPublicationYear :many
+SELECT *
+FROM books b
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(author)::text IS NULL OR b.author ILIKE '%' || sqlc.narg(author)::text || '%')
+  AND (sqlc.narg(year_from)::int IS NULL OR b.publication_year >= sqlc.narg(year_from)::int)
+  AND (sqlc.narg(year_to)::int IS NULL OR b.publication_year <= sqlc.narg(year_to)::int)
+  AND (sqlc.narg(genre_ids)::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY (sqlc.narg(genre_ids)::bigint[])))
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = sqlc.narg(tag)::text))
+  AND (sqlc.narg(series_id)::bigint IS NULL OR b.series_id = sqlc.narg(series_id)::bigint)
+  AND (sqlc.narg(decade)::int IS NULL OR b.publication_year BETWEEN sqlc.narg(decade)::int AND sqlc.narg(decade)::int + 9)
+  AND (sqlc.narg(in_stock)::bool IS NULL OR sqlc.narg(in_stock)::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (COALESCE(publication_year, 0), id) > (sqlc.narg(cursor_int)::int, sqlc.narg(cursor_id)::bigint))
+ORDER BY COALESCE(publication_year, 0), id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListBooksByPublicationYearDesc :many
+SELECT *
+FROM books b
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(author)::text IS NULL OR b.author ILIKE '%' || sqlc.narg(author)::text || '%')
+  AND (sqlc.narg(year_from)::int IS NULL OR b.publication_year >= sqlc.narg(year_from)::int)
+  AND (sqlc.narg(year_to)::int IS NULL OR b.publication_year <= sqlc.narg(year_to)::int)
+  AND (sqlc.narg(genre_ids)::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY (sqlc.narg(genre_ids)::bigint[])))
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = sqlc.narg(tag)::text))
+  AND (sqlc.narg(series_id)::bigint IS NULL OR b.series_id = sqlc.narg(series_id)::bigint)
+  AND (sqlc.narg(decade)::int IS NULL OR b.publication_year BETWEEN sqlc.narg(decade)::int AND sqlc.narg(decade)::int + 9)
+  AND (sqlc.narg(in_stock)::bool IS NULL OR sqlc.narg(in_stock)::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (COALESCE(publication_year, 0), id) < (sqlc.narg(cursor_int)::int, sqlc.narg(cursor_id)::bigint))
+ORDER BY COALESCE(publication_year, 0) DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListBooksBySeriesVolume :many
+SELECT *
+FROM books b
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(author)::text IS NULL OR b.author ILIKE '%' || sqlc.narg(author)::text || '%')
+  AND (sqlc.narg(year_from)::int IS NULL OR b.publication_year >= sqlc.narg(year_from)::int)
+  AND (sqlc.narg(year_to)::int IS NULL OR b.publication_year <= sqlc.narg(year_to)::int)
+  AND (sqlc.narg(genre_ids)::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY (sqlc.narg(genre_ids)::bigint[])))
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = sqlc.narg(tag)::text))
+  AND (sqlc.narg(series_id)::bigint IS NULL OR b.series_id = sqlc.narg(series_id)::bigint)
+  AND (sqlc.narg(decade)::int IS NULL OR b.publication_year BETWEEN sqlc.narg(decade)::int AND sqlc.narg(decade)::int + 9)
+  AND (sqlc.narg(in_stock)::bool IS NULL OR sqlc.narg(in_stock)::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (COALESCE(series_volume, 0), id) > (sqlc.narg(cursor_int)::int, sqlc.narg(cursor_id)::bigint))
+ORDER BY COALESCE(series_volume, 0), id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListBooksBySeriesVolumeDesc :many
+SELECT *
+FROM books b
 WHERE deleted_at IS NULL
-ORDER BY title;
+  AND (sqlc.narg(author)::text IS NULL OR b.author ILIKE '%' || sqlc.narg(author)::text || '%')
+  AND (sqlc.narg(year_from)::int IS NULL OR b.publication_year >= sqlc.narg(year_from)::int)
+  AND (sqlc.narg(year_to)::int IS NULL OR b.publication_year <= sqlc.narg(year_to)::int)
+  AND (sqlc.narg(genre_ids)::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY (sqlc.narg(genre_ids)::bigint[])))
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = sqlc.narg(tag)::text))
+  AND (sqlc.narg(series_id)::bigint IS NULL OR b.series_id = sqlc.narg(series_id)::bigint)
+  AND (sqlc.narg(decade)::int IS NULL OR b.publication_year BETWEEN sqlc.narg(decade)::int AND sqlc.narg(decade)::int + 9)
+  AND (sqlc.narg(in_stock)::bool IS NULL OR sqlc.narg(in_stock)::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (COALESCE(series_volume, 0), id) < (sqlc.narg(cursor_int)::int, sqlc.narg(cursor_id)::bigint))
+ORDER BY COALESCE(series_volume, 0) DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListBooksByCreatedAt :many
+SELECT *
+FROM books b
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(author)::text IS NULL OR b.author ILIKE '%' || sqlc.narg(author)::text || '%')
+  AND (sqlc.narg(year_from)::int IS NULL OR b.publication_year >= sqlc.narg(year_from)::int)
+  AND (sqlc.narg(year_to)::int IS NULL OR b.publication_year <= sqlc.narg(year_to)::int)
+  AND (sqlc.narg(genre_ids)::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY (sqlc.narg(genre_ids)::bigint[])))
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = sqlc.narg(tag)::text))
+  AND (sqlc.narg(series_id)::bigint IS NULL OR b.series_id = sqlc.narg(series_id)::bigint)
+  AND (sqlc.narg(decade)::int IS NULL OR b.publication_year BETWEEN sqlc.narg(decade)::int AND sqlc.narg(decade)::int + 9)
+  AND (sqlc.narg(in_stock)::bool IS NULL OR sqlc.narg(in_stock)::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (created_at, id) > (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListBooksByCreatedAtDesc :many
+SELECT *
+FROM books b
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(author)::text IS NULL OR b.author ILIKE '%' || sqlc.narg(author)::text || '%')
+  AND (sqlc.narg(year_from)::int IS NULL OR b.publication_year >= sqlc.narg(year_from)::int)
+  AND (sqlc.narg(year_to)::int IS NULL OR b.publication_year <= sqlc.narg(year_to)::int)
+  AND (sqlc.narg(genre_ids)::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                         FROM book_genres bg
+                                                         WHERE bg.book_id = b.id
+                                                           AND bg.genre_id = ANY (sqlc.narg(genre_ids)::bigint[])))
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (SELECT 1
+                                               FROM book_tags bt
+                                                        JOIN tags t ON bt.tag_id = t.id
+                                               WHERE bt.book_id = b.id
+                                                 AND t.name = sqlc.narg(tag)::text))
+  AND (sqlc.narg(series_id)::bigint IS NULL OR b.series_id = sqlc.narg(series_id)::bigint)
+  AND (sqlc.narg(decade)::int IS NULL OR b.publication_year BETWEEN sqlc.narg(decade)::int AND sqlc.narg(decade)::int + 9)
+  AND (sqlc.narg(in_stock)::bool IS NULL OR sqlc.narg(in_stock)::bool = EXISTS (SELECT 1
+                                                                               FROM skus s
+                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                               WHERE s.book_id = b.id
+                                                                                 AND s.deleted_at IS NULL
+                                                                                 AND st.deleted_at IS NULL
+                                                                                 AND s.stock_count > s.reserved_count))
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (created_at, id) < (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
 
 -- name: GetBookByID :one
 SELECT *
@@ -21,9 +356,183 @@ FROM books
 WHERE id = $1
   AND deleted_at IS NULL;
 
--- name: SearchBooks :many
+-- name: GetBookByIDForUpdate :one
 SELECT *
 FROM books
-WHERE (title ILIKE '%' || sqlc.arg(query) || '%' OR author ILIKE '%' || sqlc.arg(query) || '%')
+WHERE id = $1
+  AND deleted_at IS NULL
+    FOR UPDATE;
+
+-- name: SearchBooks :many
+-- isbn is the query as a canonical ISBN-13, NULL if it isn't an ISBN. Snippets are highlighted
+-- with the dictionary of the language the query is written in.
+WITH search AS (SELECT websearch_to_tsquery('russian', sqlc.arg(query)::text) ||
+                       websearch_to_tsquery('english', sqlc.arg(query)::text) AS tsq,
+                       sqlc.narg(isbn)::text                                  AS isbn,
+                       CASE
+                           WHEN sqlc.arg(query)::text ~ '[А-Яа-яЁё]' THEN 'russian'
+                           ELSE 'english' END::regconfig                      AS headline_config),
+     ranked AS (SELECT b.*,
+                       (ts_rank_cd(books_search_vector(b.title, b.author, b.description, b.isbn), search.tsq) +
+                        GREATEST(word_similarity(sqlc.arg(query)::text, b.title),
+                                 word_similarity(sqlc.arg(query)::text, b.author)) +
+                        CASE WHEN b.isbn = search.isbn THEN 1 ELSE 0 END)::real AS rank
+                FROM books b,
+                     search
+                WHERE b.deleted_at IS NULL
+                  AND (books_search_vector(b.title, b.author, b.description, b.isbn) @@ search.tsq
+                    OR sqlc.arg(query)::text <% b.title
+                    OR sqlc.arg(query)::text <% b.author
+                    OR b.isbn = search.isbn)
+                  AND (sqlc.narg(author)::text IS NULL OR b.author ILIKE '%' || sqlc.narg(author)::text || '%')
+                  AND (sqlc.narg(year_from)::int IS NULL OR b.publication_year >= sqlc.narg(year_from)::int)
+                  AND (sqlc.narg(year_to)::int IS NULL OR b.publication_year <= sqlc.narg(year_to)::int)
+                  AND (sqlc.narg(genre_ids)::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                                         FROM book_genres bg
+                                                                         WHERE bg.book_id = b.id
+                                                                           AND bg.genre_id = ANY (sqlc.narg(genre_ids)::bigint[])))
+                  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (SELECT 1
+                                                               FROM book_tags bt
+                                                                        JOIN tags t ON bt.tag_id = t.id
+                                                               WHERE bt.book_id = b.id
+                                                                 AND t.name = sqlc.narg(tag)::text))
+                  AND (sqlc.narg(series_id)::bigint IS NULL OR b.series_id = sqlc.narg(series_id)::bigint)
+                  AND (sqlc.narg(decade)::int IS NULL OR b.publication_year BETWEEN sqlc.narg(decade)::int AND sqlc.narg(decade)::int + 9)
+                  AND (sqlc.narg(in_stock)::bool IS NULL OR sqlc.narg(in_stock)::bool = EXISTS (SELECT 1
+                                                                                               FROM skus s
+                                                                                                        JOIN stores st ON s.store_id = st.id
+                                                                                               WHERE s.book_id = b.id
+                                                                                                 AND s.deleted_at IS NULL
+                                                                                                 AND st.deleted_at IS NULL
+                                                                                                 AND s.stock_count > s.reserved_count)))
+SELECT r.id,
+       r.isbn,
+       r.title,
+       r.author,
+       r.description,
+       r.page_count,
+       r.publication_year,
+       r.created_at,
+       r.updated_at,
+       r.deleted_at,
+       r.publisher_id,
+       r.series_id,
+       r.series_volume,
+       r.rank::real AS rank,
+       ts_headline(search.headline_config, concat_ws(' — ', r.title, r.author, r.description), search.tsq,
+                   'StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2')::text AS snippet
+FROM ranked r,
+     search
+WHERE sqlc.narg(cursor_id)::bigint IS NULL
+   OR r.rank < sqlc.narg(cursor_rank)::real
+   OR (r.rank = sqlc.narg(cursor_rank)::real AND r.id > sqlc.narg(cursor_id)::bigint)
+ORDER BY r.rank DESC, r.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: UpdateBook :one
+UPDATE books
+SET isbn             = $2,
+    title            = $3,
+    author           = $4,
+    description      = $5,
+    page_count       = $6,
+    publication_year = $7,
+    publisher_id     = $8,
+    series_id        = $9,
+    series_volume    = $10,
+    updated_at       = now()
+WHERE id = $1
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SoftDeleteBook :one
+UPDATE books
+SET deleted_at = now(),
+    updated_at = now()
+WHERE id = $1
   AND deleted_at IS NULL
-LIMIT 10;
+RETURNING *;
+
+-- name: GetDeletedBookByIDForUpdate :one
+SELECT *
+FROM books
+WHERE id = $1
+  AND deleted_at IS NOT NULL
+    FOR UPDATE;
+
+-- name: RestoreBook :one
+UPDATE books
+SET deleted_at = NULL,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: BookFacets :many
+-- Counts the books matching the filters, and the search query if given, per genre, author,
+-- publication decade and stock availability. Each facet keeps its facet_limit largest values.
+-- isbn is the query as a canonical ISBN-13, as in SearchBooks.
+WITH search AS (SELECT websearch_to_tsquery('russian', sqlc.narg(query)::text) ||
+                       websearch_to_tsquery('english', sqlc.narg(query)::text) AS tsq,
+                       sqlc.narg(isbn)::text                                   AS isbn),
+     matched AS (SELECT b.id, b.publication_year
+                 FROM books b,
+                      search
+                 WHERE b.deleted_at IS NULL
+                   AND (sqlc.narg(query)::text IS NULL
+                     OR books_search_vector(b.title, b.author, b.description, b.isbn) @@ search.tsq
+                     OR sqlc.narg(query)::text <% b.title
+                     OR sqlc.narg(query)::text <% b.author
+                     OR b.isbn = search.isbn)
+                   AND (sqlc.narg(author)::text IS NULL OR b.author ILIKE '%' || sqlc.narg(author)::text || '%')
+                   AND (sqlc.narg(year_from)::int IS NULL OR b.publication_year >= sqlc.narg(year_from)::int)
+                   AND (sqlc.narg(year_to)::int IS NULL OR b.publication_year <= sqlc.narg(year_to)::int)
+                   AND (sqlc.narg(genre_ids)::bigint[] IS NULL OR EXISTS (SELECT 1
+                                                                          FROM book_genres bg
+                                                                          WHERE bg.book_id = b.id
+                                                                            AND bg.genre_id = ANY (sqlc.narg(genre_ids)::bigint[])))
+                   AND (sqlc.narg(tag)::text IS NULL OR EXISTS (SELECT 1
+                                                                FROM book_tags bt
+                                                                         JOIN tags t ON bt.tag_id = t.id
+                                                                WHERE bt.book_id = b.id
+                                                                  AND t.name = sqlc.narg(tag)::text))
+                   AND (sqlc.narg(series_id)::bigint IS NULL OR b.series_id = sqlc.narg(series_id)::bigint)
+                   AND (sqlc.narg(decade)::int IS NULL OR b.publication_year BETWEEN sqlc.narg(decade)::int AND sqlc.narg(decade)::int + 9)
+                   AND (sqlc.narg(in_stock)::bool IS NULL OR sqlc.narg(in_stock)::bool = EXISTS (SELECT 1
+                                                                                                FROM skus s
+                                                                                                         JOIN stores st ON s.store_id = st.id
+                                                                                                WHERE s.book_id = b.id
+                                                                                                  AND s.deleted_at IS NULL
+                                                                                                  AND st.deleted_at IS NULL
+                                                                                                  AND s.stock_count > s.reserved_count))),
+     counts AS (SELECT 'genre'::text AS facet, g.id::text AS value, g.name AS label, count(*) AS count
+                FROM matched m
+                         JOIN book_genres bg ON bg.book_id = m.id
+                         JOIN genres g ON bg.genre_id = g.id
+                GROUP BY g.id, g.name
+                UNION ALL
+                SELECT 'author', a.id::text, a.name, count(DISTINCT m.id)
+                FROM matched m
+                         JOIN book_authors ba ON ba.book_id = m.id AND ba.role = 'author'
+                         JOIN authors a ON ba.author_id = a.id
+                GROUP BY a.id, a.name
+                UNION ALL
+                SELECT 'decade', (m.publication_year / 10 * 10)::text, (m.publication_year / 10 * 10)::text, count(*)
+                FROM matched m
+                WHERE m.publication_year IS NOT NULL
+                GROUP BY m.publication_year / 10 * 10
+                UNION ALL
+                SELECT 'in_stock', stock.in_stock::text, stock.in_stock::text, count(*)
+                FROM (SELECT EXISTS (SELECT 1
+                                     FROM skus s
+                                              JOIN stores st ON s.store_id = st.id
+                                     WHERE s.book_id = m.id
+                                       AND s.deleted_at IS NULL
+                                       AND st.deleted_at IS NULL
+                                       AND s.stock_count > s.reserved_count) AS in_stock
+                      FROM matched m) stock
+                GROUP BY stock.in_stock)
+SELECT ranked.facet::text AS facet, ranked.value::text AS value, ranked.label::text AS label, ranked.count::bigint AS count
+FROM (SELECT c.*, row_number() OVER (PARTITION BY c.facet ORDER BY c.count DESC, c.label) AS n
+      FROM counts c) ranked
+WHERE ranked.n <= sqlc.arg(facet_limit)::int
+ORDER BY ranked.facet, ranked.count DESC, ranked.label;
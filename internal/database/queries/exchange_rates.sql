@@ -0,0 +1,25 @@
+-- name: UpsertExchangeRate :one
+INSERT INTO exchange_rates (base_currency, quote_currency, rate, actor)
+VALUES (sqlc.arg(base_currency), sqlc.arg(quote_currency), sqlc.arg(rate)::text::numeric, sqlc.narg(actor))
+ON CONFLICT (base_currency, quote_currency) DO UPDATE
+    SET rate       = excluded.rate,
+        actor      = excluded.actor,
+        updated_at = now()
+RETURNING base_currency, quote_currency, rate::text AS rate, actor, updated_at;
+
+-- name: GetExchangeRate :one
+SELECT base_currency, quote_currency, rate::text AS rate, actor, updated_at
+FROM exchange_rates
+WHERE base_currency = $1
+  AND quote_currency = $2;
+
+-- name: ListExchangeRates :many
+SELECT base_currency, quote_currency, rate::text AS rate, actor, updated_at
+FROM exchange_rates
+ORDER BY base_currency, quote_currency;
+
+-- name: DeleteExchangeRate :execrows
+DELETE
+FROM exchange_rates
+WHERE base_currency = $1
+  AND quote_currency = $2;
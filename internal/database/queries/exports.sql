@@ -0,0 +1,26 @@
+-- name: BeginSnapshot :exec
+-- Must be the first statement of a transaction: all of its reads see the same snapshot.
+SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY;
+
+-- name: ExportInventory :many
+SELECT st.uuid AS store_uuid, st.name AS store_name, sqlc.embed(s), sqlc.embed(b)
+FROM skus s
+         JOIN books b ON s.book_id = b.id
+         JOIN stores st ON s.store_id = st.id
+WHERE (sqlc.narg(store_id)::bigint IS NULL OR s.store_id = sqlc.narg(store_id)::bigint)
+  AND s.deleted_at IS NULL
+  AND b.deleted_at IS NULL
+  AND st.deleted_at IS NULL
+  AND s.id > sqlc.arg(after_id)::bigint
+ORDER BY s.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ExportBooks :many
+SELECT sqlc.embed(b), p.name AS publisher_name, se.name AS series_name
+FROM books b
+         LEFT JOIN publishers p ON b.publisher_id = p.id
+         LEFT JOIN series se ON b.series_id = se.id
+WHERE b.deleted_at IS NULL
+  AND b.id > sqlc.arg(after_id)::bigint
+ORDER BY b.id
+LIMIT sqlc.arg(page_limit);
@@ -0,0 +1,56 @@
+-- name: CreateGenre :one
+INSERT INTO genres (parent_id, name)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetGenreByID :one
+SELECT *
+FROM genres
+WHERE id = $1;
+
+-- name: ListGenres :many
+SELECT *
+FROM genres
+ORDER BY name, id;
+
+-- name: UpdateGenre :one
+UPDATE genres
+SET parent_id  = $2,
+    name       = $3,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteGenre :execrows
+DELETE
+FROM genres
+WHERE id = $1;
+
+-- name: ListGenreSubtreeIDs :many
+-- The genre itself and all of its descendants.
+WITH RECURSIVE subtree AS (SELECT g.id
+                           FROM genres g
+                           WHERE g.id = sqlc.arg(id)
+                           UNION ALL
+                           SELECT g.id
+                           FROM genres g
+                                    JOIN subtree ON g.parent_id = subtree.id)
+SELECT id
+FROM subtree;
+
+-- name: ListBookGenres :many
+SELECT bg.book_id, sqlc.embed(g)
+FROM book_genres bg
+         JOIN genres g ON bg.genre_id = g.id
+WHERE bg.book_id = ANY (sqlc.arg(book_ids)::bigint[])
+ORDER BY bg.book_id, g.name;
+
+-- name: DeleteBookGenres :exec
+DELETE
+FROM book_genres
+WHERE book_id = $1;
+
+-- name: AddBookGenres :exec
+INSERT INTO book_genres (book_id, genre_id)
+SELECT sqlc.arg(book_id), unnest(sqlc.arg(genre_ids)::bigint[])
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,43 @@
+-- name: ClaimIdempotencyKey :one
+-- Takes the key of the actor for a new request. An expired key, or one whose request died
+-- before completing (claimed before stale_before), is taken over.
+INSERT INTO idempotency_keys (actor, key, method, path, fingerprint, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (actor, key) DO UPDATE
+    SET method           = EXCLUDED.method,
+        path             = EXCLUDED.path,
+        fingerprint      = EXCLUDED.fingerprint,
+        status_code      = NULL,
+        response_headers = NULL,
+        response_body    = NULL,
+        created_at       = now(),
+        expires_at       = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at < now()
+   OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < sqlc.arg(stale_before))
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT *
+FROM idempotency_keys
+WHERE actor = $1
+  AND key = $2;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code      = $3,
+    response_headers = $4,
+    response_body    = $5
+WHERE actor = $1
+  AND key = $2;
+
+-- name: ReleaseIdempotencyKey :exec
+DELETE
+FROM idempotency_keys
+WHERE actor = $1
+  AND key = $2
+  AND status_code IS NULL;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE
+FROM idempotency_keys
+WHERE expires_at < now();
@@ -0,0 +1,74 @@
+-- name: CreateImport :one
+INSERT INTO imports (format, dry_run, upsert, payload, total_rows, actor)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, uuid, format, dry_run, upsert, status, total_rows, processed_rows, failed_rows,
+    books_created, books_updated, books_existing, skus_created, error, actor,
+    created_at, updated_at, started_at, finished_at;
+
+-- name: GetImportByUUID :one
+SELECT id, uuid, format, dry_run, upsert, status, total_rows, processed_rows, failed_rows,
+       books_created, books_updated, books_existing, skus_created, error, actor,
+       created_at, updated_at, started_at, finished_at
+FROM imports
+WHERE uuid = $1;
+
+-- name: ClaimImport :one
+-- Takes the oldest pending import, or a running one whose worker stopped reporting progress.
+UPDATE imports
+SET status     = 'running',
+    attempt    = attempt + 1,
+    started_at = COALESCE(started_at, now()),
+    updated_at = now()
+WHERE id = (SELECT i.id
+            FROM imports i
+            WHERE i.status = 'pending'
+               OR (i.status = 'running' AND i.updated_at < sqlc.arg(stale_before)::timestamptz)
+            ORDER BY i.id
+            LIMIT 1 FOR UPDATE SKIP LOCKED)
+RETURNING *;
+
+-- name: RecordImportProgress :execrows
+UPDATE imports
+SET processed_rows = processed_rows + sqlc.arg(processed_rows)::int,
+    failed_rows    = failed_rows + sqlc.arg(failed_rows)::int,
+    books_created  = books_created + sqlc.arg(books_created)::int,
+    books_updated  = books_updated + sqlc.arg(books_updated)::int,
+    books_existing = books_existing + sqlc.arg(books_existing)::int,
+    skus_created   = skus_created + sqlc.arg(skus_created)::int,
+    updated_at     = now()
+WHERE id = sqlc.arg(id)
+  AND attempt = sqlc.arg(attempt)
+  AND status = 'running';
+
+-- name: FinishImport :exec
+UPDATE imports
+SET status      = sqlc.arg(status)::import_status,
+    error       = sqlc.narg(error),
+    payload     = NULL,
+    updated_at  = now(),
+    finished_at = now()
+WHERE id = sqlc.arg(id)
+  AND attempt = sqlc.arg(attempt);
+
+-- name: RequeueImport :exec
+UPDATE imports
+SET status     = 'pending',
+    updated_at = now()
+WHERE id = $1
+  AND attempt = $2
+  AND status = 'running';
+
+-- name: AddImportErrors :exec
+INSERT INTO import_errors (import_id, line, isbn, message)
+SELECT sqlc.arg(import_id), e.line, NULLIF(e.isbn, ''), e.message
+FROM (SELECT unnest(sqlc.arg(lines)::int[])     AS line,
+             unnest(sqlc.arg(isbns)::text[])    AS isbn,
+             unnest(sqlc.arg(messages)::text[]) AS message) e
+ON CONFLICT (import_id, line) DO UPDATE SET isbn    = EXCLUDED.isbn,
+                                            message = EXCLUDED.message;
+
+-- name: ListImportErrors :many
+SELECT line, isbn, message
+FROM import_errors
+WHERE import_id = $1
+ORDER BY line;
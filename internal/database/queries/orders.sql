@@ -0,0 +1,68 @@
+-- name: CreateOrder :one
+INSERT INTO orders (customer_name, customer_email, total_in_kopeks, currency)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: CreateOrderItem :one
+INSERT INTO order_items (order_id, sku_id, quantity, price_in_kopeks)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetOrderByUUID :one
+SELECT *
+FROM orders
+WHERE uuid = $1;
+
+-- name: GetOrderByUUIDForUpdate :one
+SELECT *
+FROM orders
+WHERE uuid = $1
+    FOR UPDATE;
+
+-- name: ListOrderItems :many
+SELECT oi.id,
+       oi.quantity,
+       oi.price_in_kopeks,
+       s.uuid AS sku_uuid,
+       s.book_id,
+       b.title
+FROM order_items oi
+         JOIN skus s ON oi.sku_id = s.id
+         JOIN books b ON s.book_id = b.id
+WHERE oi.order_id = $1
+ORDER BY oi.id;
+
+-- name: ListOrders :many
+SELECT *
+FROM orders
+WHERE (sqlc.narg(status)::order_status IS NULL OR status = sqlc.narg(status)::order_status)
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (created_at, id) > (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListOrdersDesc :many
+SELECT *
+FROM orders
+WHERE (sqlc.narg(status)::order_status IS NULL OR status = sqlc.narg(status)::order_status)
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (created_at, id) < (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: UpdateOrderStatus :one
+UPDATE orders
+SET status       = sqlc.arg(status)::order_status,
+    updated_at   = now(),
+    paid_at      = CASE WHEN sqlc.arg(status)::order_status = 'paid' THEN now() ELSE paid_at END,
+    fulfilled_at = CASE WHEN sqlc.arg(status)::order_status = 'fulfilled' THEN now() ELSE fulfilled_at END,
+    cancelled_at = CASE WHEN sqlc.arg(status)::order_status = 'cancelled' THEN now() ELSE cancelled_at END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListOrderSKUQuantities :many
+SELECT oi.sku_id, oi.quantity
+FROM order_items oi
+         JOIN skus s ON oi.sku_id = s.id
+WHERE oi.order_id = $1
+ORDER BY s.uuid;
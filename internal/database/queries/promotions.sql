@@ -0,0 +1,84 @@
+-- name: CreatePromotion :one
+INSERT INTO promotions (name, kind, percent_off, amount_off_in_kopeks, buy_quantity, free_quantity, priority,
+                        stackable, starts_at, ends_at, store_id, book_id, author_id, genre_id, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+RETURNING *;
+
+-- name: GetPromotionByID :one
+SELECT *
+FROM promotions
+WHERE id = $1;
+
+-- name: ListPromotions :many
+SELECT *
+FROM promotions
+WHERE (sqlc.narg(active_at)::timestamptz IS NULL
+    OR ((starts_at IS NULL OR starts_at <= sqlc.narg(active_at)::timestamptz)
+        AND (ends_at IS NULL OR ends_at > sqlc.narg(active_at)::timestamptz)))
+  AND (sqlc.narg(store_id)::bigint IS NULL OR store_id = sqlc.narg(store_id)::bigint)
+  AND (sqlc.narg(cursor_id)::bigint IS NULL OR id > sqlc.narg(cursor_id)::bigint)
+ORDER BY id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListPromotionsDesc :many
+SELECT *
+FROM promotions
+WHERE (sqlc.narg(active_at)::timestamptz IS NULL
+    OR ((starts_at IS NULL OR starts_at <= sqlc.narg(active_at)::timestamptz)
+        AND (ends_at IS NULL OR ends_at > sqlc.narg(active_at)::timestamptz)))
+  AND (sqlc.narg(store_id)::bigint IS NULL OR store_id = sqlc.narg(store_id)::bigint)
+  AND (sqlc.narg(cursor_id)::bigint IS NULL OR id < sqlc.narg(cursor_id)::bigint)
+ORDER BY id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: UpdatePromotion :one
+UPDATE promotions
+SET name                 = $2,
+    kind                 = $3,
+    percent_off          = $4,
+    amount_off_in_kopeks = $5,
+    buy_quantity         = $6,
+    free_quantity        = $7,
+    priority             = $8,
+    stackable            = $9,
+    starts_at            = $10,
+    ends_at              = $11,
+    store_id             = $12,
+    book_id              = $13,
+    author_id            = $14,
+    genre_id             = $15,
+    currency             = $16,
+    updated_at           = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeletePromotion :execrows
+DELETE
+FROM promotions
+WHERE id = $1;
+
+-- name: ListCandidatePromotions :many
+-- Promotions running at the given moment whose every scope is among the given ones;
+-- whether a promotion matches a particular SKU is decided by the caller.
+SELECT *
+FROM promotions
+WHERE (starts_at IS NULL OR starts_at <= sqlc.arg(at)::timestamptz)
+  AND (ends_at IS NULL OR ends_at > sqlc.arg(at)::timestamptz)
+  AND (store_id IS NULL OR store_id = ANY (sqlc.arg(store_ids)::bigint[]))
+  AND (book_id IS NULL OR book_id = ANY (sqlc.arg(book_ids)::bigint[]))
+  AND (author_id IS NULL OR author_id = ANY (sqlc.arg(author_ids)::bigint[]))
+  AND (genre_id IS NULL OR genre_id = ANY (sqlc.arg(genre_ids)::bigint[]))
+ORDER BY priority DESC, id;
+
+-- name: ListBookGenreLineage :many
+-- The genres of the books together with all of their ancestors.
+WITH RECURSIVE lineage AS (SELECT bg.book_id, bg.genre_id
+                           FROM book_genres bg
+                           WHERE bg.book_id = ANY (sqlc.arg(book_ids)::bigint[])
+                           UNION
+                           SELECT l.book_id, g.parent_id
+                           FROM lineage l
+                                    JOIN genres g ON g.id = l.genre_id
+                           WHERE g.parent_id IS NOT NULL)
+SELECT book_id, genre_id
+FROM lineage;
@@ -0,0 +1,45 @@
+-- name: CreatePublisher :one
+INSERT INTO publishers (name, website)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetPublisherByID :one
+SELECT *
+FROM publishers
+WHERE id = $1;
+
+-- name: ListPublishers :many
+SELECT *
+FROM publishers
+WHERE (sqlc.narg(name)::text IS NULL OR name ILIKE '%' || sqlc.narg(name)::text || '%')
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (name, id) > (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY name, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListPublishersDesc :many
+SELECT *
+FROM publishers
+WHERE (sqlc.narg(name)::text IS NULL OR name ILIKE '%' || sqlc.narg(name)::text || '%')
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (name, id) < (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY name DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListPublishersByIDs :many
+SELECT *
+FROM publishers
+WHERE id = ANY (sqlc.arg(ids)::bigint[]);
+
+-- name: UpdatePublisher :one
+UPDATE publishers
+SET name       = $2,
+    website    = $3,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeletePublisher :execrows
+DELETE
+FROM publishers
+WHERE id = $1;
@@ -0,0 +1,22 @@
+-- name: TakeRateLimitToken :one
+-- Refills the bucket for the time since it was last used and takes a token when a whole one is there;
+-- allowed tells whether it did. A new bucket starts full.
+INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+VALUES (sqlc.arg(key), sqlc.arg(burst)::float8 - 1, true, now())
+ON CONFLICT (key) DO UPDATE
+    SET tokens     = LEAST(sqlc.arg(burst)::float8,
+                           b.tokens + GREATEST(EXTRACT(EPOCH FROM now() - b.updated_at)::float8, 0) * sqlc.arg(rate)::float8)
+                         - CASE
+                               WHEN LEAST(sqlc.arg(burst)::float8,
+                                          b.tokens + GREATEST(EXTRACT(EPOCH FROM now() - b.updated_at)::float8, 0) * sqlc.arg(rate)::float8) >= 1
+                                   THEN 1
+                               ELSE 0 END,
+        allowed    = LEAST(sqlc.arg(burst)::float8,
+                           b.tokens + GREATEST(EXTRACT(EPOCH FROM now() - b.updated_at)::float8, 0) * sqlc.arg(rate)::float8) >= 1,
+        updated_at = now()
+RETURNING tokens, allowed;
+
+-- name: DeleteIdleRateLimitBuckets :execrows
+DELETE
+FROM rate_limit_buckets
+WHERE updated_at < $1;
@@ -0,0 +1,45 @@
+-- name: CreateSeries :one
+INSERT INTO series (name, description)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetSeriesByID :one
+SELECT *
+FROM series
+WHERE id = $1;
+
+-- name: ListSeries :many
+SELECT *
+FROM series
+WHERE (sqlc.narg(name)::text IS NULL OR name ILIKE '%' || sqlc.narg(name)::text || '%')
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (name, id) > (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY name, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListSeriesDesc :many
+SELECT *
+FROM series
+WHERE (sqlc.narg(name)::text IS NULL OR name ILIKE '%' || sqlc.narg(name)::text || '%')
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (name, id) < (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY name DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListSeriesByIDs :many
+SELECT *
+FROM series
+WHERE id = ANY (sqlc.arg(ids)::bigint[]);
+
+-- name: UpdateSeries :one
+UPDATE series
+SET name        = $2,
+    description = $3,
+    updated_at  = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteSeries :execrows
+DELETE
+FROM series
+WHERE id = $1;
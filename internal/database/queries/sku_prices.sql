@@ -0,0 +1,76 @@
+-- name: CreateSKUPrice :one
+INSERT INTO sku_prices (sku_id, price_in_kopeks, scheduled_for, effective_from, actor, request_id)
+VALUES (sqlc.arg(sku_id), sqlc.arg(price_in_kopeks), sqlc.narg(scheduled_for),
+        CASE WHEN sqlc.narg(scheduled_for)::timestamptz IS NULL THEN now() END,
+        sqlc.narg(actor), sqlc.narg(request_id))
+RETURNING *;
+
+-- name: CloseCurrentSKUPrice :exec
+UPDATE sku_prices
+SET effective_to = now()
+WHERE sku_id = $1
+  AND effective_from IS NOT NULL
+  AND effective_to IS NULL;
+
+-- name: ActivateSKUPrice :one
+UPDATE sku_prices
+SET effective_from = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: CancelSKUPrice :one
+UPDATE sku_prices
+SET cancelled_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetSKUPriceByUUID :one
+SELECT *
+FROM sku_prices
+WHERE uuid = $1
+  AND sku_id = $2;
+
+-- name: GetSKUPriceByIDForUpdate :one
+SELECT *
+FROM sku_prices
+WHERE id = $1
+    FOR UPDATE;
+
+-- name: ListDueSKUPrices :many
+SELECT p.id, s.uuid AS sku_uuid
+FROM sku_prices p
+         JOIN skus s ON p.sku_id = s.id
+WHERE p.effective_from IS NULL
+  AND p.cancelled_at IS NULL
+  AND p.scheduled_for <= now()
+  AND s.deleted_at IS NULL
+ORDER BY p.scheduled_for, p.id
+LIMIT $1;
+
+-- name: ListSKUPrices :many
+SELECT *
+FROM sku_prices
+WHERE sku_id = sqlc.arg(sku_id)
+  AND (sqlc.narg(status)::text IS NULL
+    OR (sqlc.narg(status)::text = 'scheduled' AND effective_from IS NULL AND cancelled_at IS NULL)
+    OR (sqlc.narg(status)::text = 'active' AND effective_from IS NOT NULL AND effective_to IS NULL)
+    OR (sqlc.narg(status)::text = 'superseded' AND effective_to IS NOT NULL)
+    OR (sqlc.narg(status)::text = 'cancelled' AND cancelled_at IS NOT NULL))
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (created_at, id) > (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListSKUPricesDesc :many
+SELECT *
+FROM sku_prices
+WHERE sku_id = sqlc.arg(sku_id)
+  AND (sqlc.narg(status)::text IS NULL
+    OR (sqlc.narg(status)::text = 'scheduled' AND effective_from IS NULL AND cancelled_at IS NULL)
+    OR (sqlc.narg(status)::text = 'active' AND effective_from IS NOT NULL AND effective_to IS NULL)
+    OR (sqlc.narg(status)::text = 'superseded' AND effective_to IS NOT NULL)
+    OR (sqlc.narg(status)::text = 'cancelled' AND cancelled_at IS NOT NULL))
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (created_at, id) < (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
@@ -0,0 +1,40 @@
+-- name: CreateReservation :one
+INSERT INTO sku_reservations (sku_id, quantity, note, actor, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetReservationByUUID :one
+SELECT sqlc.embed(r), s.uuid AS sku_uuid
+FROM sku_reservations r
+         JOIN skus s ON r.sku_id = s.id
+WHERE r.uuid = $1;
+
+-- name: GetReservationByIDForUpdate :one
+SELECT *
+FROM sku_reservations
+WHERE id = $1
+    FOR UPDATE;
+
+-- name: UpdateReservationStatus :one
+UPDATE sku_reservations
+SET status     = $2,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListExpiredReservations :many
+-- Holds on deleted SKUs expire too, or their copies would stay reserved after a restore.
+SELECT id, sku_id
+FROM sku_reservations
+WHERE status = 'active'
+  AND expires_at <= now()
+ORDER BY expires_at
+LIMIT $1;
+
+-- name: AdjustSKUReserved :one
+UPDATE skus
+SET reserved_count = reserved_count + sqlc.arg(change_by),
+    version        = version + 1,
+    updated_at     = now()
+WHERE id = $1
+RETURNING *;
@@ -1,6 +1,6 @@
 -- name: CreateSKU :one
-INSERT INTO skus (book_id, store_id, price_in_kopeks, stock_count)
-VALUES ($1, $2, $3, $4)
+INSERT INTO skus (book_id, store_id, price_in_kopeks, stock_count, currency)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: GetSKUByUUID :one
@@ -10,6 +10,20 @@ FROM skus s
 WHERE s.uuid = $1
   AND s.deleted_at IS NULL;
 
+-- name: GetSKUByUUIDForUpdate :one
+SELECT *
+FROM skus
+WHERE uuid = $1
+  AND deleted_at IS NULL
+    FOR UPDATE;
+
+-- name: GetSKUByIDForUpdate :one
+-- Finds deleted SKUs too, so that work started before the deletion can be finished.
+SELECT *
+FROM skus
+WHERE id = $1
+    FOR UPDATE;
+
 -- name: GetSKUByBookAndStore :one
 SELECT *
 FROM skus
@@ -17,12 +31,102 @@ WHERE book_id = $1
   AND store_id = $2
   AND deleted_at IS NULL;
 
--- name: ListSKUsInStore :many
+-- name: ListSKUsInStoreByTitle :many
+-- Titles live in books, so sorting by title sorts all SKUs of the store.
 SELECT sqlc.embed(s), sqlc.embed(b)
 FROM skus s
          JOIN books b ON s.book_id = b.id
-WHERE s.store_id = $1
-  AND s.deleted_at IS NULL;
+WHERE s.store_id = sqlc.arg(store_id)
+  AND s.deleted_at IS NULL
+  AND b.deleted_at IS NULL
+  AND (NOT sqlc.arg(in_stock_only)::bool OR s.stock_count > s.reserved_count)
+  AND (sqlc.narg(min_price)::int IS NULL OR s.price_in_kopeks >= sqlc.narg(min_price)::int)
+  AND (sqlc.narg(max_price)::int IS NULL OR s.price_in_kopeks <= sqlc.narg(max_price)::int)
+  AND (sqlc.narg(title)::text IS NULL OR b.title ILIKE '%' || sqlc.narg(title)::text || '%')
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (b.title, s.id) > (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY b.title, s.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListSKUsInStoreByTitleDesc :many
+SELECT sqlc.embed(s), sqlc.embed(b)
+FROM skus s
+         JOIN books b ON s.book_id = b.id
+WHERE s.store_id = sqlc.arg(store_id)
+  AND s.deleted_at IS NULL
+  AND b.deleted_at IS NULL
+  AND (NOT sqlc.arg(in_stock_only)::bool OR s.stock_count > s.reserved_count)
+  AND (sqlc.narg(min_price)::int IS NULL OR s.price_in_kopeks >= sqlc.narg(min_price)::int)
+  AND (sqlc.narg(max_price)::int IS NULL OR s.price_in_kopeks <= sqlc.narg(max_price)::int)
+  AND (sqlc.narg(title)::text IS NULL OR b.title ILIKE '%' || sqlc.narg(title)::text || '%')
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (b.title, s.id) < (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY b.title DESC, s.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListSKUsInStoreByPrice :many
+SELECT sqlc.embed(s), sqlc.embed(b)
+FROM skus s
+         JOIN books b ON s.book_id = b.id
+WHERE s.store_id = sqlc.arg(store_id)
+  AND s.deleted_at IS NULL
+  AND b.deleted_at IS NULL
+  AND (NOT sqlc.arg(in_stock_only)::bool OR s.stock_count > s.reserved_count)
+  AND (sqlc.narg(min_price)::int IS NULL OR s.price_in_kopeks >= sqlc.narg(min_price)::int)
+  AND (sqlc.narg(max_price)::int IS NULL OR s.price_in_kopeks <= sqlc.narg(max_price)::int)
+  AND (sqlc.narg(title)::text IS NULL OR b.title ILIKE '%' || sqlc.narg(title)::text || '%')
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (s.price_in_kopeks, s.id) > (sqlc.narg(cursor_int)::int, sqlc.narg(cursor_id)::bigint))
+ORDER BY s.price_in_kopeks, s.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListSKUsInStoreByPriceDesc :many
+SELECT sqlc.embed(s), sqlc.embed(b)
+FROM skus s
+         JOIN books b ON s.book_id = b.id
+WHERE s.store_id = sqlc.arg(store_id)
+  AND s.deleted_at IS NULL
+  AND b.deleted_at IS NULL
+  AND (NOT sqlc.arg(in_stock_only)::bool OR s.stock_count > s.reserved_count)
+  AND (sqlc.narg(min_price)::int IS NULL OR s.price_in_kopeks >= sqlc.narg(min_price)::int)
+  AND (sqlc.narg(max_price)::int IS NULL OR s.price_in_kopeks <= sqlc.narg(max_price)::int)
+  AND (sqlc.narg(title)::text IS NULL OR b.title ILIKE '%' || sqlc.narg(title)::text || '%')
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (s.price_in_kopeks, s.id) < (sqlc.narg(cursor_int)::int, sqlc.narg(cursor_id)::bigint))
+ORDER BY s.price_in_kopeks DESC, s.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListSKUsInStoreByStock :many
+SELECT sqlc.embed(s), sqlc.embed(b)
+FROM skus s
+         JOIN books b ON s.book_id = b.id
+WHERE s.store_id = sqlc.arg(store_id)
+  AND s.deleted_at IS NULL
+  AND b.deleted_at IS NULL
+  AND (NOT sqlc.arg(in_stock_only)::bool OR s.stock_count > s.reserved_count)
+  AND (sqlc.narg(min_price)::int IS NULL OR s.price_in_kopeks >= sqlc.narg(min_price)::int)
+  AND (sqlc.narg(max_price)::int IS NULL OR s.price_in_kopeks <= sqlc.narg(max_price)::int)
+  AND (sqlc.narg(title)::text IS NULL OR b.title ILIKE '%' || sqlc.narg(title)::text || '%')
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (s.stock_count, s.id) > (sqlc.narg(cursor_int)::int, sqlc.narg(cursor_id)::bigint))
+ORDER BY s.stock_count, s.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListSKUsInStoreByStockDesc :many
+SELECT sqlc.embed(s), sqlc.embed(b)
+FROM skus s
+         JOIN books b ON s.book_id = b.id
+WHERE s.store_id = sqlc.arg(store_id)
+  AND s.deleted_at IS NULL
+  AND b.deleted_at IS NULL
+  AND (NOT sqlc.arg(in_stock_only)::bool OR s.stock_count > s.reserved_count)
+  AND (sqlc.narg(min_price)::int IS NULL OR s.price_in_kopeks >= sqlc.narg(min_price)::int)
+  AND (sqlc.narg(max_price)::int IS NULL OR s.price_in_kopeks <= sqlc.narg(max_price)::int)
+  AND (sqlc.narg(title)::text IS NULL OR b.title ILIKE '%' || sqlc.narg(title)::text || '%')
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (s.stock_count, s.id) < (sqlc.narg(cursor_int)::int, sqlc.narg(cursor_id)::bigint))
+ORDER BY s.stock_count DESC, s.id DESC
+LIMIT sqlc.arg(page_limit);
 
 -- name: ListBookAvailability :many
 SELECT sqlc.embed(s), sqlc.embed(st)
@@ -35,6 +139,7 @@ WHERE s.book_id = $1
 -- name: UpdateSKUPrice :one
 UPDATE skus
 SET price_in_kopeks = $2,
+    version         = version + 1,
     updated_at      = now()
 WHERE uuid = $1
 RETURNING *;
@@ -42,6 +147,33 @@ RETURNING *;
 -- name: AdjustSKUStock :one
 UPDATE skus
 SET stock_count = stock_count + sqlc.arg(change_by),
+    version     = version + 1,
     updated_at  = now()
 WHERE uuid = $1
+  AND stock_count + sqlc.arg(change_by) >= reserved_count
+RETURNING *;
+
+-- name: LockBookSKUs :many
+-- Locks the live SKUs of a book in UUID order, the order every stock change takes them in.
+SELECT *
+FROM skus
+WHERE book_id = $1
+  AND deleted_at IS NULL
+ORDER BY uuid
+    FOR UPDATE;
+
+-- name: SoftDeleteSKUsByBook :many
+UPDATE skus
+SET deleted_at = sqlc.arg(deleted_at),
+    updated_at = now()
+WHERE book_id = sqlc.arg(book_id)
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreSKUsByBook :many
+UPDATE skus
+SET deleted_at = NULL,
+    updated_at = now()
+WHERE book_id = sqlc.arg(book_id)
+  AND deleted_at = sqlc.arg(deleted_at)
 RETURNING *;
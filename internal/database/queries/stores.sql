@@ -1,13 +1,43 @@
 -- name: CreateStore :one
-INSERT INTO stores (name, address)
-VALUES ($1, $2)
+INSERT INTO stores (name, address, currency)
+VALUES ($1, $2, $3)
 RETURNING *;
 
--- name: ListStores :many
+-- name: ListStoresByName :many
 SELECT *
 FROM stores
 WHERE deleted_at IS NULL
-ORDER BY name;
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (name, id) > (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY name, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListStoresByNameDesc :many
+SELECT *
+FROM stores
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (name, id) < (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY name DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListStoresByCreatedAt :many
+SELECT *
+FROM stores
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (created_at, id) > (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListStoresByCreatedAtDesc :many
+SELECT *
+FROM stores
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (created_at, id) < (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
 
 -- name: GetStoreByUUID :one
 SELECT *
@@ -15,16 +45,26 @@ FROM stores
 WHERE uuid = $1
   AND deleted_at IS NULL;
 
+-- name: GetStoreByUUIDForUpdate :one
+SELECT *
+FROM stores
+WHERE uuid = $1
+  AND deleted_at IS NULL
+    FOR UPDATE;
+
 -- name: UpdateStore :one
 UPDATE stores
-SET name       = $1,
-    address    = $2,
+SET name       = sqlc.arg(name),
+    address    = sqlc.arg(address),
+    currency   = COALESCE(sqlc.narg(currency)::text, currency),
     updated_at = now()
-WHERE uuid = $3
+WHERE uuid = sqlc.arg(uuid)
   AND deleted_at IS NULL
 RETURNING *;
 
--- name: SoftDeleteStore :exec
+-- name: SoftDeleteStore :one
 UPDATE stores
 SET deleted_at = now()
-WHERE uuid = $1;
+WHERE uuid = $1
+  AND deleted_at IS NULL
+RETURNING *;
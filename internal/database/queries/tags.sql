@@ -0,0 +1,78 @@
+-- name: CreateTag :one
+INSERT INTO tags (name)
+VALUES ($1)
+RETURNING *;
+
+-- name: UpsertTags :many
+-- Returns the tags with the given names, creating the missing ones.
+INSERT INTO tags (name)
+SELECT DISTINCT unnest(sqlc.arg(names)::text[])
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: GetTagByID :one
+SELECT *
+FROM tags
+WHERE id = $1;
+
+-- name: ListTags :many
+SELECT t.*,
+       (SELECT count(*)
+        FROM book_tags bt
+                 JOIN books b ON bt.book_id = b.id
+        WHERE bt.tag_id = t.id
+          AND b.deleted_at IS NULL) AS book_count
+FROM tags t
+WHERE (sqlc.narg(prefix)::text IS NULL OR t.name LIKE sqlc.narg(prefix)::text || '%')
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (t.name, t.id) > (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY t.name, t.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListTagsDesc :many
+SELECT t.*,
+       (SELECT count(*)
+        FROM book_tags bt
+                 JOIN books b ON bt.book_id = b.id
+        WHERE bt.tag_id = t.id
+          AND b.deleted_at IS NULL) AS book_count
+FROM tags t
+WHERE (sqlc.narg(prefix)::text IS NULL OR t.name LIKE sqlc.narg(prefix)::text || '%')
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (t.name, t.id) < (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY t.name DESC, t.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: UpdateTag :one
+UPDATE tags
+SET name = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteTag :execrows
+DELETE
+FROM tags
+WHERE id = $1;
+
+-- name: ListBookTags :many
+SELECT bt.book_id, t.name
+FROM book_tags bt
+         JOIN tags t ON bt.tag_id = t.id
+WHERE bt.book_id = ANY (sqlc.arg(book_ids)::bigint[])
+ORDER BY bt.book_id, t.name;
+
+-- name: ListBookTagIDs :many
+SELECT tag_id
+FROM book_tags
+WHERE book_id = $1
+ORDER BY tag_id;
+
+-- name: DeleteBookTags :exec
+DELETE
+FROM book_tags
+WHERE book_id = $1;
+
+-- name: AddBookTags :exec
+INSERT INTO book_tags (book_id, tag_id)
+SELECT sqlc.arg(book_id), unnest(sqlc.arg(tag_ids)::bigint[])
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,78 @@
+-- name: CreateTransfer :one
+INSERT INTO transfers (book_id, source_sku_id, destination_sku_id, quantity, note, actor)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetTransferByUUID :one
+SELECT sqlc.embed(t),
+       src.uuid  AS source_sku_uuid,
+       dst.uuid  AS destination_sku_uuid,
+       srcs.uuid AS source_store_uuid,
+       dsts.uuid AS destination_store_uuid
+FROM transfers t
+         JOIN skus src ON t.source_sku_id = src.id
+         JOIN skus dst ON t.destination_sku_id = dst.id
+         JOIN stores srcs ON src.store_id = srcs.id
+         JOIN stores dsts ON dst.store_id = dsts.id
+WHERE t.uuid = $1;
+
+-- name: GetTransferByIDForUpdate :one
+SELECT *
+FROM transfers
+WHERE id = $1
+    FOR UPDATE;
+
+-- name: ListTransfers :many
+SELECT sqlc.embed(t),
+       src.uuid  AS source_sku_uuid,
+       dst.uuid  AS destination_sku_uuid,
+       srcs.uuid AS source_store_uuid,
+       dsts.uuid AS destination_store_uuid
+FROM transfers t
+         JOIN skus src ON t.source_sku_id = src.id
+         JOIN skus dst ON t.destination_sku_id = dst.id
+         JOIN stores srcs ON src.store_id = srcs.id
+         JOIN stores dsts ON dst.store_id = dsts.id
+WHERE (sqlc.narg(status)::transfer_status IS NULL OR t.status = sqlc.narg(status)::transfer_status)
+  AND (sqlc.narg(store_id)::bigint IS NULL
+    OR src.store_id = sqlc.narg(store_id)::bigint
+    OR dst.store_id = sqlc.narg(store_id)::bigint)
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (t.created_at, t.id) > (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY t.created_at, t.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListTransfersDesc :many
+SELECT sqlc.embed(t),
+       src.uuid  AS source_sku_uuid,
+       dst.uuid  AS destination_sku_uuid,
+       srcs.uuid AS source_store_uuid,
+       dsts.uuid AS destination_store_uuid
+FROM transfers t
+         JOIN skus src ON t.source_sku_id = src.id
+         JOIN skus dst ON t.destination_sku_id = dst.id
+         JOIN stores srcs ON src.store_id = srcs.id
+         JOIN stores dsts ON dst.store_id = dsts.id
+WHERE (sqlc.narg(status)::transfer_status IS NULL OR t.status = sqlc.narg(status)::transfer_status)
+  AND (sqlc.narg(store_id)::bigint IS NULL
+    OR src.store_id = sqlc.narg(store_id)::bigint
+    OR dst.store_id = sqlc.narg(store_id)::bigint)
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR (t.created_at, t.id) < (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: UpdateTransferStatus :one
+UPDATE transfers
+SET status       = sqlc.arg(status)::transfer_status,
+    updated_at   = now(),
+    received_at  = CASE WHEN sqlc.arg(status)::transfer_status = 'received' THEN now() ELSE received_at END,
+    cancelled_at = CASE WHEN sqlc.arg(status)::transfer_status = 'cancelled' THEN now() ELSE cancelled_at END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CountDispatchedTransfersByBook :one
+SELECT count(*)
+FROM transfers
+WHERE book_id = $1
+  AND status = 'dispatched';
@@ -0,0 +1,145 @@
+package exports
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/response"
+)
+
+// busyRetryAfter is the Retry-After, in seconds, of an export refused while others run.
+const busyRetryAfter = "60"
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ExportInventory
+//
+//	@Summary		Выгрузить остатки и цены
+//	@Description	Потоково выгружает SKU магазина (или всех магазинов) с данными книг: цена, остаток, резерв и доступное количество.
+//	@Description	Все страницы читаются в одной транзакции, так что выгрузка - согласованный снимок.
+//	@Tags			exports
+//	@Produce		text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			store_uuid	query		string					false	"UUID магазина, без него - все магазины"
+//	@Param			format		query		string					false	"Формат файла"	Enums(csv, ndjson, xlsx)	default(csv)
+//	@Success		200			{file}		file					"Файл выгрузки"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Магазин не найден"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Failure		503			{object}	response.ErrorResponse	"Уже идёт максимум выгрузок"
+//	@Router			/exports/inventory [get]
+func (h *Handler) ExportInventory(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format, ok := parseExportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	name := "inventory"
+	var storeUUID *uuid.UUID
+	if s := query.Get("store_uuid"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			response.WriteError(w, r, http.StatusBadRequest, "Invalid store_uuid")
+			return
+		}
+		storeUUID = &id
+		name += "-" + id.String()
+	}
+
+	out := startDownload(w, format, name)
+	err := h.service.Inventory(r.Context(), storeUUID, format, out)
+	out.finish(r, err)
+}
+
+// ExportBooks
+//
+//	@Summary		Выгрузить каталог
+//	@Description	Потоково выгружает все неудалённые книги с издательством и серией.
+//	@Tags			exports
+//	@Produce		text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			format	query		string					false	"Формат файла"	Enums(csv, ndjson, xlsx)	default(csv)
+//	@Success		200		{file}		file					"Файл выгрузки"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Failure		503		{object}	response.ErrorResponse	"Уже идёт максимум выгрузок"
+//	@Router			/exports/books [get]
+func (h *Handler) ExportBooks(w http.ResponseWriter, r *http.Request) {
+	format, ok := parseExportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	out := startDownload(w, format, "books")
+	err := h.service.Books(r.Context(), format, out)
+	out.finish(r, err)
+}
+
+func parseExportFormat(w http.ResponseWriter, r *http.Request) (Format, bool) {
+	s := r.URL.Query().Get("format")
+	if s == "" {
+		return FormatCSV, true
+	}
+	format, ok := ParseFormat(s)
+	if !ok {
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid format, use csv, ndjson or xlsx")
+		return "", false
+	}
+	return format, true
+}
+
+// download is the response of an export. Until the first byte is written the export can
+// still fail with a JSON error, afterwards the only way to report a failure is to cut the
+// response short.
+type download struct {
+	http.ResponseWriter
+	written bool
+}
+
+func startDownload(w http.ResponseWriter, format Format, name string) *download {
+	// A large export outlives the server's write timeout.
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().UTC().Format("20060102-150405"), format)
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	return &download{ResponseWriter: w}
+}
+
+func (d *download) Write(p []byte) (int, error) {
+	d.written = true
+	return d.ResponseWriter.Write(p)
+}
+
+func (d *download) finish(r *http.Request, err error) {
+	if err == nil {
+		return
+	}
+	log := middleware.LoggerFromContext(r.Context())
+	if d.written {
+		log.Error("Export was interrupted", "error", err)
+		panic(http.ErrAbortHandler)
+	}
+
+	d.Header().Del("Content-Disposition")
+	if errors.Is(err, ErrStoreNotFound) {
+		response.WriteError(d, r, http.StatusNotFound, "Store not found")
+		return
+	}
+	if errors.Is(err, ErrBusy) {
+		d.Header().Set("Retry-After", busyRetryAfter)
+		response.WriteError(d, r, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	log.Error("Failed to export", "error", err)
+	response.WriteError(d, r, http.StatusInternalServerError, "Internal server error")
+}
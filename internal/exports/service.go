@@ -0,0 +1,183 @@
+package exports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nikallow/bookstores-api/internal/adapters/postgres"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/stores"
+)
+
+var (
+	ErrStoreNotFound = stores.ErrStoreNotFound
+	ErrBusy          = errors.New("too many exports are running, try again later")
+)
+
+// pageSize is the number of rows read from the database at a time.
+const pageSize = 500
+
+var (
+	inventoryColumns = []string{
+		"store_uuid", "store_name", "sku_uuid", "book_id", "isbn", "title", "author", "publication_year",
+		"currency", "price_in_kopeks", "stock_count", "reserved_count", "available", "updated_at",
+	}
+	bookColumns = []string{
+		"id", "isbn", "title", "author", "publisher", "series", "series_volume", "page_count",
+		"publication_year", "description", "created_at", "updated_at",
+	}
+)
+
+type Service interface {
+	Inventory(ctx context.Context, storeUUID *uuid.UUID, format Format, out io.Writer) error
+	Books(ctx context.Context, format Format, out io.Writer) error
+}
+
+type service struct {
+	db    postgres.TxBeginner
+	slots chan struct{}
+}
+
+// NewService runs at most maxConcurrent exports at a time, each holding a connection of db
+// until it is written out.
+func NewService(db postgres.TxBeginner, maxConcurrent int) Service {
+	return &service{db: db, slots: make(chan struct{}, maxConcurrent)}
+}
+
+// Inventory writes the SKUs of a store, or of all stores, with their books.
+//
+// All pages are read in one read-only transaction, so the export is a consistent snapshot.
+func (s *service) Inventory(ctx context.Context, storeUUID *uuid.UUID, format Format, out io.Writer) error {
+	return s.snapshot(ctx, func(q *repo.Queries) error {
+		params := repo.ExportInventoryParams{PageLimit: pageSize}
+		if storeUUID != nil {
+			store, err := q.GetStoreByUUID(ctx, pgtype.UUID{Bytes: *storeUUID, Valid: true})
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return ErrStoreNotFound
+				}
+				return err
+			}
+			params.StoreID = pgtype.Int8{Int64: store.ID, Valid: true}
+		}
+
+		w, err := NewWriter(format, out, "inventory", inventoryColumns)
+		if err != nil {
+			return err
+		}
+		defer w.Discard()
+		for {
+			rows, err := q.ExportInventory(ctx, params)
+			if err != nil {
+				return err
+			}
+			for _, row := range rows {
+				sku, book := row.Sku, row.Book
+				err := w.WriteRow([]any{
+					uuid.UUID(row.StoreUuid.Bytes).String(), row.StoreName, uuid.UUID(sku.Uuid.Bytes).String(),
+					book.ID, textValue(book.Isbn), book.Title, book.Author, int4Value(book.PublicationYear),
+					sku.Currency, sku.PriceInKopeks, sku.StockCount, sku.ReservedCount, sku.StockCount - sku.ReservedCount,
+					timeValue(sku.UpdatedAt),
+				})
+				if err != nil {
+					return err
+				}
+			}
+			if len(rows) < pageSize {
+				return w.Close()
+			}
+			params.AfterID = rows[len(rows)-1].Sku.ID
+		}
+	})
+}
+
+// Books writes the catalog without deleted books.
+func (s *service) Books(ctx context.Context, format Format, out io.Writer) error {
+	return s.snapshot(ctx, func(q *repo.Queries) error {
+		w, err := NewWriter(format, out, "books", bookColumns)
+		if err != nil {
+			return err
+		}
+		defer w.Discard()
+		params := repo.ExportBooksParams{PageLimit: pageSize}
+		for {
+			rows, err := q.ExportBooks(ctx, params)
+			if err != nil {
+				return err
+			}
+			for _, row := range rows {
+				book := row.Book
+				err := w.WriteRow([]any{
+					book.ID, textValue(book.Isbn), book.Title, book.Author, textValue(row.PublisherName),
+					textValue(row.SeriesName), int4Value(book.SeriesVolume), int4Value(book.PageCount),
+					int4Value(book.PublicationYear), textValue(book.Description),
+					timeValue(book.CreatedAt), timeValue(book.UpdatedAt),
+				})
+				if err != nil {
+					return err
+				}
+			}
+			if len(rows) < pageSize {
+				return w.Close()
+			}
+			params.AfterID = rows[len(rows)-1].Book.ID
+		}
+	})
+}
+
+func (s *service) snapshot(ctx context.Context, fn func(q *repo.Queries) error) error {
+	log := middleware.LoggerFromContext(ctx)
+	start := time.Now()
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	default:
+		return ErrBusy
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+	if err := qtx.BeginSnapshot(ctx); err != nil {
+		return err
+	}
+	if err := fn(qtx); err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	log.Info("Export completed", "duration", time.Since(start))
+	return nil
+}
+
+func textValue(t pgtype.Text) any {
+	if !t.Valid {
+		return nil
+	}
+	return t.String
+}
+
+func int4Value(i pgtype.Int4) any {
+	if !i.Valid {
+		return nil
+	}
+	return i.Int32
+}
+
+func timeValue(t pgtype.Timestamptz) any {
+	if !t.Valid {
+		return nil
+	}
+	return t.Time.UTC().Format(time.RFC3339)
+}
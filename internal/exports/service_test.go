@@ -0,0 +1,51 @@
+package exports
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// blockingDB holds every transaction it is asked for until release is closed, then fails it.
+type blockingDB struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (db *blockingDB) Begin(context.Context) (pgx.Tx, error) {
+	db.started <- struct{}{}
+	<-db.release
+	return nil, errors.New("no database")
+}
+
+func TestConcurrentExports(t *testing.T) {
+	db := &blockingDB{started: make(chan struct{}), release: make(chan struct{})}
+	svc := NewService(db, 2)
+	ctx := context.Background()
+
+	done := make(chan error)
+	for range 2 {
+		go func() { done <- svc.Books(ctx, FormatCSV, io.Discard) }()
+		<-db.started
+	}
+
+	if err := svc.Books(ctx, FormatCSV, io.Discard); !errors.Is(err, ErrBusy) {
+		t.Errorf("third export error = %v, want %v", err, ErrBusy)
+	}
+
+	close(db.release)
+	for range 2 {
+		if err := <-done; errors.Is(err, ErrBusy) {
+			t.Errorf("running export error = %v", err)
+		}
+	}
+
+	// Finished exports give their slots back.
+	go func() { <-db.started }()
+	if err := svc.Books(ctx, FormatCSV, io.Discard); errors.Is(err, ErrBusy) {
+		t.Errorf("export after the others finished error = %v", err)
+	}
+}
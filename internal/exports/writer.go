@@ -0,0 +1,179 @@
+package exports
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+	FormatXLSX   Format = "xlsx"
+)
+
+// ParseFormat accepts a format name or a file extension.
+func ParseFormat(s string) (Format, bool) {
+	switch strings.ToLower(strings.TrimPrefix(s, ".")) {
+	case "csv":
+		return FormatCSV, true
+	case "ndjson", "jsonl":
+		return FormatNDJSON, true
+	case "xlsx":
+		return FormatXLSX, true
+	}
+	return "", false
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Writer writes the rows of one table. Values are nil, strings, integers or booleans,
+// in the order of the columns. Close must be called to flush the output; Discard releases
+// what the writer holds without writing anything more and is a no-op after Close.
+type Writer interface {
+	WriteRow(values []any) error
+	Close() error
+	Discard()
+}
+
+// NewWriter starts a table with the given columns. The sheet name is used by XLSX only.
+func NewWriter(format Format, out io.Writer, sheet string, columns []string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		w := &csvWriter{w: csv.NewWriter(out)}
+		return w, w.w.Write(columns)
+	case FormatNDJSON:
+		return &ndjsonWriter{w: bufio.NewWriter(out), columns: columns}, nil
+	case FormatXLSX:
+		return newXLSXWriter(out, sheet, columns)
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		if v != nil {
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Discard() {}
+
+// ndjsonWriter writes every row as a JSON object keyed by the column names, in column order.
+type ndjsonWriter struct {
+	w       *bufio.Writer
+	columns []string
+}
+
+func (n *ndjsonWriter) WriteRow(values []any) error {
+	n.w.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			n.w.WriteByte(',')
+		}
+		key, _ := json.Marshal(n.columns[i])
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		n.w.Write(key)
+		n.w.WriteByte(':')
+		n.w.Write(value)
+	}
+	n.w.WriteByte('}')
+	return n.w.WriteByte('\n')
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.w.Flush()
+}
+
+func (n *ndjsonWriter) Discard() {}
+
+// xlsxWriter keeps at most a few rows in memory, the rest goes to a temporary file
+// until Close writes the workbook out.
+type xlsxWriter struct {
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	out    io.Writer
+	row    int
+	closed bool
+}
+
+func newXLSXWriter(out io.Writer, sheet string, columns []string) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	if err := file.SetSheetName("Sheet1", sheet); err != nil {
+		file.Close()
+		return nil, err
+	}
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	x := &xlsxWriter{file: file, stream: stream, out: out}
+	header := make([]any, len(columns))
+	for i, c := range columns {
+		header[i] = c
+	}
+	if err := x.WriteRow(header); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return x, nil
+}
+
+func (x *xlsxWriter) WriteRow(values []any) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.stream.SetRow(cell, values)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.Discard()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := x.file.WriteTo(x.out)
+	return err
+}
+
+// Discard removes the temporary files of the stream.
+func (x *xlsxWriter) Discard() {
+	if x.closed {
+		return
+	}
+	x.closed = true
+	x.file.Close()
+}
@@ -0,0 +1,241 @@
+package genres
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/response"
+)
+
+type Handler struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// CreateGenre
+//
+//	@Summary		Создать жанр
+//	@Description	Добавляет жанр в дерево жанров. С parent_id жанр становится поджанром.
+//	@Tags			genres
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		CreateGenreRequest		true	"Данные жанра"
+//	@Success		201		{object}	GenreResponse			"Жанр создан"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		409		{object}	response.ErrorResponse	"У родителя уже есть жанр с таким названием"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/genres [post]
+func (h *Handler) CreateGenre(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	var req CreateGenreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read create genre request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed for create genre request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	genre, err := h.service.Create(r.Context(), req)
+	if err != nil {
+		writeGenreError(w, r, err, 0)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusCreated, toGenreResponse(genre))
+}
+
+// ListGenres
+//
+//	@Summary		Дерево жанров
+//	@Description	Возвращает все жанры деревом: поджанры вложены в children, на каждом уровне сортировка по названию.
+//	@Tags			genres
+//	@Produce		json
+//	@Success		200	{array}		GenreResponse			"Жанры верхнего уровня с поджанрами"
+//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/genres [get]
+func (h *Handler) ListGenres(w http.ResponseWriter, r *http.Request) {
+	genres, err := h.service.List(r.Context())
+	if err != nil {
+		writeGenreError(w, r, err, 0)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toGenreTree(genres))
+}
+
+// GetGenre
+//
+//	@Summary		Получить жанр
+//	@Description	Возвращает жанр по его ID.
+//	@Tags			genres
+//	@Produce		json
+//	@Param			genreID	path		int						true	"ID жанра"
+//	@Success		200		{object}	GenreResponse			"Инфо о жанре"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"Жанр отсутствует"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/genres/{genreID} [get]
+func (h *Handler) GetGenre(w http.ResponseWriter, r *http.Request) {
+	genreID, ok := parseGenreID(w, r)
+	if !ok {
+		return
+	}
+
+	genre, err := h.service.GetByID(r.Context(), genreID)
+	if err != nil {
+		writeGenreError(w, r, err, genreID)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toGenreResponse(genre))
+}
+
+// UpdateGenre
+//
+//	@Summary		Обновить жанр
+//	@Description	Переименовывает жанр или переносит его вместе с поджанрами. Без parent_id жанр становится жанром верхнего уровня.
+//	@Tags			genres
+//	@Accept			json
+//	@Produce		json
+//	@Param			genreID	path		int						true	"ID жанра"
+//	@Param			input	body		UpdateGenreRequest		true	"Новые данные жанра"
+//	@Success		200		{object}	GenreResponse			"Обновлённый жанр"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"Жанр отсутствует"
+//	@Failure		409		{object}	response.ErrorResponse	"Конфликт названия или перенос в собственный поджанр"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/genres/{genreID} [put]
+func (h *Handler) UpdateGenre(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	genreID, ok := parseGenreID(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateGenreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read update genre request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed for update genre request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	genre, err := h.service.Update(r.Context(), genreID, req)
+	if err != nil {
+		writeGenreError(w, r, err, genreID)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toGenreResponse(genre))
+}
+
+// DeleteGenre
+//
+//	@Summary		Удалить жанр
+//	@Description	Удаляет жанр без поджанров, к которому не отнесена ни одна книга.
+//	@Tags			genres
+//	@Param			genreID	path	int	true	"ID жанра"
+//	@Success		204		"Жанр удалён"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"Жанр отсутствует"
+//	@Failure		409		{object}	response.ErrorResponse	"У жанра есть поджанры или книги"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/genres/{genreID} [delete]
+func (h *Handler) DeleteGenre(w http.ResponseWriter, r *http.Request) {
+	genreID, ok := parseGenreID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), genreID); err != nil {
+		writeGenreError(w, r, err, genreID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeGenreError(w http.ResponseWriter, r *http.Request, err error, genreID int64) {
+	switch {
+	case errors.Is(err, ErrGenreNotFound):
+		response.WriteError(w, r, http.StatusNotFound, "Genre not found")
+	case errors.Is(err, ErrParentNotFound):
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrGenreExists), errors.Is(err, ErrGenreCycle), errors.Is(err, ErrGenreInUse):
+		response.WriteError(w, r, http.StatusConflict, err.Error())
+	default:
+		middleware.LoggerFromContext(r.Context()).Error("Genre operation failed", "error", err, "genre_id", genreID)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func parseGenreID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	genreIDStr := chi.URLParam(r, "genreID")
+	genreID, err := strconv.ParseInt(genreIDStr, 10, 64)
+	if err != nil {
+		middleware.LoggerFromContext(r.Context()).Warn("Invalid genre ID format", "genre_id", genreIDStr)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid genre ID format")
+		return 0, false
+	}
+	return genreID, true
+}
+
+// toGenreTree nests the genres under their parents, keeping the order of the list.
+func toGenreTree(genres []repo.Genre) []GenreResponse {
+	var roots []repo.Genre
+	children := make(map[int64][]repo.Genre)
+	for _, g := range genres {
+		if g.ParentID.Valid {
+			children[g.ParentID.Int64] = append(children[g.ParentID.Int64], g)
+		} else {
+			roots = append(roots, g)
+		}
+	}
+
+	var build func(level []repo.Genre) []GenreResponse
+	build = func(level []repo.Genre) []GenreResponse {
+		resp := make([]GenreResponse, len(level))
+		for i, g := range level {
+			resp[i] = toGenreResponse(g)
+			if sub := children[g.ID]; len(sub) > 0 {
+				resp[i].Children = build(sub)
+			}
+		}
+		return resp
+	}
+	return build(roots)
+}
+
+func toGenreResponse(genre repo.Genre) GenreResponse {
+	resp := GenreResponse{
+		ID:   genre.ID,
+		Name: genre.Name,
+	}
+	if genre.ParentID.Valid {
+		resp.ParentID = &genre.ParentID.Int64
+	}
+	return resp
+}
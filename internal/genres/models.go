@@ -0,0 +1,20 @@
+package genres
+
+type CreateGenreRequest struct {
+	ParentID *int64 `json:"parent_id,omitempty"`
+	Name     string `json:"name"                validate:"required,max=100"`
+}
+
+// UpdateGenreRequest renames the genre or moves it with its subgenres; no parent_id makes it top-level.
+type UpdateGenreRequest struct {
+	ParentID *int64 `json:"parent_id"`
+	Name     string `json:"name"      validate:"required,max=100"`
+}
+
+// GenreResponse is a genre, with its subgenres when returned as part of the tree.
+type GenreResponse struct {
+	ID       int64           `json:"id"`
+	ParentID *int64          `json:"parent_id,omitempty"`
+	Name     string          `json:"name"`
+	Children []GenreResponse `json:"children,omitempty"`
+}
@@ -0,0 +1,165 @@
+package genres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/books"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+)
+
+var (
+	ErrGenreNotFound  = books.ErrGenreNotFound
+	ErrParentNotFound = errors.New("parent genre not found")
+	ErrGenreExists    = errors.New("genre with this name already exists under the parent")
+	ErrGenreCycle     = errors.New("genre cannot be moved under itself or its subgenre")
+	ErrGenreInUse     = errors.New("genre has subgenres or books")
+)
+
+const (
+	// pgUniqueViolation is the SQLSTATE of a failed UNIQUE constraint, e.g. a sibling with the same name.
+	pgUniqueViolation = "23505"
+	// pgForeignKeyViolation is the SQLSTATE of a failed REFERENCES constraint, e.g. genres.parent_id.
+	pgForeignKeyViolation = "23503"
+)
+
+type Service interface {
+	Create(ctx context.Context, params CreateGenreRequest) (repo.Genre, error)
+	List(ctx context.Context) ([]repo.Genre, error)
+	GetByID(ctx context.Context, id int64) (repo.Genre, error)
+	Update(ctx context.Context, id int64, params UpdateGenreRequest) (repo.Genre, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type service struct {
+	repo repo.Querier
+}
+
+func NewService(repo repo.Querier) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Create(ctx context.Context, params CreateGenreRequest) (repo.Genre, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	genre, err := s.repo.CreateGenre(ctx, repo.CreateGenreParams{
+		ParentID: int64ToPgInt8p(params.ParentID),
+		Name:     params.Name,
+	})
+	if err != nil {
+		if err := translateGenreError(err); err != nil {
+			return repo.Genre{}, err
+		}
+		log.Error("Failed to create genre", "error", err)
+		return repo.Genre{}, fmt.Errorf("failed to create genre: %w", err)
+	}
+
+	log.Info("Genre created successfully", "genre_id", genre.ID)
+	return genre, nil
+}
+
+// List returns the whole taxonomy ordered by name; it is small enough to send at once.
+func (s *service) List(ctx context.Context) ([]repo.Genre, error) {
+	genres, err := s.repo.ListGenres(ctx)
+	if err != nil {
+		middleware.LoggerFromContext(ctx).Error("Failed to list genres", "error", err)
+		return nil, fmt.Errorf("failed to list genres: %w", err)
+	}
+	return genres, nil
+}
+
+func (s *service) GetByID(ctx context.Context, id int64) (repo.Genre, error) {
+	genre, err := s.repo.GetGenreByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Genre{}, ErrGenreNotFound
+		}
+		return repo.Genre{}, fmt.Errorf("failed to get genre: %w", err)
+	}
+	return genre, nil
+}
+
+func (s *service) Update(ctx context.Context, id int64, params UpdateGenreRequest) (repo.Genre, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	if params.ParentID != nil {
+		subtree, err := s.repo.ListGenreSubtreeIDs(ctx, id)
+		if err != nil {
+			return repo.Genre{}, fmt.Errorf("failed to update genre: %w", err)
+		}
+		if len(subtree) == 0 {
+			return repo.Genre{}, ErrGenreNotFound
+		}
+		if slices.Contains(subtree, *params.ParentID) {
+			return repo.Genre{}, ErrGenreCycle
+		}
+	}
+
+	genre, err := s.repo.UpdateGenre(ctx, repo.UpdateGenreParams{
+		ID:       id,
+		ParentID: int64ToPgInt8p(params.ParentID),
+		Name:     params.Name,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Genre{}, ErrGenreNotFound
+		}
+		if err := translateGenreError(err); err != nil {
+			return repo.Genre{}, err
+		}
+		log.Error("Failed to update genre", "error", err, "genre_id", id)
+		return repo.Genre{}, fmt.Errorf("failed to update genre: %w", err)
+	}
+
+	log.Info("Genre updated successfully", "genre_id", id)
+	return genre, nil
+}
+
+// Delete removes a genre without subgenres that no book is filed under.
+func (s *service) Delete(ctx context.Context, id int64) error {
+	log := middleware.LoggerFromContext(ctx)
+
+	deleted, err := s.repo.DeleteGenre(ctx, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+			return ErrGenreInUse
+		}
+		log.Error("Failed to delete genre", "error", err, "genre_id", id)
+		return fmt.Errorf("failed to delete genre: %w", err)
+	}
+	if deleted == 0 {
+		return ErrGenreNotFound
+	}
+
+	log.Info("Genre deleted successfully", "genre_id", id)
+	return nil
+}
+
+// translateGenreError maps constraint violations on insert or update, nil for any other error.
+func translateGenreError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return nil
+	}
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return ErrGenreExists
+	case pgForeignKeyViolation:
+		return ErrParentNotFound
+	}
+	return nil
+}
+
+func int64ToPgInt8p(i *int64) pgtype.Int8 {
+	if i == nil {
+		return pgtype.Int8{Valid: false}
+	}
+	return pgtype.Int8{Int64: *i, Valid: true}
+}
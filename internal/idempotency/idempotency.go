@@ -0,0 +1,253 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/config"
+	appMiddleware "github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/response"
+)
+
+const (
+	Header         = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+	// Request bodies up to memoryBodySize are kept in memory, larger ones in a temporary file.
+	memoryBodySize = 1 << 20
+)
+
+// replayedHeaders are the response headers stored and sent again on replay.
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// Middleware makes POST and PUT requests carrying an Idempotency-Key header safe to retry:
+// the first response is stored and replayed for later requests of the same caller with the same key.
+// Bodies larger than maxBodySize, the largest body any route accepts, are rejected.
+type Middleware struct {
+	repo        repo.Querier
+	ttl         time.Duration
+	lockTimeout time.Duration
+	maxBodySize int64
+}
+
+func New(repo repo.Querier, cfg config.IdempotencyConfig, maxBodySize int64) *Middleware {
+	return &Middleware{
+		repo:        repo,
+		ttl:         cfg.TTL,
+		lockTimeout: cfg.LockTimeout,
+		maxBodySize: maxBodySize,
+	}
+}
+
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(Header)
+		if key == "" || (r.Method != http.MethodPost && r.Method != http.MethodPut) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		log := appMiddleware.LoggerFromContext(r.Context()).With("idempotency_key", key)
+
+		if len(key) > maxKeyLength {
+			response.WriteError(w, r, http.StatusBadRequest, "Idempotency-Key is too long")
+			return
+		}
+
+		body, fingerprint, err := m.spoolBody(w, r)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				response.WriteError(w, r, http.StatusRequestEntityTooLarge, "Request body is too large")
+				return
+			}
+			log.Warn("Failed to read request body", "error", err)
+			response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		defer body.Close()
+		r.Body = body
+
+		actor := appMiddleware.ActorFromContext(r.Context())
+		now := time.Now()
+
+		_, err = m.repo.ClaimIdempotencyKey(r.Context(), repo.ClaimIdempotencyKeyParams{
+			Actor:       actor,
+			Key:         key,
+			Method:      r.Method,
+			Path:        r.URL.Path,
+			Fingerprint: fingerprint,
+			ExpiresAt:   pgtype.Timestamptz{Time: now.Add(m.ttl), Valid: true},
+			StaleBefore: pgtype.Timestamptz{Time: now.Add(-m.lockTimeout), Valid: true},
+		})
+		switch {
+		case err == nil:
+			m.serveAndStore(w, r, next, actor, key)
+		case errors.Is(err, pgx.ErrNoRows):
+			m.replay(w, r, actor, key, fingerprint)
+		default:
+			log.Error("Failed to claim idempotency key", "error", err)
+			response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		}
+	})
+}
+
+// serveAndStore runs the request and saves its response. Server errors are not stored,
+// the key is released so that the client can retry.
+func (m *Middleware) serveAndStore(w http.ResponseWriter, r *http.Request, next http.Handler, actor, key string) {
+	log := appMiddleware.LoggerFromContext(r.Context()).With("idempotency_key", key)
+	// The outcome must be saved even if the client has gone away.
+	ctx := context.WithoutCancel(r.Context())
+
+	var buf bytes.Buffer
+	ww := middleware.NewWrapResponseWriter(w, r.ProtoMinor)
+	ww.Tee(&buf)
+
+	completed := false
+	defer func() {
+		if !completed {
+			if err := m.repo.ReleaseIdempotencyKey(ctx, repo.ReleaseIdempotencyKeyParams{Actor: actor, Key: key}); err != nil {
+				log.Error("Failed to release idempotency key", "error", err)
+			}
+		}
+	}()
+
+	next.ServeHTTP(ww, r)
+
+	status := ww.Status()
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if status >= http.StatusInternalServerError {
+		return
+	}
+
+	headers := make(map[string]string, len(replayedHeaders))
+	for _, h := range replayedHeaders {
+		if v := w.Header().Get(h); v != "" {
+			headers[h] = v
+		}
+	}
+	rawHeaders, err := json.Marshal(headers)
+	if err != nil {
+		log.Error("Failed to encode response headers", "error", err)
+		return
+	}
+
+	err = m.repo.CompleteIdempotencyKey(ctx, repo.CompleteIdempotencyKeyParams{
+		Actor:           actor,
+		Key:             key,
+		StatusCode:      pgtype.Int4{Int32: int32(status), Valid: true},
+		ResponseHeaders: rawHeaders,
+		ResponseBody:    buf.Bytes(),
+	})
+	if err != nil {
+		log.Error("Failed to store idempotent response", "error", err)
+		return
+	}
+	completed = true
+}
+
+// replay answers a request whose key has already been used.
+func (m *Middleware) replay(w http.ResponseWriter, r *http.Request, actor, key, fingerprint string) {
+	log := appMiddleware.LoggerFromContext(r.Context()).With("idempotency_key", key)
+
+	stored, err := m.repo.GetIdempotencyKey(r.Context(), repo.GetIdempotencyKeyParams{Actor: actor, Key: key})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// The original request failed and released the key in the meantime.
+			w.Header().Set("Retry-After", "1")
+			response.WriteError(w, r, http.StatusConflict, "request with this Idempotency-Key is being processed, retry later")
+			return
+		}
+		log.Error("Failed to get idempotency key", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	if stored.Fingerprint != fingerprint {
+		log.Warn("Idempotency key reused with a different request")
+		response.WriteError(w, r, http.StatusConflict, "Idempotency-Key was already used for a different request")
+		return
+	}
+	if !stored.StatusCode.Valid {
+		w.Header().Set("Retry-After", "1")
+		response.WriteError(w, r, http.StatusConflict, "request with this Idempotency-Key is being processed, retry later")
+		return
+	}
+
+	var headers map[string]string
+	if err := json.Unmarshal(stored.ResponseHeaders, &headers); err != nil {
+		log.Error("Failed to decode stored response headers", "error", err)
+	}
+	for k, v := range headers {
+		w.Header().Set(k, v)
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(int(stored.StatusCode.Int32))
+	if _, err := w.Write(stored.ResponseBody); err != nil {
+		log.Error("Failed to write replayed response", "error", err)
+	}
+}
+
+// spoolBody reads the request body through the fingerprint hash and keeps it for the handler:
+// in memory up to memoryBodySize, in a temporary file beyond that. Keys are already scoped
+// to the caller, so the fingerprint covers only the method, path and body.
+func (m *Middleware) spoolBody(w http.ResponseWriter, r *http.Request) (io.ReadCloser, string, error) {
+	h := sha256.New()
+	io.WriteString(h, r.Method)
+	h.Write([]byte{0})
+	io.WriteString(h, r.URL.Path)
+	h.Write([]byte{0})
+
+	src := io.TeeReader(http.MaxBytesReader(w, r.Body, m.maxBodySize), h)
+
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, src, memoryBodySize+1); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.NopCloser(&buf), hex.EncodeToString(h.Sum(nil)), nil
+		}
+		return nil, "", err
+	}
+
+	f, err := os.CreateTemp("", "idempotency-body-*")
+	if err != nil {
+		return nil, "", err
+	}
+	body := &tempBody{File: f}
+	if _, err := io.Copy(f, io.MultiReader(&buf, src)); err != nil {
+		body.Close()
+		return nil, "", err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		body.Close()
+		return nil, "", err
+	}
+	return body, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// tempBody is a spooled request body, its file is removed on Close.
+type tempBody struct {
+	*os.File
+}
+
+func (b *tempBody) Close() error {
+	err := b.File.Close()
+	if rmErr := os.Remove(b.File.Name()); err == nil {
+		err = rmErr
+	}
+	return err
+}
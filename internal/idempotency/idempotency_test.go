@@ -0,0 +1,329 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/config"
+	appMiddleware "github.com/nikallow/bookstores-api/internal/middleware"
+)
+
+// keyStore keeps idempotency keys in memory the way the idempotency_keys queries do.
+type keyStore struct {
+	repo.Querier
+	mu   sync.Mutex
+	keys map[storedKey]repo.IdempotencyKey
+}
+
+// storedKey is a key of one caller.
+type storedKey struct {
+	actor, key string
+}
+
+func newKeyStore() *keyStore {
+	return &keyStore{keys: make(map[storedKey]repo.IdempotencyKey)}
+}
+
+func (s *keyStore) ClaimIdempotencyKey(_ context.Context, arg repo.ClaimIdempotencyKeyParams) (repo.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	id := storedKey{arg.Actor, arg.Key}
+	if k, ok := s.keys[id]; ok {
+		expired := k.ExpiresAt.Time.Before(now)
+		stale := !k.StatusCode.Valid && k.CreatedAt.Time.Before(arg.StaleBefore.Time)
+		if !expired && !stale {
+			return repo.IdempotencyKey{}, pgx.ErrNoRows
+		}
+	}
+	k := repo.IdempotencyKey{
+		Actor:       arg.Actor,
+		Key:         arg.Key,
+		Method:      arg.Method,
+		Path:        arg.Path,
+		Fingerprint: arg.Fingerprint,
+		CreatedAt:   pgtype.Timestamptz{Time: now, Valid: true},
+		ExpiresAt:   arg.ExpiresAt,
+	}
+	s.keys[id] = k
+	return k, nil
+}
+
+func (s *keyStore) GetIdempotencyKey(_ context.Context, arg repo.GetIdempotencyKeyParams) (repo.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k, ok := s.keys[storedKey{arg.Actor, arg.Key}]
+	if !ok {
+		return repo.IdempotencyKey{}, pgx.ErrNoRows
+	}
+	return k, nil
+}
+
+func (s *keyStore) CompleteIdempotencyKey(_ context.Context, arg repo.CompleteIdempotencyKeyParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := storedKey{arg.Actor, arg.Key}
+	k := s.keys[id]
+	k.StatusCode = arg.StatusCode
+	k.ResponseHeaders = arg.ResponseHeaders
+	k.ResponseBody = arg.ResponseBody
+	s.keys[id] = k
+	return nil
+}
+
+func (s *keyStore) ReleaseIdempotencyKey(_ context.Context, arg repo.ReleaseIdempotencyKeyParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := storedKey{arg.Actor, arg.Key}
+	if !s.keys[id].StatusCode.Valid {
+		delete(s.keys, id)
+	}
+	return nil
+}
+
+// maxBodySize lets bodies through that are too large to be kept in memory.
+const maxBodySize = 2 * memoryBodySize
+
+func newMiddleware() *Middleware {
+	return New(newKeyStore(), config.IdempotencyConfig{TTL: time.Hour, LockTimeout: time.Minute}, maxBodySize)
+}
+
+// request is a request of an actor sent through the middleware and the response it should get.
+type request struct {
+	actor        string
+	method       string
+	key          string
+	body         string
+	wantStatus   int
+	wantReplayed bool
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name string
+		// statuses are answered by the handler to the requests reaching it, in turn.
+		statuses  []int
+		requests  []request
+		wantCalls int
+	}{
+		{
+			name:     "retry is replayed",
+			statuses: []int{http.StatusCreated},
+			requests: []request{
+				{"clerk", http.MethodPost, "k1", `{"a":1}`, http.StatusCreated, false},
+				{"clerk", http.MethodPost, "k1", `{"a":1}`, http.StatusCreated, true},
+				{"clerk", http.MethodPost, "k1", `{"a":1}`, http.StatusCreated, true},
+			},
+			wantCalls: 1,
+		},
+		{
+			name:     "client errors are replayed",
+			statuses: []int{http.StatusUnprocessableEntity},
+			requests: []request{
+				{"clerk", http.MethodPut, "k1", `{}`, http.StatusUnprocessableEntity, false},
+				{"clerk", http.MethodPut, "k1", `{}`, http.StatusUnprocessableEntity, true},
+			},
+			wantCalls: 1,
+		},
+		{
+			name:     "server errors release the key",
+			statuses: []int{http.StatusInternalServerError, http.StatusCreated},
+			requests: []request{
+				{"clerk", http.MethodPost, "k1", `{"a":1}`, http.StatusInternalServerError, false},
+				{"clerk", http.MethodPost, "k1", `{"a":1}`, http.StatusCreated, false},
+				{"clerk", http.MethodPost, "k1", `{"a":1}`, http.StatusCreated, true},
+			},
+			wantCalls: 2,
+		},
+		{
+			name:     "key reused with another body",
+			statuses: []int{http.StatusCreated},
+			requests: []request{
+				{"clerk", http.MethodPost, "k1", `{"a":1}`, http.StatusCreated, false},
+				{"clerk", http.MethodPost, "k1", `{"a":2}`, http.StatusConflict, false},
+			},
+			wantCalls: 1,
+		},
+		{
+			name:     "same key of another caller",
+			statuses: []int{http.StatusCreated, http.StatusCreated},
+			requests: []request{
+				{"clerk", http.MethodPost, "k1", `{"a":1}`, http.StatusCreated, false},
+				{"manager", http.MethodPost, "k1", `{"a":1}`, http.StatusCreated, false},
+				{"manager", http.MethodPost, "k1", `{"a":1}`, http.StatusCreated, true},
+			},
+			wantCalls: 2,
+		},
+		{
+			name:     "different keys",
+			statuses: []int{http.StatusCreated, http.StatusCreated},
+			requests: []request{
+				{"clerk", http.MethodPost, "k1", `{"a":1}`, http.StatusCreated, false},
+				{"clerk", http.MethodPost, "k2", `{"a":1}`, http.StatusCreated, false},
+			},
+			wantCalls: 2,
+		},
+		{
+			name:     "no key",
+			statuses: []int{http.StatusCreated, http.StatusCreated},
+			requests: []request{
+				{"clerk", http.MethodPost, "", `{"a":1}`, http.StatusCreated, false},
+				{"clerk", http.MethodPost, "", `{"a":1}`, http.StatusCreated, false},
+			},
+			wantCalls: 2,
+		},
+		{
+			name:     "other methods pass through",
+			statuses: []int{http.StatusOK, http.StatusOK},
+			requests: []request{
+				{"clerk", http.MethodDelete, "k1", "", http.StatusOK, false},
+				{"clerk", http.MethodDelete, "k1", "", http.StatusOK, false},
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "key too long",
+			requests: []request{
+				{"clerk", http.MethodPost, strings.Repeat("k", maxKeyLength+1), `{}`, http.StatusBadRequest, false},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			handler := newMiddleware().Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[calls]
+				calls++
+				w.Header().Set("Location", fmt.Sprintf("/things/%d", calls))
+				w.WriteHeader(status)
+				fmt.Fprintf(w, `{"call":%d}`, calls)
+			}))
+
+			var first *httptest.ResponseRecorder
+			for i, req := range tt.requests {
+				r := httptest.NewRequest(req.method, "/things", strings.NewReader(req.body))
+				r = r.WithContext(appMiddleware.WithActor(r.Context(), req.actor))
+				if req.key != "" {
+					r.Header.Set(Header, req.key)
+				}
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, r)
+
+				if rec.Code != req.wantStatus {
+					t.Fatalf("request %d: status = %d, want %d", i, rec.Code, req.wantStatus)
+				}
+				replayed := rec.Header().Get(ReplayedHeader) == "true"
+				if replayed != req.wantReplayed {
+					t.Errorf("request %d: replayed = %t, want %t", i, replayed, req.wantReplayed)
+				}
+				if !replayed {
+					first = rec
+					continue
+				}
+				if rec.Body.String() != first.Body.String() {
+					t.Errorf("request %d: body = %s, want %s", i, rec.Body, first.Body)
+				}
+				if got, want := rec.Header().Get("Location"), first.Header().Get("Location"); got != want {
+					t.Errorf("request %d: Location = %q, want %q", i, got, want)
+				}
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("handler called %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestMiddlewareRequestInProgress(t *testing.T) {
+	var handler http.Handler
+	var retry *httptest.ResponseRecorder
+	handler = newMiddleware().Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The client retries while the first request is still being handled.
+		retry = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(`{}`))
+		req.Header.Set(Header, "k1")
+		handler.ServeHTTP(retry, req)
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(`{}`))
+	r.Header.Set(Header, "k1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+
+	if rec.Code != http.StatusCreated {
+		t.Errorf("first request: status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if retry.Code != http.StatusConflict {
+		t.Errorf("retry: status = %d, want %d", retry.Code, http.StatusConflict)
+	}
+	if retry.Header().Get("Retry-After") == "" {
+		t.Error("retry: no Retry-After header")
+	}
+}
+
+func TestMiddlewareBodySize(t *testing.T) {
+	tests := []struct {
+		name       string
+		size       int
+		wantStatus int
+		wantCalls  int
+	}{
+		{"kept in memory", 100, http.StatusCreated, 1},
+		{"spooled to a file", memoryBodySize + 100, http.StatusCreated, 1},
+		{"largest accepted", maxBodySize, http.StatusCreated, 1},
+		{"too large", maxBodySize + 1, http.StatusRequestEntityTooLarge, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := strings.Repeat("b", tt.size)
+			calls := 0
+			handler := newMiddleware().Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				got, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("handler failed to read body: %v", err)
+				}
+				if string(got) != body {
+					t.Errorf("handler got %d bytes, want %d", len(got), len(body))
+				}
+				w.WriteHeader(http.StatusCreated)
+			}))
+
+			for i, wantReplayed := range []bool{false, true} {
+				r := httptest.NewRequest(http.MethodPost, "/imports", strings.NewReader(body))
+				r.Header.Set(Header, "k1")
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, r)
+
+				if rec.Code != tt.wantStatus {
+					t.Fatalf("request %d: status = %d, want %d", i, rec.Code, tt.wantStatus)
+				}
+				if tt.wantStatus != http.StatusCreated {
+					break
+				}
+				if replayed := rec.Header().Get(ReplayedHeader) == "true"; replayed != wantReplayed {
+					t.Errorf("request %d: replayed = %t, want %t", i, replayed, wantReplayed)
+				}
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("handler called %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package idempotency
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/config"
+)
+
+// Sweeper periodically deletes the keys whose TTL has passed, with their stored responses.
+type Sweeper struct {
+	repo     repo.Querier
+	interval time.Duration
+	log      *slog.Logger
+}
+
+func NewSweeper(repo repo.Querier, cfg config.IdempotencyConfig, log *slog.Logger) *Sweeper {
+	return &Sweeper{
+		repo:     repo,
+		interval: cfg.SweepInterval,
+		log:      log.With("component", "idempotency_sweeper"),
+	}
+}
+
+// Run sweeps every interval until ctx is cancelled.
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.log.Info("Idempotency sweeper started", "interval", s.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("Idempotency sweeper stopped")
+			return
+		case <-ticker.C:
+			s.sweep(ctx)
+		}
+	}
+}
+
+func (s *Sweeper) sweep(ctx context.Context) {
+	deleted, err := s.repo.DeleteExpiredIdempotencyKeys(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.log.Error("Failed to delete expired idempotency keys", "error", err)
+		}
+		return
+	}
+	if deleted > 0 {
+		s.log.Info("Deleted expired idempotency keys", "count", deleted)
+	}
+}
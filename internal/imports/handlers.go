@@ -0,0 +1,263 @@
+package imports
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/config"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/response"
+)
+
+// multipartOverhead is how much larger than the file a multipart/form-data upload may be,
+// for the boundaries, the part headers and any other form fields.
+const multipartOverhead = 64 << 10
+
+// MaxBodySize is the size of the largest upload body: a file of cfg.MaxFileSize sent as a form.
+func MaxBodySize(cfg config.ImportsConfig) int64 {
+	return cfg.MaxFileSize + multipartOverhead
+}
+
+type Handler struct {
+	service     Service
+	maxFileSize int64
+}
+
+func NewHandler(service Service, cfg config.ImportsConfig) *Handler {
+	return &Handler{
+		service:     service,
+		maxFileSize: cfg.MaxFileSize,
+	}
+}
+
+// CreateImport
+//
+//	@Summary		Загрузить файл импорта
+//	@Description	Принимает CSV или NDJSON с книгами и, по желанию, ценой и остатком в магазине (isbn, title, author, description, page_count, publication_year, store_uuid, price_in_kopeks, stock_count).
+//	@Description	Файл передаётся телом запроса или полем file в multipart/form-data. Строки обрабатываются в фоне пачками, статус - GET /imports/{importUUID}.
+//	@Tags			imports
+//	@Accept			text/csv,application/x-ndjson,multipart/form-data
+//	@Produce		json
+//	@Param			format	query		string					false	"Формат файла, если его не видно по Content-Type или имени"	Enums(csv, ndjson)
+//	@Param			dry_run	query		bool					false	"Только проверить строки, ничего не записывая"
+//	@Param			upsert	query		bool					false	"Обновлять уже существующие книги"
+//	@Param			file	formData	file					false	"Файл импорта"
+//	@Success		202		{object}	ImportResponse			"Импорт поставлен в очередь"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		413		{object}	response.ErrorResponse	"Файл слишком большой"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/imports [post]
+func (h *Handler) CreateImport(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	query := r.URL.Query()
+	var opts Options
+	for name, dst := range map[string]*bool{"dry_run": &opts.DryRun, "upsert": &opts.Upsert} {
+		if v := query.Get(name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				response.WriteError(w, r, http.StatusBadRequest, "Invalid "+name)
+				return
+			}
+			*dst = b
+		}
+	}
+
+	data, format, err := h.readFile(w, r)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.WriteError(w, r, http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("The file is larger than %d bytes", h.maxFileSize))
+			return
+		}
+		log.Warn("Failed to read import file", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	job, err := h.service.Create(r.Context(), CreateImportParams{Format: format, Data: data, Options: opts})
+	if err != nil {
+		if errors.Is(err, ErrInvalidFile) {
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error("Failed to create import", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	resp := toImportResponse(job)
+	w.Header().Set("Location", "/imports/"+resp.UUID.String())
+	response.WriteJSON(w, r, http.StatusAccepted, resp)
+}
+
+// readFile returns the uploaded file and its format: the format parameter wins, then the
+// content type of the file, then its extension.
+func (h *Handler) readFile(w http.ResponseWriter, r *http.Request) ([]byte, Format, error) {
+	var body io.Reader = http.MaxBytesReader(w, r.Body, h.maxFileSize)
+	hints := []string{r.URL.Query().Get("format")}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		r.Body = http.MaxBytesReader(w, r.Body, h.maxFileSize+multipartOverhead)
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			if errors.Is(err, http.ErrMissingFile) {
+				return nil, "", errors.New("the file field is missing")
+			}
+			return nil, "", err
+		}
+		defer file.Close()
+		body = http.MaxBytesReader(w, file, h.maxFileSize)
+		hints = append(hints, header.Header.Get("Content-Type"), filepath.Ext(header.Filename))
+	} else {
+		hints = append(hints, mediaType)
+	}
+
+	var format Format
+	for _, hint := range hints {
+		if f, ok := ParseFormat(hint); ok {
+			format = f
+			break
+		}
+	}
+	if format == "" {
+		return nil, "", errors.New("unknown file format, pass format=csv or format=ndjson")
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, format, nil
+}
+
+// GetImport
+//
+//	@Summary		Статус импорта
+//	@Description	Возвращает статус импорта и счётчики обработанных строк.
+//	@Tags			imports
+//	@Produce		json
+//	@Param			importUUID	path		string					true	"UUID импорта"
+//	@Success		200			{object}	ImportResponse			"Импорт"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Импорт не найден"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/imports/{importUUID} [get]
+func (h *Handler) GetImport(w http.ResponseWriter, r *http.Request) {
+	importUUID, ok := parseImportUUID(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := h.service.Get(r.Context(), importUUID)
+	if err != nil {
+		writeImportError(w, r, err)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toImportResponse(job))
+}
+
+// GetImportErrors
+//
+//	@Summary		Отчёт об ошибках импорта
+//	@Description	CSV со строками файла, которые не удалось импортировать: номер строки, ISBN и причина.
+//	@Tags			imports
+//	@Produce		text/csv
+//	@Param			importUUID	path		string					true	"UUID импорта"
+//	@Success		200			{string}	string					"CSV с колонками line, isbn, message"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Импорт не найден"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/imports/{importUUID}/errors [get]
+func (h *Handler) GetImportErrors(w http.ResponseWriter, r *http.Request) {
+	importUUID, ok := parseImportUUID(w, r)
+	if !ok {
+		return
+	}
+
+	rows, err := h.service.ListErrors(r.Context(), importUUID)
+	if err != nil {
+		writeImportError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="import-%s-errors.csv"`, importUUID))
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"line", "isbn", "message"})
+	for _, row := range rows {
+		cw.Write([]string{strconv.Itoa(int(row.Line)), row.Isbn.String, row.Message})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		middleware.LoggerFromContext(r.Context()).Error("Failed to write import errors", "error", err)
+	}
+}
+
+func writeImportError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, ErrImportNotFound) {
+		response.WriteError(w, r, http.StatusNotFound, "Import not found")
+		return
+	}
+	middleware.LoggerFromContext(r.Context()).Error("Import operation failed", "error", err)
+	response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+}
+
+func parseImportUUID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	importUUID, err := uuid.Parse(chi.URLParam(r, "importUUID"))
+	if err != nil {
+		middleware.LoggerFromContext(r.Context()).Warn("Invalid import UUID format", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid import uuid format")
+		return uuid.Nil, false
+	}
+	return importUUID, true
+}
+
+func toImportResponse(job repo.GetImportByUUIDRow) ImportResponse {
+	resp := ImportResponse{
+		UUID:          job.Uuid.Bytes,
+		Format:        string(job.Format),
+		DryRun:        job.DryRun,
+		Upsert:        job.Upsert,
+		Status:        string(job.Status),
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		FailedRows:    job.FailedRows,
+		BooksCreated:  job.BooksCreated,
+		BooksUpdated:  job.BooksUpdated,
+		BooksExisting: job.BooksExisting,
+		SKUsCreated:   job.SkusCreated,
+		CreatedAt:     job.CreatedAt.Time,
+		StartedAt:     timestamptzToTimep(job.StartedAt),
+		FinishedAt:    timestamptzToTimep(job.FinishedAt),
+	}
+	if job.Error.Valid {
+		resp.Error = &job.Error.String
+	}
+	if job.Actor.Valid {
+		resp.Actor = &job.Actor.String
+	}
+	return resp
+}
+
+func timestamptzToTimep(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
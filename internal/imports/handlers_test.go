@@ -0,0 +1,55 @@
+package imports
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReadFileSizeLimit(t *testing.T) {
+	const maxFileSize = 1000
+	h := &Handler{maxFileSize: maxFileSize}
+
+	tests := []struct {
+		name      string
+		size      int
+		multipart bool
+		tooLarge  bool
+	}{
+		{"body at the limit", maxFileSize, false, false},
+		{"body over the limit", maxFileSize + 1, false, true},
+		{"form file at the limit", maxFileSize, true, false},
+		{"form file over the limit", maxFileSize + 1, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := strings.Repeat("a", tt.size)
+			r := httptest.NewRequest(http.MethodPost, "/imports?format=csv", strings.NewReader(data))
+			if tt.multipart {
+				var body bytes.Buffer
+				mw := multipart.NewWriter(&body)
+				fw, err := mw.CreateFormFile("file", "books.csv")
+				if err != nil {
+					t.Fatal(err)
+				}
+				fw.Write([]byte(data))
+				mw.Close()
+				r = httptest.NewRequest(http.MethodPost, "/imports", &body)
+				r.Header.Set("Content-Type", mw.FormDataContentType())
+			}
+
+			got, _, err := h.readFile(httptest.NewRecorder(), r)
+			var maxBytesErr *http.MaxBytesError
+			if tooLarge := errors.As(err, &maxBytesErr); tooLarge != tt.tooLarge {
+				t.Fatalf("readFile() error = %v, want too large: %v", err, tt.tooLarge)
+			}
+			if !tt.tooLarge && len(got) != tt.size {
+				t.Errorf("readFile() read %d bytes, want %d", len(got), tt.size)
+			}
+		})
+	}
+}
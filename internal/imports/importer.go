@@ -0,0 +1,331 @@
+package imports
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nikallow/bookstores-api/internal/adapters/postgres"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/books"
+	"github.com/nikallow/bookstores-api/internal/inventory"
+)
+
+var (
+	errPriceRequired = errors.New("price_in_kopeks is required with store_uuid")
+	errStoreRequired = errors.New("price_in_kopeks and stock_count need store_uuid")
+)
+
+// Options are the switches of an import, the same for the API and the CLI.
+type Options struct {
+	// DryRun only checks the rows and reports what they would do.
+	DryRun bool
+	// Upsert updates books that already exist instead of leaving them as they are.
+	Upsert bool
+}
+
+// Result is what a batch of rows did. Errors has one entry per failed row.
+type Result struct {
+	Processed     int32
+	Failed        int32
+	BooksCreated  int32
+	BooksUpdated  int32
+	BooksExisting int32
+	SKUsCreated   int32
+	Errors        []RowError
+}
+
+type RowError struct {
+	Line    int32
+	ISBN    string
+	Message string
+}
+
+// Add sums up the results of several batches.
+func (r *Result) Add(other Result) {
+	r.Processed += other.Processed
+	r.Failed += other.Failed
+	r.BooksCreated += other.BooksCreated
+	r.BooksUpdated += other.BooksUpdated
+	r.BooksExisting += other.BooksExisting
+	r.SKUsCreated += other.SKUsCreated
+	r.Errors = append(r.Errors, other.Errors...)
+}
+
+// Importer loads rows through books.CreateBook and inventory.CreateSKU, so a row is accepted
+// exactly when POST /books and POST /skus would accept it. Each row is applied on its own:
+// a failed row does not undo the others, and a book created by a row whose SKU failed stays
+// in the catalog.
+type Importer struct {
+	books    books.Service
+	repo     repo.Querier
+	db       postgres.TxBeginner
+	validate *validator.Validate
+}
+
+func NewImporter(books books.Service, repo repo.Querier, db postgres.TxBeginner) *Importer {
+	return &Importer{
+		books:    books,
+		repo:     repo,
+		db:       db,
+		validate: validator.New(),
+	}
+}
+
+// Run imports records[from:] in batches of batchSize rows. Each batch is applied in one
+// transaction, and flush gets its result to record through q in that same transaction, so
+// a batch is committed together with its progress or not at all. The records before from
+// were imported earlier and are only used by a dry run to notice rows that repeat them.
+func (im *Importer) Run(ctx context.Context, records []Record, from, batchSize int, opts Options, flush func(q *repo.Queries, res Result) error) error {
+	plan := newDryRun()
+	if opts.DryRun {
+		for _, rec := range records[:from] {
+			if rec.Err == nil {
+				plan.remember(rec.Row)
+			}
+		}
+	}
+
+	for start := from; start < len(records); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := im.runBatch(ctx, records[start:min(start+batchSize, len(records))], plan, opts, flush); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (im *Importer) runBatch(ctx context.Context, records []Record, plan *dryRun, opts Options, flush func(q *repo.Queries, res Result) error) error {
+	tx, err := im.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var batch Result
+	for _, rec := range records {
+		err := rec.Err
+		if err == nil {
+			if opts.DryRun {
+				err = im.check(ctx, plan, rec.Row, opts, &batch)
+			} else {
+				err = im.apply(ctx, tx, rec.Row, opts, &batch)
+			}
+			if err != nil && !isRowError(err) {
+				return err
+			}
+		}
+		batch.Processed++
+		if err == nil {
+			continue
+		}
+		batch.Failed++
+		batch.Errors = append(batch.Errors, RowError{Line: rec.Line, ISBN: rec.Row.ISBN, Message: err.Error()})
+	}
+
+	if err := flush(repo.New(tx), batch); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (im *Importer) validateRow(row Row) error {
+	if err := im.validate.Struct(row); err != nil {
+		return err
+	}
+	if row.StoreUUID == nil && (row.PriceInKopeks != nil || row.StockCount != nil) {
+		return errStoreRequired
+	}
+	if row.StoreUUID != nil && row.PriceInKopeks == nil {
+		return errPriceRequired
+	}
+	return nil
+}
+
+func (im *Importer) apply(ctx context.Context, tx pgx.Tx, row Row, opts Options, res *Result) error {
+	if err := im.validateRow(row); err != nil {
+		return err
+	}
+
+	req := books.CreateBookRequest{
+		ISBN:            &row.ISBN,
+		Title:           row.Title,
+		Author:          row.Author,
+		Description:     row.Description,
+		PageCount:       row.PageCount,
+		PublicationYear: row.PublicationYear,
+	}
+
+	var book repo.Book
+	err := savepoint(ctx, tx, func(q *repo.Queries) error {
+		if opts.Upsert {
+			var created bool
+			var err error
+			if book, created, err = books.UpsertBook(ctx, q, req, false); err != nil {
+				return err
+			}
+			if created {
+				res.BooksCreated++
+			} else {
+				res.BooksUpdated++
+			}
+			return nil
+		}
+		var err error
+		book, err = books.CreateBook(ctx, q, req)
+		switch {
+		case err == nil:
+			res.BooksCreated++
+		case errors.Is(err, books.ErrBookAlreadyExists):
+			res.BooksExisting++
+		default:
+			return err
+		}
+		return nil
+	})
+	if err != nil || row.StoreUUID == nil {
+		return err
+	}
+
+	return savepoint(ctx, tx, func(q *repo.Queries) error {
+		_, err := inventory.CreateSKU(ctx, q, inventory.CreateSKURequest{
+			BookID:        book.ID,
+			StoreUUID:     *row.StoreUUID,
+			PriceInKopeks: *row.PriceInKopeks,
+			StockCount:    derefInt32(row.StockCount),
+		})
+		if err != nil {
+			return err
+		}
+		res.SKUsCreated++
+		return nil
+	})
+}
+
+// savepoint runs fn in a savepoint of tx: a failed statement aborts the whole transaction,
+// and the next rows of the batch still have to run in it.
+func savepoint(ctx context.Context, tx pgx.Tx, fn func(q *repo.Queries) error) error {
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer sp.Rollback(ctx)
+
+	if err := fn(repo.New(sp)); err != nil {
+		return err
+	}
+	return sp.Commit(ctx)
+}
+
+// dryRun remembers what the rows checked so far would have written.
+type dryRun struct {
+	books map[string]int64 // canonical ISBN -> book ID, 0 for books the import would create
+	skus  map[string]bool  // canonical ISBN + store UUID
+}
+
+func newDryRun() *dryRun {
+	return &dryRun{books: make(map[string]int64), skus: make(map[string]bool)}
+}
+
+func (p *dryRun) remember(row Row) {
+	isbn, err := books.NormalizeISBN(row.ISBN)
+	if err != nil {
+		return
+	}
+	if _, ok := p.books[isbn]; !ok {
+		p.books[isbn] = 0
+	}
+	if row.StoreUUID != nil {
+		p.skus[skuKey(isbn, *row.StoreUUID)] = true
+	}
+}
+
+// check does what apply does without writing anything.
+func (im *Importer) check(ctx context.Context, p *dryRun, row Row, opts Options, res *Result) error {
+	if err := im.validateRow(row); err != nil {
+		return err
+	}
+	isbn, err := books.NormalizeISBN(row.ISBN)
+	if err != nil {
+		return err
+	}
+
+	bookID, seen := p.books[isbn]
+	if !seen {
+		book, err := im.books.GetByISBN(ctx, isbn)
+		switch {
+		case err == nil:
+			bookID, seen = book.ID, true
+		case !errors.Is(err, books.ErrBookNotFound):
+			return err
+		}
+	}
+	switch {
+	case !seen:
+		res.BooksCreated++
+	case opts.Upsert:
+		res.BooksUpdated++
+	default:
+		res.BooksExisting++
+	}
+	if _, ok := p.books[isbn]; !ok {
+		p.books[isbn] = bookID
+	}
+
+	if row.StoreUUID == nil {
+		return nil
+	}
+	store, err := im.repo.GetStoreByUUID(ctx, pgtype.UUID{Bytes: *row.StoreUUID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return inventory.ErrStoreNotFound
+		}
+		return err
+	}
+	key := skuKey(isbn, *row.StoreUUID)
+	if p.skus[key] {
+		return inventory.ErrSKUAlreadyExists
+	}
+	if bookID != 0 {
+		_, err := im.repo.GetSKUByBookAndStore(ctx, repo.GetSKUByBookAndStoreParams{BookID: bookID, StoreID: store.ID})
+		if err == nil {
+			return inventory.ErrSKUAlreadyExists
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+	}
+	p.skus[key] = true
+	res.SKUsCreated++
+	return nil
+}
+
+// isRowError reports errors caused by the data of a row, which are reported and skipped.
+// Any other error stops the import.
+func isRowError(err error) bool {
+	var validationErrs validator.ValidationErrors
+	return errors.As(err, &validationErrs) ||
+		errors.Is(err, errPriceRequired) ||
+		errors.Is(err, errStoreRequired) ||
+		books.IsInputError(err) ||
+		errors.Is(err, books.ErrBookDeleted) ||
+		errors.Is(err, inventory.ErrStoreNotFound) ||
+		errors.Is(err, inventory.ErrBookNotFound) ||
+		errors.Is(err, inventory.ErrSKUAlreadyExists)
+}
+
+func skuKey(isbn string, storeUUID uuid.UUID) string {
+	return isbn + "/" + storeUUID.String()
+}
+
+func derefInt32(i *int32) int32 {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
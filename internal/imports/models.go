@@ -0,0 +1,33 @@
+package imports
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateImportParams struct {
+	Format  Format
+	Data    []byte
+	Options Options
+}
+
+type ImportResponse struct {
+	UUID          uuid.UUID  `json:"uuid"`
+	Format        string     `json:"format"`
+	DryRun        bool       `json:"dry_run"`
+	Upsert        bool       `json:"upsert"`
+	Status        string     `json:"status"`
+	TotalRows     int32      `json:"total_rows"`
+	ProcessedRows int32      `json:"processed_rows"`
+	FailedRows    int32      `json:"failed_rows"`
+	BooksCreated  int32      `json:"books_created"`
+	BooksUpdated  int32      `json:"books_updated"`
+	BooksExisting int32      `json:"books_existing"`
+	SKUsCreated   int32      `json:"skus_created"`
+	Error         *string    `json:"error,omitempty"`
+	Actor         *string    `json:"actor,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,216 @@
+package imports
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+var ErrInvalidFile = errors.New("invalid import file")
+
+// Row is one book of an import file, optionally with its price and stock in a store.
+type Row struct {
+	ISBN            string     `json:"isbn"             validate:"required"`
+	Title           string     `json:"title"            validate:"required"`
+	Author          string     `json:"author"           validate:"required"`
+	Description     *string    `json:"description"`
+	PageCount       *int32     `json:"page_count"       validate:"omitempty,gt=0"`
+	PublicationYear *int32     `json:"publication_year"`
+	StoreUUID       *uuid.UUID `json:"store_uuid"`
+	PriceInKopeks   *int32     `json:"price_in_kopeks"  validate:"omitempty,gte=0"`
+	StockCount      *int32     `json:"stock_count"      validate:"omitempty,gte=0"`
+}
+
+// Record is a row of the file together with the line it starts on.
+// Err is set when the row could not be read, the rest of the file still can.
+type Record struct {
+	Line int32
+	Row  Row
+	Err  error
+}
+
+// ParseFormat accepts a format name, a file extension or a content type.
+func ParseFormat(s string) (Format, bool) {
+	s = strings.ToLower(strings.TrimPrefix(s, "."))
+	if i := strings.IndexByte(s, ';'); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	switch s {
+	case "csv", "text/csv":
+		return FormatCSV, true
+	case "ndjson", "jsonl", "application/x-ndjson", "application/jsonl":
+		return FormatNDJSON, true
+	}
+	return "", false
+}
+
+// Parse reads every row of the file. Errors of single rows end up in their records,
+// an error is returned only when the file as a whole cannot be read.
+func Parse(format Format, data []byte) ([]Record, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(data)
+	case FormatNDJSON:
+		return parseNDJSON(data)
+	}
+	return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidFile, format)
+}
+
+// csvColumns are the columns a CSV file may have, in any order.
+var csvColumns = []string{
+	"isbn", "title", "author", "description", "page_count", "publication_year",
+	"store_uuid", "price_in_kopeks", "stock_count",
+}
+
+func parseCSV(data []byte) ([]Record, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: the file is empty", ErrInvalidFile)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(csvColumns, name) {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidFile, name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("%w: column %q is repeated", ErrInvalidFile, name)
+		}
+		columns[name] = i
+	}
+	for _, name := range []string{"isbn", "title", "author"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: column %q is missing", ErrInvalidFile, name)
+		}
+	}
+
+	var records []Record
+	for {
+		fields, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := r.FieldPos(0)
+		if err != nil {
+			if !errors.Is(err, csv.ErrFieldCount) {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+			}
+			records = append(records, Record{Line: int32(line), Err: errors.New("wrong number of fields")})
+			continue
+		}
+		row, err := csvRow(columns, fields)
+		records = append(records, Record{Line: int32(line), Row: row, Err: err})
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: the file has no rows", ErrInvalidFile)
+	}
+	return records, nil
+}
+
+func csvRow(columns map[string]int, fields []string) (Row, error) {
+	get := func(name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(fields[i])
+		}
+		return ""
+	}
+
+	row := Row{
+		ISBN:   get("isbn"),
+		Title:  get("title"),
+		Author: get("author"),
+	}
+	if s := get("description"); s != "" {
+		row.Description = &s
+	}
+
+	var err error
+	if row.PageCount, err = csvInt(get("page_count"), "page_count"); err != nil {
+		return row, err
+	}
+	if row.PublicationYear, err = csvInt(get("publication_year"), "publication_year"); err != nil {
+		return row, err
+	}
+	if row.PriceInKopeks, err = csvInt(get("price_in_kopeks"), "price_in_kopeks"); err != nil {
+		return row, err
+	}
+	if row.StockCount, err = csvInt(get("stock_count"), "stock_count"); err != nil {
+		return row, err
+	}
+	if s := get("store_uuid"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return row, errors.New("invalid store_uuid")
+		}
+		row.StoreUUID = &id
+	}
+	return row, nil
+}
+
+func csvInt(s, column string) (*int32, error) {
+	if s == "" {
+		return nil, nil
+	}
+	i, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", column)
+	}
+	v := int32(i)
+	return &v, nil
+}
+
+// maxLineSize bounds a single NDJSON line.
+const maxLineSize = 1 << 20
+
+func parseNDJSON(data []byte) ([]Record, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var records []Record
+	var line int32
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var row Row
+		dec := json.NewDecoder(bytes.NewReader(text))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&row); err != nil {
+			records = append(records, Record{Line: line, Err: fmt.Errorf("invalid json: %v", err)})
+			continue
+		}
+		records = append(records, Record{Line: line, Row: row})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidFile, line+1, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: the file has no rows", ErrInvalidFile)
+	}
+	return records, nil
+}
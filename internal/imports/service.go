@@ -0,0 +1,190 @@
+package imports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/config"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+)
+
+var (
+	ErrImportNotFound = errors.New("import not found")
+	// errImportLost means another worker took the import over, this one must stop.
+	errImportLost = errors.New("import was taken over by another worker")
+)
+
+// internalErrorMessage is stored instead of errors that are not caused by the file.
+const internalErrorMessage = "the import was stopped by an internal error"
+
+type Service interface {
+	Create(ctx context.Context, params CreateImportParams) (repo.GetImportByUUIDRow, error)
+	Get(ctx context.Context, importUUID uuid.UUID) (repo.GetImportByUUIDRow, error)
+	ListErrors(ctx context.Context, importUUID uuid.UUID) ([]repo.ListImportErrorsRow, error)
+	ProcessNext(ctx context.Context) (bool, error)
+}
+
+type service struct {
+	repo     repo.Querier
+	importer *Importer
+	cfg      config.ImportsConfig
+}
+
+func NewService(repo repo.Querier, importer *Importer, cfg config.ImportsConfig) Service {
+	return &service{repo: repo, importer: importer, cfg: cfg}
+}
+
+// Create - POST /imports
+//
+// The file is checked and stored, the rows are imported later by the worker.
+func (s *service) Create(ctx context.Context, params CreateImportParams) (repo.GetImportByUUIDRow, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	records, err := Parse(params.Format, params.Data)
+	if err != nil {
+		return repo.GetImportByUUIDRow{}, err
+	}
+
+	var actor pgtype.Text
+	if a := middleware.ActorFromContext(ctx); a != "" {
+		actor = pgtype.Text{String: a, Valid: true}
+	}
+
+	row, err := s.repo.CreateImport(ctx, repo.CreateImportParams{
+		Format:    repo.ImportFormat(params.Format),
+		DryRun:    params.Options.DryRun,
+		Upsert:    params.Options.Upsert,
+		Payload:   params.Data,
+		TotalRows: int32(len(records)),
+		Actor:     actor,
+	})
+	if err != nil {
+		log.Error("Failed to create import", "error", err)
+		return repo.GetImportByUUIDRow{}, fmt.Errorf("failed to create import: %w", err)
+	}
+
+	log.Info("Import created successfully", "import_id", row.ID, "rows", row.TotalRows, "dry_run", row.DryRun)
+	return repo.GetImportByUUIDRow(row), nil
+}
+
+func (s *service) Get(ctx context.Context, importUUID uuid.UUID) (repo.GetImportByUUIDRow, error) {
+	row, err := s.repo.GetImportByUUID(ctx, pgtype.UUID{Bytes: importUUID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.GetImportByUUIDRow{}, ErrImportNotFound
+		}
+		return repo.GetImportByUUIDRow{}, fmt.Errorf("failed to get import: %w", err)
+	}
+	return row, nil
+}
+
+// ListErrors returns the failed rows of an import in file order.
+func (s *service) ListErrors(ctx context.Context, importUUID uuid.UUID) ([]repo.ListImportErrorsRow, error) {
+	job, err := s.Get(ctx, importUUID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.ListImportErrors(ctx, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list import errors: %w", err)
+	}
+	return rows, nil
+}
+
+// ProcessNext runs one pending import to the end and reports whether there was one.
+//
+// Progress is saved in the transaction of every batch, so an import interrupted by a
+// shutdown or a crash goes on right after the last committed batch.
+func (s *service) ProcessNext(ctx context.Context) (bool, error) {
+	job, err := s.repo.ClaimImport(ctx, pgtype.Timestamptz{Time: time.Now().Add(-s.cfg.StaleAfter), Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to claim import: %w", err)
+	}
+
+	log := middleware.LoggerFromContext(ctx).With("import_id", job.ID)
+	ctx = middleware.WithLogger(ctx, log)
+	if job.Actor.Valid {
+		ctx = middleware.WithActor(ctx, job.Actor.String)
+	}
+	log.Info("Import started", "rows", job.TotalRows, "from_row", job.ProcessedRows, "dry_run", job.DryRun)
+
+	records, err := Parse(Format(job.Format), job.Payload)
+	if err != nil {
+		return true, s.finish(ctx, job, repo.ImportStatusFailed, err.Error())
+	}
+
+	opts := Options{DryRun: job.DryRun, Upsert: job.Upsert}
+	err = s.importer.Run(ctx, records, int(job.ProcessedRows), s.cfg.BatchSize, opts, func(q *repo.Queries, res Result) error {
+		return saveProgress(ctx, q, job, res)
+	})
+	switch {
+	case err == nil:
+		log.Info("Import completed")
+		return true, s.finish(ctx, job, repo.ImportStatusCompleted, "")
+	case errors.Is(err, errImportLost):
+		log.Warn("Import was taken over by another worker")
+		return true, nil
+	case ctx.Err() != nil:
+		// Shutting down: hand the import back so that it goes on after the restart.
+		if err := s.repo.RequeueImport(context.WithoutCancel(ctx), repo.RequeueImportParams{ID: job.ID, Attempt: job.Attempt}); err != nil {
+			return true, fmt.Errorf("failed to requeue import: %w", err)
+		}
+		log.Info("Import interrupted, requeued")
+		return true, ctx.Err()
+	default:
+		log.Error("Import failed", "error", err)
+		return true, s.finish(ctx, job, repo.ImportStatusFailed, internalErrorMessage)
+	}
+}
+
+func saveProgress(ctx context.Context, q *repo.Queries, job repo.Import, res Result) error {
+	updated, err := q.RecordImportProgress(ctx, repo.RecordImportProgressParams{
+		ID:            job.ID,
+		Attempt:       job.Attempt,
+		ProcessedRows: res.Processed,
+		FailedRows:    res.Failed,
+		BooksCreated:  res.BooksCreated,
+		BooksUpdated:  res.BooksUpdated,
+		BooksExisting: res.BooksExisting,
+		SkusCreated:   res.SKUsCreated,
+	})
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return errImportLost
+	}
+
+	if len(res.Errors) > 0 {
+		params := repo.AddImportErrorsParams{ImportID: job.ID}
+		for _, e := range res.Errors {
+			params.Lines = append(params.Lines, e.Line)
+			params.Isbns = append(params.Isbns, e.ISBN)
+			params.Messages = append(params.Messages, e.Message)
+		}
+		if err := q.AddImportErrors(ctx, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *service) finish(ctx context.Context, job repo.Import, status repo.ImportStatus, message string) error {
+	params := repo.FinishImportParams{ID: job.ID, Attempt: job.Attempt, Status: status}
+	if message != "" {
+		params.Error = pgtype.Text{String: message, Valid: true}
+	}
+	if err := s.repo.FinishImport(context.WithoutCancel(ctx), params); err != nil {
+		return fmt.Errorf("failed to finish import: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package imports
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/nikallow/bookstores-api/internal/config"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+)
+
+// Worker runs the pending imports one after another.
+type Worker struct {
+	service  Service
+	interval time.Duration
+	log      *slog.Logger
+}
+
+func NewWorker(service Service, cfg config.ImportsConfig, log *slog.Logger) *Worker {
+	return &Worker{
+		service:  service,
+		interval: cfg.PollInterval,
+		log:      log.With("component", "imports_worker"),
+	}
+}
+
+// Run looks for pending imports every interval until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	ctx = middleware.WithLogger(ctx, w.log)
+	w.log.Info("Imports worker started", "interval", w.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			w.log.Info("Imports worker stopped")
+			return
+		case <-ticker.C:
+			w.drain(ctx)
+		}
+	}
+}
+
+func (w *Worker) drain(ctx context.Context) {
+	for {
+		found, err := w.service.ProcessNext(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				w.log.Error("Failed to process import", "error", err)
+			}
+			return
+		}
+		if !found {
+			return
+		}
+	}
+}
@@ -3,7 +3,11 @@ package inventory
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
@@ -12,6 +16,7 @@ import (
 	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
 	"github.com/nikallow/bookstores-api/internal/books"
 	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/pagination"
 	"github.com/nikallow/bookstores-api/internal/response"
 )
 
@@ -82,6 +87,7 @@ func (h *Handler) CreateSKU(w http.ResponseWriter, r *http.Request) {
 //	@Produce		json
 //	@Param			skuUUID	path		string					true	"UUID товарной позиции (SKU)"
 //	@Success		200		{object}	SKUWithBookResponse		"Информация о SKU и связанной книге"
+//	@Header			200		{string}	ETag					"Версия SKU для If-Match"
 //	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
 //	@Failure		404		{object}	response.ErrorResponse	"SKU не найден"
 //	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
@@ -108,22 +114,28 @@ func (h *Handler) GetSKU(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", formatETag(sku.Sku.Version))
 	response.WriteJSON(w, r, http.StatusOK, toSKUWithBookResponse(sku))
 }
 
 // UpdateSKUPrice
 //
 //	@Summary		Обновить цену SKU
-//	@Description	Устанавливает новую цену для существующей товарной позиции (SKU).
+//	@Description	Устанавливает новую цену для существующей товарной позиции (SKU). Каждая цена записывается в историю цен.
+//	@Description	Если указан effective_at в будущем, цена планируется и применяется планировщиком в указанное время.
 //	@Tags			skus
 //	@Accept			json
 //	@Produce		json
-//	@Param			skuUUID	path		string					true	"UUID товарной позиции (SKU)"
-//	@Param			input	body		UpdateSKUPriceRequest	true	"Новая цена"
-//	@Success		200		{object}	SKUResponse				"Обновленный SKU"
-//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
-//	@Failure		404		{object}	response.ErrorResponse	"SKU не найден"
-//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Param			skuUUID		path		string					true	"UUID товарной позиции (SKU)"
+//	@Param			input		body		UpdateSKUPriceRequest	true	"Новая цена"
+//	@Param			If-Match	header		string					false	"ETag SKU, полученный ранее"
+//	@Success		200			{object}	SKUResponse				"Обновленный SKU"
+//	@Success		202			{object}	SKUPriceResponse		"Запланированная цена"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"SKU не найден"
+//	@Failure		409			{object}	response.ErrorResponse	"На это время уже запланирована другая цена"
+//	@Failure		412			{object}	response.ErrorResponse	"SKU изменён другим запросом"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
 //	@Router			/skus/{skuUUID}/price [put]
 func (h *Handler) UpdateSKUPrice(w http.ResponseWriter, r *http.Request) {
 	log := middleware.LoggerFromContext(r.Context())
@@ -147,16 +159,48 @@ func (h *Handler) UpdateSKUPrice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sku, err := h.service.UpdateSKUPrice(r.Context(), skuUUID, req.NewPriceInKopeks)
+	expectedVersion, err := parseIfMatch(r)
 	if err != nil {
-		if errors.Is(err, ErrSKUNotFound) {
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid If-Match header")
+		return
+	}
+
+	if req.EffectiveAt != nil {
+		price, err := h.service.ScheduleSKUPrice(r.Context(), skuUUID, req.NewPriceInKopeks, *req.EffectiveAt, expectedVersion)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrSKUNotFound):
+				response.WriteError(w, r, http.StatusNotFound, "SKU not found")
+			case errors.Is(err, ErrEffectiveAtInPast):
+				response.WriteError(w, r, http.StatusBadRequest, err.Error())
+			case errors.Is(err, ErrPriceAlreadyScheduled):
+				response.WriteError(w, r, http.StatusConflict, err.Error())
+			case errors.Is(err, ErrVersionMismatch):
+				response.WriteError(w, r, http.StatusPreconditionFailed, err.Error())
+			default:
+				log.Error("Failed to schedule sku price", "error", err)
+				response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+			}
+			return
+		}
+		response.WriteJSON(w, r, http.StatusAccepted, toSKUPriceResponse(price))
+		return
+	}
+
+	sku, err := h.service.UpdateSKUPrice(r.Context(), skuUUID, req.NewPriceInKopeks, expectedVersion)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrSKUNotFound):
 			response.WriteError(w, r, http.StatusNotFound, "SKU not found")
-		} else {
+		case errors.Is(err, ErrVersionMismatch):
+			response.WriteError(w, r, http.StatusPreconditionFailed, err.Error())
+		default:
 			log.Error("Failed to update sku price", "error", err)
 			response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
 		}
 		return
 	}
+	w.Header().Set("ETag", formatETag(sku.Version))
 	response.WriteJSON(w, r, http.StatusOK, toSKUResponse(sku))
 }
 
@@ -164,16 +208,20 @@ func (h *Handler) UpdateSKUPrice(w http.ResponseWriter, r *http.Request) {
 //
 //	@Summary		Скорректировать остатки
 //	@Description	Увеличивает или уменьшает количество товара на складе. Для уменьшения используйте отрицательное значение.
+//	@Description	Каждая корректировка записывается в журнал движений с причиной (по умолчанию correction).
+//	@Description	receipt и return только увеличивают остаток, sale и write_off только уменьшают, correction - в любую сторону.
 //	@Tags			skus
 //	@Accept			json
 //	@Produce		json
-//	@Param			skuUUID	path		string					true	"UUID товарной позиции (SKU)"
-//	@Param			input	body		AdjustSKUStockRequest	true	"Количество для изменения"
-//	@Success		200		{object}	SKUResponse				"Обновленный SKU"
-//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
-//	@Failure		404		{object}	response.ErrorResponse	"SKU не найден"
-//	@Failure		409		{object}	response.ErrorResponse	"Недостаточно товара для списания"
-//	@Failure		500		{object}	response.ErrorResponse	"Internal error"
+//	@Param			skuUUID		path		string					true	"UUID товарной позиции (SKU)"
+//	@Param			input		body		AdjustSKUStockRequest	true	"Количество для изменения"
+//	@Param			If-Match	header		string					false	"ETag SKU, полученный ранее"
+//	@Success		200			{object}	SKUResponse				"Обновленный SKU"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"SKU не найден"
+//	@Failure		409			{object}	response.ErrorResponse	"Недостаточно товара для списания"
+//	@Failure		412			{object}	response.ErrorResponse	"SKU изменён другим запросом"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal error"
 //	@Router			/skus/{skuUUID}/stock-adjustments [post]
 func (h *Handler) AdjustSKUStock(w http.ResponseWriter, r *http.Request) {
 	log := middleware.LoggerFromContext(r.Context())
@@ -197,13 +245,23 @@ func (h *Handler) AdjustSKUStock(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sku, err := h.service.AdjustSKUStock(r.Context(), skuUUID, req.ChangeBy)
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid If-Match header")
+		return
+	}
+
+	sku, err := h.service.AdjustSKUStock(r.Context(), skuUUID, req, expectedVersion)
 	if err != nil {
 		switch {
 		case errors.Is(err, ErrSKUNotFound):
 			response.WriteError(w, r, http.StatusNotFound, "SKU not found")
+		case errors.Is(err, ErrReasonMismatch):
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
 		case errors.Is(err, ErrInsufficientStock):
 			response.WriteError(w, r, http.StatusConflict, err.Error())
+		case errors.Is(err, ErrVersionMismatch):
+			response.WriteError(w, r, http.StatusPreconditionFailed, err.Error())
 		default:
 			log.Error("Failed to adjust sku stock", "error", err)
 			response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
@@ -211,9 +269,315 @@ func (h *Handler) AdjustSKUStock(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", formatETag(sku.Version))
 	response.WriteJSON(w, r, http.StatusOK, toSKUResponse(sku))
 }
 
+// ListStoreSKUs
+//
+//	@Summary		Ассортимент магазина
+//	@Description	Возвращает страницу SKU магазина вместе с данными о книгах.
+//	@Tags			stores
+//	@Produce		json
+//	@Param			storeUUID	path		string					true	"UUID магазина"
+//	@Param			in_stock	query		bool					false	"Только товары в наличии"
+//	@Param			min_price	query		int						false	"Минимальная цена в копейках"
+//	@Param			max_price	query		int						false	"Максимальная цена в копейках"
+//	@Param			title		query		string					false	"Фильтр по названию книги (подстрока)"
+//	@Param			limit		query		int						false	"Размер страницы (1-100)"	default(20)
+//	@Param			cursor		query		string					false	"Курсор следующей страницы"
+//	@Param			sort		query		string					false	"Поле сортировки"			Enums(title, price, stock)	default(title)
+//	@Param			order		query		string					false	"Направление сортировки"	Enums(asc, desc)			default(asc)
+//	@Success		200			{object}	SKUListResponse			"Страница SKU магазина"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Магазин не найден"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/stores/{storeUUID}/skus [get]
+func (h *Handler) ListStoreSKUs(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	storeUUID, err := uuid.Parse(chi.URLParam(r, "storeUUID"))
+	if err != nil {
+		log.Warn("Invalid store UUID format", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid store UUID format")
+		return
+	}
+
+	query := r.URL.Query()
+	page, err := pagination.FromQuery(query, storeSKUSorts...)
+	if err != nil {
+		log.Warn("Invalid list store skus parameters", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := ListStoreSKUsParams{Request: page}
+	if inStock := query.Get("in_stock"); inStock != "" {
+		if params.InStockOnly, err = strconv.ParseBool(inStock); err != nil {
+			response.WriteError(w, r, http.StatusBadRequest, "Invalid in_stock")
+			return
+		}
+	}
+	if params.MinPrice, err = parseInt32Query(query.Get("min_price")); err != nil {
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid min_price")
+		return
+	}
+	if params.MaxPrice, err = parseInt32Query(query.Get("max_price")); err != nil {
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid max_price")
+		return
+	}
+	if title := query.Get("title"); title != "" {
+		params.Title = &title
+	}
+
+	rows, nextCursor, err := h.service.ListStoreSKUs(r.Context(), storeUUID, params)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrStoreNotFound):
+			response.WriteError(w, r, http.StatusNotFound, "Store not found")
+		case errors.Is(err, pagination.ErrInvalidCursor):
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		default:
+			log.Error("Failed to list store skus", "error", err, "store_uuid", storeUUID)
+			response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	resp := make([]SKUWithBookResponse, len(rows))
+	for i, row := range rows {
+		resp[i] = SKUWithBookResponse{
+			SKU:  toSKUResponse(row.Sku),
+			Book: books.ToBookResponse(row.Book),
+		}
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, SKUListResponse{Items: resp, NextCursor: nextCursor})
+}
+
+// ListStockMovements
+//
+//	@Summary		Журнал движений SKU
+//	@Description	Возвращает историю изменений остатка SKU: изменение, итоговый остаток, причину, автора и ID запроса.
+//	@Tags			skus
+//	@Produce		json
+//	@Param			skuUUID	path		string						true	"UUID товарной позиции (SKU)"
+//	@Param			from	query		string						false	"Начало периода (RFC3339, включительно)"
+//	@Param			to		query		string						false	"Конец периода (RFC3339, не включительно)"
+//	@Param			limit	query		int							false	"Размер страницы (1-100)"	default(20)
+//	@Param			cursor	query		string						false	"Курсор следующей страницы"
+//	@Param			order	query		string						false	"Направление сортировки по времени"	Enums(asc, desc)	default(asc)
+//	@Success		200		{object}	StockMovementListResponse	"Страница движений"
+//	@Failure		400		{object}	response.ErrorResponse		"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse		"SKU не найден"
+//	@Failure		500		{object}	response.ErrorResponse		"Internal server error"
+//	@Router			/skus/{skuUUID}/movements [get]
+func (h *Handler) ListStockMovements(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	skuUUID, err := uuid.Parse(chi.URLParam(r, "skuUUID"))
+	if err != nil {
+		log.Warn("Invalid sku UUID format", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid sku uuid format")
+		return
+	}
+
+	query := r.URL.Query()
+	page, err := pagination.FromQuery(query, "created_at")
+	if err != nil {
+		log.Warn("Invalid list stock movements parameters", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := ListStockMovementsParams{Request: page}
+	if params.From, err = parseTimeQuery(query.Get("from")); err != nil {
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid from, expected RFC3339")
+		return
+	}
+	if params.To, err = parseTimeQuery(query.Get("to")); err != nil {
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid to, expected RFC3339")
+		return
+	}
+
+	movements, nextCursor, err := h.service.ListStockMovements(r.Context(), skuUUID, params)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrSKUNotFound):
+			response.WriteError(w, r, http.StatusNotFound, "SKU not found")
+		case errors.Is(err, pagination.ErrInvalidCursor):
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		default:
+			log.Error("Failed to list stock movements", "error", err, "sku_uuid", skuUUID)
+			response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	resp := make([]StockMovementResponse, len(movements))
+	for i, m := range movements {
+		resp[i] = toStockMovementResponse(m)
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, StockMovementListResponse{Items: resp, NextCursor: nextCursor})
+}
+
+// ListSKUPriceHistory
+//
+//	@Summary		История цен SKU
+//	@Description	Возвращает все цены SKU: действующую, прежние с периодом действия, запланированные и отменённые, с автором и ID запроса.
+//	@Tags			skus
+//	@Produce		json
+//	@Param			skuUUID	path		string					true	"UUID товарной позиции (SKU)"
+//	@Param			status	query		string					false	"Фильтр по статусу"			Enums(scheduled, active, superseded, cancelled)
+//	@Param			limit	query		int						false	"Размер страницы (1-100)"	default(20)
+//	@Param			cursor	query		string					false	"Курсор следующей страницы"
+//	@Param			order	query		string					false	"Направление сортировки по времени создания"	Enums(asc, desc)	default(asc)
+//	@Success		200		{object}	SKUPriceListResponse	"Страница истории цен"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"SKU не найден"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/skus/{skuUUID}/price-history [get]
+func (h *Handler) ListSKUPriceHistory(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	skuUUID, err := uuid.Parse(chi.URLParam(r, "skuUUID"))
+	if err != nil {
+		log.Warn("Invalid sku UUID format", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid sku uuid format")
+		return
+	}
+
+	query := r.URL.Query()
+	page, err := pagination.FromQuery(query, "created_at")
+	if err != nil {
+		log.Warn("Invalid list sku prices parameters", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := ListSKUPricesParams{Request: page}
+	if status := query.Get("status"); status != "" {
+		switch status {
+		case PriceScheduled, PriceActive, PriceSuperseded, PriceCancelled:
+			params.Status = &status
+		default:
+			response.WriteError(w, r, http.StatusBadRequest, "Invalid status")
+			return
+		}
+	}
+
+	prices, nextCursor, err := h.service.ListSKUPrices(r.Context(), skuUUID, params)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrSKUNotFound):
+			response.WriteError(w, r, http.StatusNotFound, "SKU not found")
+		case errors.Is(err, pagination.ErrInvalidCursor):
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		default:
+			log.Error("Failed to list sku prices", "error", err, "sku_uuid", skuUUID)
+			response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	resp := make([]SKUPriceResponse, len(prices))
+	for i, p := range prices {
+		resp[i] = toSKUPriceResponse(p)
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, SKUPriceListResponse{Items: resp, NextCursor: nextCursor})
+}
+
+// CancelSKUPrice
+//
+//	@Summary		Отменить запланированную цену
+//	@Description	Отменяет цену, которая ещё не вступила в силу. Запись остаётся в истории со статусом cancelled.
+//	@Tags			skus
+//	@Produce		json
+//	@Param			skuUUID		path		string					true	"UUID товарной позиции (SKU)"
+//	@Param			priceUUID	path		string					true	"UUID цены из истории"
+//	@Success		200			{object}	SKUPriceResponse		"Отменённая цена"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"SKU или цена не найдены"
+//	@Failure		409			{object}	response.ErrorResponse	"Цена уже применена или отменена"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/skus/{skuUUID}/price-history/{priceUUID}/cancel [post]
+func (h *Handler) CancelSKUPrice(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	skuUUID, err := uuid.Parse(chi.URLParam(r, "skuUUID"))
+	if err != nil {
+		log.Warn("Invalid sku UUID format", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid sku uuid format")
+		return
+	}
+	priceUUID, err := uuid.Parse(chi.URLParam(r, "priceUUID"))
+	if err != nil {
+		log.Warn("Invalid price UUID format", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid price uuid format")
+		return
+	}
+
+	price, err := h.service.CancelSKUPrice(r.Context(), skuUUID, priceUUID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrSKUNotFound):
+			response.WriteError(w, r, http.StatusNotFound, "SKU not found")
+		case errors.Is(err, ErrPriceNotFound):
+			response.WriteError(w, r, http.StatusNotFound, "Price not found")
+		case errors.Is(err, ErrPriceNotScheduled):
+			response.WriteError(w, r, http.StatusConflict, err.Error())
+		default:
+			log.Error("Failed to cancel sku price", "error", err, "price_uuid", priceUUID)
+			response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toSKUPriceResponse(price))
+}
+
+func toSKUPriceResponse(p repo.SkuPrice) SKUPriceResponse {
+	resp := SKUPriceResponse{
+		UUID:          mustConvertUUID(p.Uuid),
+		PriceInKopeks: p.PriceInKopeks,
+		Status:        PriceStatus(p),
+		ScheduledFor:  timestamptzToTimep(p.ScheduledFor),
+		EffectiveFrom: timestamptzToTimep(p.EffectiveFrom),
+		EffectiveTo:   timestamptzToTimep(p.EffectiveTo),
+		CancelledAt:   timestamptzToTimep(p.CancelledAt),
+		CreatedAt:     p.CreatedAt.Time,
+	}
+	if p.Actor.Valid {
+		resp.Actor = &p.Actor.String
+	}
+	if p.RequestID.Valid {
+		resp.RequestID = &p.RequestID.String
+	}
+	return resp
+}
+
+func toStockMovementResponse(m repo.StockMovement) StockMovementResponse {
+	resp := StockMovementResponse{
+		UUID:      mustConvertUUID(m.Uuid),
+		Delta:     m.Delta,
+		Balance:   m.Balance,
+		Reason:    string(m.Reason),
+		CreatedAt: m.CreatedAt.Time,
+	}
+	if m.Note.Valid {
+		resp.Note = &m.Note.String
+	}
+	if m.Actor.Valid {
+		resp.Actor = &m.Actor.String
+	}
+	if m.RequestID.Valid {
+		resp.RequestID = &m.RequestID.String
+	}
+	return resp
+}
+
 func toSKUResponse(sku repo.Sku) SKUResponse {
 	return SKUResponse{
 		ID:            sku.ID,
@@ -221,7 +585,11 @@ func toSKUResponse(sku repo.Sku) SKUResponse {
 		BookID:        sku.BookID,
 		StoreID:       sku.StoreID,
 		PriceInKopeks: sku.PriceInKopeks,
+		Currency:      sku.Currency,
 		StockCount:    sku.StockCount,
+		Reserved:      sku.ReservedCount,
+		Available:     Available(sku),
+		Version:       sku.Version,
 		CreatedAt:     sku.CreatedAt.Time,
 		UpdatedAt:     sku.UpdatedAt.Time,
 	}
@@ -240,3 +608,51 @@ func mustConvertUUID(pgUUID pgtype.UUID) uuid.UUID {
 	}
 	return pgUUID.Bytes
 }
+
+func timestamptzToTimep(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func parseInt32Query(value string) (*int32, error) {
+	if value == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	i := int32(v)
+	return &i, nil
+}
+
+func parseTimeQuery(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func formatETag(version int32) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// parseIfMatch returns the SKU version from an If-Match header, nil if the header is absent or "*".
+func parseIfMatch(r *http.Request) (*int32, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+	value = strings.TrimPrefix(value, "W/")
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		unquoted = value
+	}
+	return parseInt32Query(unquoted)
+}
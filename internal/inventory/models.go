@@ -5,8 +5,12 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nikallow/bookstores-api/internal/books"
+	"github.com/nikallow/bookstores-api/internal/pagination"
 )
 
+// Sort fields accepted by GET /stores/{storeUUID}/skus, the first one is the default.
+var storeSKUSorts = []string{"title", "price", "stock"}
+
 type CreateSKURequest struct {
 	BookID        int64     `json:"book_id"         validate:"required"`
 	StoreUUID     uuid.UUID `json:"store_uuid"      validate:"required"`
@@ -15,11 +19,33 @@ type CreateSKURequest struct {
 }
 
 type UpdateSKUPriceRequest struct {
-	NewPriceInKopeks int32 `json:"new_price_in_kopeks" validate:"gte=0"`
+	NewPriceInKopeks int32      `json:"new_price_in_kopeks"    validate:"gte=0"`
+	EffectiveAt      *time.Time `json:"effective_at,omitempty"`
 }
 
 type AdjustSKUStockRequest struct {
-	ChangeBy int32 `json:"change_by"`
+	ChangeBy int32   `json:"change_by"        validate:"required,ne=0"`
+	Reason   string  `json:"reason,omitempty" validate:"omitempty,oneof=receipt sale return write_off correction" enums:"receipt,sale,return,write_off,correction"`
+	Note     *string `json:"note,omitempty"   validate:"omitempty,max=1000"`
+}
+
+type ListStockMovementsParams struct {
+	pagination.Request
+	From *time.Time
+	To   *time.Time
+}
+
+type ListSKUPricesParams struct {
+	pagination.Request
+	Status *string
+}
+
+type ListStoreSKUsParams struct {
+	pagination.Request
+	InStockOnly bool
+	MinPrice    *int32
+	MaxPrice    *int32
+	Title       *string
 }
 
 type SKUResponse struct {
@@ -28,7 +54,11 @@ type SKUResponse struct {
 	BookID        int64     `json:"book_id"`
 	StoreID       int64     `json:"store_id"`
 	PriceInKopeks int32     `json:"price_in_kopeks"`
+	Currency      string    `json:"currency" example:"RUB"`
 	StockCount    int32     `json:"stock_count"`
+	Reserved      int32     `json:"reserved"`
+	Available     int32     `json:"available"`
+	Version       int32     `json:"version"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 }
@@ -37,3 +67,42 @@ type SKUWithBookResponse struct {
 	SKU  SKUResponse        `json:"sku"`
 	Book books.BookResponse `json:"book"`
 }
+
+type SKUListResponse struct {
+	Items      []SKUWithBookResponse `json:"items"`
+	NextCursor *string               `json:"next_cursor"`
+}
+
+type StockMovementResponse struct {
+	UUID      uuid.UUID `json:"uuid"`
+	Delta     int32     `json:"delta"`
+	Balance   int32     `json:"balance"`
+	Reason    string    `json:"reason"`
+	Note      *string   `json:"note,omitempty"`
+	Actor     *string   `json:"actor,omitempty"`
+	RequestID *string   `json:"request_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type StockMovementListResponse struct {
+	Items      []StockMovementResponse `json:"items"`
+	NextCursor *string                 `json:"next_cursor"`
+}
+
+type SKUPriceResponse struct {
+	UUID          uuid.UUID  `json:"uuid"`
+	PriceInKopeks int32      `json:"price_in_kopeks"`
+	Status        string     `json:"status"                   enums:"scheduled,active,superseded,cancelled"`
+	ScheduledFor  *time.Time `json:"scheduled_for,omitempty"`
+	EffectiveFrom *time.Time `json:"effective_from,omitempty"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+	CancelledAt   *time.Time `json:"cancelled_at,omitempty"`
+	Actor         *string    `json:"actor,omitempty"`
+	RequestID     *string    `json:"request_id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type SKUPriceListResponse struct {
+	Items      []SKUPriceResponse `json:"items"`
+	NextCursor *string            `json:"next_cursor"`
+}
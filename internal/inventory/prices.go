@@ -0,0 +1,66 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/audit"
+	appMiddleware "github.com/nikallow/bookstores-api/internal/middleware"
+)
+
+// Statuses of a price in the history of a SKU, derived from its timestamps.
+const (
+	PriceScheduled  = "scheduled"
+	PriceActive     = "active"
+	PriceSuperseded = "superseded"
+	PriceCancelled  = "cancelled"
+)
+
+// setPrice makes newPrice the current price of a SKU locked with LockSKU: the current
+// entry of the price history is closed and a new one opened at the same moment.
+func setPrice(ctx context.Context, q *repo.Queries, sku repo.Sku, newPrice int32) (repo.Sku, error) {
+	if err := q.CloseCurrentSKUPrice(ctx, sku.ID); err != nil {
+		return repo.Sku{}, err
+	}
+	if _, err := recordPrice(ctx, q, sku.ID, newPrice, nil); err != nil {
+		return repo.Sku{}, err
+	}
+	return q.UpdateSKUPrice(ctx, repo.UpdateSKUPriceParams{
+		Uuid:          sku.Uuid,
+		PriceInKopeks: newPrice,
+	})
+}
+
+// PriceStatus tells where a price is in its lifecycle.
+func PriceStatus(p repo.SkuPrice) string {
+	switch {
+	case p.CancelledAt.Valid:
+		return PriceCancelled
+	case !p.EffectiveFrom.Valid:
+		return PriceScheduled
+	case p.EffectiveTo.Valid:
+		return PriceSuperseded
+	default:
+		return PriceActive
+	}
+}
+
+// recordPrice adds an entry to the price history: the current price when scheduledFor is nil,
+// a pending one otherwise.
+func recordPrice(ctx context.Context, q *repo.Queries, skuID int64, price int32, scheduledFor *time.Time) (repo.SkuPrice, error) {
+	return q.CreateSKUPrice(ctx, repo.CreateSKUPriceParams{
+		SkuID:         skuID,
+		PriceInKopeks: price,
+		ScheduledFor:  timeToPgTimestamptzp(scheduledFor),
+		Actor:         stringToPgText(appMiddleware.ActorFromContext(ctx)),
+		RequestID:     stringToPgText(middleware.GetReqID(ctx)),
+	})
+}
+
+// recordSKUPrice adds the audit event of a change of a price in the history; before is nil for a created one.
+func recordSKUPrice(ctx context.Context, q *repo.Queries, action audit.Action, before any, after repo.SkuPrice) error {
+	return audit.Record(ctx, q, audit.EntitySKUPrice, uuid.UUID(after.Uuid.Bytes).String(), action, before, after)
+}
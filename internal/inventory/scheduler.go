@@ -0,0 +1,56 @@
+package inventory
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/nikallow/bookstores-api/internal/config"
+	appMiddleware "github.com/nikallow/bookstores-api/internal/middleware"
+)
+
+// PriceScheduler periodically applies scheduled prices whose time has come.
+type PriceScheduler struct {
+	service  Service
+	interval time.Duration
+	log      *slog.Logger
+}
+
+func NewPriceScheduler(service Service, cfg config.PricesConfig, log *slog.Logger) *PriceScheduler {
+	return &PriceScheduler{
+		service:  service,
+		interval: cfg.ApplyInterval,
+		log:      log.With("component", "price_scheduler"),
+	}
+}
+
+// Run applies due prices every interval until ctx is cancelled.
+func (s *PriceScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	ctx = appMiddleware.WithLogger(ctx, s.log)
+	s.log.Info("Price scheduler started", "interval", s.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("Price scheduler stopped")
+			return
+		case <-ticker.C:
+			s.apply(ctx)
+		}
+	}
+}
+
+func (s *PriceScheduler) apply(ctx context.Context) {
+	applied, err := s.service.ApplyDuePrices(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.log.Error("Failed to apply scheduled prices", "error", err, "applied", applied)
+		}
+		return
+	}
+	if applied > 0 {
+		s.log.Info("Applied scheduled prices", "count", applied)
+	}
+}
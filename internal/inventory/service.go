@@ -3,43 +3,95 @@ package inventory
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nikallow/bookstores-api/internal/adapters/postgres"
 	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
-	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/audit"
+	"github.com/nikallow/bookstores-api/internal/config"
+	appMiddleware "github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+	"github.com/nikallow/bookstores-api/internal/stores"
 )
 
 var (
-	ErrStoreNotFound     = errors.New("store not found")
+	ErrStoreNotFound     = stores.ErrStoreNotFound
 	ErrBookNotFound      = errors.New("book not found")
 	ErrSKUNotFound       = errors.New("sku not found")
 	ErrSKUAlreadyExists  = errors.New("this book already exists in this store")
 	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrVersionMismatch   = errors.New("sku was modified by another request")
+	ErrReasonMismatch    = errors.New("change_by doesn't match the reason: receipt and return add stock, sale and write_off remove it")
+
+	ErrPriceNotFound         = errors.New("price not found")
+	ErrPriceNotScheduled     = errors.New("price is not scheduled")
+	ErrPriceAlreadyScheduled = errors.New("another price is scheduled for the same time")
+	ErrEffectiveAtInPast     = errors.New("effective_at must be in the future")
 )
 
+// pgCheckViolation is the SQLSTATE of a failed CHECK constraint, e.g. stock_count >= 0.
+const pgCheckViolation = "23514"
+
+// pgUniqueViolation is the SQLSTATE of a failed UNIQUE constraint, e.g. (book_id, store_id).
+const pgUniqueViolation = "23505"
+
 type Service interface {
 	CreateSKU(ctx context.Context, params CreateSKURequest) (repo.Sku, error)
 	GetSKU(ctx context.Context, skuUUID uuid.UUID) (repo.GetSKUByUUIDRow, error)
-	UpdateSKUPrice(ctx context.Context, skuUUID uuid.UUID, newPrice int32) (repo.Sku, error)
-	AdjustSKUStock(ctx context.Context, skuUUID uuid.UUID, changeBy int32) (repo.Sku, error)
+	UpdateSKUPrice(ctx context.Context, skuUUID uuid.UUID, newPrice int32, expectedVersion *int32) (repo.Sku, error)
+	ScheduleSKUPrice(ctx context.Context, skuUUID uuid.UUID, newPrice int32, effectiveAt time.Time, expectedVersion *int32) (repo.SkuPrice, error)
+	CancelSKUPrice(ctx context.Context, skuUUID, priceUUID uuid.UUID) (repo.SkuPrice, error)
+	ListSKUPrices(ctx context.Context, skuUUID uuid.UUID, params ListSKUPricesParams) ([]repo.SkuPrice, *string, error)
+	ApplyDuePrices(ctx context.Context) (int, error)
+	AdjustSKUStock(ctx context.Context, skuUUID uuid.UUID, params AdjustSKUStockRequest, expectedVersion *int32) (repo.Sku, error)
+	ListStockMovements(ctx context.Context, skuUUID uuid.UUID, params ListStockMovementsParams) ([]repo.StockMovement, *string, error)
+	ListStoreSKUs(ctx context.Context, storeUUID uuid.UUID, params ListStoreSKUsParams) ([]StoreSKU, *string, error)
 }
 
 type service struct {
 	repo repo.Querier
-	db   *pgx.Conn
+	db   postgres.TxBeginner
+	cfg  config.PricesConfig
 }
 
-func NewService(repo repo.Querier, db *pgx.Conn) Service {
-	return &service{repo: repo, db: db}
+func NewService(repo repo.Querier, db postgres.TxBeginner, cfg config.PricesConfig) Service {
+	return &service{repo: repo, db: db, cfg: cfg}
 }
 
 // CreateSKU - POST /skus
 func (s *service) CreateSKU(ctx context.Context, params CreateSKURequest) (repo.Sku, error) {
-	log := middleware.LoggerFromContext(ctx)
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.Sku{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	sku, err := CreateSKU(ctx, repo.New(tx), params)
+	if err != nil {
+		return repo.Sku{}, err
+	}
 
-	if _, err := s.repo.GetBookByID(ctx, params.BookID); err != nil {
+	if err := tx.Commit(ctx); err != nil {
+		return repo.Sku{}, err
+	}
+
+	log.Info("SKU created successfully", "sku_id", sku.ID)
+	return sku, nil
+}
+
+// CreateSKU is Service.CreateSKU on q, for callers that make the SKU part of a larger transaction.
+func CreateSKU(ctx context.Context, q *repo.Queries, params CreateSKURequest) (repo.Sku, error) {
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	if _, err := q.GetBookByID(ctx, params.BookID); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return repo.Sku{}, ErrBookNotFound
 		}
@@ -47,7 +99,7 @@ func (s *service) CreateSKU(ctx context.Context, params CreateSKURequest) (repo.
 		return repo.Sku{}, err
 	}
 
-	store, err := s.repo.GetStoreByUUID(ctx, uuidToPgUUID(params.StoreUUID))
+	store, err := q.GetStoreByUUID(ctx, uuidToPgUUID(params.StoreUUID))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return repo.Sku{}, ErrStoreNotFound
@@ -56,7 +108,7 @@ func (s *service) CreateSKU(ctx context.Context, params CreateSKURequest) (repo.
 		return repo.Sku{}, err
 	}
 
-	_, err = s.repo.GetSKUByBookAndStore(ctx, repo.GetSKUByBookAndStoreParams{
+	_, err = q.GetSKUByBookAndStore(ctx, repo.GetSKUByBookAndStoreParams{
 		BookID:  params.BookID,
 		StoreID: store.ID,
 	})
@@ -68,18 +120,17 @@ func (s *service) CreateSKU(ctx context.Context, params CreateSKURequest) (repo.
 		return repo.Sku{}, err
 	}
 
-	sku, err := s.repo.CreateSKU(ctx, repo.CreateSKUParams{
+	sku, err := InsertSKU(ctx, q, repo.CreateSKUParams{
 		BookID:        params.BookID,
 		StoreID:       store.ID,
 		PriceInKopeks: params.PriceInKopeks,
 		StockCount:    params.StockCount,
+		Currency:      store.Currency,
 	})
 	if err != nil {
 		log.Error("failed to create sku", "error", err)
 		return repo.Sku{}, err
 	}
-
-	log.Info("SKU created successfully", "sku_id", sku.ID)
 	return sku, nil
 }
 
@@ -94,63 +145,478 @@ func (s *service) GetSKU(ctx context.Context, skuUUID uuid.UUID) (repo.GetSKUByU
 	return row, nil
 }
 
-func (s *service) UpdateSKUPrice(ctx context.Context, skuUUID uuid.UUID, newPrice int32) (repo.Sku, error) {
-	log := middleware.LoggerFromContext(ctx)
+// UpdateSKUPrice sets a new price and records it in the price history. If expectedVersion
+// is given and the SKU has changed since, ErrVersionMismatch is returned.
+func (s *service) UpdateSKUPrice(ctx context.Context, skuUUID uuid.UUID, newPrice int32, expectedVersion *int32) (repo.Sku, error) {
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.Sku{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
 
-	_, err := s.GetSKU(ctx, skuUUID)
+	sku, err := LockSKU(ctx, qtx, skuUUID, expectedVersion)
 	if err != nil {
 		return repo.Sku{}, err
 	}
 
-	sku, err := s.repo.UpdateSKUPrice(ctx, repo.UpdateSKUPriceParams{
-		Uuid:          uuidToPgUUID(skuUUID),
-		PriceInKopeks: newPrice,
-	})
+	updatedSKU, err := setPrice(ctx, qtx, sku, newPrice)
 	if err != nil {
 		log.Error("Failed to update sku price", "error", err, "sku_uuid", skuUUID)
 		return repo.Sku{}, err
 	}
-	return sku, nil
+	if err := recordSKU(ctx, qtx, audit.ActionUpdate, sku, updatedSKU); err != nil {
+		return repo.Sku{}, err
+	}
+	return updatedSKU, tx.Commit(ctx)
 }
 
-func (s *service) AdjustSKUStock(ctx context.Context, skuUUID uuid.UUID, changeBy int32) (repo.Sku, error) {
-	log := middleware.LoggerFromContext(ctx)
+// ScheduleSKUPrice plans a price change for effectiveAt, the scheduler applies it once it is due.
+// The SKU itself doesn't change until then, so its version stays the same.
+func (s *service) ScheduleSKUPrice(ctx context.Context, skuUUID uuid.UUID, newPrice int32, effectiveAt time.Time, expectedVersion *int32) (repo.SkuPrice, error) {
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	if !effectiveAt.After(time.Now()) {
+		return repo.SkuPrice{}, ErrEffectiveAtInPast
+	}
 
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		return repo.Sku{}, err
+		return repo.SkuPrice{}, err
 	}
 	defer tx.Rollback(ctx)
 
 	qtx := repo.New(tx)
 
-	skuRow, err := qtx.GetSKUByUUID(ctx, uuidToPgUUID(skuUUID))
+	sku, err := LockSKU(ctx, qtx, skuUUID, expectedVersion)
+	if err != nil {
+		return repo.SkuPrice{}, err
+	}
+
+	price, err := recordPrice(ctx, qtx, sku.ID, newPrice, &effectiveAt)
+	if err != nil {
+		if isPgError(err, pgUniqueViolation) {
+			return repo.SkuPrice{}, ErrPriceAlreadyScheduled
+		}
+		log.Error("Failed to schedule sku price", "error", err, "sku_uuid", skuUUID)
+		return repo.SkuPrice{}, err
+	}
+	if err := recordSKUPrice(ctx, qtx, audit.ActionCreate, nil, price); err != nil {
+		return repo.SkuPrice{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return repo.SkuPrice{}, err
+	}
+
+	log.Info("SKU price scheduled", "sku_uuid", skuUUID, "price_uuid", mustConvertUUID(price.Uuid), "effective_at", effectiveAt)
+	return price, nil
+}
+
+// CancelSKUPrice withdraws a scheduled price that has not been applied yet.
+func (s *service) CancelSKUPrice(ctx context.Context, skuUUID, priceUUID uuid.UUID) (repo.SkuPrice, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.SkuPrice{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+
+	// The scheduler locks the SKU before applying its prices, so holding the lock
+	// means the price can't be applied while it's being cancelled.
+	sku, err := LockSKU(ctx, qtx, skuUUID, nil)
+	if err != nil {
+		return repo.SkuPrice{}, err
+	}
+
+	price, err := qtx.GetSKUPriceByUUID(ctx, repo.GetSKUPriceByUUIDParams{
+		Uuid:  uuidToPgUUID(priceUUID),
+		SkuID: sku.ID,
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			log.Error("SKU not found", "error", err)
-			return repo.Sku{}, ErrSKUNotFound
+			return repo.SkuPrice{}, ErrPriceNotFound
 		}
-		log.Error("Failed to get SKU by uuid", "error", err)
-		return repo.Sku{}, err
+		return repo.SkuPrice{}, err
+	}
+	if status := PriceStatus(price); status != PriceScheduled {
+		return repo.SkuPrice{}, fmt.Errorf("%w: %s", ErrPriceNotScheduled, status)
+	}
+
+	cancelled, err := qtx.CancelSKUPrice(ctx, price.ID)
+	if err != nil {
+		return repo.SkuPrice{}, err
+	}
+	if err := recordSKUPrice(ctx, qtx, audit.ActionUpdate, price, cancelled); err != nil {
+		return repo.SkuPrice{}, err
+	}
+	return cancelled, tx.Commit(ctx)
+}
+
+// ListSKUPrices - GET /skus/{skuUUID}/price-history
+func (s *service) ListSKUPrices(ctx context.Context, skuUUID uuid.UUID, params ListSKUPricesParams) ([]repo.SkuPrice, *string, error) {
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	cursor, err := params.DecodeCursor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	skuRow, err := s.GetSKU(ctx, skuUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queryParams := repo.ListSKUPricesParams{
+		SkuID:     skuRow.Sku.ID,
+		Status:    stringToPgTextp(params.Status),
+		PageLimit: params.QueryLimit(),
+	}
+	if cursor != nil {
+		t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: bad timestamp", pagination.ErrInvalidCursor)
+		}
+		queryParams.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+		queryParams.CursorTime = pgtype.Timestamptz{Time: t, Valid: true}
+	}
+
+	var prices []repo.SkuPrice
+	if params.Desc {
+		prices, err = s.repo.ListSKUPricesDesc(ctx, repo.ListSKUPricesDescParams(queryParams))
+	} else {
+		prices, err = s.repo.ListSKUPrices(ctx, queryParams)
+	}
+	if err != nil {
+		log.Error("Failed to list sku prices", "error", err, "sku_uuid", skuUUID)
+		return nil, nil, err
+	}
+
+	prices, hasMore := pagination.Trim(prices, params.Request)
+	if !hasMore {
+		return prices, nil, nil
+	}
+	last := prices[len(prices)-1]
+	next := params.NextCursor(last.CreatedAt.Time.Format(time.RFC3339Nano), last.ID)
+	return prices, &next, nil
+}
+
+// ApplyDuePrices makes the scheduled prices whose time has come current, oldest first.
+// It returns how many were applied.
+func (s *service) ApplyDuePrices(ctx context.Context) (int, error) {
+	due, err := s.repo.ListDueSKUPrices(ctx, s.cfg.ApplyBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	applied := 0
+	for _, p := range due {
+		ok, err := s.applyPrice(ctx, p.ID, p.SkuUuid.Bytes)
+		if err != nil {
+			if ctx.Err() != nil {
+				return applied, ctx.Err()
+			}
+			log.Error("Failed to apply scheduled price", "error", err, "price_id", p.ID)
+			continue
+		}
+		if ok {
+			applied++
+		}
+	}
+	return applied, nil
+}
+
+func (s *service) applyPrice(ctx context.Context, priceID int64, skuUUID uuid.UUID) (bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, err
 	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
 
-	if skuRow.Sku.StockCount+changeBy < 0 {
-		log.Error("SKU stock count is negative", "error", err)
-		return repo.Sku{}, ErrInsufficientStock
+	sku, err := LockSKU(ctx, qtx, skuUUID, nil)
+	if err != nil {
+		if errors.Is(err, ErrSKUNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	price, err := qtx.GetSKUPriceByIDForUpdate(ctx, priceID)
+	if err != nil {
+		return false, err
+	}
+	if PriceStatus(price) != PriceScheduled || price.ScheduledFor.Time.After(time.Now()) {
+		return false, nil
 	}
 
-	updatedSKU, err := qtx.AdjustSKUStock(ctx, repo.AdjustSKUStockParams{
-		Uuid:     uuidToPgUUID(skuUUID),
-		ChangeBy: changeBy,
+	if err := qtx.CloseCurrentSKUPrice(ctx, sku.ID); err != nil {
+		return false, err
+	}
+	if _, err := qtx.ActivateSKUPrice(ctx, price.ID); err != nil {
+		return false, err
+	}
+	updatedSKU, err := qtx.UpdateSKUPrice(ctx, repo.UpdateSKUPriceParams{
+		Uuid:          sku.Uuid,
+		PriceInKopeks: price.PriceInKopeks,
 	})
 	if err != nil {
-		log.Error("Failed to adjust sku stock", "error", err, "sku_uuid", skuUUID)
+		return false, err
+	}
+	if err := recordSKU(ctx, qtx, audit.ActionUpdate, sku, updatedSKU); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+// AdjustSKUStock changes the stock by params.ChangeBy. The SKU row is locked for the
+// duration of the transaction, so concurrent decrements can't both pass the stock check.
+func (s *service) AdjustSKUStock(ctx context.Context, skuUUID uuid.UUID, params AdjustSKUStockRequest, expectedVersion *int32) (repo.Sku, error) {
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	reason := repo.StockMovementReasonCorrection
+	if params.Reason != "" {
+		reason = repo.StockMovementReason(params.Reason)
+	}
+	if !reasonAllows(reason, params.ChangeBy) {
+		return repo.Sku{}, ErrReasonMismatch
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.Sku{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+
+	sku, err := LockSKU(ctx, qtx, skuUUID, expectedVersion)
+	if err != nil {
+		return repo.Sku{}, err
+	}
+
+	updatedSKU, err := ApplyStockChange(ctx, qtx, sku, params.ChangeBy, reason, params.Note)
+	if err != nil {
+		if errors.Is(err, ErrInsufficientStock) {
+			log.Warn("Insufficient stock", "sku_uuid", skuUUID, "stock_count", sku.StockCount, "change_by", params.ChangeBy)
+		} else {
+			log.Error("Failed to adjust sku stock", "error", err, "sku_uuid", skuUUID)
+		}
 		return repo.Sku{}, err
 	}
 
 	return updatedSKU, tx.Commit(ctx)
 }
 
+// reasonAllows reports whether a manual adjustment with the reason may change the stock by delta.
+// Transfers are recorded only by the transfers themselves.
+func reasonAllows(reason repo.StockMovementReason, delta int32) bool {
+	switch reason {
+	case repo.StockMovementReasonReceipt, repo.StockMovementReasonReturn:
+		return delta > 0
+	case repo.StockMovementReasonSale, repo.StockMovementReasonWriteOff:
+		return delta < 0
+	case repo.StockMovementReasonCorrection:
+		return delta != 0
+	}
+	return false
+}
+
+// ListStockMovements - GET /skus/{skuUUID}/movements
+func (s *service) ListStockMovements(ctx context.Context, skuUUID uuid.UUID, params ListStockMovementsParams) ([]repo.StockMovement, *string, error) {
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	cursor, err := params.DecodeCursor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	skuRow, err := s.GetSKU(ctx, skuUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queryParams := repo.ListStockMovementsParams{
+		SkuID:     skuRow.Sku.ID,
+		FromTime:  timeToPgTimestamptzp(params.From),
+		ToTime:    timeToPgTimestamptzp(params.To),
+		PageLimit: params.QueryLimit(),
+	}
+	if cursor != nil {
+		t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: bad timestamp", pagination.ErrInvalidCursor)
+		}
+		queryParams.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+		queryParams.CursorTime = pgtype.Timestamptz{Time: t, Valid: true}
+	}
+
+	var movements []repo.StockMovement
+	if params.Desc {
+		movements, err = s.repo.ListStockMovementsDesc(ctx, repo.ListStockMovementsDescParams(queryParams))
+	} else {
+		movements, err = s.repo.ListStockMovements(ctx, queryParams)
+	}
+	if err != nil {
+		log.Error("Failed to list stock movements", "error", err, "sku_uuid", skuUUID)
+		return nil, nil, err
+	}
+
+	movements, hasMore := pagination.Trim(movements, params.Request)
+	if !hasMore {
+		return movements, nil, nil
+	}
+	last := movements[len(movements)-1]
+	next := params.NextCursor(last.CreatedAt.Time.Format(time.RFC3339Nano), last.ID)
+	return movements, &next, nil
+}
+
+// ListStoreSKUs - GET /stores/{storeUUID}/skus
+func (s *service) ListStoreSKUs(ctx context.Context, storeUUID uuid.UUID, params ListStoreSKUsParams) ([]StoreSKU, *string, error) {
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	cursor, err := params.DecodeCursor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	store, err := s.repo.GetStoreByUUID(ctx, uuidToPgUUID(storeUUID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrStoreNotFound
+		}
+		log.Error("Failed to get store by uuid", "error", err)
+		return nil, nil, err
+	}
+
+	var rows []StoreSKU
+	if params.SortBy == "title" {
+		queryParams := repo.ListSKUsInStoreByTitleParams{
+			StoreID:     store.ID,
+			InStockOnly: params.InStockOnly,
+			MinPrice:    int32ToPgInt4p(params.MinPrice),
+			MaxPrice:    int32ToPgInt4p(params.MaxPrice),
+			Title:       stringToPgTextp(params.Title),
+			PageLimit:   params.QueryLimit(),
+		}
+		if cursor != nil {
+			queryParams.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+			queryParams.CursorText = pgtype.Text{String: cursor.Value, Valid: true}
+		}
+		if params.Desc {
+			rows, err = storeSKUs(s.repo.ListSKUsInStoreByTitleDesc(ctx, repo.ListSKUsInStoreByTitleDescParams(queryParams)))
+		} else {
+			rows, err = storeSKUs(s.repo.ListSKUsInStoreByTitle(ctx, queryParams))
+		}
+	} else {
+		queryParams := repo.ListSKUsInStoreByPriceParams{
+			StoreID:     store.ID,
+			InStockOnly: params.InStockOnly,
+			MinPrice:    int32ToPgInt4p(params.MinPrice),
+			MaxPrice:    int32ToPgInt4p(params.MaxPrice),
+			Title:       stringToPgTextp(params.Title),
+			PageLimit:   params.QueryLimit(),
+		}
+		if cursor != nil {
+			v, err := strconv.ParseInt(cursor.Value, 10, 32)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%w: bad %s", pagination.ErrInvalidCursor, cursor.SortBy)
+			}
+			queryParams.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+			queryParams.CursorInt = pgtype.Int4{Int32: int32(v), Valid: true}
+		}
+		switch {
+		case params.SortBy == "price" && params.Desc:
+			rows, err = storeSKUs(s.repo.ListSKUsInStoreByPriceDesc(ctx, repo.ListSKUsInStoreByPriceDescParams(queryParams)))
+		case params.SortBy == "price":
+			rows, err = storeSKUs(s.repo.ListSKUsInStoreByPrice(ctx, queryParams))
+		case params.Desc:
+			rows, err = storeSKUs(s.repo.ListSKUsInStoreByStockDesc(ctx, repo.ListSKUsInStoreByStockDescParams(queryParams)))
+		default:
+			rows, err = storeSKUs(s.repo.ListSKUsInStoreByStock(ctx, repo.ListSKUsInStoreByStockParams(queryParams)))
+		}
+	}
+	if err != nil {
+		log.Error("Failed to list skus in store", "error", err, "store_uuid", storeUUID)
+		return nil, nil, err
+	}
+
+	rows, hasMore := pagination.Trim(rows, params.Request)
+	if !hasMore {
+		return rows, nil, nil
+	}
+	last := rows[len(rows)-1]
+	var value string
+	switch params.SortBy {
+	case "price":
+		value = strconv.Itoa(int(last.Sku.PriceInKopeks))
+	case "stock":
+		value = strconv.Itoa(int(last.Sku.StockCount))
+	default:
+		value = last.Book.Title
+	}
+	next := params.NextCursor(value, last.Sku.ID)
+	return rows, &next, nil
+}
+
+// StoreSKU is a SKU of a store with its book, a row of any of the ListSKUsInStore queries.
+type StoreSKU struct {
+	Sku  repo.Sku  `json:"sku"`
+	Book repo.Book `json:"book"`
+}
+
+// storeSKUs converts the rows of a ListSKUsInStore query, the queries differ only in their sort.
+func storeSKUs[T ~struct {
+	Sku  repo.Sku  `json:"sku"`
+	Book repo.Book `json:"book"`
+}](rows []T, err error) ([]StoreSKU, error) {
+	if err != nil {
+		return nil, err
+	}
+	skus := make([]StoreSKU, len(rows))
+	for i, row := range rows {
+		skus[i] = StoreSKU(row)
+	}
+	return skus, nil
+}
+
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
+
+func stringToPgText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+func stringToPgTextp(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{Valid: false}
+	}
+	return pgtype.Text{String: *s, Valid: true}
+}
+
+func timeToPgTimestamptzp(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{Valid: false}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
+
+func int32ToPgInt4p(i *int32) pgtype.Int4 {
+	if i == nil {
+		return pgtype.Int4{Valid: false}
+	}
+	return pgtype.Int4{Int32: *i, Valid: true}
+}
+
 func uuidToPgUUID(u uuid.UUID) pgtype.UUID {
 	return pgtype.UUID{Bytes: u, Valid: true}
 }
@@ -0,0 +1,140 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/config"
+	"github.com/nikallow/bookstores-api/internal/database/dbtest"
+	appMiddleware "github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+)
+
+func TestAdjustSKUStock(t *testing.T) {
+	db := dbtest.New(t)
+	svc := NewService(repo.New(db), db, config.PricesConfig{})
+	ctx := appMiddleware.WithActor(context.Background(), "clerk@example.com")
+
+	bookID := dbtest.CreateBook(t, db)
+
+	tests := []struct {
+		name       string
+		changeBy   int32
+		reason     string
+		version    *int32
+		wantStock  int32
+		wantReason repo.StockMovementReason
+		wantErr    error
+	}{
+		{"receipt", 5, "receipt", nil, 15, repo.StockMovementReasonReceipt, nil},
+		{"sale", -3, "sale", nil, 7, repo.StockMovementReasonSale, nil},
+		{"whole stock", -10, "write_off", nil, 0, repo.StockMovementReasonWriteOff, nil},
+		{"no reason is a correction", 2, "", nil, 12, repo.StockMovementReasonCorrection, nil},
+		{"negative correction", -2, "correction", nil, 8, repo.StockMovementReasonCorrection, nil},
+		{"receipt removing stock", -1, "receipt", nil, 10, "", ErrReasonMismatch},
+		{"sale adding stock", 1, "sale", nil, 10, "", ErrReasonMismatch},
+		{"manual transfer", 1, "transfer", nil, 10, "", ErrReasonMismatch},
+		{"more than in stock", -11, "sale", nil, 10, "", ErrInsufficientStock},
+		{"current version", 1, "receipt", ptr[int32](1), 11, repo.StockMovementReasonReceipt, nil},
+		{"stale version", 1, "receipt", ptr[int32](2), 10, "", ErrVersionMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, storeUUID := dbtest.CreateStore(t, db)
+			sku, err := svc.CreateSKU(ctx, CreateSKURequest{BookID: bookID, StoreUUID: storeUUID, PriceInKopeks: 50000, StockCount: 10})
+			if err != nil {
+				t.Fatalf("CreateSKU() error = %v", err)
+			}
+			skuUUID := uuid.UUID(sku.Uuid.Bytes)
+
+			_, err = svc.AdjustSKUStock(ctx, skuUUID, AdjustSKUStockRequest{ChangeBy: tt.changeBy, Reason: tt.reason}, tt.version)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AdjustSKUStock() error = %v, want %v", err, tt.wantErr)
+			}
+
+			row, err := svc.GetSKU(ctx, skuUUID)
+			if err != nil {
+				t.Fatalf("GetSKU() error = %v", err)
+			}
+			if row.Sku.StockCount != tt.wantStock {
+				t.Errorf("stock = %d, want %d", row.Sku.StockCount, tt.wantStock)
+			}
+
+			movements, _, err := svc.ListStockMovements(ctx, skuUUID, ListStockMovementsParams{
+				Request: pagination.Request{Limit: pagination.DefaultLimit},
+			})
+			if err != nil {
+				t.Fatalf("ListStockMovements() error = %v", err)
+			}
+			// The opening stock is the first movement; a rejected change leaves no trace.
+			wantMovements := 2
+			if tt.wantErr != nil {
+				wantMovements = 1
+			}
+			if len(movements) != wantMovements {
+				t.Fatalf("got %d movements, want %d", len(movements), wantMovements)
+			}
+			opening := movements[0]
+			if opening.Delta != 10 || opening.Balance != 10 || opening.Reason != repo.StockMovementReasonReceipt {
+				t.Errorf("opening movement = %+d/%d %s, want +10/10 receipt", opening.Delta, opening.Balance, opening.Reason)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			m := movements[1]
+			if m.Delta != tt.changeBy || m.Balance != tt.wantStock || m.Reason != tt.wantReason {
+				t.Errorf("movement = %+d/%d %s, want %+d/%d %s", m.Delta, m.Balance, m.Reason, tt.changeBy, tt.wantStock, tt.wantReason)
+			}
+			if m.Actor.String != "clerk@example.com" {
+				t.Errorf("movement actor = %q, want clerk@example.com", m.Actor.String)
+			}
+		})
+	}
+}
+
+func TestReasonAllows(t *testing.T) {
+	tests := []struct {
+		reason repo.StockMovementReason
+		delta  int32
+		want   bool
+	}{
+		{repo.StockMovementReasonReceipt, 1, true},
+		{repo.StockMovementReasonReceipt, -1, false},
+		{repo.StockMovementReasonReturn, 1, true},
+		{repo.StockMovementReasonReturn, -1, false},
+		{repo.StockMovementReasonSale, -1, true},
+		{repo.StockMovementReasonSale, 1, false},
+		{repo.StockMovementReasonWriteOff, -1, true},
+		{repo.StockMovementReasonWriteOff, 1, false},
+		{repo.StockMovementReasonCorrection, 1, true},
+		{repo.StockMovementReasonCorrection, -1, true},
+		{repo.StockMovementReasonCorrection, 0, false},
+		{repo.StockMovementReasonTransfer, 1, false},
+		{repo.StockMovementReasonTransfer, -1, false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %+d", tt.reason, tt.delta), func(t *testing.T) {
+			if got := reasonAllows(tt.reason, tt.delta); got != tt.want {
+				t.Errorf("reasonAllows(%s, %d) = %v, want %v", tt.reason, tt.delta, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdjustSKUStockUnknownSKU(t *testing.T) {
+	db := dbtest.New(t)
+	svc := NewService(repo.New(db), db, config.PricesConfig{})
+
+	_, err := svc.AdjustSKUStock(context.Background(), uuid.New(), AdjustSKUStockRequest{ChangeBy: 1}, nil)
+	if !errors.Is(err, ErrSKUNotFound) {
+		t.Errorf("AdjustSKUStock() error = %v, want %v", err, ErrSKUNotFound)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
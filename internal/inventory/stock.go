@@ -0,0 +1,146 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/audit"
+	appMiddleware "github.com/nikallow/bookstores-api/internal/middleware"
+)
+
+// InsertSKU creates a SKU, records its opening stock as a receipt, starts its price history
+// and adds its audit event. A SKU for the same book and store yields ErrSKUAlreadyExists.
+func InsertSKU(ctx context.Context, q *repo.Queries, params repo.CreateSKUParams) (repo.Sku, error) {
+	sku, err := q.CreateSKU(ctx, params)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return repo.Sku{}, ErrSKUAlreadyExists
+		}
+		return repo.Sku{}, err
+	}
+
+	if err := recordMovement(ctx, q, sku, sku.StockCount, repo.StockMovementReasonReceipt, nil); err != nil {
+		return repo.Sku{}, err
+	}
+	if _, err := recordPrice(ctx, q, sku.ID, sku.PriceInKopeks, nil); err != nil {
+		return repo.Sku{}, err
+	}
+	if err := recordSKU(ctx, q, audit.ActionCreate, nil, sku); err != nil {
+		return repo.Sku{}, err
+	}
+	return sku, nil
+}
+
+// ApplyStockChange changes the stock of a SKU locked with LockSKU, records the movement
+// in the ledger and adds the audit event of the SKU. Reserved copies can't be written off,
+// only the available ones.
+func ApplyStockChange(ctx context.Context, q *repo.Queries, sku repo.Sku, delta int32, reason repo.StockMovementReason, note *string) (repo.Sku, error) {
+	if Available(sku)+delta < 0 {
+		return repo.Sku{}, ErrInsufficientStock
+	}
+
+	updatedSKU, err := q.AdjustSKUStock(ctx, repo.AdjustSKUStockParams{
+		Uuid:     sku.Uuid,
+		ChangeBy: delta,
+	})
+	if err != nil {
+		return repo.Sku{}, translateStockError(err)
+	}
+
+	if err := recordMovement(ctx, q, updatedSKU, delta, reason, note); err != nil {
+		return repo.Sku{}, err
+	}
+	if err := recordSKU(ctx, q, audit.ActionUpdate, sku, updatedSKU); err != nil {
+		return repo.Sku{}, err
+	}
+	return updatedSKU, nil
+}
+
+// AdjustReserved changes the reserved copies of a locked SKU by delta, for holds and orders
+// taking stock or giving it back, and adds the audit event of the SKU.
+func AdjustReserved(ctx context.Context, q *repo.Queries, sku repo.Sku, delta int32) (repo.Sku, error) {
+	updatedSKU, err := q.AdjustSKUReserved(ctx, repo.AdjustSKUReservedParams{
+		ID:       sku.ID,
+		ChangeBy: delta,
+	})
+	if err != nil {
+		return repo.Sku{}, err
+	}
+
+	if err := recordSKU(ctx, q, audit.ActionUpdate, sku, updatedSKU); err != nil {
+		return repo.Sku{}, err
+	}
+	return updatedSKU, nil
+}
+
+// Available is the number of copies that can be sold or reserved: stock minus active holds.
+func Available(sku repo.Sku) int32 {
+	return sku.StockCount - sku.ReservedCount
+}
+
+// LockSKU takes a row lock on the SKU until the end of the caller's transaction and,
+// if expectedVersion is set, checks that the SKU hasn't changed since.
+func LockSKU(ctx context.Context, q *repo.Queries, skuUUID uuid.UUID, expectedVersion *int32) (repo.Sku, error) {
+	sku, err := q.GetSKUByUUIDForUpdate(ctx, uuidToPgUUID(skuUUID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Sku{}, ErrSKUNotFound
+		}
+		return repo.Sku{}, err
+	}
+	if expectedVersion != nil && *expectedVersion != sku.Version {
+		return repo.Sku{}, ErrVersionMismatch
+	}
+	return sku, nil
+}
+
+// LockSKUByID takes a row lock on the SKU like LockSKU, also on a deleted one: orders, holds
+// and transfers taken before the deletion must still be able to release their stock.
+func LockSKUByID(ctx context.Context, q *repo.Queries, id int64) (repo.Sku, error) {
+	sku, err := q.GetSKUByIDForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Sku{}, ErrSKUNotFound
+		}
+		return repo.Sku{}, err
+	}
+	return sku, nil
+}
+
+// translateStockError maps a rejected stock update to ErrInsufficientStock: either the
+// guarded UPDATE matched no rows or a stock_count/reserved_count CHECK constraint fired.
+func translateStockError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrInsufficientStock
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgCheckViolation {
+		return ErrInsufficientStock
+	}
+	return err
+}
+
+// recordMovement appends a ledger entry for a stock change made within the same transaction.
+func recordMovement(ctx context.Context, q *repo.Queries, sku repo.Sku, delta int32, reason repo.StockMovementReason, note *string) error {
+	_, err := q.CreateStockMovement(ctx, repo.CreateStockMovementParams{
+		SkuID:     sku.ID,
+		Delta:     delta,
+		Balance:   sku.StockCount,
+		Reason:    reason,
+		Note:      stringToPgTextp(note),
+		Actor:     stringToPgText(appMiddleware.ActorFromContext(ctx)),
+		RequestID: stringToPgText(middleware.GetReqID(ctx)),
+	})
+	return err
+}
+
+// recordSKU adds the audit event of a change of a SKU; before is nil for a created one.
+func recordSKU(ctx context.Context, q *repo.Queries, action audit.Action, before any, after repo.Sku) error {
+	return audit.Record(ctx, q, audit.EntitySKU, uuid.UUID(after.Uuid.Bytes).String(), action, before, after)
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor names the caller recorded in stock movements and other history: the subject of
+// the principal for API requests, the CLI or the owner of a background job otherwise.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the caller of the request, or an empty string if it is unknown.
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
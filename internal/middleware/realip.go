@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// RealIP replaces the remote address of a request with the client address that a reverse proxy
+// put in X-Real-IP or X-Forwarded-For. The headers are believed only from the proxies in the
+// trusted list: anyone else could name any address there and pass for another client.
+type RealIP struct {
+	trusted []netip.Prefix
+}
+
+// NewRealIP takes the addresses or CIDR prefixes of the trusted proxies. With none, every
+// request keeps the address of its connection.
+func NewRealIP(proxies []string) (*RealIP, error) {
+	m := &RealIP{}
+	for _, p := range proxies {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(p)
+			if addrErr != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", p)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		m.trusted = append(m.trusted, prefix.Masked())
+	}
+	return m, nil
+}
+
+func (m *RealIP) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if client, ok := m.clientAddr(r); ok {
+			r.RemoteAddr = client.String()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientAddr walks X-Forwarded-For from the right, past the trusted proxies, since every proxy
+// appends the address it got the request from and the leftmost entries are whatever the client sent.
+func (m *RealIP) clientAddr(r *http.Request) (netip.Addr, bool) {
+	peer, ok := m.parse(r.RemoteAddr)
+	if !ok || !m.isTrusted(peer) {
+		return netip.Addr{}, false
+	}
+
+	if addr, ok := m.parse(r.Header.Get("X-Real-IP")); ok {
+		return addr, true
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, ok := m.parse(hops[i])
+		if !ok {
+			return netip.Addr{}, false
+		}
+		if !m.isTrusted(addr) {
+			return addr, true
+		}
+	}
+	return netip.Addr{}, false
+}
+
+func (m *RealIP) isTrusted(addr netip.Addr) bool {
+	for _, prefix := range m.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parse accepts an address with or without a port.
+func (m *RealIP) parse(s string) (netip.Addr, bool) {
+	s = strings.TrimSpace(s)
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
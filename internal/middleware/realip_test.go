@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRealIP(t *testing.T) {
+	proxies := []string{"10.0.0.0/8", "192.0.2.10"}
+
+	tests := []struct {
+		name    string
+		trusted []string
+		peer    string
+		realIP  string
+		xff     []string
+		want    string
+	}{
+		{"no trusted proxies", nil, "10.0.0.1:443", "", []string{"198.51.100.7"}, "10.0.0.1:443"},
+		{"untrusted peer", proxies, "203.0.113.5:1234", "", []string{"198.51.100.7"}, "203.0.113.5:1234"},
+		{"x-real-ip", proxies, "10.0.0.1:443", "198.51.100.7", nil, "198.51.100.7"},
+		{"chain of trusted proxies", proxies, "10.0.0.1:443", "", []string{"198.51.100.7, 10.0.0.2"}, "198.51.100.7"},
+		{"address made up by the client", proxies, "10.0.0.1:443", "", []string{"192.0.2.1, 198.51.100.7"}, "198.51.100.7"},
+		{"several headers", proxies, "10.0.0.1:443", "", []string{"192.0.2.1", "198.51.100.7", "10.0.0.2"}, "198.51.100.7"},
+		{"only trusted hops", proxies, "10.0.0.1:443", "", []string{"10.0.0.3, 10.0.0.2"}, "10.0.0.1:443"},
+		{"malformed hop", proxies, "10.0.0.1:443", "", []string{"198.51.100.7, unknown"}, "10.0.0.1:443"},
+		{"single trusted address", proxies, "192.0.2.10:443", "", []string{"198.51.100.7"}, "198.51.100.7"},
+		{"mapped ipv4 peer", proxies, "[::ffff:10.0.0.1]:443", "", []string{"2001:db8::7"}, "2001:db8::7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewRealIP(tt.trusted)
+			if err != nil {
+				t.Fatalf("NewRealIP() error = %v", err)
+			}
+			var got string
+			handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.peer
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+			for _, v := range tt.xff {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+
+			if got != tt.want {
+				t.Errorf("RemoteAddr = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRealIPRejectsBadProxy(t *testing.T) {
+	for _, proxy := range []string{"10.0.0.0/33", "proxy.local"} {
+		if _, err := NewRealIP([]string{proxy}); err == nil {
+			t.Errorf("NewRealIP(%q) error = nil, want an error", proxy)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Package money keeps amounts in minor units together with their currency, so amounts in
+// different currencies can't be mixed up and arithmetic never silently overflows.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrCurrencyMismatch = errors.New("amounts are in different currencies")
+	ErrOverflow         = errors.New("amount is out of range")
+	ErrNoExchangeRate   = errors.New("no exchange rate")
+	ErrInvalidRate      = errors.New("exchange rate must be a positive decimal")
+)
+
+// Currency is an ISO 4217 code.
+type Currency string
+
+// DefaultCurrency is the currency of stores and prices that don't name one.
+const DefaultCurrency Currency = "RUB"
+
+// minorUnits is the number of decimal places of the minor unit of each supported currency.
+var minorUnits = map[Currency]int{
+	"RUB": 2,
+	"BYN": 2,
+	"KZT": 2,
+	"KGS": 2,
+	"AMD": 2,
+	"UZS": 2,
+	"GEL": 2,
+	"CNY": 2,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"TRY": 2,
+	"JPY": 0,
+	"KRW": 0,
+}
+
+// ParseCurrency accepts a supported ISO 4217 code in any case.
+func ParseCurrency(code string) (Currency, error) {
+	c := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if _, ok := minorUnits[c]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return c, nil
+}
+
+// MinorUnits is the number of decimal places of the currency's minor unit.
+func (c Currency) MinorUnits() int {
+	return minorUnits[c]
+}
+
+// Money is an amount in minor units of a currency, e.g. kopecks for RUB.
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+func New(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	if (other.Amount > 0 && m.Amount > math.MaxInt64-other.Amount) ||
+		(other.Amount < 0 && m.Amount < math.MinInt64-other.Amount) {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if other.Amount == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return m.Add(Money{Amount: -other.Amount, Currency: other.Currency})
+}
+
+// Mul multiplies the amount, e.g. a unit price by a quantity.
+func (m Money) Mul(n int64) (Money, error) {
+	if m.Amount == 0 || n == 0 {
+		return Money{Currency: m.Currency}, nil
+	}
+	product := m.Amount * n
+	if product/n != m.Amount || (m.Amount == -1 && n == math.MinInt64) || (n == -1 && m.Amount == math.MinInt64) {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: product, Currency: m.Currency}, nil
+}
+
+// Int32 returns the amount for a column that holds 32-bit prices.
+func (m Money) Int32() (int32, error) {
+	if m.Amount > math.MaxInt32 || m.Amount < math.MinInt32 {
+		return 0, ErrOverflow
+	}
+	return int32(m.Amount), nil
+}
+
+// Convert turns the amount into another currency at rate units of it per unit of m.Currency.
+// The result is rounded to the nearest minor unit, halves away from zero.
+func (m Money) Convert(to Currency, rate *big.Rat) (Money, error) {
+	if m.Currency == to {
+		return m, nil
+	}
+	if rate == nil || rate.Sign() <= 0 {
+		return Money{}, ErrInvalidRate
+	}
+
+	v := new(big.Rat).SetInt64(m.Amount)
+	v.Mul(v, rate)
+	shift := to.MinorUnits() - m.Currency.MinorUnits()
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(shift))), nil))
+	if shift >= 0 {
+		v.Mul(v, scale)
+	} else {
+		v.Quo(v, scale)
+	}
+
+	amount := roundHalfAway(v)
+	if !amount.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: amount.Int64(), Currency: to}, nil
+}
+
+// String formats the amount in major units, e.g. "1234.50 RUB".
+func (m Money) String() string {
+	units := m.Currency.MinorUnits()
+	return new(big.Rat).SetFrac(big.NewInt(m.Amount), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(units)), nil)).
+		FloatString(units) + " " + string(m.Currency)
+}
+
+// ParseRate reads an exchange rate written as a positive decimal, e.g. "5.4321".
+func ParseRate(s string) (*big.Rat, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.ContainsAny(s, "/eE") {
+		return nil, ErrInvalidRate
+	}
+	rate, ok := new(big.Rat).SetString(s)
+	if !ok || rate.Sign() <= 0 {
+		return nil, ErrInvalidRate
+	}
+	return rate, nil
+}
+
+func roundHalfAway(v *big.Rat) *big.Int {
+	num, den := new(big.Int).Abs(v.Num()), v.Denom()
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Lsh(r, 1).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if v.Sign() < 0 {
+		q.Neg(q)
+	}
+	return q
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,206 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Money
+		want    Money
+		wantErr error
+	}{
+		{"sum", New(150, "RUB"), New(250, "RUB"), New(400, "RUB"), nil},
+		{"negative", New(100, "RUB"), New(-250, "RUB"), New(-150, "RUB"), nil},
+		{"up to max", New(math.MaxInt64-1, "RUB"), New(1, "RUB"), New(math.MaxInt64, "RUB"), nil},
+		{"over max", New(math.MaxInt64, "RUB"), New(1, "RUB"), Money{}, ErrOverflow},
+		{"under min", New(math.MinInt64, "RUB"), New(-1, "RUB"), Money{}, ErrOverflow},
+		{"currency mismatch", New(100, "RUB"), New(100, "USD"), Money{}, ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.Add(tt.b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Add() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Add() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSub(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Money
+		want    Money
+		wantErr error
+	}{
+		{"difference", New(400, "RUB"), New(150, "RUB"), New(250, "RUB"), nil},
+		{"below zero", New(100, "RUB"), New(150, "RUB"), New(-50, "RUB"), nil},
+		{"negating min", New(0, "RUB"), New(math.MinInt64, "RUB"), Money{}, ErrOverflow},
+		{"under min", New(math.MinInt64, "RUB"), New(1, "RUB"), Money{}, ErrOverflow},
+		{"currency mismatch", New(100, "RUB"), New(100, "EUR"), Money{}, ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.Sub(tt.b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Sub() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Sub() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMul(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       Money
+		n       int64
+		want    Money
+		wantErr error
+	}{
+		{"quantity", New(1999, "RUB"), 3, New(5997, "RUB"), nil},
+		{"by zero", New(math.MaxInt64, "RUB"), 0, New(0, "RUB"), nil},
+		{"zero amount", New(0, "RUB"), math.MaxInt64, New(0, "RUB"), nil},
+		{"negative", New(-5, "RUB"), 4, New(-20, "RUB"), nil},
+		{"over max", New(math.MaxInt64/2+1, "RUB"), 2, Money{}, ErrOverflow},
+		{"min by minus one", New(math.MinInt64, "RUB"), -1, Money{}, ErrOverflow},
+		{"minus one by min", New(-1, "RUB"), math.MinInt64, Money{}, ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.m.Mul(tt.n)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Mul() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Mul() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInt32(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int64
+		want    int32
+		wantErr error
+	}{
+		{"in range", 123456, 123456, nil},
+		{"max", math.MaxInt32, math.MaxInt32, nil},
+		{"min", math.MinInt32, math.MinInt32, nil},
+		{"over max", math.MaxInt32 + 1, 0, ErrOverflow},
+		{"under min", math.MinInt32 - 1, 0, ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.amount, "RUB").Int32()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Int32() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Int32() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       Money
+		to      Currency
+		rate    string
+		want    Money
+		wantErr error
+	}{
+		{"same currency", New(1234, "RUB"), "RUB", "", New(1234, "RUB"), nil},
+		{"exact", New(10000, "USD"), "RUB", "90.5", New(905000, "RUB"), nil},
+		{"half rounds up", New(1, "RUB"), "USD", "0.5", New(1, "USD"), nil},
+		{"below half rounds down", New(1, "RUB"), "USD", "0.49", New(0, "USD"), nil},
+		{"negative half rounds away from zero", New(-1, "RUB"), "USD", "0.5", New(-1, "USD"), nil},
+		{"negative below half rounds to zero", New(-1, "RUB"), "USD", "0.49", New(0, "USD"), nil},
+		{"to fewer minor units", New(150, "USD"), "JPY", "1", New(2, "JPY"), nil},
+		{"to more minor units", New(3, "JPY"), "RUB", "0.6", New(180, "RUB"), nil},
+		{"overflow", New(math.MaxInt64, "RUB"), "USD", "2", Money{}, ErrOverflow},
+		{"zero rate", New(100, "RUB"), "USD", "0", Money{}, ErrInvalidRate},
+		{"no rate", New(100, "RUB"), "USD", "", Money{}, ErrInvalidRate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rate *big.Rat
+			if tt.rate != "" {
+				rate, _ = new(big.Rat).SetString(tt.rate)
+			}
+			got, err := tt.m.Convert(tt.to, rate)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Convert() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"5.4321", "54321/10000", false},
+		{" 90 ", "90", false},
+		{"0", "", true},
+		{"-1.5", "", true},
+		{"1/3", "", true},
+		{"1e3", "", true},
+		{"", "", true},
+		{"abc", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseRate(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRate) {
+					t.Fatalf("ParseRate() error = %v, want %v", err, ErrInvalidRate)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRate() error = %v", err)
+			}
+			if got.RatString() != tt.want {
+				t.Errorf("ParseRate() = %s, want %s", got.RatString(), tt.want)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		m    Money
+		want string
+	}{
+		{New(123450, "RUB"), "1234.50 RUB"},
+		{New(-5, "USD"), "-0.05 USD"},
+		{New(1500, "JPY"), "1500 JPY"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.m.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
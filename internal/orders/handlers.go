@@ -0,0 +1,286 @@
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+	"github.com/nikallow/bookstores-api/internal/response"
+)
+
+type Handler struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// CreateOrder
+//
+//	@Summary		Создать заказ
+//	@Description	Создает заказ из позиций (SKU и количество). Товар резервируется в одной транзакции,
+//	@Description	сумма считается по текущим ценам SKU. Повторяющиеся SKU объединяются.
+//	@Description	Все SKU заказа должны быть в одной валюте, она же становится валютой заказа.
+//	@Tags			orders
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		CreateOrderRequest		true	"Данные заказа"
+//	@Success		201		{object}	OrderResponse			"Заказ создан"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error или SKU в разных валютах"
+//	@Failure		404		{object}	response.ErrorResponse	"SKU не найден"
+//	@Failure		409		{object}	response.ErrorResponse	"Недостаточно товара"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/orders [post]
+func (h *Handler) CreateOrder(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	var req CreateOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read create order request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	order, err := h.service.CreateOrder(r.Context(), req)
+	if err != nil {
+		h.writeServiceError(w, r, err, "Failed to create order")
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusCreated, toOrderResponse(order))
+}
+
+// GetOrder
+//
+//	@Summary		Получить заказ
+//	@Description	Возвращает заказ вместе с позициями.
+//	@Tags			orders
+//	@Produce		json
+//	@Param			orderUUID	path		string					true	"UUID заказа"
+//	@Success		200			{object}	OrderResponse			"Заказ"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Заказ не найден"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/orders/{orderUUID} [get]
+func (h *Handler) GetOrder(w http.ResponseWriter, r *http.Request) {
+	orderUUID, ok := parseOrderUUID(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.service.GetOrder(r.Context(), orderUUID)
+	if err != nil {
+		h.writeServiceError(w, r, err, "Failed to get order")
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toOrderResponse(order))
+}
+
+// ListOrders
+//
+//	@Summary		Список заказов
+//	@Description	Возвращает страницу заказов, отсортированных по времени создания. Позиции не включаются.
+//	@Tags			orders
+//	@Produce		json
+//	@Param			status	query		string					false	"Фильтр по статусу"			Enums(pending, paid, fulfilled, cancelled)
+//	@Param			limit	query		int						false	"Размер страницы (1-100)"	default(20)
+//	@Param			cursor	query		string					false	"Курсор следующей страницы"
+//	@Param			order	query		string					false	"Направление сортировки по времени"	Enums(asc, desc)	default(asc)
+//	@Success		200		{object}	OrderListResponse		"Страница заказов"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/orders [get]
+func (h *Handler) ListOrders(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	query := r.URL.Query()
+	page, err := pagination.FromQuery(query, "created_at")
+	if err != nil {
+		log.Warn("Invalid list orders parameters", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := ListOrdersParams{Request: page}
+	if status := query.Get("status"); status != "" {
+		if err := h.validate.Var(status, "oneof=pending paid fulfilled cancelled"); err != nil {
+			response.WriteError(w, r, http.StatusBadRequest, "Invalid status")
+			return
+		}
+		params.Status = &status
+	}
+
+	orders, nextCursor, err := h.service.ListOrders(r.Context(), params)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error("Failed to list orders", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	resp := make([]OrderResponse, len(orders))
+	for i, order := range orders {
+		resp[i] = toOrderResponse(OrderDetails{Order: order})
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, OrderListResponse{Items: resp, NextCursor: nextCursor})
+}
+
+// PayOrder
+//
+//	@Summary		Оплатить заказ
+//	@Description	Переводит заказ из статуса pending в paid и списывает зарезервированный товар со склада.
+//	@Tags			orders
+//	@Produce		json
+//	@Param			orderUUID	path		string					true	"UUID заказа"
+//	@Success		200			{object}	OrderResponse			"Обновленный заказ"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Заказ не найден"
+//	@Failure		409			{object}	response.ErrorResponse	"Переход из текущего статуса невозможен"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/orders/{orderUUID}/pay [post]
+func (h *Handler) PayOrder(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.PayOrder, "Failed to pay order")
+}
+
+// FulfillOrder
+//
+//	@Summary		Выполнить заказ
+//	@Description	Переводит оплаченный заказ в статус fulfilled.
+//	@Tags			orders
+//	@Produce		json
+//	@Param			orderUUID	path		string					true	"UUID заказа"
+//	@Success		200			{object}	OrderResponse			"Обновленный заказ"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Заказ не найден"
+//	@Failure		409			{object}	response.ErrorResponse	"Переход из текущего статуса невозможен"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/orders/{orderUUID}/fulfill [post]
+func (h *Handler) FulfillOrder(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.FulfillOrder, "Failed to fulfill order")
+}
+
+// CancelOrder
+//
+//	@Summary		Отменить заказ
+//	@Description	Отменяет заказ в статусе pending или paid: снимает резерв или возвращает проданный товар на склад.
+//	@Tags			orders
+//	@Produce		json
+//	@Param			orderUUID	path		string					true	"UUID заказа"
+//	@Success		200			{object}	OrderResponse			"Обновленный заказ"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Заказ не найден"
+//	@Failure		409			{object}	response.ErrorResponse	"Переход из текущего статуса невозможен"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/orders/{orderUUID}/cancel [post]
+func (h *Handler) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.CancelOrder, "Failed to cancel order")
+}
+
+func (h *Handler) transition(w http.ResponseWriter, r *http.Request, apply func(ctx context.Context, orderUUID uuid.UUID) (OrderDetails, error), failMsg string) {
+	orderUUID, ok := parseOrderUUID(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := apply(r.Context(), orderUUID)
+	if err != nil {
+		h.writeServiceError(w, r, err, failMsg)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toOrderResponse(order))
+}
+
+func (h *Handler) writeServiceError(w http.ResponseWriter, r *http.Request, err error, failMsg string) {
+	switch {
+	case errors.Is(err, ErrOrderNotFound), errors.Is(err, ErrSKUNotFound):
+		response.WriteError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrCurrencyMismatch):
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrInsufficientStock), errors.Is(err, ErrInvalidTransition):
+		response.WriteError(w, r, http.StatusConflict, err.Error())
+	default:
+		middleware.LoggerFromContext(r.Context()).Error(failMsg, "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func parseOrderUUID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	orderUUID, err := uuid.Parse(chi.URLParam(r, "orderUUID"))
+	if err != nil {
+		middleware.LoggerFromContext(r.Context()).Warn("Invalid order UUID format", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid order uuid format")
+		return uuid.Nil, false
+	}
+	return orderUUID, true
+}
+
+func toOrderResponse(details OrderDetails) OrderResponse {
+	order := details.Order
+	resp := OrderResponse{
+		UUID:          mustConvertUUID(order.Uuid),
+		Status:        string(order.Status),
+		CustomerName:  order.CustomerName,
+		TotalInKopeks: order.TotalInKopeks,
+		Currency:      order.Currency,
+		CreatedAt:     order.CreatedAt.Time,
+		UpdatedAt:     order.UpdatedAt.Time,
+		PaidAt:        timestamptzToTimep(order.PaidAt),
+		FulfilledAt:   timestamptzToTimep(order.FulfilledAt),
+		CancelledAt:   timestamptzToTimep(order.CancelledAt),
+	}
+	if order.CustomerEmail.Valid {
+		resp.CustomerEmail = &order.CustomerEmail.String
+	}
+	if details.Items != nil {
+		resp.Items = make([]OrderItemResponse, len(details.Items))
+		for i, item := range details.Items {
+			resp.Items[i] = OrderItemResponse{
+				SKUUUID:       mustConvertUUID(item.SkuUuid),
+				BookID:        item.BookID,
+				Title:         item.Title,
+				Quantity:      item.Quantity,
+				PriceInKopeks: item.PriceInKopeks,
+			}
+		}
+	}
+	return resp
+}
+
+func timestamptzToTimep(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func mustConvertUUID(pgUUID pgtype.UUID) uuid.UUID {
+	if !pgUUID.Valid {
+		return uuid.Nil
+	}
+	return pgUUID.Bytes
+}
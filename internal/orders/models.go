@@ -0,0 +1,52 @@
+package orders
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+)
+
+type CreateOrderItemRequest struct {
+	SKUUUID  uuid.UUID `json:"sku_uuid" validate:"required"`
+	Quantity int32     `json:"quantity" validate:"gt=0,max=10000"`
+}
+
+type CreateOrderRequest struct {
+	CustomerName  string                   `json:"customer_name"            validate:"required,max=255"`
+	CustomerEmail *string                  `json:"customer_email,omitempty" validate:"omitempty,email"`
+	Items         []CreateOrderItemRequest `json:"items"                    validate:"required,min=1,max=100,dive"`
+}
+
+type ListOrdersParams struct {
+	pagination.Request
+	Status *string
+}
+
+type OrderItemResponse struct {
+	SKUUUID       uuid.UUID `json:"sku_uuid"`
+	BookID        int64     `json:"book_id"`
+	Title         string    `json:"title"`
+	Quantity      int32     `json:"quantity"`
+	PriceInKopeks int32     `json:"price_in_kopeks"`
+}
+
+type OrderResponse struct {
+	UUID          uuid.UUID           `json:"uuid"`
+	Status        string              `json:"status"`
+	CustomerName  string              `json:"customer_name"`
+	CustomerEmail *string             `json:"customer_email,omitempty"`
+	TotalInKopeks int64               `json:"total_in_kopeks"`
+	Currency      string              `json:"currency" example:"RUB"`
+	Items         []OrderItemResponse `json:"items,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+	PaidAt        *time.Time          `json:"paid_at,omitempty"`
+	FulfilledAt   *time.Time          `json:"fulfilled_at,omitempty"`
+	CancelledAt   *time.Time          `json:"cancelled_at,omitempty"`
+}
+
+type OrderListResponse struct {
+	Items      []OrderResponse `json:"items"`
+	NextCursor *string         `json:"next_cursor"`
+}
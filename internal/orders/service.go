@@ -0,0 +1,335 @@
+package orders
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nikallow/bookstores-api/internal/adapters/postgres"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/inventory"
+	appMiddleware "github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/money"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+)
+
+var (
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrInvalidTransition = errors.New("order status does not allow this transition")
+	ErrSKUNotFound       = inventory.ErrSKUNotFound
+	ErrInsufficientStock = inventory.ErrInsufficientStock
+	ErrCurrencyMismatch  = errors.New("all items of an order must be priced in one currency")
+)
+
+// transitions lists the statuses an order may move to from each status.
+var transitions = map[repo.OrderStatus][]repo.OrderStatus{
+	repo.OrderStatusPending: {repo.OrderStatusPaid, repo.OrderStatusCancelled},
+	repo.OrderStatusPaid:    {repo.OrderStatusFulfilled, repo.OrderStatusCancelled},
+}
+
+// OrderDetails is an order together with its line items.
+type OrderDetails struct {
+	Order repo.Order
+	Items []repo.ListOrderItemsRow
+}
+
+type Service interface {
+	CreateOrder(ctx context.Context, params CreateOrderRequest) (OrderDetails, error)
+	GetOrder(ctx context.Context, orderUUID uuid.UUID) (OrderDetails, error)
+	ListOrders(ctx context.Context, params ListOrdersParams) ([]repo.Order, *string, error)
+	PayOrder(ctx context.Context, orderUUID uuid.UUID) (OrderDetails, error)
+	FulfillOrder(ctx context.Context, orderUUID uuid.UUID) (OrderDetails, error)
+	CancelOrder(ctx context.Context, orderUUID uuid.UUID) (OrderDetails, error)
+}
+
+type service struct {
+	repo repo.Querier
+	db   postgres.TxBeginner
+}
+
+func NewService(repo repo.Querier, db postgres.TxBeginner) Service {
+	return &service{repo: repo, db: db}
+}
+
+// CreateOrder - POST /orders
+//
+// All SKUs of the order are locked in UUID order, so concurrent orders touching the
+// same SKUs can't deadlock, and their stock is reserved in the same transaction. It is
+// written off as a sale when the order is paid.
+func (s *service) CreateOrder(ctx context.Context, params CreateOrderRequest) (OrderDetails, error) {
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	quantities := make(map[uuid.UUID]int32, len(params.Items))
+	for _, item := range params.Items {
+		quantities[item.SKUUUID] += item.Quantity
+	}
+	skuUUIDs := make([]uuid.UUID, 0, len(quantities))
+	for id := range quantities {
+		skuUUIDs = append(skuUUIDs, id)
+	}
+	slices.SortFunc(skuUUIDs, func(a, b uuid.UUID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return OrderDetails{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+
+	skus := make([]repo.Sku, len(skuUUIDs))
+	var total money.Money
+	for i, id := range skuUUIDs {
+		sku, err := inventory.LockSKU(ctx, qtx, id, nil)
+		if err != nil {
+			return OrderDetails{}, fmt.Errorf("sku %s: %w", id, err)
+		}
+		skus[i] = sku
+
+		line, err := money.New(int64(sku.PriceInKopeks), money.Currency(sku.Currency)).Mul(int64(quantities[id]))
+		if err != nil {
+			return OrderDetails{}, fmt.Errorf("sku %s: %w", id, err)
+		}
+		if i == 0 {
+			total = money.New(0, line.Currency)
+		}
+		if total, err = total.Add(line); err != nil {
+			if errors.Is(err, money.ErrCurrencyMismatch) {
+				return OrderDetails{}, ErrCurrencyMismatch
+			}
+			return OrderDetails{}, err
+		}
+	}
+
+	order, err := qtx.CreateOrder(ctx, repo.CreateOrderParams{
+		CustomerName:  params.CustomerName,
+		CustomerEmail: stringToPgTextp(params.CustomerEmail),
+		TotalInKopeks: total.Amount,
+		Currency:      string(total.Currency),
+	})
+	if err != nil {
+		log.Error("Failed to create order", "error", err)
+		return OrderDetails{}, err
+	}
+
+	for i, sku := range skus {
+		qty := quantities[skuUUIDs[i]]
+		if inventory.Available(sku) < qty {
+			return OrderDetails{}, fmt.Errorf("sku %s: %w", skuUUIDs[i], ErrInsufficientStock)
+		}
+		if _, err := inventory.AdjustReserved(ctx, qtx, sku, qty); err != nil {
+			log.Error("Failed to reserve sku stock", "error", err, "sku_id", sku.ID)
+			return OrderDetails{}, err
+		}
+		_, err := qtx.CreateOrderItem(ctx, repo.CreateOrderItemParams{
+			OrderID:       order.ID,
+			SkuID:         sku.ID,
+			Quantity:      qty,
+			PriceInKopeks: sku.PriceInKopeks,
+		})
+		if err != nil {
+			log.Error("Failed to create order item", "error", err, "order_id", order.ID)
+			return OrderDetails{}, err
+		}
+	}
+
+	details, err := loadDetails(ctx, qtx, order)
+	if err != nil {
+		return OrderDetails{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return OrderDetails{}, err
+	}
+
+	log.Info("Order created successfully", "order_id", order.ID, "total", total.String())
+	return details, nil
+}
+
+func (s *service) GetOrder(ctx context.Context, orderUUID uuid.UUID) (OrderDetails, error) {
+	order, err := s.repo.GetOrderByUUID(ctx, uuidToPgUUID(orderUUID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return OrderDetails{}, ErrOrderNotFound
+		}
+		return OrderDetails{}, err
+	}
+	return loadDetails(ctx, s.repo, order)
+}
+
+// ListOrders - GET /orders
+func (s *service) ListOrders(ctx context.Context, params ListOrdersParams) ([]repo.Order, *string, error) {
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	cursor, err := params.DecodeCursor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queryParams := repo.ListOrdersParams{
+		PageLimit: params.QueryLimit(),
+	}
+	if params.Status != nil {
+		queryParams.Status = repo.NullOrderStatus{OrderStatus: repo.OrderStatus(*params.Status), Valid: true}
+	}
+	if cursor != nil {
+		t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: bad timestamp", pagination.ErrInvalidCursor)
+		}
+		queryParams.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+		queryParams.CursorTime = pgtype.Timestamptz{Time: t, Valid: true}
+	}
+
+	var orders []repo.Order
+	if params.Desc {
+		orders, err = s.repo.ListOrdersDesc(ctx, repo.ListOrdersDescParams(queryParams))
+	} else {
+		orders, err = s.repo.ListOrders(ctx, queryParams)
+	}
+	if err != nil {
+		log.Error("Failed to list orders", "error", err)
+		return nil, nil, err
+	}
+
+	orders, hasMore := pagination.Trim(orders, params.Request)
+	if !hasMore {
+		return orders, nil, nil
+	}
+	last := orders[len(orders)-1]
+	next := params.NextCursor(last.CreatedAt.Time.Format(time.RFC3339Nano), last.ID)
+	return orders, &next, nil
+}
+
+// PayOrder - POST /orders/{orderUUID}/pay
+func (s *service) PayOrder(ctx context.Context, orderUUID uuid.UUID) (OrderDetails, error) {
+	return s.transition(ctx, orderUUID, repo.OrderStatusPaid)
+}
+
+// FulfillOrder - POST /orders/{orderUUID}/fulfill
+func (s *service) FulfillOrder(ctx context.Context, orderUUID uuid.UUID) (OrderDetails, error) {
+	return s.transition(ctx, orderUUID, repo.OrderStatusFulfilled)
+}
+
+// CancelOrder - POST /orders/{orderUUID}/cancel
+//
+// The stock of an unpaid order is released; that of a paid one goes back to the SKUs as a return.
+func (s *service) CancelOrder(ctx context.Context, orderUUID uuid.UUID) (OrderDetails, error) {
+	return s.transition(ctx, orderUUID, repo.OrderStatusCancelled)
+}
+
+func (s *service) transition(ctx context.Context, orderUUID uuid.UUID, to repo.OrderStatus) (OrderDetails, error) {
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return OrderDetails{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+
+	order, err := qtx.GetOrderByUUIDForUpdate(ctx, uuidToPgUUID(orderUUID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return OrderDetails{}, ErrOrderNotFound
+		}
+		return OrderDetails{}, err
+	}
+	if !slices.Contains(transitions[order.Status], to) {
+		return OrderDetails{}, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, order.Status, to)
+	}
+
+	if to != repo.OrderStatusFulfilled {
+		if err := settleStock(ctx, qtx, order, to); err != nil {
+			log.Error("Failed to settle order stock", "error", err, "order_id", order.ID)
+			return OrderDetails{}, err
+		}
+	}
+
+	updated, err := qtx.UpdateOrderStatus(ctx, repo.UpdateOrderStatusParams{
+		Status: to,
+		ID:     order.ID,
+	})
+	if err != nil {
+		log.Error("Failed to update order status", "error", err, "order_id", order.ID)
+		return OrderDetails{}, err
+	}
+
+	details, err := loadDetails(ctx, qtx, updated)
+	if err != nil {
+		return OrderDetails{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return OrderDetails{}, err
+	}
+
+	log.Info("Order status changed", "order_id", order.ID, "from", order.Status, "to", to)
+	return details, nil
+}
+
+// settleStock moves the stock of an order going to status to: paying writes the reserved
+// copies off as a sale, cancelling releases them or, once paid, takes them back as a return.
+func settleStock(ctx context.Context, q *repo.Queries, order repo.Order, to repo.OrderStatus) error {
+	// Sorted by SKU UUID, the same lock order as in CreateOrder.
+	items, err := q.ListOrderSKUQuantities(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+
+	note := orderNote(order)
+	for _, item := range items {
+		sku, err := inventory.LockSKUByID(ctx, q, item.SkuID)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case to == repo.OrderStatusPaid:
+			sku, err = inventory.AdjustReserved(ctx, q, sku, -item.Quantity)
+			if err == nil {
+				_, err = inventory.ApplyStockChange(ctx, q, sku, -item.Quantity, repo.StockMovementReasonSale, &note)
+			}
+		case order.Status == repo.OrderStatusPaid:
+			_, err = inventory.ApplyStockChange(ctx, q, sku, item.Quantity, repo.StockMovementReasonReturn, &note)
+		default:
+			_, err = inventory.AdjustReserved(ctx, q, sku, -item.Quantity)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func loadDetails(ctx context.Context, q repo.Querier, order repo.Order) (OrderDetails, error) {
+	items, err := q.ListOrderItems(ctx, order.ID)
+	if err != nil {
+		return OrderDetails{}, err
+	}
+	return OrderDetails{Order: order, Items: items}, nil
+}
+
+func orderNote(order repo.Order) string {
+	return "order " + uuid.UUID(order.Uuid.Bytes).String()
+}
+
+func stringToPgTextp(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{Valid: false}
+	}
+	return pgtype.Text{String: *s, Valid: true}
+}
+
+func uuidToPgUUID(u uuid.UUID) pgtype.UUID {
+	return pgtype.UUID{Bytes: u, Valid: true}
+}
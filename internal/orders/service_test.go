@@ -0,0 +1,278 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nikallow/bookstores-api/internal/adapters/postgres"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/database/dbtest"
+)
+
+// createSKUs creates two SKUs in a new store: 10 copies at 1000 and 5 copies at 250.
+func createSKUs(t *testing.T, db *postgres.DB) (uuid.UUID, uuid.UUID) {
+	t.Helper()
+	storeID, _ := dbtest.CreateStore(t, db)
+	a := dbtest.CreateSKU(t, db, dbtest.CreateBook(t, db), storeID, 1000, 10)
+	b := dbtest.CreateSKU(t, db, dbtest.CreateBook(t, db), storeID, 250, 5)
+	return a, b
+}
+
+func move(ctx context.Context, svc Service, orderUUID uuid.UUID, to repo.OrderStatus) (OrderDetails, error) {
+	switch to {
+	case repo.OrderStatusPaid:
+		return svc.PayOrder(ctx, orderUUID)
+	case repo.OrderStatusFulfilled:
+		return svc.FulfillOrder(ctx, orderUUID)
+	default:
+		return svc.CancelOrder(ctx, orderUUID)
+	}
+}
+
+func TestOrderLifecycle(t *testing.T) {
+	db := dbtest.New(t)
+	svc := NewService(repo.New(db), db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		steps         []repo.OrderStatus
+		wantStatus    repo.OrderStatus
+		wantStock     [2]int32
+		wantReserved  [2]int32
+		wantMovements []string
+	}{
+		{
+			name:         "created",
+			wantStatus:   repo.OrderStatusPending,
+			wantStock:    [2]int32{10, 5},
+			wantReserved: [2]int32{3, 2},
+		},
+		{
+			name:          "paid and fulfilled",
+			steps:         []repo.OrderStatus{repo.OrderStatusPaid, repo.OrderStatusFulfilled},
+			wantStatus:    repo.OrderStatusFulfilled,
+			wantStock:     [2]int32{7, 3},
+			wantMovements: []string{"sale"},
+		},
+		{
+			name:       "cancelled before payment",
+			steps:      []repo.OrderStatus{repo.OrderStatusCancelled},
+			wantStatus: repo.OrderStatusCancelled,
+			wantStock:  [2]int32{10, 5},
+		},
+		{
+			name:          "cancelled after payment",
+			steps:         []repo.OrderStatus{repo.OrderStatusPaid, repo.OrderStatusCancelled},
+			wantStatus:    repo.OrderStatusCancelled,
+			wantStock:     [2]int32{10, 5},
+			wantMovements: []string{"sale", "return"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := createSKUs(t, db)
+
+			details, err := svc.CreateOrder(ctx, CreateOrderRequest{
+				CustomerName: "Customer",
+				Items: []CreateOrderItemRequest{
+					{SKUUUID: a, Quantity: 1},
+					{SKUUUID: b, Quantity: 2},
+					{SKUUUID: a, Quantity: 2},
+				},
+			})
+			if err != nil {
+				t.Fatalf("CreateOrder() error = %v", err)
+			}
+			if details.Order.TotalInKopeks != 3500 {
+				t.Errorf("total = %d, want 3500", details.Order.TotalInKopeks)
+			}
+			if len(details.Items) != 2 {
+				t.Errorf("got %d items, want 2", len(details.Items))
+			}
+
+			orderUUID := uuid.UUID(details.Order.Uuid.Bytes)
+			for _, to := range tt.steps {
+				if details, err = move(ctx, svc, orderUUID, to); err != nil {
+					t.Fatalf("%s: error = %v", to, err)
+				}
+			}
+			if details.Order.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", details.Order.Status, tt.wantStatus)
+			}
+
+			for i, sku := range []uuid.UUID{a, b} {
+				if stock := dbtest.Stock(t, db, sku); stock != tt.wantStock[i] {
+					t.Errorf("sku %d: stock = %d, want %d", i, stock, tt.wantStock[i])
+				}
+				if reserved := dbtest.Reserved(t, db, sku); reserved != tt.wantReserved[i] {
+					t.Errorf("sku %d: reserved = %d, want %d", i, reserved, tt.wantReserved[i])
+				}
+				if movements := dbtest.Movements(t, db, sku); !slices.Equal(movements, tt.wantMovements) {
+					t.Errorf("sku %d: movements = %v, want %v", i, movements, tt.wantMovements)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateOrderInsufficientStock(t *testing.T) {
+	db := dbtest.New(t)
+	svc := NewService(repo.New(db), db)
+	a, b := createSKUs(t, db)
+
+	_, err := svc.CreateOrder(context.Background(), CreateOrderRequest{
+		CustomerName: "Customer",
+		Items: []CreateOrderItemRequest{
+			{SKUUUID: a, Quantity: 3},
+			{SKUUUID: b, Quantity: 6},
+		},
+	})
+	if !errors.Is(err, ErrInsufficientStock) {
+		t.Fatalf("CreateOrder() error = %v, want %v", err, ErrInsufficientStock)
+	}
+
+	// Nothing of the order is left behind.
+	for i, sku := range []uuid.UUID{a, b} {
+		if stock, want := dbtest.Stock(t, db, sku), []int32{10, 5}[i]; stock != want {
+			t.Errorf("sku %d: stock = %d, want %d", i, stock, want)
+		}
+		if reserved := dbtest.Reserved(t, db, sku); reserved != 0 {
+			t.Errorf("sku %d: reserved = %d, want 0", i, reserved)
+		}
+		if movements := dbtest.Movements(t, db, sku); len(movements) != 0 {
+			t.Errorf("sku %d: movements = %v, want none", i, movements)
+		}
+	}
+	orders, _, err := svc.ListOrders(context.Background(), ListOrdersParams{})
+	if err != nil {
+		t.Fatalf("ListOrders() error = %v", err)
+	}
+	if len(orders) != 0 {
+		t.Errorf("got %d orders, want none", len(orders))
+	}
+}
+
+func TestCreateOrderBeyondReservedStock(t *testing.T) {
+	db := dbtest.New(t)
+	svc := NewService(repo.New(db), db)
+	ctx := context.Background()
+	a, _ := createSKUs(t, db)
+
+	order := func(quantity int32) error {
+		_, err := svc.CreateOrder(ctx, CreateOrderRequest{
+			CustomerName: "Customer",
+			Items:        []CreateOrderItemRequest{{SKUUUID: a, Quantity: quantity}},
+		})
+		return err
+	}
+	if err := order(8); err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	// The copies held by the pending order are on the shelf but not for sale.
+	if err := order(3); !errors.Is(err, ErrInsufficientStock) {
+		t.Errorf("CreateOrder() error = %v, want %v", err, ErrInsufficientStock)
+	}
+	if err := order(2); err != nil {
+		t.Errorf("CreateOrder() of the rest error = %v", err)
+	}
+	if reserved := dbtest.Reserved(t, db, a); reserved != 10 {
+		t.Errorf("reserved = %d, want 10", reserved)
+	}
+}
+
+func TestSettleOrderOnDeletedSKU(t *testing.T) {
+	db := dbtest.New(t)
+	svc := NewService(repo.New(db), db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		to            repo.OrderStatus
+		wantStock     int32
+		wantMovements []string
+	}{
+		{"paid", repo.OrderStatusPaid, 7, []string{"sale"}},
+		{"cancelled", repo.OrderStatusCancelled, 10, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _ := createSKUs(t, db)
+			details, err := svc.CreateOrder(ctx, CreateOrderRequest{
+				CustomerName: "Customer",
+				Items:        []CreateOrderItemRequest{{SKUUUID: a, Quantity: 3}},
+			})
+			if err != nil {
+				t.Fatalf("CreateOrder() error = %v", err)
+			}
+			if _, err := db.Exec(ctx, "UPDATE skus SET deleted_at = now() WHERE uuid = $1", pgtype.UUID{Bytes: a, Valid: true}); err != nil {
+				t.Fatalf("failed to delete sku: %v", err)
+			}
+
+			if _, err := move(ctx, svc, details.Order.Uuid.Bytes, tt.to); err != nil {
+				t.Fatalf("%s: error = %v", tt.to, err)
+			}
+			if stock := dbtest.Stock(t, db, a); stock != tt.wantStock {
+				t.Errorf("stock = %d, want %d", stock, tt.wantStock)
+			}
+			if reserved := dbtest.Reserved(t, db, a); reserved != 0 {
+				t.Errorf("reserved = %d, want 0", reserved)
+			}
+			if movements := dbtest.Movements(t, db, a); !slices.Equal(movements, tt.wantMovements) {
+				t.Errorf("movements = %v, want %v", movements, tt.wantMovements)
+			}
+		})
+	}
+}
+
+func TestOrderInvalidTransitions(t *testing.T) {
+	db := dbtest.New(t)
+	svc := NewService(repo.New(db), db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		steps []repo.OrderStatus
+		to    repo.OrderStatus
+	}{
+		{"fulfil unpaid", nil, repo.OrderStatusFulfilled},
+		{"pay twice", []repo.OrderStatus{repo.OrderStatusPaid}, repo.OrderStatusPaid},
+		{"pay cancelled", []repo.OrderStatus{repo.OrderStatusCancelled}, repo.OrderStatusPaid},
+		{"cancel twice", []repo.OrderStatus{repo.OrderStatusCancelled}, repo.OrderStatusCancelled},
+		{"cancel fulfilled", []repo.OrderStatus{repo.OrderStatusPaid, repo.OrderStatusFulfilled}, repo.OrderStatusCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _ := createSKUs(t, db)
+			details, err := svc.CreateOrder(ctx, CreateOrderRequest{
+				CustomerName: "Customer",
+				Items:        []CreateOrderItemRequest{{SKUUUID: a, Quantity: 1}},
+			})
+			if err != nil {
+				t.Fatalf("CreateOrder() error = %v", err)
+			}
+			orderUUID := uuid.UUID(details.Order.Uuid.Bytes)
+			for _, to := range tt.steps {
+				if _, err := move(ctx, svc, orderUUID, to); err != nil {
+					t.Fatalf("%s: error = %v", to, err)
+				}
+			}
+			stock := dbtest.Stock(t, db, a)
+
+			if _, err := move(ctx, svc, orderUUID, tt.to); !errors.Is(err, ErrInvalidTransition) {
+				t.Errorf("%s: error = %v, want %v", tt.to, err, ErrInvalidTransition)
+			}
+			if got := dbtest.Stock(t, db, a); got != stock {
+				t.Errorf("stock = %d, want %d", got, stock)
+			}
+		})
+	}
+
+	if _, err := svc.PayOrder(ctx, uuid.New()); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("PayOrder() of an unknown order error = %v, want %v", err, ErrOrderNotFound)
+	}
+}
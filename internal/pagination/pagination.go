@@ -0,0 +1,115 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidLimit  = fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+	ErrInvalidSort   = errors.New("invalid sort field")
+	ErrInvalidOrder  = errors.New("order must be 'asc' or 'desc'")
+)
+
+// Request holds the common list parameters: ?limit=&cursor=&sort=&order=
+// Lists run one query per sort and direction, so that a page is read in order off the
+// (column, id) index of its sort from the cursor on; ordering by a CASE on the sort can't use an index.
+type Request struct {
+	Limit  int32
+	Cursor string
+	SortBy string
+	Desc   bool
+}
+
+// Cursor points at the last row of the previous page. It is bound to the sort it was
+// issued for, so a cursor can't be replayed against a different ordering.
+type Cursor struct {
+	SortBy string `json:"s"`
+	Desc   bool   `json:"d"`
+	Value  string `json:"v"`
+	ID     int64  `json:"id"`
+}
+
+// FromQuery parses list parameters. sorts lists the allowed sort fields, the first one is the default.
+func FromQuery(q url.Values, sorts ...string) (Request, error) {
+	req := Request{
+		Limit:  DefaultLimit,
+		Cursor: q.Get("cursor"),
+	}
+
+	if limitStr := q.Get("limit"); limitStr != "" {
+		limit, err := strconv.ParseInt(limitStr, 10, 32)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return Request{}, ErrInvalidLimit
+		}
+		req.Limit = int32(limit)
+	}
+
+	req.SortBy = q.Get("sort")
+	if req.SortBy == "" && len(sorts) > 0 {
+		req.SortBy = sorts[0]
+	}
+	if !slices.Contains(sorts, req.SortBy) {
+		return Request{}, fmt.Errorf("%w: %q", ErrInvalidSort, req.SortBy)
+	}
+
+	switch q.Get("order") {
+	case "", "asc":
+	case "desc":
+		req.Desc = true
+	default:
+		return Request{}, ErrInvalidOrder
+	}
+
+	return req, nil
+}
+
+// DecodeCursor returns nil for the first page.
+func (r Request) DecodeCursor() (*Cursor, error) {
+	if r.Cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(r.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.SortBy != r.SortBy || c.Desc != r.Desc {
+		return nil, fmt.Errorf("%w: cursor was issued for a different sort", ErrInvalidCursor)
+	}
+	return &c, nil
+}
+
+// NextCursor builds the cursor for the page following a row with the given sort value and ID.
+func (r Request) NextCursor(value string, id int64) string {
+	raw, _ := json.Marshal(Cursor{SortBy: r.SortBy, Desc: r.Desc, Value: value, ID: id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// QueryLimit is the number of rows to fetch: one extra row tells whether there is a next page.
+func (r Request) QueryLimit() int32 {
+	return r.Limit + 1
+}
+
+// Trim drops the extra row fetched with QueryLimit and reports whether there is a next page.
+func Trim[T any](items []T, r Request) ([]T, bool) {
+	if int32(len(items)) > r.Limit {
+		return items[:r.Limit], true
+	}
+	return items, false
+}
@@ -0,0 +1,128 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestFromQuery(t *testing.T) {
+	sorts := []string{"created_at", "title"}
+	tests := []struct {
+		name    string
+		query   string
+		want    Request
+		wantErr error
+	}{
+		{"defaults", "", Request{Limit: DefaultLimit, SortBy: "created_at"}, nil},
+		{"all set", "limit=5&cursor=abc&sort=title&order=desc", Request{Limit: 5, Cursor: "abc", SortBy: "title", Desc: true}, nil},
+		{"ascending", "order=asc", Request{Limit: DefaultLimit, SortBy: "created_at"}, nil},
+		{"max limit", "limit=100", Request{Limit: MaxLimit, SortBy: "created_at"}, nil},
+		{"zero limit", "limit=0", Request{}, ErrInvalidLimit},
+		{"limit over max", "limit=101", Request{}, ErrInvalidLimit},
+		{"negative limit", "limit=-1", Request{}, ErrInvalidLimit},
+		{"limit not a number", "limit=ten", Request{}, ErrInvalidLimit},
+		{"unknown sort", "sort=price", Request{}, ErrInvalidSort},
+		{"unknown order", "order=up", Request{}, ErrInvalidOrder},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := FromQuery(q, sorts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FromQuery() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("FromQuery() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		req   Request
+		value string
+		id    int64
+	}{
+		{"ascending", Request{SortBy: "created_at"}, "2024-05-01T10:00:00Z", 42},
+		{"descending", Request{SortBy: "title", Desc: true}, "Война и мир", 7},
+		{"empty value", Request{SortBy: "title"}, "", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := tt.req
+			next.Cursor = tt.req.NextCursor(tt.value, tt.id)
+			got, err := next.DecodeCursor()
+			if err != nil {
+				t.Fatalf("DecodeCursor() error = %v", err)
+			}
+			want := Cursor{SortBy: tt.req.SortBy, Desc: tt.req.Desc, Value: tt.value, ID: tt.id}
+			if got == nil || *got != want {
+				t.Errorf("DecodeCursor() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	issued := Request{SortBy: "title"}.NextCursor("Dune", 3)
+	tests := []struct {
+		name   string
+		req    Request
+		errors bool
+	}{
+		{"first page", Request{SortBy: "title"}, false},
+		{"not base64", Request{SortBy: "title", Cursor: "not a cursor!"}, true},
+		{"not json", Request{SortBy: "title", Cursor: base64.RawURLEncoding.EncodeToString([]byte("dune"))}, true},
+		{"other sort", Request{SortBy: "created_at", Cursor: issued}, true},
+		{"other order", Request{SortBy: "title", Desc: true, Cursor: issued}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.req.DecodeCursor()
+			if !tt.errors {
+				if err != nil || got != nil {
+					t.Errorf("DecodeCursor() = %+v, %v, want nil, nil", got, err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor() error = %v, want %v", err, ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestTrim(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []int
+		limit    int32
+		want     []int
+		wantMore bool
+	}{
+		{"empty", nil, 2, nil, false},
+		{"short page", []int{1}, 2, []int{1}, false},
+		{"full page", []int{1, 2}, 2, []int{1, 2}, false},
+		{"extra row", []int{1, 2, 3}, 2, []int{1, 2}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := Request{Limit: tt.limit}
+			if req.QueryLimit() != tt.limit+1 {
+				t.Fatalf("QueryLimit() = %d, want %d", req.QueryLimit(), tt.limit+1)
+			}
+			got, more := Trim(tt.items, req)
+			if !slices.Equal(got, tt.want) || more != tt.wantMore {
+				t.Errorf("Trim() = %v, %t, want %v, %t", got, more, tt.want, tt.wantMore)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package promotions
+
+import (
+	"cmp"
+	"slices"
+
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/money"
+)
+
+// Quote is the price of a quantity of one SKU after promotions, in the currency of the SKU.
+type Quote struct {
+	BasePrice    money.Money
+	UnitPrice    money.Money
+	Quantity     int32
+	FreeQuantity int32
+	Total        money.Money
+	Applied      []Applied
+}
+
+// Applied is a promotion that took part in a quote and how much it took off the total.
+type Applied struct {
+	Promotion repo.Promotion
+	Discount  money.Money
+}
+
+// Evaluate prices quantity copies of a SKU that costs basePrice under the promotions that
+// match it. The outcome doesn't depend on the order of promotions:
+//
+//   - A non-stackable promotion never combines with another one and competes on its own.
+//   - All stackable promotions combine into one offer: percentages first, compounded in
+//     precedence order, then fixed amounts; the unit price never drops below zero. Of the
+//     buy-X-get-Y deals in the offer only the one giving the most free copies applies,
+//     on top of the discounted unit price.
+//   - The offer with the lowest total wins. Precedence is priority, higher first, then ID,
+//     lower first; on a tie the offer holding the promotion with the highest precedence wins.
+//
+// A fixed amount in another currency than basePrice fails with money.ErrCurrencyMismatch and
+// a total out of range with money.ErrOverflow.
+func Evaluate(basePrice money.Money, quantity int32, promotions []repo.Promotion) (Quote, error) {
+	ranked := slices.Clone(promotions)
+	slices.SortFunc(ranked, comparePrecedence)
+
+	var offers [][]repo.Promotion
+	var stack []repo.Promotion
+	for _, p := range ranked {
+		if p.Stackable {
+			if stack == nil {
+				// The stack competes at the position of its highest-ranked promotion.
+				offers = append(offers, nil)
+			}
+			stack = append(stack, p)
+			continue
+		}
+		offers = append(offers, []repo.Promotion{p})
+	}
+
+	best, err := price(basePrice, quantity, nil)
+	if err != nil {
+		return Quote{}, err
+	}
+	for _, offer := range offers {
+		if offer == nil {
+			offer = stack
+		}
+		q, err := price(basePrice, quantity, offer)
+		if err != nil {
+			return Quote{}, err
+		}
+		if q.Total.Amount < best.Total.Amount {
+			best = q
+		}
+	}
+	return best, nil
+}
+
+// price applies an offer whose promotions are in precedence order.
+func price(basePrice money.Money, quantity int32, offer []repo.Promotion) (Quote, error) {
+	q := Quote{BasePrice: basePrice, UnitPrice: basePrice, Quantity: quantity}
+
+	unitDiscount := func(p repo.Promotion, off money.Money) error {
+		if off.Amount > q.UnitPrice.Amount {
+			off.Amount = q.UnitPrice.Amount
+		}
+		if off.Amount <= 0 {
+			return nil
+		}
+		unitPrice, err := q.UnitPrice.Sub(off)
+		if err != nil {
+			return err
+		}
+		discount, err := off.Mul(int64(quantity))
+		if err != nil {
+			return err
+		}
+		q.UnitPrice = unitPrice
+		q.Applied = append(q.Applied, Applied{Promotion: p, Discount: discount})
+		return nil
+	}
+	for _, p := range offer {
+		if p.Kind != repo.PromotionKindPercent {
+			continue
+		}
+		off, err := q.UnitPrice.Mul(int64(p.PercentOff.Int32))
+		if err != nil {
+			return Quote{}, err
+		}
+		off.Amount /= 100
+		if err := unitDiscount(p, off); err != nil {
+			return Quote{}, err
+		}
+	}
+	for _, p := range offer {
+		if p.Kind != repo.PromotionKindFixed {
+			continue
+		}
+		// A fixed amount without a currency predates currencies and is in the currency of the price.
+		currency := basePrice.Currency
+		if p.Currency.Valid {
+			currency = money.Currency(p.Currency.String)
+		}
+		if err := unitDiscount(p, money.New(int64(p.AmountOffInKopeks.Int32), currency)); err != nil {
+			return Quote{}, err
+		}
+	}
+
+	var deal *repo.Promotion
+	for i, p := range offer {
+		if p.Kind != repo.PromotionKindBuyXGetY {
+			continue
+		}
+		if free := freeCopies(p, quantity); free > q.FreeQuantity {
+			deal, q.FreeQuantity = &offer[i], free
+		}
+	}
+	if deal != nil && q.UnitPrice.Amount > 0 {
+		discount, err := q.UnitPrice.Mul(int64(q.FreeQuantity))
+		if err != nil {
+			return Quote{}, err
+		}
+		q.Applied = append(q.Applied, Applied{Promotion: *deal, Discount: discount})
+	}
+
+	total, err := q.UnitPrice.Mul(int64(quantity - q.FreeQuantity))
+	if err != nil {
+		return Quote{}, err
+	}
+	q.Total = total
+	return q, nil
+}
+
+// freeCopies is how many of quantity copies a buy-X-get-Y deal gives away: Y of every X+Y.
+func freeCopies(p repo.Promotion, quantity int32) int32 {
+	buy, free := p.BuyQuantity.Int32, p.FreeQuantity.Int32
+	return quantity / (buy + free) * free
+}
+
+func comparePrecedence(a, b repo.Promotion) int {
+	if c := cmp.Compare(b.Priority, a.Priority); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
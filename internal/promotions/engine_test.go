@@ -0,0 +1,249 @@
+package promotions
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/money"
+)
+
+func percentOff(id int64, priority, percent int32, stackable bool) repo.Promotion {
+	return repo.Promotion{
+		ID:         id,
+		Kind:       repo.PromotionKindPercent,
+		PercentOff: pgtype.Int4{Int32: percent, Valid: true},
+		Priority:   priority,
+		Stackable:  stackable,
+	}
+}
+
+// amountOff is a fixed discount; an empty currency is the currency of the price.
+func amountOff(id int64, priority, amount int32, currency string, stackable bool) repo.Promotion {
+	return repo.Promotion{
+		ID:                id,
+		Kind:              repo.PromotionKindFixed,
+		AmountOffInKopeks: pgtype.Int4{Int32: amount, Valid: true},
+		Currency:          pgtype.Text{String: currency, Valid: currency != ""},
+		Priority:          priority,
+		Stackable:         stackable,
+	}
+}
+
+func buyXGetY(id int64, priority, buy, free int32, stackable bool) repo.Promotion {
+	return repo.Promotion{
+		ID:           id,
+		Kind:         repo.PromotionKindBuyXGetY,
+		BuyQuantity:  pgtype.Int4{Int32: buy, Valid: true},
+		FreeQuantity: pgtype.Int4{Int32: free, Valid: true},
+		Priority:     priority,
+		Stackable:    stackable,
+	}
+}
+
+// applied is a promotion ID and the discount it gave.
+type applied struct {
+	id       int64
+	discount int64
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name       string
+		price      int64
+		quantity   int32
+		promotions []repo.Promotion
+		unitPrice  int64
+		free       int32
+		total      int64
+		applied    []applied
+	}{
+		{
+			name:     "no promotions",
+			price:    1000,
+			quantity: 2,
+			total:    2000, unitPrice: 1000,
+		},
+		{
+			name:       "percent",
+			price:      1000,
+			quantity:   2,
+			promotions: []repo.Promotion{percentOff(1, 0, 10, false)},
+			unitPrice:  900, total: 1800,
+			applied: []applied{{1, 200}},
+		},
+		{
+			name:       "percent rounds the discount down",
+			price:      999,
+			quantity:   1,
+			promotions: []repo.Promotion{percentOff(1, 0, 15, false)},
+			unitPrice:  850, total: 850,
+			applied: []applied{{1, 149}},
+		},
+		{
+			name:       "best non-stackable wins",
+			price:      1000,
+			quantity:   1,
+			promotions: []repo.Promotion{percentOff(1, 0, 10, false), amountOff(2, 0, 300, "", false)},
+			unitPrice:  700, total: 700,
+			applied: []applied{{2, 300}},
+		},
+		{
+			name:       "tie goes to the higher priority",
+			price:      1000,
+			quantity:   1,
+			promotions: []repo.Promotion{percentOff(1, 1, 20, false), amountOff(2, 5, 200, "", false)},
+			unitPrice:  800, total: 800,
+			applied: []applied{{2, 200}},
+		},
+		{
+			name:       "tie at the same priority goes to the lower ID",
+			price:      1000,
+			quantity:   1,
+			promotions: []repo.Promotion{amountOff(4, 3, 200, "", false), percentOff(3, 3, 20, false)},
+			unitPrice:  800, total: 800,
+			applied: []applied{{3, 200}},
+		},
+		{
+			name:     "stackable percentages compound before fixed amounts",
+			price:    1000,
+			quantity: 1,
+			promotions: []repo.Promotion{
+				amountOff(1, 9, 50, "", true),
+				percentOff(2, 2, 10, true),
+				percentOff(3, 1, 10, true),
+			},
+			unitPrice: 760, total: 760,
+			applied: []applied{{2, 100}, {3, 90}, {1, 50}},
+		},
+		{
+			name:     "non-stackable beats a weaker stack",
+			price:    1000,
+			quantity: 1,
+			promotions: []repo.Promotion{
+				percentOff(1, 0, 10, true),
+				amountOff(2, 0, 50, "", true),
+				percentOff(3, 0, 30, false),
+			},
+			unitPrice: 700, total: 700,
+			applied: []applied{{3, 300}},
+		},
+		{
+			name:     "stack beats a weaker non-stackable",
+			price:    1000,
+			quantity: 1,
+			promotions: []repo.Promotion{
+				percentOff(1, 0, 20, true),
+				amountOff(2, 0, 100, "", true),
+				percentOff(3, 0, 25, false),
+			},
+			unitPrice: 700, total: 700,
+			applied: []applied{{1, 200}, {2, 100}},
+		},
+		{
+			name:       "price doesn't drop below zero",
+			price:      1000,
+			quantity:   3,
+			promotions: []repo.Promotion{amountOff(1, 0, 1500, "RUB", false)},
+			unitPrice:  0, total: 0,
+			applied: []applied{{1, 3000}},
+		},
+		{
+			name:       "buy two get one",
+			price:      1000,
+			quantity:   7,
+			promotions: []repo.Promotion{buyXGetY(1, 0, 2, 1, false)},
+			unitPrice:  1000, free: 2, total: 5000,
+			applied: []applied{{1, 2000}},
+		},
+		{
+			name:       "too few copies for a deal",
+			price:      1000,
+			quantity:   2,
+			promotions: []repo.Promotion{buyXGetY(1, 0, 2, 1, false)},
+			unitPrice:  1000, total: 2000,
+		},
+		{
+			name:     "only the most generous stacked deal applies, on the discounted price",
+			price:    1000,
+			quantity: 4,
+			promotions: []repo.Promotion{
+				buyXGetY(1, 5, 2, 1, true),
+				buyXGetY(2, 0, 1, 1, true),
+				percentOff(3, 0, 10, true),
+			},
+			unitPrice: 900, free: 2, total: 1800,
+			applied: []applied{{3, 400}, {2, 1800}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The outcome must not depend on the order the promotions come in.
+			reversed := slices.Clone(tt.promotions)
+			slices.Reverse(reversed)
+			for _, promotions := range [][]repo.Promotion{tt.promotions, reversed} {
+				q, err := Evaluate(money.New(tt.price, "RUB"), tt.quantity, promotions)
+				if err != nil {
+					t.Fatalf("Evaluate() error = %v", err)
+				}
+				if q.UnitPrice != money.New(tt.unitPrice, "RUB") {
+					t.Errorf("UnitPrice = %v, want %d", q.UnitPrice, tt.unitPrice)
+				}
+				if q.FreeQuantity != tt.free {
+					t.Errorf("FreeQuantity = %d, want %d", q.FreeQuantity, tt.free)
+				}
+				if q.Total != money.New(tt.total, "RUB") {
+					t.Errorf("Total = %v, want %d", q.Total, tt.total)
+				}
+				var got []applied
+				for _, a := range q.Applied {
+					got = append(got, applied{a.Promotion.ID, a.Discount.Amount})
+				}
+				if !slices.Equal(got, tt.applied) {
+					t.Errorf("Applied = %v, want %v", got, tt.applied)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		price      money.Money
+		quantity   int32
+		promotions []repo.Promotion
+		wantErr    error
+	}{
+		{
+			name:       "fixed amount in another currency",
+			price:      money.New(1000, "RUB"),
+			quantity:   1,
+			promotions: []repo.Promotion{amountOff(1, 0, 100, "USD", false)},
+			wantErr:    money.ErrCurrencyMismatch,
+		},
+		{
+			name:       "stacked fixed amount in another currency",
+			price:      money.New(1000, "USD"),
+			quantity:   1,
+			promotions: []repo.Promotion{percentOff(1, 0, 10, true), amountOff(2, 0, 100, "EUR", true)},
+			wantErr:    money.ErrCurrencyMismatch,
+		},
+		{
+			name:     "total out of range",
+			price:    money.New(math.MaxInt64/2, "RUB"),
+			quantity: 3,
+			wantErr:  money.ErrOverflow,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Evaluate(tt.price, tt.quantity, tt.promotions); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Evaluate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
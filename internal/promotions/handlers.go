@@ -0,0 +1,377 @@
+package promotions
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/money"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+	"github.com/nikallow/bookstores-api/internal/response"
+)
+
+type Handler struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// CreatePromotion
+//
+//	@Summary		Создать акцию
+//	@Description	Создаёт скидку в процентах (percent), фиксированную скидку в копейках (fixed) или акцию «купи X - получи Y бесплатно» (buy_x_get_y).
+//	@Description	Акция действует в окне starts_at - ends_at и на SKU, подходящие под все заданные области: магазин, книгу, автора, жанр (с поджанрами).
+//	@Description	Фиксированная скидка задаётся в минорных единицах currency (по умолчанию - валюта магазина акции или RUB) и действует только на SKU в этой валюте.
+//	@Tags			promotions
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		PromotionRequest		true	"Данные акции"
+//	@Success		201		{object}	PromotionResponse		"Акция создана"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"Магазин, книга, автор или жанр не найдены"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/promotions [post]
+func (h *Handler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	var req PromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read create promotion request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed for create promotion request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	promotion, err := h.service.Create(r.Context(), req)
+	if err != nil {
+		writePromotionError(w, r, err, 0)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusCreated, toPromotionResponse(promotion))
+}
+
+// ListPromotions
+//
+//	@Summary		Получить список акций
+//	@Description	Возвращает страницу акций, отсортированных по ID. Для следующей страницы передайте next_cursor из ответа.
+//	@Tags			promotions
+//	@Produce		json
+//	@Param			active_at	query		string					false	"Только акции, действующие в этот момент (RFC3339)"
+//	@Param			store_uuid	query		string					false	"Только акции, ограниченные этим магазином"
+//	@Param			limit		query		int						false	"Размер страницы (1-100)"	default(20)
+//	@Param			cursor		query		string					false	"Курсор следующей страницы"
+//	@Param			order		query		string					false	"Направление сортировки"	Enums(asc, desc)	default(asc)
+//	@Success		200			{object}	PromotionListResponse	"Страница акций"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Магазин не найден"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/promotions [get]
+func (h *Handler) ListPromotions(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	query := r.URL.Query()
+	page, err := pagination.FromQuery(query, "id")
+	if err != nil {
+		log.Warn("Invalid list promotions parameters", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := ListPromotionsParams{Request: page}
+	if activeAt := query.Get("active_at"); activeAt != "" {
+		t, err := time.Parse(time.RFC3339, activeAt)
+		if err != nil {
+			response.WriteError(w, r, http.StatusBadRequest, "Invalid active_at, expected RFC3339")
+			return
+		}
+		params.ActiveAt = &t
+	}
+	if storeUUID := query.Get("store_uuid"); storeUUID != "" {
+		u, err := uuid.Parse(storeUUID)
+		if err != nil {
+			response.WriteError(w, r, http.StatusBadRequest, "Invalid store_uuid")
+			return
+		}
+		params.StoreUUID = &u
+	}
+
+	promotions, nextCursor, err := h.service.List(r.Context(), params)
+	if err != nil {
+		switch {
+		case errors.Is(err, pagination.ErrInvalidCursor):
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		case errors.Is(err, ErrStoreNotFound):
+			response.WriteError(w, r, http.StatusNotFound, "Store not found")
+		default:
+			log.Error("Failed to list promotions", "error", err)
+			response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	resp := make([]PromotionResponse, len(promotions))
+	for i, p := range promotions {
+		resp[i] = toPromotionResponse(p)
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, PromotionListResponse{Items: resp, NextCursor: nextCursor})
+}
+
+// GetPromotion
+//
+//	@Summary		Получить акцию
+//	@Description	Возвращает акцию по её ID.
+//	@Tags			promotions
+//	@Produce		json
+//	@Param			promotionID	path		int						true	"ID акции"
+//	@Success		200			{object}	PromotionResponse		"Инфо об акции"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Акция отсутствует"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/promotions/{promotionID} [get]
+func (h *Handler) GetPromotion(w http.ResponseWriter, r *http.Request) {
+	promotionID, ok := parsePromotionID(w, r)
+	if !ok {
+		return
+	}
+
+	promotion, err := h.service.GetByID(r.Context(), promotionID)
+	if err != nil {
+		writePromotionError(w, r, err, promotionID)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toPromotionResponse(promotion))
+}
+
+// UpdatePromotion
+//
+//	@Summary		Обновить акцию
+//	@Description	Полностью заменяет акцию. Не переданные необязательные поля очищаются.
+//	@Tags			promotions
+//	@Accept			json
+//	@Produce		json
+//	@Param			promotionID	path		int						true	"ID акции"
+//	@Param			input		body		PromotionRequest		true	"Новые данные акции"
+//	@Success		200			{object}	PromotionResponse		"Обновлённая акция"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Акция, магазин, книга, автор или жанр не найдены"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/promotions/{promotionID} [put]
+func (h *Handler) UpdatePromotion(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	promotionID, ok := parsePromotionID(w, r)
+	if !ok {
+		return
+	}
+
+	var req PromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read update promotion request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed for update promotion request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	promotion, err := h.service.Update(r.Context(), promotionID, req)
+	if err != nil {
+		writePromotionError(w, r, err, promotionID)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toPromotionResponse(promotion))
+}
+
+// DeletePromotion
+//
+//	@Summary		Удалить акцию
+//	@Description	Удаляет акцию. Цены SKU сразу пересчитываются без неё.
+//	@Tags			promotions
+//	@Param			promotionID	path	int	true	"ID акции"
+//	@Success		204			"Акция удалена"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Акция отсутствует"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/promotions/{promotionID} [delete]
+func (h *Handler) DeletePromotion(w http.ResponseWriter, r *http.Request) {
+	promotionID, ok := parsePromotionID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), promotionID); err != nil {
+		writePromotionError(w, r, err, promotionID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetEffectivePrice
+//
+//	@Summary		Цена SKU с учётом акций
+//	@Description	Возвращает цену quantity экземпляров SKU после действующих сейчас акций и список применённых акций со скидкой каждой.
+//	@Description	Несуммируемая акция конкурирует с остальными одна; все суммируемые объединяются: сначала проценты, затем фиксированные скидки,
+//	@Description	затем лучшая из акций «купи X - получи Y». Побеждает вариант с наименьшей суммой, при равенстве - с акцией выше по приоритету (затем по меньшему ID).
+//	@Tags			skus
+//	@Produce		json
+//	@Param			skuUUID		path		string					true	"UUID товарной позиции (SKU)"
+//	@Param			quantity	query		int						false	"Количество экземпляров"	default(1)
+//	@Success		200			{object}	EffectivePriceResponse	"Цена с учётом акций"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"SKU не найден"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/skus/{skuUUID}/effective-price [get]
+func (h *Handler) GetEffectivePrice(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	skuUUID, err := uuid.Parse(chi.URLParam(r, "skuUUID"))
+	if err != nil {
+		log.Warn("Invalid sku UUID format", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid sku uuid format")
+		return
+	}
+
+	quantity := int32(1)
+	if q := r.URL.Query().Get("quantity"); q != "" {
+		v, err := strconv.ParseInt(q, 10, 32)
+		if err != nil || v < 1 || v > maxQuoteQuantity {
+			response.WriteError(w, r, http.StatusBadRequest, "quantity must be between 1 and "+strconv.Itoa(maxQuoteQuantity))
+			return
+		}
+		quantity = int32(v)
+	}
+
+	quote, err := h.service.QuoteSKU(r.Context(), skuUUID, quantity)
+	if err != nil {
+		if errors.Is(err, ErrSKUNotFound) {
+			response.WriteError(w, r, http.StatusNotFound, "SKU not found")
+			return
+		}
+		log.Error("Failed to quote sku", "error", err, "sku_uuid", skuUUID)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	applied := make([]AppliedPromotionResponse, len(quote.Applied))
+	for i, a := range quote.Applied {
+		applied[i] = AppliedPromotionResponse{
+			ID:               a.Promotion.ID,
+			Name:             a.Promotion.Name,
+			Kind:             string(a.Promotion.Kind),
+			DiscountInKopeks: a.Discount.Amount,
+		}
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, EffectivePriceResponse{
+		SkuUUID:           skuUUID,
+		Quantity:          quote.Quantity,
+		Currency:          string(quote.Total.Currency),
+		BasePriceInKopeks: quote.BasePrice.Amount,
+		UnitPriceInKopeks: quote.UnitPrice.Amount,
+		FreeQuantity:      quote.FreeQuantity,
+		TotalInKopeks:     quote.Total.Amount,
+		Promotions:        applied,
+	})
+}
+
+func writePromotionError(w http.ResponseWriter, r *http.Request, err error, promotionID int64) {
+	switch {
+	case errors.Is(err, ErrPromotionNotFound):
+		response.WriteError(w, r, http.StatusNotFound, "Promotion not found")
+	case errors.Is(err, ErrStoreNotFound), errors.Is(err, ErrBookNotFound),
+		errors.Is(err, ErrAuthorNotFound), errors.Is(err, ErrGenreNotFound):
+		response.WriteError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrInvalidWindow), errors.Is(err, money.ErrUnknownCurrency):
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+	default:
+		middleware.LoggerFromContext(r.Context()).Error("Promotion operation failed", "error", err, "promotion_id", promotionID)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func parsePromotionID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	promotionIDStr := chi.URLParam(r, "promotionID")
+	promotionID, err := strconv.ParseInt(promotionIDStr, 10, 64)
+	if err != nil {
+		middleware.LoggerFromContext(r.Context()).Warn("Invalid promotion ID format", "promotion_id", promotionIDStr)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid promotion ID format")
+		return 0, false
+	}
+	return promotionID, true
+}
+
+func toPromotionResponse(p repo.Promotion) PromotionResponse {
+	return PromotionResponse{
+		ID:                p.ID,
+		Name:              p.Name,
+		Kind:              string(p.Kind),
+		PercentOff:        int4ToInt32p(p.PercentOff),
+		AmountOffInKopeks: int4ToInt32p(p.AmountOffInKopeks),
+		Currency:          textToStringp(p.Currency),
+		BuyQuantity:       int4ToInt32p(p.BuyQuantity),
+		FreeQuantity:      int4ToInt32p(p.FreeQuantity),
+		Priority:          p.Priority,
+		Stackable:         p.Stackable,
+		StartsAt:          timestamptzToTimep(p.StartsAt),
+		EndsAt:            timestamptzToTimep(p.EndsAt),
+		StoreID:           int8ToInt64p(p.StoreID),
+		BookID:            int8ToInt64p(p.BookID),
+		AuthorID:          int8ToInt64p(p.AuthorID),
+		GenreID:           int8ToInt64p(p.GenreID),
+		CreatedAt:         p.CreatedAt.Time,
+		UpdatedAt:         p.UpdatedAt.Time,
+	}
+}
+
+func int4ToInt32p(i pgtype.Int4) *int32 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int32
+}
+
+func textToStringp(t pgtype.Text) *string {
+	if !t.Valid {
+		return nil
+	}
+	return &t.String
+}
+
+func int8ToInt64p(i pgtype.Int8) *int64 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int64
+}
+
+func timestamptzToTimep(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,79 @@
+package promotions
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+)
+
+// maxQuoteQuantity bounds the quantity of GET /skus/{skuUUID}/effective-price.
+const maxQuoteQuantity = 10000
+
+type PromotionRequest struct {
+	Name              string     `json:"name"                           validate:"required,max=200"`
+	Kind              string     `json:"kind"                           validate:"required,oneof=percent fixed buy_x_get_y"                                     enums:"percent,fixed,buy_x_get_y"`
+	PercentOff        *int32     `json:"percent_off,omitempty"          validate:"required_if=Kind percent,excluded_unless=Kind percent,omitempty,min=1,max=100"`
+	AmountOffInKopeks *int32     `json:"amount_off_in_kopeks,omitempty" validate:"required_if=Kind fixed,excluded_unless=Kind fixed,omitempty,min=1"`
+	Currency          *string    `json:"currency,omitempty"             validate:"excluded_unless=Kind fixed,omitempty,len=3" example:"RUB"`
+	BuyQuantity       *int32     `json:"buy_quantity,omitempty"         validate:"required_if=Kind buy_x_get_y,excluded_unless=Kind buy_x_get_y,omitempty,min=1"`
+	FreeQuantity      *int32     `json:"free_quantity,omitempty"        validate:"required_if=Kind buy_x_get_y,excluded_unless=Kind buy_x_get_y,omitempty,min=1"`
+	Priority          int32      `json:"priority"`
+	Stackable         bool       `json:"stackable"`
+	StartsAt          *time.Time `json:"starts_at,omitempty"`
+	EndsAt            *time.Time `json:"ends_at,omitempty"`
+	StoreUUID         *uuid.UUID `json:"store_uuid,omitempty"`
+	BookID            *int64     `json:"book_id,omitempty"`
+	AuthorID          *int64     `json:"author_id,omitempty"`
+	GenreID           *int64     `json:"genre_id,omitempty"`
+}
+
+type ListPromotionsParams struct {
+	pagination.Request
+	ActiveAt  *time.Time
+	StoreUUID *uuid.UUID
+}
+
+type PromotionResponse struct {
+	ID                int64      `json:"id"`
+	Name              string     `json:"name"`
+	Kind              string     `json:"kind"                           enums:"percent,fixed,buy_x_get_y"`
+	PercentOff        *int32     `json:"percent_off,omitempty"`
+	AmountOffInKopeks *int32     `json:"amount_off_in_kopeks,omitempty"`
+	Currency          *string    `json:"currency,omitempty"`
+	BuyQuantity       *int32     `json:"buy_quantity,omitempty"`
+	FreeQuantity      *int32     `json:"free_quantity,omitempty"`
+	Priority          int32      `json:"priority"`
+	Stackable         bool       `json:"stackable"`
+	StartsAt          *time.Time `json:"starts_at,omitempty"`
+	EndsAt            *time.Time `json:"ends_at,omitempty"`
+	StoreID           *int64     `json:"store_id,omitempty"`
+	BookID            *int64     `json:"book_id,omitempty"`
+	AuthorID          *int64     `json:"author_id,omitempty"`
+	GenreID           *int64     `json:"genre_id,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+type PromotionListResponse struct {
+	Items      []PromotionResponse `json:"items"`
+	NextCursor *string             `json:"next_cursor"`
+}
+
+type EffectivePriceResponse struct {
+	SkuUUID           uuid.UUID                  `json:"sku_uuid"`
+	Quantity          int32                      `json:"quantity"`
+	Currency          string                     `json:"currency"`
+	BasePriceInKopeks int64                      `json:"base_price_in_kopeks"`
+	UnitPriceInKopeks int64                      `json:"unit_price_in_kopeks"`
+	FreeQuantity      int32                      `json:"free_quantity"`
+	TotalInKopeks     int64                      `json:"total_in_kopeks"`
+	Promotions        []AppliedPromotionResponse `json:"promotions"`
+}
+
+type AppliedPromotionResponse struct {
+	ID               int64  `json:"id"`
+	Name             string `json:"name"`
+	Kind             string `json:"kind"`
+	DiscountInKopeks int64  `json:"discount_in_kopeks"`
+}
@@ -0,0 +1,399 @@
+package promotions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/books"
+	"github.com/nikallow/bookstores-api/internal/inventory"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/money"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+	"github.com/nikallow/bookstores-api/internal/stores"
+)
+
+var (
+	ErrPromotionNotFound = errors.New("promotion not found")
+	ErrInvalidWindow     = errors.New("ends_at must be after starts_at")
+	ErrStoreNotFound     = stores.ErrStoreNotFound
+	ErrBookNotFound      = books.ErrBookNotFound
+	ErrAuthorNotFound    = books.ErrAuthorNotFound
+	ErrGenreNotFound     = books.ErrGenreNotFound
+	ErrSKUNotFound       = inventory.ErrSKUNotFound
+)
+
+// pgForeignKeyViolation is the SQLSTATE of a failed REFERENCES constraint, e.g. promotions.book_id.
+const pgForeignKeyViolation = "23503"
+
+type Service interface {
+	Create(ctx context.Context, params PromotionRequest) (repo.Promotion, error)
+	List(ctx context.Context, params ListPromotionsParams) ([]repo.Promotion, *string, error)
+	GetByID(ctx context.Context, id int64) (repo.Promotion, error)
+	Update(ctx context.Context, id int64, params PromotionRequest) (repo.Promotion, error)
+	Delete(ctx context.Context, id int64) error
+	QuoteSKU(ctx context.Context, skuUUID uuid.UUID, quantity int32) (Quote, error)
+	EffectivePrices(ctx context.Context, skus []repo.Sku) (map[int64]int32, error)
+}
+
+type service struct {
+	repo repo.Querier
+}
+
+func NewService(repo repo.Querier) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Create(ctx context.Context, params PromotionRequest) (repo.Promotion, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	fields, err := s.resolve(ctx, params)
+	if err != nil {
+		return repo.Promotion{}, err
+	}
+
+	promotion, err := s.repo.CreatePromotion(ctx, fields)
+	if err != nil {
+		if scopeErr := scopeError(err); scopeErr != nil {
+			return repo.Promotion{}, scopeErr
+		}
+		log.Error("Failed to create promotion", "error", err)
+		return repo.Promotion{}, fmt.Errorf("failed to create promotion: %w", err)
+	}
+
+	log.Info("Promotion created successfully", "promotion_id", promotion.ID)
+	return promotion, nil
+}
+
+func (s *service) List(ctx context.Context, params ListPromotionsParams) ([]repo.Promotion, *string, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	cursor, err := params.DecodeCursor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queryParams := repo.ListPromotionsParams{
+		PageLimit: params.QueryLimit(),
+	}
+	if params.ActiveAt != nil {
+		queryParams.ActiveAt = pgtype.Timestamptz{Time: *params.ActiveAt, Valid: true}
+	}
+	if params.StoreUUID != nil {
+		store, err := s.store(ctx, *params.StoreUUID)
+		if err != nil {
+			return nil, nil, err
+		}
+		queryParams.StoreID = pgtype.Int8{Int64: store.ID, Valid: true}
+	}
+	if cursor != nil {
+		queryParams.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+	}
+
+	var promotions []repo.Promotion
+	if params.Desc {
+		promotions, err = s.repo.ListPromotionsDesc(ctx, repo.ListPromotionsDescParams(queryParams))
+	} else {
+		promotions, err = s.repo.ListPromotions(ctx, queryParams)
+	}
+	if err != nil {
+		log.Error("Failed to list promotions", "error", err)
+		return nil, nil, fmt.Errorf("failed to list promotions: %w", err)
+	}
+
+	promotions, hasMore := pagination.Trim(promotions, params.Request)
+	if !hasMore {
+		return promotions, nil, nil
+	}
+	last := promotions[len(promotions)-1]
+	next := params.NextCursor(strconv.FormatInt(last.ID, 10), last.ID)
+	return promotions, &next, nil
+}
+
+func (s *service) GetByID(ctx context.Context, id int64) (repo.Promotion, error) {
+	promotion, err := s.repo.GetPromotionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Promotion{}, ErrPromotionNotFound
+		}
+		return repo.Promotion{}, fmt.Errorf("failed to get promotion: %w", err)
+	}
+	return promotion, nil
+}
+
+func (s *service) Update(ctx context.Context, id int64, params PromotionRequest) (repo.Promotion, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	fields, err := s.resolve(ctx, params)
+	if err != nil {
+		return repo.Promotion{}, err
+	}
+
+	promotion, err := s.repo.UpdatePromotion(ctx, repo.UpdatePromotionParams{
+		ID:                id,
+		Name:              fields.Name,
+		Kind:              fields.Kind,
+		PercentOff:        fields.PercentOff,
+		AmountOffInKopeks: fields.AmountOffInKopeks,
+		BuyQuantity:       fields.BuyQuantity,
+		FreeQuantity:      fields.FreeQuantity,
+		Priority:          fields.Priority,
+		Stackable:         fields.Stackable,
+		StartsAt:          fields.StartsAt,
+		EndsAt:            fields.EndsAt,
+		StoreID:           fields.StoreID,
+		BookID:            fields.BookID,
+		AuthorID:          fields.AuthorID,
+		GenreID:           fields.GenreID,
+		Currency:          fields.Currency,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Promotion{}, ErrPromotionNotFound
+		}
+		if scopeErr := scopeError(err); scopeErr != nil {
+			return repo.Promotion{}, scopeErr
+		}
+		log.Error("Failed to update promotion", "error", err, "promotion_id", id)
+		return repo.Promotion{}, fmt.Errorf("failed to update promotion: %w", err)
+	}
+
+	log.Info("Promotion updated successfully", "promotion_id", id)
+	return promotion, nil
+}
+
+func (s *service) Delete(ctx context.Context, id int64) error {
+	log := middleware.LoggerFromContext(ctx)
+
+	deleted, err := s.repo.DeletePromotion(ctx, id)
+	if err != nil {
+		log.Error("Failed to delete promotion", "error", err, "promotion_id", id)
+		return fmt.Errorf("failed to delete promotion: %w", err)
+	}
+	if deleted == 0 {
+		return ErrPromotionNotFound
+	}
+
+	log.Info("Promotion deleted successfully", "promotion_id", id)
+	return nil
+}
+
+// QuoteSKU - GET /skus/{skuUUID}/effective-price
+func (s *service) QuoteSKU(ctx context.Context, skuUUID uuid.UUID, quantity int32) (Quote, error) {
+	row, err := s.repo.GetSKUByUUID(ctx, uuidToPgUUID(skuUUID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Quote{}, ErrSKUNotFound
+		}
+		return Quote{}, err
+	}
+
+	quotes, err := s.quote(ctx, []repo.Sku{row.Sku}, quantity, time.Now())
+	if err != nil {
+		return Quote{}, err
+	}
+	return quotes[row.Sku.ID], nil
+}
+
+// EffectivePrices returns the price of one copy of each SKU after promotions, keyed by SKU ID.
+func (s *service) EffectivePrices(ctx context.Context, skus []repo.Sku) (map[int64]int32, error) {
+	quotes, err := s.quote(ctx, skus, 1, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	prices := make(map[int64]int32, len(quotes))
+	for id, q := range quotes {
+		if prices[id], err = q.Total.Int32(); err != nil {
+			return nil, err
+		}
+	}
+	return prices, nil
+}
+
+// quote evaluates the promotions running at the given moment for every SKU, keyed by SKU ID.
+// Candidates for all SKUs are loaded with one query and matched to each SKU here.
+func (s *service) quote(ctx context.Context, skus []repo.Sku, quantity int32, at time.Time) (map[int64]Quote, error) {
+	quotes := make(map[int64]Quote, len(skus))
+	if len(skus) == 0 {
+		return quotes, nil
+	}
+
+	var storeIDs, bookIDs []int64
+	for _, sku := range skus {
+		storeIDs = append(storeIDs, sku.StoreID)
+		bookIDs = append(bookIDs, sku.BookID)
+	}
+
+	authors, err := s.repo.ListBookAuthors(ctx, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+	bookAuthors := make(map[int64]map[int64]bool)
+	var authorIDs []int64
+	for _, a := range authors {
+		if a.Role != repo.AuthorRoleAuthor {
+			continue
+		}
+		if bookAuthors[a.BookID] == nil {
+			bookAuthors[a.BookID] = make(map[int64]bool)
+		}
+		bookAuthors[a.BookID][a.Author.ID] = true
+		authorIDs = append(authorIDs, a.Author.ID)
+	}
+
+	lineage, err := s.repo.ListBookGenreLineage(ctx, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+	bookGenres := make(map[int64]map[int64]bool)
+	var genreIDs []int64
+	for _, g := range lineage {
+		if bookGenres[g.BookID] == nil {
+			bookGenres[g.BookID] = make(map[int64]bool)
+		}
+		bookGenres[g.BookID][g.GenreID] = true
+		genreIDs = append(genreIDs, g.GenreID)
+	}
+
+	candidates, err := s.repo.ListCandidatePromotions(ctx, repo.ListCandidatePromotionsParams{
+		At:        pgtype.Timestamptz{Time: at, Valid: true},
+		StoreIds:  storeIDs,
+		BookIds:   bookIDs,
+		AuthorIds: authorIDs,
+		GenreIds:  genreIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sku := range skus {
+		var matching []repo.Promotion
+		for _, p := range candidates {
+			if matches(p, sku, bookAuthors[sku.BookID], bookGenres[sku.BookID]) {
+				matching = append(matching, p)
+			}
+		}
+		q, err := Evaluate(money.New(int64(sku.PriceInKopeks), money.Currency(sku.Currency)), quantity, matching)
+		if err != nil {
+			return nil, fmt.Errorf("failed to quote sku %d: %w", sku.ID, err)
+		}
+		quotes[sku.ID] = q
+	}
+	return quotes, nil
+}
+
+// matches tells whether every scope the promotion sets covers the SKU. A genre covers
+// the books of its subgenres too. A fixed amount off only applies in its own currency.
+func matches(p repo.Promotion, sku repo.Sku, authors, genres map[int64]bool) bool {
+	return (!p.Currency.Valid || p.Currency.String == sku.Currency) &&
+		(!p.StoreID.Valid || p.StoreID.Int64 == sku.StoreID) &&
+		(!p.BookID.Valid || p.BookID.Int64 == sku.BookID) &&
+		(!p.AuthorID.Valid || authors[p.AuthorID.Int64]) &&
+		(!p.GenreID.Valid || genres[p.GenreID.Int64])
+}
+
+// resolve checks a promotion request and turns it into the stored fields.
+func (s *service) resolve(ctx context.Context, params PromotionRequest) (repo.CreatePromotionParams, error) {
+	if params.StartsAt != nil && params.EndsAt != nil && !params.EndsAt.After(*params.StartsAt) {
+		return repo.CreatePromotionParams{}, ErrInvalidWindow
+	}
+
+	fields := repo.CreatePromotionParams{
+		Name:              params.Name,
+		Kind:              repo.PromotionKind(params.Kind),
+		PercentOff:        int32ToPgInt4p(params.PercentOff),
+		AmountOffInKopeks: int32ToPgInt4p(params.AmountOffInKopeks),
+		BuyQuantity:       int32ToPgInt4p(params.BuyQuantity),
+		FreeQuantity:      int32ToPgInt4p(params.FreeQuantity),
+		Priority:          params.Priority,
+		Stackable:         params.Stackable,
+		StartsAt:          timeToPgTimestamptzp(params.StartsAt),
+		EndsAt:            timeToPgTimestamptzp(params.EndsAt),
+		BookID:            int64ToPgInt8p(params.BookID),
+		AuthorID:          int64ToPgInt8p(params.AuthorID),
+		GenreID:           int64ToPgInt8p(params.GenreID),
+	}
+	currency := money.DefaultCurrency
+	if params.StoreUUID != nil {
+		store, err := s.store(ctx, *params.StoreUUID)
+		if err != nil {
+			return repo.CreatePromotionParams{}, err
+		}
+		fields.StoreID = pgtype.Int8{Int64: store.ID, Valid: true}
+		currency = money.Currency(store.Currency)
+	}
+	if fields.Kind == repo.PromotionKindFixed {
+		if params.Currency != nil {
+			c, err := money.ParseCurrency(*params.Currency)
+			if err != nil {
+				return repo.CreatePromotionParams{}, err
+			}
+			currency = c
+		}
+		fields.Currency = pgtype.Text{String: string(currency), Valid: true}
+	}
+	return fields, nil
+}
+
+func (s *service) store(ctx context.Context, storeUUID uuid.UUID) (repo.Store, error) {
+	store, err := s.repo.GetStoreByUUID(ctx, uuidToPgUUID(storeUUID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Store{}, ErrStoreNotFound
+		}
+		return repo.Store{}, err
+	}
+	return store, nil
+}
+
+// scopeError maps a reference to a missing book, author or genre to its error,
+// and returns nil for any other error.
+func scopeError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != pgForeignKeyViolation {
+		return nil
+	}
+	switch pgErr.ConstraintName {
+	case "promotions_book_id_fkey":
+		return ErrBookNotFound
+	case "promotions_author_id_fkey":
+		return ErrAuthorNotFound
+	case "promotions_genre_id_fkey":
+		return ErrGenreNotFound
+	case "promotions_store_id_fkey":
+		return ErrStoreNotFound
+	}
+	return nil
+}
+
+func int32ToPgInt4p(i *int32) pgtype.Int4 {
+	if i == nil {
+		return pgtype.Int4{Valid: false}
+	}
+	return pgtype.Int4{Int32: *i, Valid: true}
+}
+
+func int64ToPgInt8p(i *int64) pgtype.Int8 {
+	if i == nil {
+		return pgtype.Int8{Valid: false}
+	}
+	return pgtype.Int8{Int64: *i, Valid: true}
+}
+
+func timeToPgTimestamptzp(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{Valid: false}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
+
+func uuidToPgUUID(u uuid.UUID) pgtype.UUID {
+	return pgtype.UUID{Bytes: u, Valid: true}
+}
@@ -0,0 +1,249 @@
+package publishers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+	"github.com/nikallow/bookstores-api/internal/response"
+)
+
+type Handler struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// CreatePublisher
+//
+//	@Summary		Создать издательство
+//	@Description	Добавляет издательство в справочник. Книга ссылается на него полем publisher_id.
+//	@Tags			publishers
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		CreatePublisherRequest	true	"Данные издательства"
+//	@Success		201		{object}	PublisherResponse		"Издательство создано"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		409		{object}	response.ErrorResponse	"Издательство с таким названием уже есть"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/publishers [post]
+func (h *Handler) CreatePublisher(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	var req CreatePublisherRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read create publisher request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed for create publisher request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	publisher, err := h.service.Create(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, ErrPublisherExists) {
+			response.WriteError(w, r, http.StatusConflict, err.Error())
+			return
+		}
+		log.Error("Failed to create publisher", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusCreated, toPublisherResponse(publisher))
+}
+
+// ListPublishers
+//
+//	@Summary		Получить список издательств
+//	@Description	Возвращает страницу издательств, отсортированных по названию. Для следующей страницы передайте next_cursor из ответа.
+//	@Tags			publishers
+//	@Produce		json
+//	@Param			name	query		string					false	"Фильтр по названию (подстрока)"
+//	@Param			limit	query		int						false	"Размер страницы (1-100)"	default(20)
+//	@Param			cursor	query		string					false	"Курсор следующей страницы"
+//	@Param			order	query		string					false	"Направление сортировки"	Enums(asc, desc)	default(asc)
+//	@Success		200		{object}	PublisherListResponse	"Страница издательств"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/publishers [get]
+func (h *Handler) ListPublishers(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	query := r.URL.Query()
+	page, err := pagination.FromQuery(query, publisherSorts...)
+	if err != nil {
+		log.Warn("Invalid list publishers parameters", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := ListPublishersParams{Request: page}
+	if name := query.Get("name"); name != "" {
+		params.Name = &name
+	}
+
+	publishers, nextCursor, err := h.service.List(r.Context(), params)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error("Failed to list publishers", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	resp := make([]PublisherResponse, len(publishers))
+	for i, a := range publishers {
+		resp[i] = toPublisherResponse(a)
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, PublisherListResponse{Items: resp, NextCursor: nextCursor})
+}
+
+// GetPublisher
+//
+//	@Summary		Получить издательство
+//	@Description	Возвращает издательство по его ID.
+//	@Tags			publishers
+//	@Produce		json
+//	@Param			publisherID	path		int						true	"ID издательства"
+//	@Success		200			{object}	PublisherResponse		"Инфо об издательстве"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Издательство отсутствует"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/publishers/{publisherID} [get]
+func (h *Handler) GetPublisher(w http.ResponseWriter, r *http.Request) {
+	publisherID, ok := parsePublisherID(w, r)
+	if !ok {
+		return
+	}
+
+	publisher, err := h.service.GetByID(r.Context(), publisherID)
+	if err != nil {
+		writePublisherError(w, r, err, publisherID)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toPublisherResponse(publisher))
+}
+
+// UpdatePublisher
+//
+//	@Summary		Обновить издательство
+//	@Description	Заменяет данные издательства. Не переданный website очищается.
+//	@Tags			publishers
+//	@Accept			json
+//	@Produce		json
+//	@Param			publisherID	path		int						true	"ID издательства"
+//	@Param			input		body		UpdatePublisherRequest	true	"Новые данные издательства"
+//	@Success		200			{object}	PublisherResponse		"Обновлённое издательство"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Издательство отсутствует"
+//	@Failure		409			{object}	response.ErrorResponse	"Издательство с таким названием уже есть"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/publishers/{publisherID} [put]
+func (h *Handler) UpdatePublisher(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	publisherID, ok := parsePublisherID(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdatePublisherRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read update publisher request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed for update publisher request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	publisher, err := h.service.Update(r.Context(), publisherID, req)
+	if err != nil {
+		writePublisherError(w, r, err, publisherID)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toPublisherResponse(publisher))
+}
+
+// DeletePublisher
+//
+//	@Summary		Удалить издательство
+//	@Description	Удаляет издательство, если на него не ссылается ни одна книга, включая удалённые.
+//	@Tags			publishers
+//	@Param			publisherID	path	int	true	"ID издательства"
+//	@Success		204			"Издательство удалено"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Издательство отсутствует"
+//	@Failure		409			{object}	response.ErrorResponse	"У издательства есть книги"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/publishers/{publisherID} [delete]
+func (h *Handler) DeletePublisher(w http.ResponseWriter, r *http.Request) {
+	publisherID, ok := parsePublisherID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), publisherID); err != nil {
+		writePublisherError(w, r, err, publisherID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writePublisherError(w http.ResponseWriter, r *http.Request, err error, publisherID int64) {
+	switch {
+	case errors.Is(err, ErrPublisherNotFound):
+		response.WriteError(w, r, http.StatusNotFound, "Publisher not found")
+	case errors.Is(err, ErrPublisherExists), errors.Is(err, ErrPublisherHasBooks):
+		response.WriteError(w, r, http.StatusConflict, err.Error())
+	default:
+		middleware.LoggerFromContext(r.Context()).Error("Publisher operation failed", "error", err, "publisher_id", publisherID)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func parsePublisherID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	publisherIDStr := chi.URLParam(r, "publisherID")
+	publisherID, err := strconv.ParseInt(publisherIDStr, 10, 64)
+	if err != nil {
+		middleware.LoggerFromContext(r.Context()).Warn("Invalid publisher ID format", "publisher_id", publisherIDStr)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid publisher ID format")
+		return 0, false
+	}
+	return publisherID, true
+}
+
+func toPublisherResponse(publisher repo.Publisher) PublisherResponse {
+	resp := PublisherResponse{
+		ID:   publisher.ID,
+		Name: publisher.Name,
+	}
+	if publisher.Website.Valid {
+		resp.Website = &publisher.Website.String
+	}
+	return resp
+}
@@ -0,0 +1,32 @@
+package publishers
+
+import "github.com/nikallow/bookstores-api/internal/pagination"
+
+// Sort fields accepted by GET /publishers, the first one is the default.
+var publisherSorts = []string{"name"}
+
+type CreatePublisherRequest struct {
+	Name    string  `json:"name"              validate:"required"`
+	Website *string `json:"website,omitempty" validate:"omitempty,url"`
+}
+
+type UpdatePublisherRequest struct {
+	Name    string  `json:"name"    validate:"required"`
+	Website *string `json:"website" validate:"omitempty,url"`
+}
+
+type ListPublishersParams struct {
+	pagination.Request
+	Name *string
+}
+
+type PublisherListResponse struct {
+	Items      []PublisherResponse `json:"items"`
+	NextCursor *string             `json:"next_cursor"`
+}
+
+type PublisherResponse struct {
+	ID      int64   `json:"id"`
+	Name    string  `json:"name"`
+	Website *string `json:"website,omitempty"`
+}
@@ -0,0 +1,166 @@
+package publishers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/books"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+)
+
+var (
+	ErrPublisherNotFound = books.ErrPublisherNotFound
+	ErrPublisherExists   = errors.New("publisher with this name already exists")
+	ErrPublisherHasBooks = errors.New("publisher has books")
+)
+
+const (
+	// pgUniqueViolation is the SQLSTATE of a failed UNIQUE constraint, e.g. publishers.name.
+	pgUniqueViolation = "23505"
+	// pgForeignKeyViolation is the SQLSTATE of a failed REFERENCES constraint, e.g. books.publisher_id.
+	pgForeignKeyViolation = "23503"
+)
+
+type Service interface {
+	Create(ctx context.Context, params CreatePublisherRequest) (repo.Publisher, error)
+	List(ctx context.Context, params ListPublishersParams) ([]repo.Publisher, *string, error)
+	GetByID(ctx context.Context, id int64) (repo.Publisher, error)
+	Update(ctx context.Context, id int64, params UpdatePublisherRequest) (repo.Publisher, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type service struct {
+	repo repo.Querier
+}
+
+func NewService(repo repo.Querier) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Create(ctx context.Context, params CreatePublisherRequest) (repo.Publisher, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	publisher, err := s.repo.CreatePublisher(ctx, repo.CreatePublisherParams{
+		Name:    params.Name,
+		Website: stringToPgTextp(params.Website),
+	})
+	if err != nil {
+		if isPgError(err, pgUniqueViolation) {
+			return repo.Publisher{}, ErrPublisherExists
+		}
+		log.Error("Failed to create publisher", "error", err)
+		return repo.Publisher{}, fmt.Errorf("failed to create publisher: %w", err)
+	}
+
+	log.Info("Publisher created successfully", "publisher_id", publisher.ID)
+	return publisher, nil
+}
+
+func (s *service) List(ctx context.Context, params ListPublishersParams) ([]repo.Publisher, *string, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	cursor, err := params.DecodeCursor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queryParams := repo.ListPublishersParams{
+		Name:      stringToPgTextp(params.Name),
+		PageLimit: params.QueryLimit(),
+	}
+	if cursor != nil {
+		queryParams.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+		queryParams.CursorText = pgtype.Text{String: cursor.Value, Valid: true}
+	}
+
+	var publishers []repo.Publisher
+	if params.Desc {
+		publishers, err = s.repo.ListPublishersDesc(ctx, repo.ListPublishersDescParams(queryParams))
+	} else {
+		publishers, err = s.repo.ListPublishers(ctx, queryParams)
+	}
+	if err != nil {
+		log.Error("Failed to list publishers", "error", err)
+		return nil, nil, fmt.Errorf("failed to list publishers: %w", err)
+	}
+
+	publishers, hasMore := pagination.Trim(publishers, params.Request)
+	if !hasMore {
+		return publishers, nil, nil
+	}
+	last := publishers[len(publishers)-1]
+	next := params.NextCursor(last.Name, last.ID)
+	return publishers, &next, nil
+}
+
+func (s *service) GetByID(ctx context.Context, id int64) (repo.Publisher, error) {
+	publisher, err := s.repo.GetPublisherByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Publisher{}, ErrPublisherNotFound
+		}
+		return repo.Publisher{}, fmt.Errorf("failed to get publisher: %w", err)
+	}
+	return publisher, nil
+}
+
+func (s *service) Update(ctx context.Context, id int64, params UpdatePublisherRequest) (repo.Publisher, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	publisher, err := s.repo.UpdatePublisher(ctx, repo.UpdatePublisherParams{
+		ID:      id,
+		Name:    params.Name,
+		Website: stringToPgTextp(params.Website),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Publisher{}, ErrPublisherNotFound
+		}
+		if isPgError(err, pgUniqueViolation) {
+			return repo.Publisher{}, ErrPublisherExists
+		}
+		log.Error("Failed to update publisher", "error", err, "publisher_id", id)
+		return repo.Publisher{}, fmt.Errorf("failed to update publisher: %w", err)
+	}
+
+	log.Info("Publisher updated successfully", "publisher_id", id)
+	return publisher, nil
+}
+
+// Delete removes a publisher that no book refers to, soft-deleted books included.
+func (s *service) Delete(ctx context.Context, id int64) error {
+	log := middleware.LoggerFromContext(ctx)
+
+	deleted, err := s.repo.DeletePublisher(ctx, id)
+	if err != nil {
+		if isPgError(err, pgForeignKeyViolation) {
+			return ErrPublisherHasBooks
+		}
+		log.Error("Failed to delete publisher", "error", err, "publisher_id", id)
+		return fmt.Errorf("failed to delete publisher: %w", err)
+	}
+	if deleted == 0 {
+		return ErrPublisherNotFound
+	}
+
+	log.Info("Publisher deleted successfully", "publisher_id", id)
+	return nil
+}
+
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
+
+func stringToPgTextp(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{Valid: false}
+	}
+	return pgtype.Text{String: *s, Valid: true}
+}
@@ -0,0 +1,35 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+)
+
+// PostgresStore keeps the buckets in the database, shared by every replica. A request costs
+// a single upsert refilling the bucket by the database clock.
+type PostgresStore struct {
+	repo repo.Querier
+}
+
+func NewPostgresStore(repo repo.Querier) *PostgresStore {
+	return &PostgresStore{repo: repo}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	row, err := s.repo.TakeRateLimitToken(ctx, repo.TakeRateLimitTokenParams{
+		Key:   key,
+		Burst: float64(limit.Burst),
+		Rate:  limit.Rate,
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return limit.result(row.Tokens, row.Allowed), nil
+}
+
+func (s *PostgresStore) Sweep(ctx context.Context, idleSince time.Time) (int64, error) {
+	return s.repo.DeleteIdleRateLimitBuckets(ctx, pgtype.Timestamptz{Time: idleSince, Valid: true})
+}
@@ -0,0 +1,238 @@
+// Package ratelimit limits the requests of every client with token buckets, one per route group.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/auth"
+	"github.com/nikallow/bookstores-api/internal/config"
+	appMiddleware "github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/response"
+)
+
+const (
+	LimitHeader      = "RateLimit-Limit"
+	RemainingHeader  = "RateLimit-Remaining"
+	ResetHeader      = "RateLimit-Reset"
+	RetryAfterHeader = "Retry-After"
+
+	StoreMemory   = "memory"
+	StorePostgres = "postgres"
+)
+
+// Group is a set of routes sharing a limit; a client has a bucket in every group it calls.
+type Group string
+
+const (
+	// GroupIP covers every route but the health checks by the address of the client, before
+	// authentication, so that requests with wrong credentials are limited too.
+	GroupIP Group = "ip"
+	// GroupDefault covers every authenticated route.
+	GroupDefault Group = "default"
+	// GroupSearch covers the full-text book search on top of the default limit.
+	GroupSearch Group = "search"
+	// GroupExports covers the exports on top of the default limit.
+	GroupExports Group = "exports"
+)
+
+// Limit is a token bucket holding up to Burst requests and refilled at Rate requests per second.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l Limit) validate() error {
+	if l.Rate <= 0 || math.IsInf(l.Rate, 0) || math.IsNaN(l.Rate) {
+		return errors.New("rate must be positive")
+	}
+	if l.Burst < 1 {
+		return errors.New("burst must be at least 1")
+	}
+	return nil
+}
+
+// take refills a bucket holding tokens for the time elapsed since it was last used and takes
+// a token when a whole one is there.
+func (l Limit) take(tokens float64, elapsed time.Duration) (float64, bool) {
+	tokens = min(float64(l.Burst), tokens+max(elapsed.Seconds(), 0)*l.Rate)
+	if tokens < 1 {
+		return tokens, false
+	}
+	return tokens - 1, true
+}
+
+func (l Limit) result(tokens float64, allowed bool) Result {
+	res := Result{
+		Allowed:   allowed,
+		Remaining: max(int(tokens), 0),
+		Reset:     l.refillTime(float64(l.Burst) - tokens),
+	}
+	if !allowed {
+		res.RetryAfter = l.refillTime(1 - tokens)
+	}
+	return res
+}
+
+func (l Limit) refillTime(tokens float64) time.Duration {
+	return time.Duration(max(tokens, 0) / l.Rate * float64(time.Second))
+}
+
+// Result is the state of a bucket after a request took from it.
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// Reset is when the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is when a denied request may be retried.
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets of the clients.
+type Store interface {
+	// Take takes a token from the bucket under key, creating it full.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+	// Sweep drops the buckets not used since idleSince.
+	Sweep(ctx context.Context, idleSince time.Time) (int64, error)
+}
+
+// Limiter answers 429 Too Many Requests to clients that have used up their bucket and sends
+// the state of the bucket in the RateLimit-* headers.
+type Limiter struct {
+	store   Store
+	limits  map[Group]Limit
+	enabled bool
+	idleTTL time.Duration
+	log     *slog.Logger
+}
+
+func New(repo repo.Querier, cfg config.RateLimitConfig, log *slog.Logger) (*Limiter, error) {
+	limits := map[Group]Limit{
+		GroupIP:      {Rate: cfg.IPRate, Burst: cfg.IPBurst},
+		GroupDefault: {Rate: cfg.DefaultRate, Burst: cfg.DefaultBurst},
+		GroupSearch:  {Rate: cfg.SearchRate, Burst: cfg.SearchBurst},
+		GroupExports: {Rate: cfg.ExportsRate, Burst: cfg.ExportsBurst},
+	}
+	for group, limit := range limits {
+		if err := limit.validate(); err != nil {
+			return nil, fmt.Errorf("ratelimit: %s: %w", group, err)
+		}
+	}
+
+	var store Store
+	switch cfg.Store {
+	case StoreMemory:
+		store = NewMemoryStore()
+	case StorePostgres:
+		store = NewPostgresStore(repo)
+	default:
+		return nil, fmt.Errorf("ratelimit: unknown store %q", cfg.Store)
+	}
+
+	return &Limiter{
+		store:   store,
+		limits:  limits,
+		enabled: cfg.Enabled,
+		idleTTL: cfg.IdleTTL,
+		log:     log.With("component", "rate_limit_sweeper"),
+	}, nil
+}
+
+// Limit takes a token from the bucket of the client in the group. The GroupIP limit goes before
+// authentication and tells clients apart by their address; the others go after it so that clients
+// are told apart by their API key or token. When the store fails, the request goes on.
+func (l *Limiter) Limit(group Group) func(http.Handler) http.Handler {
+	limit := l.limits[group]
+	return func(next http.Handler) http.Handler {
+		if !l.enabled {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			log := appMiddleware.LoggerFromContext(r.Context())
+
+			client := clientKey(r)
+			if group == GroupIP {
+				client = addressKey(r)
+			}
+			res, err := l.store.Take(r.Context(), string(group)+":"+client, limit)
+			if err != nil {
+				log.Error("Failed to check rate limit", "error", err, "group", group)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set(LimitHeader, strconv.Itoa(limit.Burst))
+			w.Header().Set(RemainingHeader, strconv.Itoa(res.Remaining))
+			w.Header().Set(ResetHeader, seconds(res.Reset))
+			if !res.Allowed {
+				log.Warn("Rate limit exceeded", "group", group, "client", client)
+				w.Header().Set(RetryAfterHeader, seconds(res.RetryAfter))
+				response.WriteError(w, r, http.StatusTooManyRequests, "Too many requests")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Run drops idle buckets every IdleTTL until ctx is cancelled.
+func (l *Limiter) Run(ctx context.Context) {
+	if !l.enabled {
+		return
+	}
+
+	ticker := time.NewTicker(l.idleTTL)
+	defer ticker.Stop()
+
+	l.log.Info("Rate limit sweeper started", "interval", l.idleTTL)
+	for {
+		select {
+		case <-ctx.Done():
+			l.log.Info("Rate limit sweeper stopped")
+			return
+		case <-ticker.C:
+			dropped, err := l.store.Sweep(ctx, time.Now().Add(-l.idleTTL))
+			if err != nil {
+				if ctx.Err() == nil {
+					l.log.Error("Failed to drop idle rate limit buckets", "error", err)
+				}
+				continue
+			}
+			if dropped > 0 {
+				l.log.Debug("Dropped idle rate limit buckets", "count", dropped)
+			}
+		}
+	}
+}
+
+// clientKey identifies the caller by its API key, the subject of its token or else its address,
+// taken from the proxy headers only behind a trusted proxy (see middleware.RealIP).
+func clientKey(r *http.Request) string {
+	if principal, ok := auth.PrincipalFromContext(r.Context()); ok {
+		if principal.IsAPIKey() {
+			return "key:" + principal.KeyPrefix
+		}
+		return "user:" + principal.Subject
+	}
+	return addressKey(r)
+}
+
+func addressKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
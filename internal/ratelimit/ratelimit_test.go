@@ -0,0 +1,268 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nikallow/bookstores-api/internal/auth"
+	"github.com/nikallow/bookstores-api/internal/config"
+)
+
+func TestLimitTake(t *testing.T) {
+	limit := Limit{Rate: 2, Burst: 5}
+
+	tests := []struct {
+		name        string
+		tokens      float64
+		elapsed     time.Duration
+		wantTokens  float64
+		wantAllowed bool
+	}{
+		{"full bucket", 5, 0, 4, true},
+		{"last token", 1, 0, 0, true},
+		{"empty bucket", 0.5, 0, 0.5, false},
+		{"refilled", 0, time.Second, 1, true},
+		{"refill stops at the burst", 4, time.Hour, 4, true},
+		{"clock going back", 0.5, -time.Second, 0.5, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, allowed := limit.take(tt.tokens, tt.elapsed)
+			if tokens != tt.wantTokens || allowed != tt.wantAllowed {
+				t.Errorf("take(%v, %s) = %v, %v, want %v, %v", tt.tokens, tt.elapsed, tokens, allowed, tt.wantTokens, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestLimitResult(t *testing.T) {
+	limit := Limit{Rate: 2, Burst: 5}
+
+	tests := []struct {
+		name    string
+		tokens  float64
+		allowed bool
+		want    Result
+	}{
+		{"full", 5, true, Result{Allowed: true, Remaining: 5}},
+		{"partly used", 2.5, true, Result{Allowed: true, Remaining: 2, Reset: 1250 * time.Millisecond}},
+		{"denied", 0.5, false, Result{Remaining: 0, Reset: 2250 * time.Millisecond, RetryAfter: 250 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := limit.result(tt.tokens, tt.allowed); got != tt.want {
+				t.Errorf("result(%v, %v) = %+v, want %+v", tt.tokens, tt.allowed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	limit := Limit{Rate: 0.001, Burst: 2}
+
+	for i, want := range []bool{true, true, false} {
+		res, err := store.Take(ctx, "a", limit)
+		if err != nil {
+			t.Fatalf("Take() error = %v", err)
+		}
+		if res.Allowed != want {
+			t.Errorf("request %d: allowed = %v, want %v", i+1, res.Allowed, want)
+		}
+	}
+	if res, _ := store.Take(ctx, "b", limit); !res.Allowed {
+		t.Error("another key shares the bucket")
+	}
+
+	if dropped, _ := store.Sweep(ctx, time.Now().Add(-time.Minute)); dropped != 0 {
+		t.Errorf("Sweep() dropped %d fresh buckets", dropped)
+	}
+	if dropped, _ := store.Sweep(ctx, time.Now().Add(time.Minute)); dropped != 2 {
+		t.Errorf("Sweep() dropped %d idle buckets, want 2", dropped)
+	}
+	if res, _ := store.Take(ctx, "a", limit); !res.Allowed {
+		t.Error("a dropped bucket isn't full again")
+	}
+}
+
+func newLimiter(t *testing.T, enabled bool) *Limiter {
+	t.Helper()
+	l, err := New(nil, config.RateLimitConfig{
+		Enabled:      enabled,
+		Store:        StoreMemory,
+		IPRate:       1,
+		IPBurst:      1,
+		DefaultRate:  1,
+		DefaultBurst: 3,
+		SearchRate:   1,
+		SearchBurst:  1,
+		ExportsRate:  1,
+		ExportsBurst: 1,
+		IdleTTL:      time.Minute,
+	}, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return l
+}
+
+// call sends a request as the principal, or from addr without one, through the group's limit.
+func call(l *Limiter, group Group, principal *auth.Principal, addr string) *httptest.ResponseRecorder {
+	handler := l.Limit(group)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = addr
+	if principal != nil {
+		req = req.WithContext(auth.WithPrincipal(req.Context(), *principal))
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLimiter(t *testing.T) {
+	l := newLimiter(t, true)
+	user := &auth.Principal{Subject: "clerk@example.com", Role: auth.RoleClerk}
+
+	tests := []struct {
+		wantStatus     int
+		wantRemaining  string
+		wantReset      string
+		wantRetryAfter string
+	}{
+		{http.StatusOK, "2", "1", ""},
+		{http.StatusOK, "1", "2", ""},
+		{http.StatusOK, "0", "3", ""},
+		{http.StatusTooManyRequests, "0", "3", "1"},
+	}
+	for i, tt := range tests {
+		rec := call(l, GroupDefault, user, "192.0.2.1:1234")
+		if rec.Code != tt.wantStatus {
+			t.Errorf("request %d: status = %d, want %d", i+1, rec.Code, tt.wantStatus)
+		}
+		headers := map[string]string{
+			LimitHeader:      "3",
+			RemainingHeader:  tt.wantRemaining,
+			ResetHeader:      tt.wantReset,
+			RetryAfterHeader: tt.wantRetryAfter,
+		}
+		for name, want := range headers {
+			if got := rec.Header().Get(name); got != want {
+				t.Errorf("request %d: %s = %q, want %q", i+1, name, got, want)
+			}
+		}
+	}
+
+	// Every other client and group has a bucket of its own.
+	others := []struct {
+		name      string
+		group     Group
+		principal *auth.Principal
+		addr      string
+	}{
+		{"another user", GroupDefault, &auth.Principal{Subject: "manager@example.com", Role: auth.RoleStoreManager}, "192.0.2.1:1234"},
+		{"api key named like the user", GroupDefault, &auth.Principal{Subject: user.Subject, KeyPrefix: user.Subject}, "192.0.2.1:1234"},
+		{"anonymous at the same address", GroupDefault, nil, "192.0.2.1:1234"},
+		{"another group", GroupSearch, user, "192.0.2.1:1234"},
+	}
+	for _, o := range others {
+		if rec := call(l, o.group, o.principal, o.addr); rec.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, want %d", o.name, rec.Code, http.StatusOK)
+		}
+	}
+
+	// Anonymous clients are told apart by address, not port.
+	if rec := call(l, GroupDefault, nil, "192.0.2.1:5678"); rec.Header().Get(RemainingHeader) != "1" {
+		t.Errorf("another port of the address got a bucket of its own")
+	}
+}
+
+func TestLimiterByAddress(t *testing.T) {
+	l := newLimiter(t, true)
+
+	requests := []struct {
+		name       string
+		principal  *auth.Principal
+		addr       string
+		wantStatus int
+	}{
+		{"first client", &auth.Principal{Subject: "clerk@example.com"}, "192.0.2.1:1234", http.StatusOK},
+		{"another user at the address", &auth.Principal{Subject: "manager@example.com"}, "192.0.2.1:1234", http.StatusTooManyRequests},
+		{"anonymous at the address", nil, "192.0.2.1:5678", http.StatusTooManyRequests},
+		{"another address", nil, "192.0.2.2:1234", http.StatusOK},
+	}
+	for _, r := range requests {
+		if rec := call(l, GroupIP, r.principal, r.addr); rec.Code != r.wantStatus {
+			t.Errorf("%s: status = %d, want %d", r.name, rec.Code, r.wantStatus)
+		}
+	}
+}
+
+func TestLimiterDisabled(t *testing.T) {
+	l := newLimiter(t, false)
+	for range 5 {
+		rec := call(l, GroupSearch, nil, "192.0.2.1:1234")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+		}
+		if rec.Header().Get(LimitHeader) != "" {
+			t.Fatalf("%s is set with the limit disabled", LimitHeader)
+		}
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Limit) (Result, error) {
+	return Result{}, errors.New("store is down")
+}
+
+func (failingStore) Sweep(context.Context, time.Time) (int64, error) {
+	return 0, errors.New("store is down")
+}
+
+func TestLimiterStoreFailure(t *testing.T) {
+	l := newLimiter(t, true)
+	l.store = failingStore{}
+	if rec := call(l, GroupDefault, nil, "192.0.2.1:1234"); rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want the request to go on", rec.Code)
+	}
+}
+
+func TestNewRejectsBadConfig(t *testing.T) {
+	valid := config.RateLimitConfig{
+		Store:        StoreMemory,
+		IPRate:       1,
+		IPBurst:      1,
+		DefaultRate:  1,
+		DefaultBurst: 1,
+		SearchRate:   1,
+		SearchBurst:  1,
+		ExportsRate:  1,
+		ExportsBurst: 1,
+	}
+	tests := []struct {
+		name   string
+		change func(*config.RateLimitConfig)
+	}{
+		{"zero rate", func(c *config.RateLimitConfig) { c.SearchRate = 0 }},
+		{"infinite rate", func(c *config.RateLimitConfig) { c.DefaultRate = math.Inf(1) }},
+		{"zero burst", func(c *config.RateLimitConfig) { c.ExportsBurst = 0 }},
+		{"unknown store", func(c *config.RateLimitConfig) { c.Store = "redis" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.change(&cfg)
+			if _, err := New(nil, cfg, slog.New(slog.DiscardHandler)); err == nil {
+				t.Error("New() error = nil, want an error")
+			}
+		})
+	}
+}
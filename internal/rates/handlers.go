@@ -0,0 +1,161 @@
+package rates
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/money"
+	"github.com/nikallow/bookstores-api/internal/response"
+)
+
+type Handler struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// ListExchangeRates
+//
+//	@Summary		Получить курсы валют
+//	@Description	Возвращает все сохранённые курсы. Курс пары показывает, сколько единиц quote_currency стоит одна единица base_currency.
+//	@Tags			exchange-rates
+//	@Produce		json
+//	@Success		200	{object}	ExchangeRateListResponse	"Курсы валют"
+//	@Failure		500	{object}	response.ErrorResponse		"Internal server error"
+//	@Router			/exchange-rates [get]
+func (h *Handler) ListExchangeRates(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	rates, err := h.service.List(r.Context())
+	if err != nil {
+		log.Error("Failed to list exchange rates", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	resp := make([]ExchangeRateResponse, len(rates))
+	for i, rate := range rates {
+		resp[i] = toExchangeRateResponse(rate)
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, ExchangeRateListResponse{Items: resp})
+}
+
+// SetExchangeRate
+//
+//	@Summary		Задать курс валюты
+//	@Description	Создаёт или заменяет курс пары. Обратный курс, если он не задан отдельно, считается как 1/rate.
+//	@Tags			exchange-rates
+//	@Accept			json
+//	@Produce		json
+//	@Param			base	path		string					true	"Базовая валюта (ISO 4217)"
+//	@Param			quote	path		string					true	"Котируемая валюта (ISO 4217)"
+//	@Param			input	body		SetExchangeRateRequest	true	"Курс"
+//	@Success		200		{object}	ExchangeRateResponse	"Курс сохранён"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/exchange-rates/{base}/{quote} [put]
+func (h *Handler) SetExchangeRate(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	base, quote, ok := parsePair(w, r)
+	if !ok {
+		return
+	}
+
+	var req SetExchangeRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read set exchange rate request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed for set exchange rate request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rate, err := h.service.Set(r.Context(), base, quote, req.Rate)
+	if err != nil {
+		writeRateError(w, r, err)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toExchangeRateResponse(rate))
+}
+
+// DeleteExchangeRate
+//
+//	@Summary		Удалить курс валюты
+//	@Description	Удаляет курс пары. Обратный курс, если он задан, остаётся.
+//	@Tags			exchange-rates
+//	@Param			base	path	string	true	"Базовая валюта (ISO 4217)"
+//	@Param			quote	path	string	true	"Котируемая валюта (ISO 4217)"
+//	@Success		204		"Курс удалён"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"Курс не задан"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/exchange-rates/{base}/{quote} [delete]
+func (h *Handler) DeleteExchangeRate(w http.ResponseWriter, r *http.Request) {
+	base, quote, ok := parsePair(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), base, quote); err != nil {
+		writeRateError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeRateError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrRateNotFound):
+		response.WriteError(w, r, http.StatusNotFound, "Exchange rate not found")
+	case errors.Is(err, ErrInvalidRate), errors.Is(err, ErrSameCurrency):
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+	default:
+		middleware.LoggerFromContext(r.Context()).Error("Exchange rate operation failed", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func parsePair(w http.ResponseWriter, r *http.Request) (money.Currency, money.Currency, bool) {
+	base, err := money.ParseCurrency(chi.URLParam(r, "base"))
+	if err != nil {
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return "", "", false
+	}
+	quote, err := money.ParseCurrency(chi.URLParam(r, "quote"))
+	if err != nil {
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return "", "", false
+	}
+	return base, quote, true
+}
+
+func toExchangeRateResponse(rate repo.ListExchangeRatesRow) ExchangeRateResponse {
+	resp := ExchangeRateResponse{
+		BaseCurrency:  rate.BaseCurrency,
+		QuoteCurrency: rate.QuoteCurrency,
+		Rate:          rate.Rate,
+		UpdatedAt:     rate.UpdatedAt.Time,
+	}
+	if rate.Actor.Valid {
+		resp.Actor = &rate.Actor.String
+	}
+	return resp
+}
@@ -0,0 +1,20 @@
+package rates
+
+import "time"
+
+type SetExchangeRateRequest struct {
+	// Units of the quote currency per unit of the base currency, as a decimal string.
+	Rate string `json:"rate" validate:"required" example:"5.4321"`
+}
+
+type ExchangeRateListResponse struct {
+	Items []ExchangeRateResponse `json:"items"`
+}
+
+type ExchangeRateResponse struct {
+	BaseCurrency  string    `json:"base_currency"  example:"RUB"`
+	QuoteCurrency string    `json:"quote_currency" example:"KZT"`
+	Rate          string    `json:"rate"           example:"5.4321"`
+	Actor         *string   `json:"actor,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
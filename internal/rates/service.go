@@ -0,0 +1,143 @@
+package rates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/money"
+)
+
+var (
+	ErrRateNotFound    = money.ErrNoExchangeRate
+	ErrInvalidRate     = money.ErrInvalidRate
+	ErrUnknownCurrency = money.ErrUnknownCurrency
+	ErrSameCurrency    = errors.New("base and quote currencies must differ")
+)
+
+// Bounds of the rates exchange_rates.rate NUMERIC(20, 10) holds without rounding to zero or overflowing.
+var (
+	minRate = big.NewRat(1, 1e10)
+	maxRate = new(big.Rat).SetInt64(1e10)
+)
+
+type Service interface {
+	Set(ctx context.Context, base, quote money.Currency, rate string) (repo.ListExchangeRatesRow, error)
+	List(ctx context.Context) ([]repo.ListExchangeRatesRow, error)
+	Delete(ctx context.Context, base, quote money.Currency) error
+	// Convert uses the rate from the currency of m to the target one, or the inverse of
+	// the opposite rate when only that one is known.
+	Convert(ctx context.Context, m money.Money, to money.Currency) (money.Money, error)
+}
+
+type service struct {
+	repo repo.Querier
+}
+
+func NewService(repo repo.Querier) Service {
+	return &service{repo: repo}
+}
+
+// Set - PUT /exchange-rates/{base}/{quote}
+func (s *service) Set(ctx context.Context, base, quote money.Currency, rate string) (repo.ListExchangeRatesRow, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	if base == quote {
+		return repo.ListExchangeRatesRow{}, ErrSameCurrency
+	}
+	r, err := money.ParseRate(rate)
+	if err != nil {
+		return repo.ListExchangeRatesRow{}, err
+	}
+	if r.Cmp(minRate) < 0 || r.Cmp(maxRate) >= 0 {
+		return repo.ListExchangeRatesRow{}, fmt.Errorf("%w: must be between %s and %s", ErrInvalidRate, minRate.FloatString(10), maxRate.FloatString(0))
+	}
+
+	row, err := s.repo.UpsertExchangeRate(ctx, repo.UpsertExchangeRateParams{
+		BaseCurrency:  string(base),
+		QuoteCurrency: string(quote),
+		Rate:          r.FloatString(10),
+		Actor:         stringToPgText(middleware.ActorFromContext(ctx)),
+	})
+	if err != nil {
+		log.Error("Failed to set exchange rate", "error", err, "base", base, "quote", quote)
+		return repo.ListExchangeRatesRow{}, fmt.Errorf("failed to set exchange rate: %w", err)
+	}
+
+	log.Info("Exchange rate set", "base", base, "quote", quote, "rate", row.Rate)
+	return repo.ListExchangeRatesRow(row), nil
+}
+
+// List - GET /exchange-rates
+func (s *service) List(ctx context.Context) ([]repo.ListExchangeRatesRow, error) {
+	rates, err := s.repo.ListExchangeRates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list exchange rates: %w", err)
+	}
+	return rates, nil
+}
+
+// Delete - DELETE /exchange-rates/{base}/{quote}
+func (s *service) Delete(ctx context.Context, base, quote money.Currency) error {
+	log := middleware.LoggerFromContext(ctx)
+
+	n, err := s.repo.DeleteExchangeRate(ctx, repo.DeleteExchangeRateParams{
+		BaseCurrency:  string(base),
+		QuoteCurrency: string(quote),
+	})
+	if err != nil {
+		log.Error("Failed to delete exchange rate", "error", err, "base", base, "quote", quote)
+		return fmt.Errorf("failed to delete exchange rate: %w", err)
+	}
+	if n == 0 {
+		return ErrRateNotFound
+	}
+
+	log.Info("Exchange rate deleted", "base", base, "quote", quote)
+	return nil
+}
+
+func (s *service) Convert(ctx context.Context, m money.Money, to money.Currency) (money.Money, error) {
+	if m.Currency == to {
+		return m, nil
+	}
+
+	rate, err := s.rate(ctx, m.Currency, to)
+	if errors.Is(err, ErrRateNotFound) {
+		inverse, ierr := s.rate(ctx, to, m.Currency)
+		if ierr != nil {
+			if errors.Is(ierr, ErrRateNotFound) {
+				return money.Money{}, fmt.Errorf("%w from %s to %s", ErrRateNotFound, m.Currency, to)
+			}
+			return money.Money{}, ierr
+		}
+		rate, err = inverse.Inv(inverse), nil
+	}
+	if err != nil {
+		return money.Money{}, err
+	}
+	return m.Convert(to, rate)
+}
+
+func (s *service) rate(ctx context.Context, base, quote money.Currency) (*big.Rat, error) {
+	row, err := s.repo.GetExchangeRate(ctx, repo.GetExchangeRateParams{
+		BaseCurrency:  string(base),
+		QuoteCurrency: string(quote),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRateNotFound
+		}
+		return nil, err
+	}
+	return money.ParseRate(row.Rate)
+}
+
+func stringToPgText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
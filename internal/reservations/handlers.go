@@ -0,0 +1,183 @@
+package reservations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/response"
+)
+
+type Handler struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// CreateReservation
+//
+//	@Summary		Зарезервировать товар
+//	@Description	Резервирует экземпляры SKU на время (ttl_seconds, по умолчанию 30 минут). Зарезервированный товар
+//	@Description	остаётся в stock_count, но не входит в available, пока резерв не подтвердят, не снимут или он не истечёт.
+//	@Tags			reservations
+//	@Accept			json
+//	@Produce		json
+//	@Param			skuUUID	path		string						true	"UUID товарной позиции (SKU)"
+//	@Param			input	body		CreateReservationRequest	true	"Количество и время резерва"
+//	@Success		201		{object}	ReservationResponse			"Резерв создан"
+//	@Failure		400		{object}	response.ErrorResponse		"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse		"SKU не найден"
+//	@Failure		409		{object}	response.ErrorResponse		"Недостаточно доступного товара"
+//	@Failure		500		{object}	response.ErrorResponse		"Internal server error"
+//	@Router			/skus/{skuUUID}/reservations [post]
+func (h *Handler) CreateReservation(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	skuUUID, err := uuid.Parse(chi.URLParam(r, "skuUUID"))
+	if err != nil {
+		log.Warn("Invalid sku UUID format", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid sku uuid format")
+		return
+	}
+
+	var req CreateReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read create reservation request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reservation, err := h.service.CreateReservation(r.Context(), skuUUID, req)
+	if err != nil {
+		h.writeServiceError(w, r, err, "Failed to create reservation")
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusCreated, toReservationResponse(reservation))
+}
+
+// GetReservation
+//
+//	@Summary		Получить резерв
+//	@Description	Возвращает резерв и его статус.
+//	@Tags			reservations
+//	@Produce		json
+//	@Param			reservationUUID	path		string					true	"UUID резерва"
+//	@Success		200				{object}	ReservationResponse		"Резерв"
+//	@Failure		400				{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404				{object}	response.ErrorResponse	"Резерв не найден"
+//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/reservations/{reservationUUID} [get]
+func (h *Handler) GetReservation(w http.ResponseWriter, r *http.Request) {
+	h.apply(w, r, h.service.GetReservation, "Failed to get reservation")
+}
+
+// ConfirmReservation
+//
+//	@Summary		Подтвердить резерв
+//	@Description	Списывает зарезервированный товар как продажу (sale).
+//	@Tags			reservations
+//	@Produce		json
+//	@Param			reservationUUID	path		string					true	"UUID резерва"
+//	@Success		200				{object}	ReservationResponse		"Подтверждённый резерв"
+//	@Failure		400				{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404				{object}	response.ErrorResponse	"Резерв не найден"
+//	@Failure		409				{object}	response.ErrorResponse	"Резерв уже не активен или истёк"
+//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/reservations/{reservationUUID}/confirm [post]
+func (h *Handler) ConfirmReservation(w http.ResponseWriter, r *http.Request) {
+	h.apply(w, r, h.service.ConfirmReservation, "Failed to confirm reservation")
+}
+
+// ReleaseReservation
+//
+//	@Summary		Снять резерв
+//	@Description	Возвращает зарезервированный товар в доступный остаток.
+//	@Tags			reservations
+//	@Produce		json
+//	@Param			reservationUUID	path		string					true	"UUID резерва"
+//	@Success		200				{object}	ReservationResponse		"Снятый резерв"
+//	@Failure		400				{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404				{object}	response.ErrorResponse	"Резерв не найден"
+//	@Failure		409				{object}	response.ErrorResponse	"Резерв уже не активен"
+//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/reservations/{reservationUUID}/release [post]
+func (h *Handler) ReleaseReservation(w http.ResponseWriter, r *http.Request) {
+	h.apply(w, r, h.service.ReleaseReservation, "Failed to release reservation")
+}
+
+func (h *Handler) apply(w http.ResponseWriter, r *http.Request, fn func(ctx context.Context, reservationUUID uuid.UUID) (repo.GetReservationByUUIDRow, error), failMsg string) {
+	reservationUUID, err := uuid.Parse(chi.URLParam(r, "reservationUUID"))
+	if err != nil {
+		middleware.LoggerFromContext(r.Context()).Warn("Invalid reservation UUID format", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid reservation uuid format")
+		return
+	}
+
+	reservation, err := fn(r.Context(), reservationUUID)
+	if err != nil {
+		h.writeServiceError(w, r, err, failMsg)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toReservationResponse(reservation))
+}
+
+func (h *Handler) writeServiceError(w http.ResponseWriter, r *http.Request, err error, failMsg string) {
+	switch {
+	case errors.Is(err, ErrReservationNotFound), errors.Is(err, ErrSKUNotFound):
+		response.WriteError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrTTLTooLong):
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrInsufficientStock), errors.Is(err, ErrReservationNotActive), errors.Is(err, ErrReservationExpired):
+		response.WriteError(w, r, http.StatusConflict, err.Error())
+	default:
+		middleware.LoggerFromContext(r.Context()).Error(failMsg, "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func toReservationResponse(row repo.GetReservationByUUIDRow) ReservationResponse {
+	res := row.SkuReservation
+	resp := ReservationResponse{
+		UUID:      mustConvertUUID(res.Uuid),
+		SKUUUID:   mustConvertUUID(row.SkuUuid),
+		Quantity:  res.Quantity,
+		Status:    string(res.Status),
+		ExpiresAt: res.ExpiresAt.Time,
+		CreatedAt: res.CreatedAt.Time,
+		UpdatedAt: res.UpdatedAt.Time,
+	}
+	if res.Note.Valid {
+		resp.Note = &res.Note.String
+	}
+	if res.Actor.Valid {
+		resp.Actor = &res.Actor.String
+	}
+	return resp
+}
+
+func mustConvertUUID(pgUUID pgtype.UUID) uuid.UUID {
+	if !pgUUID.Valid {
+		return uuid.Nil
+	}
+	return pgUUID.Bytes
+}
@@ -0,0 +1,25 @@
+package reservations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateReservationRequest struct {
+	Quantity   int32   `json:"quantity"              validate:"gt=0"`
+	TTLSeconds int32   `json:"ttl_seconds,omitempty" validate:"omitempty,gt=0"`
+	Note       *string `json:"note,omitempty"        validate:"omitempty,max=1000"`
+}
+
+type ReservationResponse struct {
+	UUID      uuid.UUID `json:"uuid"`
+	SKUUUID   uuid.UUID `json:"sku_uuid"`
+	Quantity  int32     `json:"quantity"`
+	Status    string    `json:"status"`
+	Note      *string   `json:"note,omitempty"`
+	Actor     *string   `json:"actor,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
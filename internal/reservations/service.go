@@ -0,0 +1,279 @@
+package reservations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nikallow/bookstores-api/internal/adapters/postgres"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/config"
+	"github.com/nikallow/bookstores-api/internal/inventory"
+	appMiddleware "github.com/nikallow/bookstores-api/internal/middleware"
+)
+
+var (
+	ErrReservationNotFound  = errors.New("reservation not found")
+	ErrReservationNotActive = errors.New("reservation is no longer active")
+	ErrReservationExpired   = errors.New("reservation has expired")
+	ErrTTLTooLong           = errors.New("ttl exceeds the maximum reservation time")
+	ErrSKUNotFound          = inventory.ErrSKUNotFound
+	ErrInsufficientStock    = inventory.ErrInsufficientStock
+)
+
+type Service interface {
+	CreateReservation(ctx context.Context, skuUUID uuid.UUID, params CreateReservationRequest) (repo.GetReservationByUUIDRow, error)
+	GetReservation(ctx context.Context, reservationUUID uuid.UUID) (repo.GetReservationByUUIDRow, error)
+	ConfirmReservation(ctx context.Context, reservationUUID uuid.UUID) (repo.GetReservationByUUIDRow, error)
+	ReleaseReservation(ctx context.Context, reservationUUID uuid.UUID) (repo.GetReservationByUUIDRow, error)
+	ExpireStale(ctx context.Context) (int, error)
+}
+
+type service struct {
+	repo repo.Querier
+	db   postgres.TxBeginner
+	cfg  config.ReservationsConfig
+}
+
+func NewService(repo repo.Querier, db postgres.TxBeginner, cfg config.ReservationsConfig) Service {
+	return &service{repo: repo, db: db, cfg: cfg}
+}
+
+// CreateReservation - POST /skus/{skuUUID}/reservations
+//
+// The held copies stay in stock_count but no longer count as available, so neither
+// orders nor other reservations can take them until the hold is released or expires.
+func (s *service) CreateReservation(ctx context.Context, skuUUID uuid.UUID, params CreateReservationRequest) (repo.GetReservationByUUIDRow, error) {
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	ttl := s.cfg.DefaultTTL
+	if params.TTLSeconds > 0 {
+		ttl = time.Duration(params.TTLSeconds) * time.Second
+	}
+	if ttl > s.cfg.MaxTTL {
+		return repo.GetReservationByUUIDRow{}, fmt.Errorf("%w (%s)", ErrTTLTooLong, s.cfg.MaxTTL)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.GetReservationByUUIDRow{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+
+	sku, err := inventory.LockSKU(ctx, qtx, skuUUID, nil)
+	if err != nil {
+		return repo.GetReservationByUUIDRow{}, err
+	}
+	if inventory.Available(sku) < params.Quantity {
+		return repo.GetReservationByUUIDRow{}, ErrInsufficientStock
+	}
+
+	if _, err := inventory.AdjustReserved(ctx, qtx, sku, params.Quantity); err != nil {
+		log.Error("Failed to reserve sku stock", "error", err, "sku_id", sku.ID)
+		return repo.GetReservationByUUIDRow{}, err
+	}
+
+	reservation, err := qtx.CreateReservation(ctx, repo.CreateReservationParams{
+		SkuID:     sku.ID,
+		Quantity:  params.Quantity,
+		Note:      stringToPgTextp(params.Note),
+		Actor:     stringToPgText(appMiddleware.ActorFromContext(ctx)),
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(ttl), Valid: true},
+	})
+	if err != nil {
+		log.Error("Failed to create reservation", "error", err, "sku_id", sku.ID)
+		return repo.GetReservationByUUIDRow{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return repo.GetReservationByUUIDRow{}, err
+	}
+
+	log.Info("Reservation created", "reservation_id", reservation.ID, "sku_id", sku.ID, "quantity", reservation.Quantity)
+	return repo.GetReservationByUUIDRow{SkuReservation: reservation, SkuUuid: sku.Uuid}, nil
+}
+
+func (s *service) GetReservation(ctx context.Context, reservationUUID uuid.UUID) (repo.GetReservationByUUIDRow, error) {
+	row, err := s.repo.GetReservationByUUID(ctx, uuidToPgUUID(reservationUUID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.GetReservationByUUIDRow{}, ErrReservationNotFound
+		}
+		return repo.GetReservationByUUIDRow{}, err
+	}
+	return row, nil
+}
+
+// ConfirmReservation - POST /reservations/{reservationUUID}/confirm
+//
+// The held copies are written off as a sale.
+func (s *service) ConfirmReservation(ctx context.Context, reservationUUID uuid.UUID) (repo.GetReservationByUUIDRow, error) {
+	return s.finish(ctx, reservationUUID, repo.ReservationStatusConfirmed)
+}
+
+// ReleaseReservation - POST /reservations/{reservationUUID}/release
+//
+// The held copies become available again.
+func (s *service) ReleaseReservation(ctx context.Context, reservationUUID uuid.UUID) (repo.GetReservationByUUIDRow, error) {
+	return s.finish(ctx, reservationUUID, repo.ReservationStatusReleased)
+}
+
+// ExpireStale expires up to SweepBatch active reservations whose TTL has passed
+// and returns how many were expired.
+func (s *service) ExpireStale(ctx context.Context) (int, error) {
+	stale, err := s.repo.ListExpiredReservations(ctx, s.cfg.SweepBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	expired := 0
+	for _, r := range stale {
+		ok, err := s.expire(ctx, r.ID, r.SkuID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return expired, ctx.Err()
+			}
+			// One broken reservation must not hold back the others.
+			log.Error("Failed to expire reservation", "error", err, "reservation_id", r.ID)
+			continue
+		}
+		if ok {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+func (s *service) finish(ctx context.Context, reservationUUID uuid.UUID, to repo.ReservationStatus) (repo.GetReservationByUUIDRow, error) {
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	row, err := s.GetReservation(ctx, reservationUUID)
+	if err != nil {
+		return repo.GetReservationByUUIDRow{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.GetReservationByUUIDRow{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+
+	sku, reservation, err := lockReservation(ctx, qtx, row.SkuReservation.ID, row.SkuUuid.Bytes)
+	if err != nil {
+		return repo.GetReservationByUUIDRow{}, err
+	}
+	if reservation.Status != repo.ReservationStatusActive {
+		return repo.GetReservationByUUIDRow{}, fmt.Errorf("%w: %s", ErrReservationNotActive, reservation.Status)
+	}
+	if to == repo.ReservationStatusConfirmed && !reservation.ExpiresAt.Time.After(time.Now()) {
+		return repo.GetReservationByUUIDRow{}, ErrReservationExpired
+	}
+
+	sku, err = inventory.AdjustReserved(ctx, qtx, sku, -reservation.Quantity)
+	if err != nil {
+		log.Error("Failed to release reserved stock", "error", err, "sku_id", sku.ID)
+		return repo.GetReservationByUUIDRow{}, err
+	}
+
+	if to == repo.ReservationStatusConfirmed {
+		note := "reservation " + reservationUUID.String()
+		if _, err := inventory.ApplyStockChange(ctx, qtx, sku, -reservation.Quantity, repo.StockMovementReasonSale, &note); err != nil {
+			return repo.GetReservationByUUIDRow{}, err
+		}
+	}
+
+	reservation, err = qtx.UpdateReservationStatus(ctx, repo.UpdateReservationStatusParams{
+		ID:     reservation.ID,
+		Status: to,
+	})
+	if err != nil {
+		log.Error("Failed to update reservation status", "error", err, "reservation_id", reservation.ID)
+		return repo.GetReservationByUUIDRow{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return repo.GetReservationByUUIDRow{}, err
+	}
+
+	log.Info("Reservation finished", "reservation_id", reservation.ID, "status", to)
+	return repo.GetReservationByUUIDRow{SkuReservation: reservation, SkuUuid: sku.Uuid}, nil
+}
+
+// expire returns the copies of a stale reservation to the available stock, also when the SKU
+// has been deleted since. It reports false if the reservation was confirmed or released in the meantime.
+func (s *service) expire(ctx context.Context, reservationID, skuID int64) (bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+
+	// The SKU is locked before the reservation, as in lockReservation.
+	sku, err := inventory.LockSKUByID(ctx, qtx, skuID)
+	if err != nil {
+		return false, err
+	}
+	reservation, err := qtx.GetReservationByIDForUpdate(ctx, reservationID)
+	if err != nil {
+		return false, err
+	}
+	if reservation.Status != repo.ReservationStatusActive || reservation.ExpiresAt.Time.After(time.Now()) {
+		return false, nil
+	}
+
+	if _, err := inventory.AdjustReserved(ctx, qtx, sku, -reservation.Quantity); err != nil {
+		return false, err
+	}
+	if _, err := qtx.UpdateReservationStatus(ctx, repo.UpdateReservationStatusParams{
+		ID:     reservation.ID,
+		Status: repo.ReservationStatusExpired,
+	}); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+// lockReservation locks the SKU first and the reservation second, the same order
+// every stock change uses, so a sweep can't deadlock with a request.
+func lockReservation(ctx context.Context, q *repo.Queries, reservationID int64, skuUUID uuid.UUID) (repo.Sku, repo.SkuReservation, error) {
+	sku, err := inventory.LockSKU(ctx, q, skuUUID, nil)
+	if err != nil {
+		return repo.Sku{}, repo.SkuReservation{}, err
+	}
+	reservation, err := q.GetReservationByIDForUpdate(ctx, reservationID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Sku{}, repo.SkuReservation{}, ErrReservationNotFound
+		}
+		return repo.Sku{}, repo.SkuReservation{}, err
+	}
+	return sku, reservation, nil
+}
+
+func stringToPgText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+func stringToPgTextp(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{Valid: false}
+	}
+	return pgtype.Text{String: *s, Valid: true}
+}
+
+func uuidToPgUUID(u uuid.UUID) pgtype.UUID {
+	return pgtype.UUID{Bytes: u, Valid: true}
+}
@@ -0,0 +1,208 @@
+package reservations
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nikallow/bookstores-api/internal/adapters/postgres"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/config"
+	"github.com/nikallow/bookstores-api/internal/database/dbtest"
+)
+
+func newService(t *testing.T) (Service, *postgres.DB) {
+	t.Helper()
+	db := dbtest.New(t)
+	return NewService(repo.New(db), db, config.ReservationsConfig{
+		DefaultTTL: time.Hour,
+		MaxTTL:     24 * time.Hour,
+		SweepBatch: 100,
+	}), db
+}
+
+// createSKU creates a SKU with 10 copies in a new store.
+func createSKU(t *testing.T, db *postgres.DB) uuid.UUID {
+	t.Helper()
+	storeID, _ := dbtest.CreateStore(t, db)
+	return dbtest.CreateSKU(t, db, dbtest.CreateBook(t, db), storeID, 1000, 10)
+}
+
+// backdate makes a reservation's TTL run out.
+func backdate(t *testing.T, db *postgres.DB, reservationUUID uuid.UUID) {
+	t.Helper()
+	_, err := db.Exec(context.Background(),
+		"UPDATE sku_reservations SET expires_at = now() - interval '1 second' WHERE uuid = $1",
+		pgtype.UUID{Bytes: reservationUUID, Valid: true},
+	)
+	if err != nil {
+		t.Fatalf("failed to backdate reservation: %v", err)
+	}
+}
+
+func TestReservationLifecycle(t *testing.T) {
+	svc, db := newService(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		finish        func(t *testing.T, id uuid.UUID) error
+		wantStatus    repo.ReservationStatus
+		wantStock     int32
+		wantMovements []string
+	}{
+		{
+			name: "confirmed",
+			finish: func(t *testing.T, id uuid.UUID) error {
+				_, err := svc.ConfirmReservation(ctx, id)
+				return err
+			},
+			wantStatus:    repo.ReservationStatusConfirmed,
+			wantStock:     6,
+			wantMovements: []string{"sale"},
+		},
+		{
+			name: "released",
+			finish: func(t *testing.T, id uuid.UUID) error {
+				_, err := svc.ReleaseReservation(ctx, id)
+				return err
+			},
+			wantStatus: repo.ReservationStatusReleased,
+			wantStock:  10,
+		},
+		{
+			name: "expired",
+			finish: func(t *testing.T, id uuid.UUID) error {
+				backdate(t, db, id)
+				expired, err := svc.ExpireStale(ctx)
+				if err == nil && expired != 1 {
+					t.Errorf("ExpireStale() = %d, want 1", expired)
+				}
+				return err
+			},
+			wantStatus: repo.ReservationStatusExpired,
+			wantStock:  10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sku := createSKU(t, db)
+
+			reservation, err := svc.CreateReservation(ctx, sku, CreateReservationRequest{Quantity: 4})
+			if err != nil {
+				t.Fatalf("CreateReservation() error = %v", err)
+			}
+			if reservation.SkuReservation.Status != repo.ReservationStatusActive {
+				t.Errorf("status = %s, want %s", reservation.SkuReservation.Status, repo.ReservationStatusActive)
+			}
+			if reserved := dbtest.Reserved(t, db, sku); reserved != 4 {
+				t.Errorf("reserved = %d after the hold, want 4", reserved)
+			}
+			if _, err := svc.CreateReservation(ctx, sku, CreateReservationRequest{Quantity: 7}); !errors.Is(err, ErrInsufficientStock) {
+				t.Errorf("CreateReservation() beyond the available stock error = %v, want %v", err, ErrInsufficientStock)
+			}
+
+			id := uuid.UUID(reservation.SkuReservation.Uuid.Bytes)
+			if err := tt.finish(t, id); err != nil {
+				t.Fatalf("finish error = %v", err)
+			}
+
+			got, err := svc.GetReservation(ctx, id)
+			if err != nil {
+				t.Fatalf("GetReservation() error = %v", err)
+			}
+			if got.SkuReservation.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", got.SkuReservation.Status, tt.wantStatus)
+			}
+			if stock := dbtest.Stock(t, db, sku); stock != tt.wantStock {
+				t.Errorf("stock = %d, want %d", stock, tt.wantStock)
+			}
+			if reserved := dbtest.Reserved(t, db, sku); reserved != 0 {
+				t.Errorf("reserved = %d, want 0", reserved)
+			}
+			if movements := dbtest.Movements(t, db, sku); !slices.Equal(movements, tt.wantMovements) {
+				t.Errorf("movements = %v, want %v", movements, tt.wantMovements)
+			}
+
+			if _, err := svc.ReleaseReservation(ctx, id); !errors.Is(err, ErrReservationNotActive) {
+				t.Errorf("ReleaseReservation() of a finished hold error = %v, want %v", err, ErrReservationNotActive)
+			}
+		})
+	}
+}
+
+func TestConfirmExpiredReservation(t *testing.T) {
+	svc, db := newService(t)
+	ctx := context.Background()
+	sku := createSKU(t, db)
+
+	reservation, err := svc.CreateReservation(ctx, sku, CreateReservationRequest{Quantity: 1})
+	if err != nil {
+		t.Fatalf("CreateReservation() error = %v", err)
+	}
+	id := uuid.UUID(reservation.SkuReservation.Uuid.Bytes)
+	backdate(t, db, id)
+
+	if _, err := svc.ConfirmReservation(ctx, id); !errors.Is(err, ErrReservationExpired) {
+		t.Errorf("ConfirmReservation() error = %v, want %v", err, ErrReservationExpired)
+	}
+	if stock := dbtest.Stock(t, db, sku); stock != 10 {
+		t.Errorf("stock = %d, want 10", stock)
+	}
+}
+
+func TestCreateReservationErrors(t *testing.T) {
+	svc, db := newService(t)
+	sku := createSKU(t, db)
+
+	tests := []struct {
+		name    string
+		sku     uuid.UUID
+		params  CreateReservationRequest
+		wantErr error
+	}{
+		{"ttl too long", sku, CreateReservationRequest{Quantity: 1, TTLSeconds: 25 * 60 * 60}, ErrTTLTooLong},
+		{"more than in stock", sku, CreateReservationRequest{Quantity: 11}, ErrInsufficientStock},
+		{"unknown sku", uuid.New(), CreateReservationRequest{Quantity: 1}, ErrSKUNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.CreateReservation(context.Background(), tt.sku, tt.params); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateReservation() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	if reserved := dbtest.Reserved(t, db, sku); reserved != 0 {
+		t.Errorf("reserved = %d, want 0", reserved)
+	}
+}
+
+func TestExpireStaleOnDeletedSKU(t *testing.T) {
+	svc, db := newService(t)
+	ctx := context.Background()
+	sku := createSKU(t, db)
+
+	reservation, err := svc.CreateReservation(ctx, sku, CreateReservationRequest{Quantity: 3})
+	if err != nil {
+		t.Fatalf("CreateReservation() error = %v", err)
+	}
+	backdate(t, db, reservation.SkuReservation.Uuid.Bytes)
+	if _, err := db.Exec(ctx, "UPDATE skus SET deleted_at = now() WHERE uuid = $1", pgtype.UUID{Bytes: sku, Valid: true}); err != nil {
+		t.Fatalf("failed to delete sku: %v", err)
+	}
+
+	expired, err := svc.ExpireStale(ctx)
+	if err != nil {
+		t.Fatalf("ExpireStale() error = %v", err)
+	}
+	if expired != 1 {
+		t.Errorf("ExpireStale() = %d, want 1", expired)
+	}
+	if reserved := dbtest.Reserved(t, db, sku); reserved != 0 {
+		t.Errorf("reserved = %d, want 0", reserved)
+	}
+}
@@ -0,0 +1,56 @@
+package reservations
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/nikallow/bookstores-api/internal/config"
+	appMiddleware "github.com/nikallow/bookstores-api/internal/middleware"
+)
+
+// Sweeper periodically expires reservations whose TTL has passed.
+type Sweeper struct {
+	service  Service
+	interval time.Duration
+	log      *slog.Logger
+}
+
+func NewSweeper(service Service, cfg config.ReservationsConfig, log *slog.Logger) *Sweeper {
+	return &Sweeper{
+		service:  service,
+		interval: cfg.SweepInterval,
+		log:      log.With("component", "reservations_sweeper"),
+	}
+}
+
+// Run sweeps every interval until ctx is cancelled.
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	ctx = appMiddleware.WithLogger(ctx, s.log)
+	s.log.Info("Reservations sweeper started", "interval", s.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("Reservations sweeper stopped")
+			return
+		case <-ticker.C:
+			s.sweep(ctx)
+		}
+	}
+}
+
+func (s *Sweeper) sweep(ctx context.Context) {
+	expired, err := s.service.ExpireStale(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.log.Error("Failed to expire reservations", "error", err, "expired", expired)
+		}
+		return
+	}
+	if expired > 0 {
+		s.log.Info("Expired stale reservations", "count", expired)
+	}
+}
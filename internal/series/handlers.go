@@ -0,0 +1,243 @@
+package series
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+	"github.com/nikallow/bookstores-api/internal/response"
+)
+
+type Handler struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// CreateSeries
+//
+//	@Summary		Создать серию
+//	@Description	Добавляет книжную серию. Книга входит в серию через поля series_id и series_volume.
+//	@Tags			series
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		CreateSeriesRequest		true	"Данные серии"
+//	@Success		201		{object}	SeriesResponse			"Серия создана"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/series [post]
+func (h *Handler) CreateSeries(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	var req CreateSeriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read create series request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed for create series request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	series, err := h.service.Create(r.Context(), req)
+	if err != nil {
+		log.Error("Failed to create series", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusCreated, toSeriesResponse(series))
+}
+
+// ListSeries
+//
+//	@Summary		Получить список серий
+//	@Description	Возвращает страницу серий, отсортированных по названию. Книги серии по порядку томов: GET /books?series_id=&sort=series_volume.
+//	@Tags			series
+//	@Produce		json
+//	@Param			name	query		string					false	"Фильтр по названию (подстрока)"
+//	@Param			limit	query		int						false	"Размер страницы (1-100)"	default(20)
+//	@Param			cursor	query		string					false	"Курсор следующей страницы"
+//	@Param			order	query		string					false	"Направление сортировки"	Enums(asc, desc)	default(asc)
+//	@Success		200		{object}	SeriesListResponse		"Страница серий"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/series [get]
+func (h *Handler) ListSeries(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	query := r.URL.Query()
+	page, err := pagination.FromQuery(query, seriesSorts...)
+	if err != nil {
+		log.Warn("Invalid list series parameters", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := ListSeriesParams{Request: page}
+	if name := query.Get("name"); name != "" {
+		params.Name = &name
+	}
+
+	list, nextCursor, err := h.service.List(r.Context(), params)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error("Failed to list series", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	resp := make([]SeriesResponse, len(list))
+	for i, s := range list {
+		resp[i] = toSeriesResponse(s)
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, SeriesListResponse{Items: resp, NextCursor: nextCursor})
+}
+
+// GetSeries
+//
+//	@Summary		Получить серию
+//	@Description	Возвращает серию по её ID.
+//	@Tags			series
+//	@Produce		json
+//	@Param			seriesID	path		int						true	"ID серии"
+//	@Success		200			{object}	SeriesResponse			"Инфо о серии"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Серия отсутствует"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/series/{seriesID} [get]
+func (h *Handler) GetSeries(w http.ResponseWriter, r *http.Request) {
+	seriesID, ok := parseSeriesID(w, r)
+	if !ok {
+		return
+	}
+
+	series, err := h.service.GetByID(r.Context(), seriesID)
+	if err != nil {
+		writeSeriesError(w, r, err, seriesID)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toSeriesResponse(series))
+}
+
+// UpdateSeries
+//
+//	@Summary		Обновить серию
+//	@Description	Заменяет данные серии. Не переданное описание очищается.
+//	@Tags			series
+//	@Accept			json
+//	@Produce		json
+//	@Param			seriesID	path		int						true	"ID серии"
+//	@Param			input		body		UpdateSeriesRequest		true	"Новые данные серии"
+//	@Success		200			{object}	SeriesResponse			"Обновлённая серия"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Серия отсутствует"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/series/{seriesID} [put]
+func (h *Handler) UpdateSeries(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	seriesID, ok := parseSeriesID(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateSeriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read update series request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed for update series request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	series, err := h.service.Update(r.Context(), seriesID, req)
+	if err != nil {
+		writeSeriesError(w, r, err, seriesID)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toSeriesResponse(series))
+}
+
+// DeleteSeries
+//
+//	@Summary		Удалить серию
+//	@Description	Удаляет серию, если в неё не входит ни одна книга, включая удалённые.
+//	@Tags			series
+//	@Param			seriesID	path	int	true	"ID серии"
+//	@Success		204			"Серия удалена"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Серия отсутствует"
+//	@Failure		409			{object}	response.ErrorResponse	"В серии есть книги"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/series/{seriesID} [delete]
+func (h *Handler) DeleteSeries(w http.ResponseWriter, r *http.Request) {
+	seriesID, ok := parseSeriesID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), seriesID); err != nil {
+		writeSeriesError(w, r, err, seriesID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeSeriesError(w http.ResponseWriter, r *http.Request, err error, seriesID int64) {
+	switch {
+	case errors.Is(err, ErrSeriesNotFound):
+		response.WriteError(w, r, http.StatusNotFound, "Series not found")
+	case errors.Is(err, ErrSeriesHasBooks):
+		response.WriteError(w, r, http.StatusConflict, err.Error())
+	default:
+		middleware.LoggerFromContext(r.Context()).Error("Series operation failed", "error", err, "series_id", seriesID)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func parseSeriesID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	seriesIDStr := chi.URLParam(r, "seriesID")
+	seriesID, err := strconv.ParseInt(seriesIDStr, 10, 64)
+	if err != nil {
+		middleware.LoggerFromContext(r.Context()).Warn("Invalid series ID format", "series_id", seriesIDStr)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid series ID format")
+		return 0, false
+	}
+	return seriesID, true
+}
+
+func toSeriesResponse(series repo.Series) SeriesResponse {
+	resp := SeriesResponse{
+		ID:   series.ID,
+		Name: series.Name,
+	}
+	if series.Description.Valid {
+		resp.Description = &series.Description.String
+	}
+	return resp
+}
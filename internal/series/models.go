@@ -0,0 +1,32 @@
+package series
+
+import "github.com/nikallow/bookstores-api/internal/pagination"
+
+// Sort fields accepted by GET /series, the first one is the default.
+var seriesSorts = []string{"name"}
+
+type CreateSeriesRequest struct {
+	Name        string  `json:"name"                  validate:"required"`
+	Description *string `json:"description,omitempty"`
+}
+
+type UpdateSeriesRequest struct {
+	Name        string  `json:"name"        validate:"required"`
+	Description *string `json:"description"`
+}
+
+type ListSeriesParams struct {
+	pagination.Request
+	Name *string
+}
+
+type SeriesListResponse struct {
+	Items      []SeriesResponse `json:"items"`
+	NextCursor *string          `json:"next_cursor"`
+}
+
+type SeriesResponse struct {
+	ID          int64   `json:"id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
@@ -0,0 +1,151 @@
+package series
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/books"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+)
+
+var (
+	ErrSeriesNotFound = books.ErrSeriesNotFound
+	ErrSeriesHasBooks = errors.New("series has books")
+)
+
+// pgForeignKeyViolation is the SQLSTATE of a failed REFERENCES constraint, e.g. books.series_id.
+const pgForeignKeyViolation = "23503"
+
+type Service interface {
+	Create(ctx context.Context, params CreateSeriesRequest) (repo.Series, error)
+	List(ctx context.Context, params ListSeriesParams) ([]repo.Series, *string, error)
+	GetByID(ctx context.Context, id int64) (repo.Series, error)
+	Update(ctx context.Context, id int64, params UpdateSeriesRequest) (repo.Series, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type service struct {
+	repo repo.Querier
+}
+
+func NewService(repo repo.Querier) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Create(ctx context.Context, params CreateSeriesRequest) (repo.Series, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	series, err := s.repo.CreateSeries(ctx, repo.CreateSeriesParams{
+		Name:        params.Name,
+		Description: stringToPgTextp(params.Description),
+	})
+	if err != nil {
+		log.Error("Failed to create series", "error", err)
+		return repo.Series{}, fmt.Errorf("failed to create series: %w", err)
+	}
+
+	log.Info("Series created successfully", "series_id", series.ID)
+	return series, nil
+}
+
+func (s *service) List(ctx context.Context, params ListSeriesParams) ([]repo.Series, *string, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	cursor, err := params.DecodeCursor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queryParams := repo.ListSeriesParams{
+		Name:      stringToPgTextp(params.Name),
+		PageLimit: params.QueryLimit(),
+	}
+	if cursor != nil {
+		queryParams.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+		queryParams.CursorText = pgtype.Text{String: cursor.Value, Valid: true}
+	}
+
+	var list []repo.Series
+	if params.Desc {
+		list, err = s.repo.ListSeriesDesc(ctx, repo.ListSeriesDescParams(queryParams))
+	} else {
+		list, err = s.repo.ListSeries(ctx, queryParams)
+	}
+	if err != nil {
+		log.Error("Failed to list series", "error", err)
+		return nil, nil, fmt.Errorf("failed to list series: %w", err)
+	}
+
+	list, hasMore := pagination.Trim(list, params.Request)
+	if !hasMore {
+		return list, nil, nil
+	}
+	last := list[len(list)-1]
+	next := params.NextCursor(last.Name, last.ID)
+	return list, &next, nil
+}
+
+func (s *service) GetByID(ctx context.Context, id int64) (repo.Series, error) {
+	series, err := s.repo.GetSeriesByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Series{}, ErrSeriesNotFound
+		}
+		return repo.Series{}, fmt.Errorf("failed to get series: %w", err)
+	}
+	return series, nil
+}
+
+func (s *service) Update(ctx context.Context, id int64, params UpdateSeriesRequest) (repo.Series, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	series, err := s.repo.UpdateSeries(ctx, repo.UpdateSeriesParams{
+		ID:          id,
+		Name:        params.Name,
+		Description: stringToPgTextp(params.Description),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Series{}, ErrSeriesNotFound
+		}
+		log.Error("Failed to update series", "error", err, "series_id", id)
+		return repo.Series{}, fmt.Errorf("failed to update series: %w", err)
+	}
+
+	log.Info("Series updated successfully", "series_id", id)
+	return series, nil
+}
+
+// Delete removes a series that no book refers to, soft-deleted books included.
+func (s *service) Delete(ctx context.Context, id int64) error {
+	log := middleware.LoggerFromContext(ctx)
+
+	deleted, err := s.repo.DeleteSeries(ctx, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+			return ErrSeriesHasBooks
+		}
+		log.Error("Failed to delete series", "error", err, "series_id", id)
+		return fmt.Errorf("failed to delete series: %w", err)
+	}
+	if deleted == 0 {
+		return ErrSeriesNotFound
+	}
+
+	log.Info("Series deleted successfully", "series_id", id)
+	return nil
+}
+
+func stringToPgTextp(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{Valid: false}
+	}
+	return pgtype.Text{String: *s, Valid: true}
+}
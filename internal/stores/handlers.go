@@ -8,7 +8,10 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
 	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/money"
+	"github.com/nikallow/bookstores-api/internal/pagination"
 	"github.com/nikallow/bookstores-api/internal/response"
 )
 
@@ -48,20 +51,23 @@ func (h *Handler) CreateStore(w http.ResponseWriter, r *http.Request) {
 	if err := h.validate.Struct(req); err != nil {
 		log.Warn("Validation failed for create store request", "error", err)
 		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	currency, err := parseCurrency(req.Currency)
+	if err != nil {
+		log.Warn("Invalid store currency", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	store, err := h.service.Create(r.Context(), req.Name, req.Address)
+	store, err := h.service.Create(r.Context(), req.Name, req.Address, currency)
 	if err != nil {
 		log.Error("Failed to create store", "error", err)
 		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	resp := StoreResponse{
-		UUID:    store.Uuid.Bytes,
-		Name:    store.Name,
-		Address: store.Address,
-	}
+	resp := toStoreResponse(store)
 
 	response.WriteJSON(w, r, http.StatusCreated, resp)
 }
@@ -69,17 +75,33 @@ func (h *Handler) CreateStore(w http.ResponseWriter, r *http.Request) {
 // ListStores
 //
 //	@Summary		Получить список магазинов
-//	@Description	Возвращает список всех действующих магазинов.
+//	@Description	Возвращает страницу действующих магазинов. Для следующей страницы передайте next_cursor из ответа.
 //	@Tags			stores
 //	@Produce		json
-//	@Success		200	{array}		StoreResponse			"Список действующих магазинов"
-//	@Failure		500	{object}	response.ErrorResponse	"Internal server error"
+//	@Param			limit	query		int						false	"Размер страницы (1-100)"	default(20)
+//	@Param			cursor	query		string					false	"Курсор следующей страницы"
+//	@Param			sort	query		string					false	"Поле сортировки"			Enums(name, created_at)	default(name)
+//	@Param			order	query		string					false	"Направление сортировки"	Enums(asc, desc)		default(asc)
+//	@Success		200		{object}	StoreListResponse		"Страница действующих магазинов"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
 //	@Router			/stores [get]
 func (h *Handler) ListStores(w http.ResponseWriter, r *http.Request) {
 	log := middleware.LoggerFromContext(r.Context())
 
-	stores, err := h.service.List(r.Context())
+	page, err := pagination.FromQuery(r.URL.Query(), storeSorts...)
 	if err != nil {
+		log.Warn("Invalid list stores parameters", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stores, nextCursor, err := h.service.List(r.Context(), page)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
 		log.Error("Failed to list stores", "error", err)
 		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
 		return
@@ -87,14 +109,10 @@ func (h *Handler) ListStores(w http.ResponseWriter, r *http.Request) {
 
 	resp := make([]StoreResponse, len(stores))
 	for i, s := range stores {
-		resp[i] = StoreResponse{
-			UUID:    s.Uuid.Bytes,
-			Name:    s.Name,
-			Address: s.Address,
-		}
+		resp[i] = toStoreResponse(s)
 	}
 
-	response.WriteJSON(w, r, http.StatusOK, resp)
+	response.WriteJSON(w, r, http.StatusOK, StoreListResponse{Items: resp, NextCursor: nextCursor})
 }
 
 // GetStore
@@ -131,11 +149,7 @@ func (h *Handler) GetStore(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := StoreResponse{
-		UUID:    store.Uuid.Bytes,
-		Name:    store.Name,
-		Address: store.Address,
-	}
+	resp := toStoreResponse(store)
 	response.WriteJSON(w, r, http.StatusOK, resp)
 }
 
@@ -150,6 +164,8 @@ func (h *Handler) GetStore(w http.ResponseWriter, r *http.Request) {
 //	@Param			input		body		UpdateStoreRequest		true	"Данные для обновления информации о магазине"
 //	@Success		200			{object}	StoreResponse			"Обновлённое инфо об обновлённом магазине"
 //	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Искомый магазин отсутствует"
+//	@Failure		409			{object}	response.ErrorResponse	"Валюту нельзя сменить, пока у магазина есть SKU"
 //	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
 //	@Router			/stores/{storeUUID} [put]
 func (h *Handler) UpdateStore(w http.ResponseWriter, r *http.Request) {
@@ -175,10 +191,19 @@ func (h *Handler) UpdateStore(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	store, err := h.service.Update(r.Context(), id, req.Name, req.Address)
+	currency, err := parseCurrency(req.Currency)
+	if err != nil {
+		log.Warn("Invalid store currency", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	store, err := h.service.Update(r.Context(), id, req.Name, req.Address, currency)
 	if err != nil {
 		if errors.Is(err, ErrStoreNotFound) {
 			response.WriteError(w, r, http.StatusNotFound, "Store not found")
+		} else if errors.Is(err, ErrCurrencyInUse) {
+			response.WriteError(w, r, http.StatusConflict, err.Error())
 		} else {
 			log.Error("Failed to update store", "error", err, "store_uuid", id)
 			response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
@@ -186,11 +211,7 @@ func (h *Handler) UpdateStore(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := StoreResponse{
-		UUID:    store.Uuid.Bytes,
-		Name:    store.Name,
-		Address: store.Address,
-	}
+	resp := toStoreResponse(store)
 
 	response.WriteJSON(w, r, http.StatusOK, resp)
 }
@@ -224,3 +245,20 @@ func (h *Handler) DeleteStore(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func toStoreResponse(s repo.Store) StoreResponse {
+	return StoreResponse{
+		UUID:     s.Uuid.Bytes,
+		Name:     s.Name,
+		Address:  s.Address,
+		Currency: s.Currency,
+	}
+}
+
+// parseCurrency returns an empty currency when code is empty.
+func parseCurrency(code string) (money.Currency, error) {
+	if code == "" {
+		return "", nil
+	}
+	return money.ParseCurrency(code)
+}
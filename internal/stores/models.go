@@ -2,18 +2,31 @@ package stores
 
 import "github.com/google/uuid"
 
+// Sort fields accepted by GET /stores, the first one is the default.
+var storeSorts = []string{"name", "created_at"}
+
 type CreateStoreRequest struct {
 	Name    string `json:"name"    validate:"required"`
 	Address string `json:"address" validate:"required"`
+	// ISO 4217, RUB when omitted.
+	Currency string `json:"currency" validate:"omitempty,len=3" example:"KZT"`
 }
 
 type UpdateStoreRequest struct {
 	Name    string `json:"name"    validate:"required"`
 	Address string `json:"address" validate:"required"`
+	// ISO 4217, unchanged when omitted. Can change only while the store has no SKUs.
+	Currency string `json:"currency" validate:"omitempty,len=3" example:"KZT"`
+}
+
+type StoreListResponse struct {
+	Items      []StoreResponse `json:"items"`
+	NextCursor *string         `json:"next_cursor"`
 }
 
 type StoreResponse struct {
-	UUID    uuid.UUID `json:"uuid"`
-	Name    string    `json:"name"`
-	Address string    `json:"address"`
+	UUID     uuid.UUID `json:"uuid"`
+	Name     string    `json:"name"`
+	Address  string    `json:"address"`
+	Currency string    `json:"currency" example:"RUB"`
 }
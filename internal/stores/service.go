@@ -4,60 +4,134 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nikallow/bookstores-api/internal/adapters/postgres"
 	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/audit"
 	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/money"
+	"github.com/nikallow/bookstores-api/internal/pagination"
 )
 
 var (
 	ErrStoreNotFound = errors.New("store not found")
+	ErrCurrencyInUse = errors.New("store currency can't change while the store has SKUs")
 )
 
+// pgForeignKeyViolation is the SQLSTATE of a failed REFERENCES constraint, e.g. skus (store_id, currency).
+const pgForeignKeyViolation = "23503"
+
 type Service interface {
-	Create(ctx context.Context, name, address string) (repo.Store, error)
-	List(ctx context.Context) ([]repo.Store, error)
+	Create(ctx context.Context, name, address string, currency money.Currency) (repo.Store, error)
+	List(ctx context.Context, page pagination.Request) ([]repo.Store, *string, error)
 	GetByUUID(ctx context.Context, id uuid.UUID) (repo.Store, error)
-	Update(ctx context.Context, id uuid.UUID, name, address string) (repo.Store, error)
+	// Update keeps the currency of the store when currency is empty.
+	Update(ctx context.Context, id uuid.UUID, name, address string, currency money.Currency) (repo.Store, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type service struct {
 	repo repo.Querier
+	db   postgres.TxBeginner
 }
 
-func NewService(repo repo.Querier) Service {
-	return &service{repo: repo}
+func NewService(repo repo.Querier, db postgres.TxBeginner) Service {
+	return &service{repo: repo, db: db}
 }
 
-func (s *service) Create(ctx context.Context, name, address string) (repo.Store, error) {
+func (s *service) Create(ctx context.Context, name, address string, currency money.Currency) (repo.Store, error) {
 	log := middleware.LoggerFromContext(ctx)
 
-	store, err := s.repo.CreateStore(ctx, repo.CreateStoreParams{
-		Name:    name,
-		Address: address,
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.Store{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+
+	store, err := qtx.CreateStore(ctx, repo.CreateStoreParams{
+		Name:     name,
+		Address:  address,
+		Currency: string(currency),
 	})
 	if err != nil {
 		log.Error("Failed to create store", "error", err)
 		return repo.Store{}, fmt.Errorf("failed to create store: %w", err)
 	}
+	if err := recordStore(ctx, qtx, audit.ActionCreate, nil, store); err != nil {
+		return repo.Store{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return repo.Store{}, err
+	}
 
 	log.Info("Store created successfully", "store_uuid", store.Uuid)
 	return store, nil
 }
 
-func (s *service) List(ctx context.Context) ([]repo.Store, error) {
+func (s *service) List(ctx context.Context, page pagination.Request) ([]repo.Store, *string, error) {
 	log := middleware.LoggerFromContext(ctx)
 
-	stores, err := s.repo.ListStores(ctx)
+	cursor, err := page.DecodeCursor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var stores []repo.Store
+	if page.SortBy == "created_at" {
+		params := repo.ListStoresByCreatedAtParams{PageLimit: page.QueryLimit()}
+		if cursor != nil {
+			t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%w: bad timestamp", pagination.ErrInvalidCursor)
+			}
+			params.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+			params.CursorTime = pgtype.Timestamptz{Time: t, Valid: true}
+		}
+		if page.Desc {
+			stores, err = s.repo.ListStoresByCreatedAtDesc(ctx, repo.ListStoresByCreatedAtDescParams(params))
+		} else {
+			stores, err = s.repo.ListStoresByCreatedAt(ctx, params)
+		}
+	} else {
+		params := repo.ListStoresByNameParams{PageLimit: page.QueryLimit()}
+		if cursor != nil {
+			params.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+			params.CursorText = pgtype.Text{String: cursor.Value, Valid: true}
+		}
+		if page.Desc {
+			stores, err = s.repo.ListStoresByNameDesc(ctx, repo.ListStoresByNameDescParams(params))
+		} else {
+			stores, err = s.repo.ListStoresByName(ctx, params)
+		}
+	}
 	if err != nil {
 		log.Error("Failed to list stores", "error", err)
-		return nil, fmt.Errorf("failed to list stores: %w", err)
+		return nil, nil, fmt.Errorf("failed to list stores: %w", err)
 	}
 
-	return stores, nil
+	stores, hasMore := pagination.Trim(stores, page)
+	if !hasMore {
+		return stores, nil, nil
+	}
+	last := stores[len(stores)-1]
+	value := last.Name
+	if page.SortBy == "created_at" {
+		value = last.CreatedAt.Time.Format(time.RFC3339Nano)
+	}
+	next := page.NextCursor(value, last.ID)
+	return stores, &next, nil
 }
 
 func (s *service) GetByUUID(ctx context.Context, id uuid.UUID) (repo.Store, error) {
@@ -74,21 +148,46 @@ func (s *service) GetByUUID(ctx context.Context, id uuid.UUID) (repo.Store, erro
 	return store, nil
 }
 
-func (s *service) Update(ctx context.Context, id uuid.UUID, name, address string) (repo.Store, error) {
+func (s *service) Update(ctx context.Context, id uuid.UUID, name, address string, currency money.Currency) (repo.Store, error) {
 	log := middleware.LoggerFromContext(ctx)
 
-	store, err := s.repo.UpdateStore(ctx, repo.UpdateStoreParams{
-		Uuid:    uuidToPgUUID(id),
-		Name:    name,
-		Address: address,
-	})
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.Store{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+
+	before, err := qtx.GetStoreByUUIDForUpdate(ctx, uuidToPgUUID(id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return repo.Store{}, ErrStoreNotFound
 		}
+		log.Error("Failed to get store for update", "error", err, "store_uuid", id)
+		return repo.Store{}, fmt.Errorf("failed to update store: %w", err)
+	}
+
+	store, err := qtx.UpdateStore(ctx, repo.UpdateStoreParams{
+		Uuid:     uuidToPgUUID(id),
+		Name:     name,
+		Address:  address,
+		Currency: pgtype.Text{String: string(currency), Valid: currency != ""},
+	})
+	if err != nil {
+		if isPgError(err, pgForeignKeyViolation) {
+			return repo.Store{}, ErrCurrencyInUse
+		}
 		log.Error("Failed to update store", "error", err, "store_uuid", id)
 		return repo.Store{}, fmt.Errorf("failed to update store: %w", err)
 	}
+	if err := recordStore(ctx, qtx, audit.ActionUpdate, before, store); err != nil {
+		return repo.Store{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return repo.Store{}, err
+	}
 
 	log.Info("Store updated successfully", "store_uuid", store.Uuid)
 	return store, nil
@@ -97,16 +196,51 @@ func (s *service) Update(ctx context.Context, id uuid.UUID, name, address string
 func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
 	log := middleware.LoggerFromContext(ctx)
 
-	err := s.repo.SoftDeleteStore(ctx, uuidToPgUUID(id))
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+
+	// Deleting a store that is already gone succeeds and changes nothing.
+	before, err := qtx.GetStoreByUUIDForUpdate(ctx, uuidToPgUUID(id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		log.Error("Failed to get store for delete", "error", err, "store_uuid", id)
+		return fmt.Errorf("failed to delete store: %w", err)
+	}
+
+	store, err := qtx.SoftDeleteStore(ctx, before.Uuid)
 	if err != nil {
 		log.Error("Failed to soft delete store", "error", err, "store_uuid", id)
 		return fmt.Errorf("failed to delete store: %w", err)
 	}
+	if err := recordStore(ctx, qtx, audit.ActionDelete, before, store); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
 
 	log.Info("Store soft-deleted successfully", "store_uuid", id)
 	return nil
 }
 
+// recordStore adds the audit event of a change of a store; before is nil for a created one.
+func recordStore(ctx context.Context, q *repo.Queries, action audit.Action, before any, after repo.Store) error {
+	return audit.Record(ctx, q, audit.EntityStore, uuid.UUID(after.Uuid.Bytes).String(), action, before, after)
+}
+
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
+
 func uuidToPgUUID(u uuid.UUID) pgtype.UUID {
 	return pgtype.UUID{Bytes: u, Valid: true}
 }
@@ -0,0 +1,241 @@
+package tags
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+	"github.com/nikallow/bookstores-api/internal/response"
+)
+
+type Handler struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// CreateTag
+//
+//	@Summary		Создать тег
+//	@Description	Добавляет тег. Название приводится к нижнему регистру, пробелы по краям отбрасываются.
+//	@Tags			tags
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		CreateTagRequest		true	"Данные тега"
+//	@Success		201		{object}	TagResponse				"Тег создан"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		409		{object}	response.ErrorResponse	"Тег с таким названием уже есть"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/tags [post]
+func (h *Handler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	var req CreateTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read create tag request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed for create tag request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tag, err := h.service.Create(r.Context(), req)
+	if err != nil {
+		writeTagError(w, r, err, 0)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusCreated, toTagResponse(tag))
+}
+
+// ListTags
+//
+//	@Summary		Получить список тегов
+//	@Description	Возвращает страницу тегов, отсортированных по названию, с числом книг у каждого. Для следующей страницы передайте next_cursor из ответа.
+//	@Tags			tags
+//	@Produce		json
+//	@Param			prefix	query		string					false	"Начало названия (для автодополнения)"
+//	@Param			limit	query		int						false	"Размер страницы (1-100)"	default(20)
+//	@Param			cursor	query		string					false	"Курсор следующей страницы"
+//	@Param			order	query		string					false	"Направление сортировки"	Enums(asc, desc)	default(asc)
+//	@Success		200		{object}	TagListResponse			"Страница тегов"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/tags [get]
+func (h *Handler) ListTags(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	query := r.URL.Query()
+	page, err := pagination.FromQuery(query, tagSorts...)
+	if err != nil {
+		log.Warn("Invalid list tags parameters", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := ListTagsParams{Request: page}
+	if prefix := query.Get("prefix"); prefix != "" {
+		params.Prefix = &prefix
+	}
+
+	tags, nextCursor, err := h.service.List(r.Context(), params)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error("Failed to list tags", "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	resp := make([]TagResponse, len(tags))
+	for i, t := range tags {
+		resp[i] = TagResponse{ID: t.ID, Name: t.Name, BookCount: &t.BookCount}
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, TagListResponse{Items: resp, NextCursor: nextCursor})
+}
+
+// GetTag
+//
+//	@Summary		Получить тег
+//	@Description	Возвращает тег по его ID.
+//	@Tags			tags
+//	@Produce		json
+//	@Param			tagID	path		int						true	"ID тега"
+//	@Success		200		{object}	TagResponse				"Инфо о теге"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"Тег отсутствует"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/tags/{tagID} [get]
+func (h *Handler) GetTag(w http.ResponseWriter, r *http.Request) {
+	tagID, ok := parseTagID(w, r)
+	if !ok {
+		return
+	}
+
+	tag, err := h.service.GetByID(r.Context(), tagID)
+	if err != nil {
+		writeTagError(w, r, err, tagID)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toTagResponse(tag))
+}
+
+// UpdateTag
+//
+//	@Summary		Переименовать тег
+//	@Description	Меняет название тега, книги остаются с ним связаны.
+//	@Tags			tags
+//	@Accept			json
+//	@Produce		json
+//	@Param			tagID	path		int						true	"ID тега"
+//	@Param			input	body		UpdateTagRequest		true	"Новое название тега"
+//	@Success		200		{object}	TagResponse				"Обновлённый тег"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"Тег отсутствует"
+//	@Failure		409		{object}	response.ErrorResponse	"Тег с таким названием уже есть"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/tags/{tagID} [put]
+func (h *Handler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	tagID, ok := parseTagID(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read update tag request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed for update tag request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tag, err := h.service.Update(r.Context(), tagID, req)
+	if err != nil {
+		writeTagError(w, r, err, tagID)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toTagResponse(tag))
+}
+
+// DeleteTag
+//
+//	@Summary		Удалить тег
+//	@Description	Удаляет тег и снимает его со всех книг.
+//	@Tags			tags
+//	@Param			tagID	path	int	true	"ID тега"
+//	@Success		204		"Тег удалён"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"Тег отсутствует"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/tags/{tagID} [delete]
+func (h *Handler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	tagID, ok := parseTagID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), tagID); err != nil {
+		writeTagError(w, r, err, tagID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeTagError(w http.ResponseWriter, r *http.Request, err error, tagID int64) {
+	switch {
+	case errors.Is(err, ErrTagNotFound):
+		response.WriteError(w, r, http.StatusNotFound, "Tag not found")
+	case errors.Is(err, ErrBlankTag):
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrTagExists):
+		response.WriteError(w, r, http.StatusConflict, err.Error())
+	default:
+		middleware.LoggerFromContext(r.Context()).Error("Tag operation failed", "error", err, "tag_id", tagID)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func parseTagID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	tagIDStr := chi.URLParam(r, "tagID")
+	tagID, err := strconv.ParseInt(tagIDStr, 10, 64)
+	if err != nil {
+		middleware.LoggerFromContext(r.Context()).Warn("Invalid tag ID format", "tag_id", tagIDStr)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid tag ID format")
+		return 0, false
+	}
+	return tagID, true
+}
+
+func toTagResponse(tag repo.Tag) TagResponse {
+	return TagResponse{
+		ID:   tag.ID,
+		Name: tag.Name,
+	}
+}
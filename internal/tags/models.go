@@ -0,0 +1,30 @@
+package tags
+
+import "github.com/nikallow/bookstores-api/internal/pagination"
+
+// Sort fields accepted by GET /tags, the first one is the default.
+var tagSorts = []string{"name"}
+
+type CreateTagRequest struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+type UpdateTagRequest struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+type ListTagsParams struct {
+	pagination.Request
+	Prefix *string
+}
+
+type TagListResponse struct {
+	Items      []TagResponse `json:"items"`
+	NextCursor *string       `json:"next_cursor"`
+}
+
+type TagResponse struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	BookCount *int64 `json:"book_count,omitempty"`
+}
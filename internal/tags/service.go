@@ -0,0 +1,162 @@
+package tags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/books"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+)
+
+var (
+	ErrTagNotFound = errors.New("tag not found")
+	ErrTagExists   = errors.New("tag with this name already exists")
+	ErrBlankTag    = books.ErrBlankTag
+)
+
+// pgUniqueViolation is the SQLSTATE of a failed UNIQUE constraint, e.g. tags.name.
+const pgUniqueViolation = "23505"
+
+type Service interface {
+	Create(ctx context.Context, params CreateTagRequest) (repo.Tag, error)
+	List(ctx context.Context, params ListTagsParams) ([]repo.ListTagsRow, *string, error)
+	GetByID(ctx context.Context, id int64) (repo.Tag, error)
+	Update(ctx context.Context, id int64, params UpdateTagRequest) (repo.Tag, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type service struct {
+	repo repo.Querier
+}
+
+func NewService(repo repo.Querier) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Create(ctx context.Context, params CreateTagRequest) (repo.Tag, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	name := books.NormalizeTag(params.Name)
+	if name == "" {
+		return repo.Tag{}, ErrBlankTag
+	}
+
+	tag, err := s.repo.CreateTag(ctx, name)
+	if err != nil {
+		if isPgError(err, pgUniqueViolation) {
+			return repo.Tag{}, ErrTagExists
+		}
+		log.Error("Failed to create tag", "error", err)
+		return repo.Tag{}, fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	log.Info("Tag created successfully", "tag_id", tag.ID)
+	return tag, nil
+}
+
+func (s *service) List(ctx context.Context, params ListTagsParams) ([]repo.ListTagsRow, *string, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	cursor, err := params.DecodeCursor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queryParams := repo.ListTagsParams{
+		PageLimit: params.QueryLimit(),
+	}
+	if params.Prefix != nil {
+		queryParams.Prefix = pgtype.Text{String: books.NormalizeTag(*params.Prefix), Valid: true}
+	}
+	if cursor != nil {
+		queryParams.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+		queryParams.CursorText = pgtype.Text{String: cursor.Value, Valid: true}
+	}
+
+	var tags []repo.ListTagsRow
+	if params.Desc {
+		var rows []repo.ListTagsDescRow
+		rows, err = s.repo.ListTagsDesc(ctx, repo.ListTagsDescParams(queryParams))
+		for _, row := range rows {
+			tags = append(tags, repo.ListTagsRow(row))
+		}
+	} else {
+		tags, err = s.repo.ListTags(ctx, queryParams)
+	}
+	if err != nil {
+		log.Error("Failed to list tags", "error", err)
+		return nil, nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	tags, hasMore := pagination.Trim(tags, params.Request)
+	if !hasMore {
+		return tags, nil, nil
+	}
+	last := tags[len(tags)-1]
+	next := params.NextCursor(last.Name, last.ID)
+	return tags, &next, nil
+}
+
+func (s *service) GetByID(ctx context.Context, id int64) (repo.Tag, error) {
+	tag, err := s.repo.GetTagByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Tag{}, ErrTagNotFound
+		}
+		return repo.Tag{}, fmt.Errorf("failed to get tag: %w", err)
+	}
+	return tag, nil
+}
+
+// Update renames a tag, the books keep it under the new name.
+func (s *service) Update(ctx context.Context, id int64, params UpdateTagRequest) (repo.Tag, error) {
+	log := middleware.LoggerFromContext(ctx)
+
+	name := books.NormalizeTag(params.Name)
+	if name == "" {
+		return repo.Tag{}, ErrBlankTag
+	}
+
+	tag, err := s.repo.UpdateTag(ctx, repo.UpdateTagParams{ID: id, Name: name})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Tag{}, ErrTagNotFound
+		}
+		if isPgError(err, pgUniqueViolation) {
+			return repo.Tag{}, ErrTagExists
+		}
+		log.Error("Failed to update tag", "error", err, "tag_id", id)
+		return repo.Tag{}, fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	log.Info("Tag updated successfully", "tag_id", id)
+	return tag, nil
+}
+
+// Delete removes a tag together with its links to books.
+func (s *service) Delete(ctx context.Context, id int64) error {
+	log := middleware.LoggerFromContext(ctx)
+
+	deleted, err := s.repo.DeleteTag(ctx, id)
+	if err != nil {
+		log.Error("Failed to delete tag", "error", err, "tag_id", id)
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	if deleted == 0 {
+		return ErrTagNotFound
+	}
+
+	log.Info("Tag deleted successfully", "tag_id", id)
+	return nil
+}
+
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
@@ -0,0 +1,255 @@
+package transfers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+	"github.com/nikallow/bookstores-api/internal/response"
+)
+
+type Handler struct {
+	service  Service
+	validate *validator.Validate
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// CreateTransfer
+//
+//	@Summary		Отправить товар в другой магазин
+//	@Description	Атомарно списывает экземпляры книги в магазине-отправителе и переводит их в пути (dispatched).
+//	@Description	Если в магазине-получателе нет SKU этой книги, он создаётся с ценой отправителя и нулевым остатком;
+//	@Description	если магазины в разных валютах, SKU получателя нужно создать заранее.
+//	@Tags			transfers
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		CreateTransferRequest	true	"Книга, магазины и количество"
+//	@Success		201		{object}	TransferResponse		"Перемещение создано"
+//	@Failure		400		{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404		{object}	response.ErrorResponse	"Книга, магазин или SKU отправителя не найдены"
+//	@Failure		409		{object}	response.ErrorResponse	"Недостаточно товара или у получателя нет SKU, а магазин в другой валюте"
+//	@Failure		500		{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/transfers [post]
+func (h *Handler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	var req CreateTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Failed to read create transfer request", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		log.Warn("Validation failed", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	transfer, err := h.service.CreateTransfer(r.Context(), req)
+	if err != nil {
+		h.writeServiceError(w, r, err, "Failed to create transfer")
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusCreated, toTransferResponse(transfer))
+}
+
+// GetTransfer
+//
+//	@Summary		Получить перемещение
+//	@Description	Возвращает перемещение и его статус.
+//	@Tags			transfers
+//	@Produce		json
+//	@Param			transferUUID	path		string					true	"UUID перемещения"
+//	@Success		200				{object}	TransferResponse		"Перемещение"
+//	@Failure		400				{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404				{object}	response.ErrorResponse	"Перемещение не найдено"
+//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/transfers/{transferUUID} [get]
+func (h *Handler) GetTransfer(w http.ResponseWriter, r *http.Request) {
+	h.apply(w, r, h.service.GetTransfer, "Failed to get transfer")
+}
+
+// ListTransfers
+//
+//	@Summary		Список перемещений
+//	@Description	Возвращает страницу перемещений, отсортированных по времени создания.
+//	@Tags			transfers
+//	@Produce		json
+//	@Param			status		query		string					false	"Фильтр по статусу"	Enums(dispatched, received, cancelled)
+//	@Param			store_uuid	query		string					false	"Магазин-отправитель или получатель"
+//	@Param			limit		query		int						false	"Размер страницы (1-100)"	default(20)
+//	@Param			cursor		query		string					false	"Курсор следующей страницы"
+//	@Param			order		query		string					false	"Направление сортировки по времени"	Enums(asc, desc)	default(asc)
+//	@Success		200			{object}	TransferListResponse	"Страница перемещений"
+//	@Failure		400			{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404			{object}	response.ErrorResponse	"Магазин не найден"
+//	@Failure		500			{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/transfers [get]
+func (h *Handler) ListTransfers(w http.ResponseWriter, r *http.Request) {
+	log := middleware.LoggerFromContext(r.Context())
+
+	query := r.URL.Query()
+	page, err := pagination.FromQuery(query, "created_at")
+	if err != nil {
+		log.Warn("Invalid list transfers parameters", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := ListTransfersParams{Request: page}
+	if status := query.Get("status"); status != "" {
+		if err := h.validate.Var(status, "oneof=dispatched received cancelled"); err != nil {
+			response.WriteError(w, r, http.StatusBadRequest, "Invalid status")
+			return
+		}
+		params.Status = &status
+	}
+	if storeUUID := query.Get("store_uuid"); storeUUID != "" {
+		id, err := uuid.Parse(storeUUID)
+		if err != nil {
+			response.WriteError(w, r, http.StatusBadRequest, "Invalid store_uuid")
+			return
+		}
+		params.StoreUUID = &id
+	}
+
+	rows, nextCursor, err := h.service.ListTransfers(r.Context(), params)
+	if err != nil {
+		switch {
+		case errors.Is(err, pagination.ErrInvalidCursor):
+			response.WriteError(w, r, http.StatusBadRequest, err.Error())
+		case errors.Is(err, ErrStoreNotFound):
+			response.WriteError(w, r, http.StatusNotFound, "Store not found")
+		default:
+			log.Error("Failed to list transfers", "error", err)
+			response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	resp := make([]TransferResponse, len(rows))
+	for i, row := range rows {
+		resp[i] = toTransferResponse(repo.GetTransferByUUIDRow(row))
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, TransferListResponse{Items: resp, NextCursor: nextCursor})
+}
+
+// ReceiveTransfer
+//
+//	@Summary		Принять перемещение
+//	@Description	Зачисляет товар в пути на склад магазина-получателя.
+//	@Tags			transfers
+//	@Produce		json
+//	@Param			transferUUID	path		string					true	"UUID перемещения"
+//	@Success		200				{object}	TransferResponse		"Принятое перемещение"
+//	@Failure		400				{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404				{object}	response.ErrorResponse	"Перемещение не найдено"
+//	@Failure		409				{object}	response.ErrorResponse	"Перемещение уже принято или отменено"
+//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/transfers/{transferUUID}/receive [post]
+func (h *Handler) ReceiveTransfer(w http.ResponseWriter, r *http.Request) {
+	h.apply(w, r, h.service.ReceiveTransfer, "Failed to receive transfer")
+}
+
+// CancelTransfer
+//
+//	@Summary		Отменить перемещение
+//	@Description	Возвращает товар в пути на склад магазина-отправителя.
+//	@Tags			transfers
+//	@Produce		json
+//	@Param			transferUUID	path		string					true	"UUID перемещения"
+//	@Success		200				{object}	TransferResponse		"Отменённое перемещение"
+//	@Failure		400				{object}	response.ErrorResponse	"Bad request error"
+//	@Failure		404				{object}	response.ErrorResponse	"Перемещение не найдено"
+//	@Failure		409				{object}	response.ErrorResponse	"Перемещение уже принято или отменено"
+//	@Failure		500				{object}	response.ErrorResponse	"Internal server error"
+//	@Router			/transfers/{transferUUID}/cancel [post]
+func (h *Handler) CancelTransfer(w http.ResponseWriter, r *http.Request) {
+	h.apply(w, r, h.service.CancelTransfer, "Failed to cancel transfer")
+}
+
+func (h *Handler) apply(w http.ResponseWriter, r *http.Request, fn func(ctx context.Context, transferUUID uuid.UUID) (repo.GetTransferByUUIDRow, error), failMsg string) {
+	transferUUID, err := uuid.Parse(chi.URLParam(r, "transferUUID"))
+	if err != nil {
+		middleware.LoggerFromContext(r.Context()).Warn("Invalid transfer UUID format", "error", err)
+		response.WriteError(w, r, http.StatusBadRequest, "Invalid transfer uuid format")
+		return
+	}
+
+	transfer, err := fn(r.Context(), transferUUID)
+	if err != nil {
+		h.writeServiceError(w, r, err, failMsg)
+		return
+	}
+
+	response.WriteJSON(w, r, http.StatusOK, toTransferResponse(transfer))
+}
+
+func (h *Handler) writeServiceError(w http.ResponseWriter, r *http.Request, err error, failMsg string) {
+	switch {
+	case errors.Is(err, ErrTransferNotFound), errors.Is(err, ErrBookNotFound),
+		errors.Is(err, ErrStoreNotFound), errors.Is(err, ErrSKUNotFound):
+		response.WriteError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrInsufficientStock), errors.Is(err, ErrInvalidTransition), errors.Is(err, ErrCurrencyMismatch):
+		response.WriteError(w, r, http.StatusConflict, err.Error())
+	default:
+		middleware.LoggerFromContext(r.Context()).Error(failMsg, "error", err)
+		response.WriteError(w, r, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func toTransferResponse(row repo.GetTransferByUUIDRow) TransferResponse {
+	t := row.Transfer
+	resp := TransferResponse{
+		UUID:                 mustConvertUUID(t.Uuid),
+		BookID:               t.BookID,
+		SourceStoreUUID:      mustConvertUUID(row.SourceStoreUuid),
+		DestinationStoreUUID: mustConvertUUID(row.DestinationStoreUuid),
+		SourceSKUUUID:        mustConvertUUID(row.SourceSkuUuid),
+		DestinationSKUUUID:   mustConvertUUID(row.DestinationSkuUuid),
+		Quantity:             t.Quantity,
+		Status:               string(t.Status),
+		CreatedAt:            t.CreatedAt.Time,
+		UpdatedAt:            t.UpdatedAt.Time,
+		ReceivedAt:           timestamptzToTimep(t.ReceivedAt),
+		CancelledAt:          timestamptzToTimep(t.CancelledAt),
+	}
+	if t.Note.Valid {
+		resp.Note = &t.Note.String
+	}
+	if t.Actor.Valid {
+		resp.Actor = &t.Actor.String
+	}
+	return resp
+}
+
+func timestamptzToTimep(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func mustConvertUUID(pgUUID pgtype.UUID) uuid.UUID {
+	if !pgUUID.Valid {
+		return uuid.Nil
+	}
+	return pgUUID.Bytes
+}
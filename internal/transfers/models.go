@@ -0,0 +1,44 @@
+package transfers
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+)
+
+type CreateTransferRequest struct {
+	BookID               int64     `json:"book_id"                validate:"required"`
+	SourceStoreUUID      uuid.UUID `json:"source_store_uuid"      validate:"required"`
+	DestinationStoreUUID uuid.UUID `json:"destination_store_uuid" validate:"required,nefield=SourceStoreUUID"`
+	Quantity             int32     `json:"quantity"               validate:"gt=0"`
+	Note                 *string   `json:"note,omitempty"         validate:"omitempty,max=1000"`
+}
+
+type ListTransfersParams struct {
+	pagination.Request
+	Status    *string
+	StoreUUID *uuid.UUID
+}
+
+type TransferResponse struct {
+	UUID                 uuid.UUID  `json:"uuid"`
+	BookID               int64      `json:"book_id"`
+	SourceStoreUUID      uuid.UUID  `json:"source_store_uuid"`
+	DestinationStoreUUID uuid.UUID  `json:"destination_store_uuid"`
+	SourceSKUUUID        uuid.UUID  `json:"source_sku_uuid"`
+	DestinationSKUUUID   uuid.UUID  `json:"destination_sku_uuid"`
+	Quantity             int32      `json:"quantity"`
+	Status               string     `json:"status"`
+	Note                 *string    `json:"note,omitempty"`
+	Actor                *string    `json:"actor,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+	ReceivedAt           *time.Time `json:"received_at,omitempty"`
+	CancelledAt          *time.Time `json:"cancelled_at,omitempty"`
+}
+
+type TransferListResponse struct {
+	Items      []TransferResponse `json:"items"`
+	NextCursor *string            `json:"next_cursor"`
+}
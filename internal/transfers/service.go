@@ -0,0 +1,361 @@
+package transfers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nikallow/bookstores-api/internal/adapters/postgres"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/inventory"
+	appMiddleware "github.com/nikallow/bookstores-api/internal/middleware"
+	"github.com/nikallow/bookstores-api/internal/pagination"
+)
+
+var (
+	ErrTransferNotFound  = errors.New("transfer not found")
+	ErrInvalidTransition = errors.New("transfer status does not allow this transition")
+	ErrBookNotFound      = inventory.ErrBookNotFound
+	ErrStoreNotFound     = inventory.ErrStoreNotFound
+	ErrSKUNotFound       = inventory.ErrSKUNotFound
+	ErrSKUAlreadyExists  = inventory.ErrSKUAlreadyExists
+	ErrInsufficientStock = inventory.ErrInsufficientStock
+	ErrCurrencyMismatch  = errors.New("destination store prices in another currency, create its SKU first")
+)
+
+type Service interface {
+	CreateTransfer(ctx context.Context, params CreateTransferRequest) (repo.GetTransferByUUIDRow, error)
+	GetTransfer(ctx context.Context, transferUUID uuid.UUID) (repo.GetTransferByUUIDRow, error)
+	ListTransfers(ctx context.Context, params ListTransfersParams) ([]repo.ListTransfersRow, *string, error)
+	ReceiveTransfer(ctx context.Context, transferUUID uuid.UUID) (repo.GetTransferByUUIDRow, error)
+	CancelTransfer(ctx context.Context, transferUUID uuid.UUID) (repo.GetTransferByUUIDRow, error)
+}
+
+type service struct {
+	repo repo.Querier
+	db   postgres.TxBeginner
+}
+
+func NewService(repo repo.Querier, db postgres.TxBeginner) Service {
+	return &service{repo: repo, db: db}
+}
+
+// CreateTransfer - POST /transfers
+//
+// The copies leave the source store right away and are in transit until the transfer
+// is received. A destination SKU is created with the source price if the book isn't
+// stocked there yet.
+func (s *service) CreateTransfer(ctx context.Context, params CreateTransferRequest) (repo.GetTransferByUUIDRow, error) {
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	if _, err := s.repo.GetBookByID(ctx, params.BookID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.GetTransferByUUIDRow{}, ErrBookNotFound
+		}
+		return repo.GetTransferByUUIDRow{}, err
+	}
+
+	sourceStore, err := s.getStore(ctx, params.SourceStoreUUID)
+	if err != nil {
+		return repo.GetTransferByUUIDRow{}, err
+	}
+	destinationStore, err := s.getStore(ctx, params.DestinationStoreUUID)
+	if err != nil {
+		return repo.GetTransferByUUIDRow{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.GetTransferByUUIDRow{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+
+	source, err := qtx.GetSKUByBookAndStore(ctx, repo.GetSKUByBookAndStoreParams{
+		BookID:  params.BookID,
+		StoreID: sourceStore.ID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.GetTransferByUUIDRow{}, fmt.Errorf("source store: %w", ErrSKUNotFound)
+		}
+		return repo.GetTransferByUUIDRow{}, err
+	}
+
+	destination, err := qtx.GetSKUByBookAndStore(ctx, repo.GetSKUByBookAndStoreParams{
+		BookID:  params.BookID,
+		StoreID: destinationStore.ID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The price of the source SKU only carries over within one currency.
+		if destinationStore.Currency != source.Currency {
+			return repo.GetTransferByUUIDRow{}, ErrCurrencyMismatch
+		}
+		destination, err = insertDestination(ctx, tx, repo.CreateSKUParams{
+			BookID:        params.BookID,
+			StoreID:       destinationStore.ID,
+			PriceInKopeks: source.PriceInKopeks,
+			Currency:      source.Currency,
+		})
+	}
+	if err != nil {
+		return repo.GetTransferByUUIDRow{}, err
+	}
+
+	source, _, err = lockPair(ctx, qtx, source.Uuid.Bytes, destination.Uuid.Bytes)
+	if err != nil {
+		return repo.GetTransferByUUIDRow{}, err
+	}
+
+	transfer, err := qtx.CreateTransfer(ctx, repo.CreateTransferParams{
+		BookID:           params.BookID,
+		SourceSkuID:      source.ID,
+		DestinationSkuID: destination.ID,
+		Quantity:         params.Quantity,
+		Note:             stringToPgTextp(params.Note),
+		Actor:            stringToPgText(appMiddleware.ActorFromContext(ctx)),
+	})
+	if err != nil {
+		log.Error("Failed to create transfer", "error", err)
+		return repo.GetTransferByUUIDRow{}, err
+	}
+
+	note := transferNote(transfer, "dispatched")
+	if _, err := inventory.ApplyStockChange(ctx, qtx, source, -params.Quantity, repo.StockMovementReasonTransfer, &note); err != nil {
+		return repo.GetTransferByUUIDRow{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return repo.GetTransferByUUIDRow{}, err
+	}
+
+	log.Info("Transfer dispatched", "transfer_id", transfer.ID, "quantity", transfer.Quantity)
+	return repo.GetTransferByUUIDRow{
+		Transfer:             transfer,
+		SourceSkuUuid:        source.Uuid,
+		DestinationSkuUuid:   destination.Uuid,
+		SourceStoreUuid:      sourceStore.Uuid,
+		DestinationStoreUuid: destinationStore.Uuid,
+	}, nil
+}
+
+func (s *service) GetTransfer(ctx context.Context, transferUUID uuid.UUID) (repo.GetTransferByUUIDRow, error) {
+	row, err := s.repo.GetTransferByUUID(ctx, uuidToPgUUID(transferUUID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.GetTransferByUUIDRow{}, ErrTransferNotFound
+		}
+		return repo.GetTransferByUUIDRow{}, err
+	}
+	return row, nil
+}
+
+// ListTransfers - GET /transfers
+func (s *service) ListTransfers(ctx context.Context, params ListTransfersParams) ([]repo.ListTransfersRow, *string, error) {
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	cursor, err := params.DecodeCursor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queryParams := repo.ListTransfersParams{
+		PageLimit: params.QueryLimit(),
+	}
+	if params.Status != nil {
+		queryParams.Status = repo.NullTransferStatus{TransferStatus: repo.TransferStatus(*params.Status), Valid: true}
+	}
+	if params.StoreUUID != nil {
+		store, err := s.getStore(ctx, *params.StoreUUID)
+		if err != nil {
+			return nil, nil, err
+		}
+		queryParams.StoreID = pgtype.Int8{Int64: store.ID, Valid: true}
+	}
+	if cursor != nil {
+		t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: bad timestamp", pagination.ErrInvalidCursor)
+		}
+		queryParams.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+		queryParams.CursorTime = pgtype.Timestamptz{Time: t, Valid: true}
+	}
+
+	var rows []repo.ListTransfersRow
+	if params.Desc {
+		var descRows []repo.ListTransfersDescRow
+		descRows, err = s.repo.ListTransfersDesc(ctx, repo.ListTransfersDescParams(queryParams))
+		for _, row := range descRows {
+			rows = append(rows, repo.ListTransfersRow(row))
+		}
+	} else {
+		rows, err = s.repo.ListTransfers(ctx, queryParams)
+	}
+	if err != nil {
+		log.Error("Failed to list transfers", "error", err)
+		return nil, nil, err
+	}
+
+	rows, hasMore := pagination.Trim(rows, params.Request)
+	if !hasMore {
+		return rows, nil, nil
+	}
+	last := rows[len(rows)-1].Transfer
+	next := params.NextCursor(last.CreatedAt.Time.Format(time.RFC3339Nano), last.ID)
+	return rows, &next, nil
+}
+
+// ReceiveTransfer - POST /transfers/{transferUUID}/receive
+//
+// The copies in transit are added to the destination SKU.
+func (s *service) ReceiveTransfer(ctx context.Context, transferUUID uuid.UUID) (repo.GetTransferByUUIDRow, error) {
+	return s.complete(ctx, transferUUID, repo.TransferStatusReceived)
+}
+
+// CancelTransfer - POST /transfers/{transferUUID}/cancel
+//
+// The copies in transit go back to the source SKU.
+func (s *service) CancelTransfer(ctx context.Context, transferUUID uuid.UUID) (repo.GetTransferByUUIDRow, error) {
+	return s.complete(ctx, transferUUID, repo.TransferStatusCancelled)
+}
+
+func (s *service) complete(ctx context.Context, transferUUID uuid.UUID, to repo.TransferStatus) (repo.GetTransferByUUIDRow, error) {
+	log := appMiddleware.LoggerFromContext(ctx)
+
+	row, err := s.GetTransfer(ctx, transferUUID)
+	if err != nil {
+		return repo.GetTransferByUUIDRow{}, err
+	}
+
+	skuID := row.Transfer.DestinationSkuID
+	if to == repo.TransferStatusCancelled {
+		skuID = row.Transfer.SourceSkuID
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.GetTransferByUUIDRow{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := repo.New(tx)
+
+	sku, err := inventory.LockSKUByID(ctx, qtx, skuID)
+	if err != nil {
+		return repo.GetTransferByUUIDRow{}, err
+	}
+	transfer, err := qtx.GetTransferByIDForUpdate(ctx, row.Transfer.ID)
+	if err != nil {
+		return repo.GetTransferByUUIDRow{}, err
+	}
+	if transfer.Status != repo.TransferStatusDispatched {
+		return repo.GetTransferByUUIDRow{}, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, transfer.Status, to)
+	}
+
+	note := transferNote(transfer, string(to))
+	if _, err := inventory.ApplyStockChange(ctx, qtx, sku, transfer.Quantity, repo.StockMovementReasonTransfer, &note); err != nil {
+		return repo.GetTransferByUUIDRow{}, err
+	}
+
+	row.Transfer, err = qtx.UpdateTransferStatus(ctx, repo.UpdateTransferStatusParams{
+		Status: to,
+		ID:     transfer.ID,
+	})
+	if err != nil {
+		log.Error("Failed to update transfer status", "error", err, "transfer_id", transfer.ID)
+		return repo.GetTransferByUUIDRow{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return repo.GetTransferByUUIDRow{}, err
+	}
+
+	log.Info("Transfer status changed", "transfer_id", transfer.ID, "status", to)
+	return row, nil
+}
+
+func (s *service) getStore(ctx context.Context, storeUUID uuid.UUID) (repo.Store, error) {
+	store, err := s.repo.GetStoreByUUID(ctx, uuidToPgUUID(storeUUID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.Store{}, ErrStoreNotFound
+		}
+		return repo.Store{}, err
+	}
+	return store, nil
+}
+
+// lockPair locks both SKUs of a transfer in UUID order, the order orders use too,
+// and returns them as (source, destination).
+func lockPair(ctx context.Context, q *repo.Queries, sourceUUID, destinationUUID uuid.UUID) (repo.Sku, repo.Sku, error) {
+	first, second := sourceUUID, destinationUUID
+	if bytes.Compare(first[:], second[:]) > 0 {
+		first, second = second, first
+	}
+
+	locked := make(map[uuid.UUID]repo.Sku, 2)
+	for _, id := range []uuid.UUID{first, second} {
+		sku, err := inventory.LockSKU(ctx, q, id, nil)
+		if err != nil {
+			return repo.Sku{}, repo.Sku{}, err
+		}
+		locked[id] = sku
+	}
+	return locked[sourceUUID], locked[destinationUUID], nil
+}
+
+// insertDestination creates the destination SKU in a savepoint of tx. A concurrent request may
+// create it first; its unique violation then rolls back only the savepoint, and the SKU that
+// request created is used instead.
+func insertDestination(ctx context.Context, tx pgx.Tx, params repo.CreateSKUParams) (repo.Sku, error) {
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return repo.Sku{}, err
+	}
+	defer sp.Rollback(ctx)
+
+	sku, err := inventory.InsertSKU(ctx, repo.New(sp), params)
+	if errors.Is(err, ErrSKUAlreadyExists) {
+		if err := sp.Rollback(ctx); err != nil {
+			return repo.Sku{}, err
+		}
+		return repo.New(tx).GetSKUByBookAndStore(ctx, repo.GetSKUByBookAndStoreParams{
+			BookID:  params.BookID,
+			StoreID: params.StoreID,
+		})
+	}
+	if err != nil {
+		return repo.Sku{}, err
+	}
+	if err := sp.Commit(ctx); err != nil {
+		return repo.Sku{}, err
+	}
+
+	appMiddleware.LoggerFromContext(ctx).Info("Destination SKU created for transfer", "sku_id", sku.ID)
+	return sku, nil
+}
+
+func transferNote(transfer repo.Transfer, event string) string {
+	return fmt.Sprintf("transfer %s %s", uuid.UUID(transfer.Uuid.Bytes), event)
+}
+
+func stringToPgText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+func stringToPgTextp(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{Valid: false}
+	}
+	return pgtype.Text{String: *s, Valid: true}
+}
+
+func uuidToPgUUID(u uuid.UUID) pgtype.UUID {
+	return pgtype.UUID{Bytes: u, Valid: true}
+}
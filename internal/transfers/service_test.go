@@ -0,0 +1,275 @@
+package transfers
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	repo "github.com/nikallow/bookstores-api/internal/adapters/postgres/sqlc"
+	"github.com/nikallow/bookstores-api/internal/database/dbtest"
+)
+
+func TestTransferLifecycle(t *testing.T) {
+	db := dbtest.New(t)
+	svc := NewService(repo.New(db), db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name             string
+		destinationStock int32 // -1 if the book isn't stocked at the destination
+		cancel           bool
+		wantSource       int32
+		wantDestination  int32
+		wantSourceMoves  []string
+		wantDestMoves    []string
+	}{
+		{
+			name:             "received",
+			destinationStock: 2,
+			wantSource:       6,
+			wantDestination:  6,
+			wantSourceMoves:  []string{"transfer"},
+			wantDestMoves:    []string{"transfer"},
+		},
+		{
+			name:             "received where the book isn't stocked",
+			destinationStock: -1,
+			wantSource:       6,
+			wantDestination:  4,
+			wantSourceMoves:  []string{"transfer"},
+			wantDestMoves:    []string{"transfer"},
+		},
+		{
+			name:             "cancelled",
+			destinationStock: 2,
+			cancel:           true,
+			wantSource:       10,
+			wantDestination:  2,
+			wantSourceMoves:  []string{"transfer", "transfer"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bookID := dbtest.CreateBook(t, db)
+			sourceStoreID, sourceStoreUUID := dbtest.CreateStore(t, db)
+			destinationStoreID, destinationStoreUUID := dbtest.CreateStore(t, db)
+			source := dbtest.CreateSKU(t, db, bookID, sourceStoreID, 1000, 10)
+			if tt.destinationStock >= 0 {
+				dbtest.CreateSKU(t, db, bookID, destinationStoreID, 1200, tt.destinationStock)
+			}
+
+			transfer, err := svc.CreateTransfer(ctx, CreateTransferRequest{
+				BookID:               bookID,
+				SourceStoreUUID:      sourceStoreUUID,
+				DestinationStoreUUID: destinationStoreUUID,
+				Quantity:             4,
+			})
+			if err != nil {
+				t.Fatalf("CreateTransfer() error = %v", err)
+			}
+			if uuid.UUID(transfer.SourceSkuUuid.Bytes) != source {
+				t.Errorf("source sku = %s, want %s", uuid.UUID(transfer.SourceSkuUuid.Bytes), source)
+			}
+			destination := uuid.UUID(transfer.DestinationSkuUuid.Bytes)
+			if stock := dbtest.Stock(t, db, source); stock != 6 {
+				t.Errorf("source stock in transit = %d, want 6", stock)
+			}
+			if tt.destinationStock < 0 {
+				var price int32
+				err := db.QueryRow(ctx, "SELECT price_in_kopeks FROM skus WHERE uuid = $1", transfer.DestinationSkuUuid).Scan(&price)
+				if err != nil {
+					t.Fatalf("failed to get destination sku: %v", err)
+				}
+				if price != 1000 {
+					t.Errorf("destination price = %d, want the source price 1000", price)
+				}
+			}
+
+			id := uuid.UUID(transfer.Transfer.Uuid.Bytes)
+			complete, wantStatus := svc.ReceiveTransfer, repo.TransferStatusReceived
+			if tt.cancel {
+				complete, wantStatus = svc.CancelTransfer, repo.TransferStatusCancelled
+			}
+			transfer, err = complete(ctx, id)
+			if err != nil {
+				t.Fatalf("%s: error = %v", wantStatus, err)
+			}
+			if transfer.Transfer.Status != wantStatus {
+				t.Errorf("status = %s, want %s", transfer.Transfer.Status, wantStatus)
+			}
+
+			if stock := dbtest.Stock(t, db, source); stock != tt.wantSource {
+				t.Errorf("source stock = %d, want %d", stock, tt.wantSource)
+			}
+			if stock := dbtest.Stock(t, db, destination); stock != tt.wantDestination {
+				t.Errorf("destination stock = %d, want %d", stock, tt.wantDestination)
+			}
+			if moves := dbtest.Movements(t, db, source); !slices.Equal(moves, tt.wantSourceMoves) {
+				t.Errorf("source movements = %v, want %v", moves, tt.wantSourceMoves)
+			}
+			if moves := dbtest.Movements(t, db, destination); !slices.Equal(moves, tt.wantDestMoves) {
+				t.Errorf("destination movements = %v, want %v", moves, tt.wantDestMoves)
+			}
+
+			for _, again := range []func(context.Context, uuid.UUID) (repo.GetTransferByUUIDRow, error){svc.ReceiveTransfer, svc.CancelTransfer} {
+				if _, err := again(ctx, id); !errors.Is(err, ErrInvalidTransition) {
+					t.Errorf("completing twice error = %v, want %v", err, ErrInvalidTransition)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateTransferErrors(t *testing.T) {
+	db := dbtest.New(t)
+	svc := NewService(repo.New(db), db)
+
+	bookID := dbtest.CreateBook(t, db)
+	unstockedBookID := dbtest.CreateBook(t, db)
+	sourceStoreID, sourceStoreUUID := dbtest.CreateStore(t, db)
+	_, destinationStoreUUID := dbtest.CreateStore(t, db)
+	source := dbtest.CreateSKU(t, db, bookID, sourceStoreID, 1000, 10)
+
+	tests := []struct {
+		name    string
+		params  CreateTransferRequest
+		wantErr error
+	}{
+		{
+			name:    "more than in stock",
+			params:  CreateTransferRequest{BookID: bookID, SourceStoreUUID: sourceStoreUUID, DestinationStoreUUID: destinationStoreUUID, Quantity: 11},
+			wantErr: ErrInsufficientStock,
+		},
+		{
+			name:    "book not stocked at the source",
+			params:  CreateTransferRequest{BookID: unstockedBookID, SourceStoreUUID: sourceStoreUUID, DestinationStoreUUID: destinationStoreUUID, Quantity: 1},
+			wantErr: ErrSKUNotFound,
+		},
+		{
+			name:    "unknown destination store",
+			params:  CreateTransferRequest{BookID: bookID, SourceStoreUUID: sourceStoreUUID, DestinationStoreUUID: uuid.New(), Quantity: 1},
+			wantErr: ErrStoreNotFound,
+		},
+		{
+			name:    "unknown book",
+			params:  CreateTransferRequest{BookID: -1, SourceStoreUUID: sourceStoreUUID, DestinationStoreUUID: destinationStoreUUID, Quantity: 1},
+			wantErr: ErrBookNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.CreateTransfer(context.Background(), tt.params); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateTransfer() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if stock := dbtest.Stock(t, db, source); stock != 10 {
+		t.Errorf("source stock = %d, want 10", stock)
+	}
+	var skus int
+	if err := db.QueryRow(context.Background(), "SELECT count(*) FROM skus WHERE book_id = $1", bookID).Scan(&skus); err != nil {
+		t.Fatalf("failed to count skus: %v", err)
+	}
+	if skus != 1 {
+		t.Errorf("got %d skus of the book, want only the source one", skus)
+	}
+}
+
+func TestConcurrentTransfersToUnstockedStore(t *testing.T) {
+	db := dbtest.New(t)
+	svc := NewService(repo.New(db), db)
+
+	bookID := dbtest.CreateBook(t, db)
+	sourceStoreID, sourceStoreUUID := dbtest.CreateStore(t, db)
+	_, destinationStoreUUID := dbtest.CreateStore(t, db)
+	source := dbtest.CreateSKU(t, db, bookID, sourceStoreID, 1000, 10)
+
+	const transfers = 4
+	type result struct {
+		destination uuid.UUID
+		err         error
+	}
+	results := make(chan result, transfers)
+	for range transfers {
+		go func() {
+			transfer, err := svc.CreateTransfer(context.Background(), CreateTransferRequest{
+				BookID:               bookID,
+				SourceStoreUUID:      sourceStoreUUID,
+				DestinationStoreUUID: destinationStoreUUID,
+				Quantity:             1,
+			})
+			results <- result{uuid.UUID(transfer.DestinationSkuUuid.Bytes), err}
+		}()
+	}
+
+	var destination uuid.UUID
+	for range transfers {
+		res := <-results
+		if res.err != nil {
+			t.Fatalf("CreateTransfer() error = %v", res.err)
+		}
+		if destination == uuid.Nil {
+			destination = res.destination
+		} else if res.destination != destination {
+			t.Errorf("destination sku = %s, want %s", res.destination, destination)
+		}
+	}
+
+	if stock := dbtest.Stock(t, db, source); stock != 10-transfers {
+		t.Errorf("source stock = %d, want %d", stock, 10-transfers)
+	}
+}
+
+func TestCompleteTransferOnDeletedSKU(t *testing.T) {
+	db := dbtest.New(t)
+	svc := NewService(repo.New(db), db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		cancel  bool
+		deleted func(source, destination uuid.UUID) uuid.UUID
+		want    int32
+	}{
+		{"received at a deleted destination", false, func(_, destination uuid.UUID) uuid.UUID { return destination }, 6},
+		{"cancelled to a deleted source", true, func(source, _ uuid.UUID) uuid.UUID { return source }, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bookID := dbtest.CreateBook(t, db)
+			sourceStoreID, sourceStoreUUID := dbtest.CreateStore(t, db)
+			destinationStoreID, destinationStoreUUID := dbtest.CreateStore(t, db)
+			source := dbtest.CreateSKU(t, db, bookID, sourceStoreID, 1000, 10)
+			destination := dbtest.CreateSKU(t, db, bookID, destinationStoreID, 1000, 2)
+
+			transfer, err := svc.CreateTransfer(ctx, CreateTransferRequest{
+				BookID:               bookID,
+				SourceStoreUUID:      sourceStoreUUID,
+				DestinationStoreUUID: destinationStoreUUID,
+				Quantity:             4,
+			})
+			if err != nil {
+				t.Fatalf("CreateTransfer() error = %v", err)
+			}
+			deleted := tt.deleted(source, destination)
+			if _, err := db.Exec(ctx, "UPDATE skus SET deleted_at = now() WHERE uuid = $1", pgtype.UUID{Bytes: deleted, Valid: true}); err != nil {
+				t.Fatalf("failed to delete sku: %v", err)
+			}
+
+			complete := svc.ReceiveTransfer
+			if tt.cancel {
+				complete = svc.CancelTransfer
+			}
+			if _, err := complete(ctx, transfer.Transfer.Uuid.Bytes); err != nil {
+				t.Fatalf("completing error = %v", err)
+			}
+			if stock := dbtest.Stock(t, db, deleted); stock != tt.want {
+				t.Errorf("stock of the deleted sku = %d, want %d", stock, tt.want)
+			}
+		})
+	}
+}